@@ -357,6 +357,10 @@ func runDoctorPathInfo(ctx *cli.Context) ([]string, error) {
 	// Note print paths inside quotes to make any leading/trailing spaces evident
 	check("Configuration File Path", setting.CustomConf, false, true, false)
 	check("Repository Root Path", setting.RepoRootPath, true, true, true)
+	for _, name := range setting.RepoStorageVolumeNames()[1:] {
+		path, _ := setting.RepoStorageVolumePath(name)
+		check("Repository Volume "+name+" Path", path, true, true, true)
+	}
 	check("Data Root Path", setting.AppDataPath, true, true, true)
 	check("Custom File Root Path", setting.CustomPath, true, false, false)
 	check("Work directory", setting.AppWorkPath, true, true, false)
@@ -654,6 +658,22 @@ func runDoctorCheckDBConsistency(ctx *cli.Context) ([]string, error) {
 		}
 	}
 
+	//find review states without existing pull requests
+	count, err = models.CountOrphanedObjects("review_state", "pull_request", "review_state.pull_id=pull_request.id")
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		if ctx.Bool("fix") {
+			if err = models.DeleteOrphanedObjects("review_state", "pull_request", "review_state.pull_id=pull_request.id"); err != nil {
+				return nil, err
+			}
+			results = append(results, fmt.Sprintf("%d review states without existing pull request deleted", count))
+		} else {
+			results = append(results, fmt.Sprintf("%d review states without existing pull request", count))
+		}
+	}
+
 	count, err = models.CountNullArchivedRepository()
 	if err != nil {
 		return nil, err
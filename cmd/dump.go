@@ -237,6 +237,12 @@ func runDump(ctx *cli.Context) error {
 		if err := addRecursive(w, "repos", setting.RepoRootPath, verbose); err != nil {
 			fatal("Failed to include repositories: %v", err)
 		}
+		for name, volume := range setting.RepoStorage.Volumes {
+			log.Info("Dumping local repositories of the volume %s... %s", name, volume.Path)
+			if err := addRecursive(w, path.Join("repos-volumes", name), volume.Path, verbose); err != nil {
+				fatal("Failed to include repositories of the volume %s: %v", name, err)
+			}
+		}
 
 		if err := storage.LFS.IterateObjects(func(objPath string, object storage.Object) error {
 			info, err := object.Stat()
@@ -319,6 +325,9 @@ func runDump(ctx *cli.Context) error {
 		}
 
 		excludes = append(excludes, setting.RepoRootPath)
+		for _, volume := range setting.RepoStorage.Volumes {
+			excludes = append(excludes, volume.Path)
+		}
 		excludes = append(excludes, setting.LFS.Path)
 		excludes = append(excludes, setting.Attachment.Path)
 		excludes = append(excludes, setting.LogRootPath)
@@ -26,6 +26,7 @@ var (
 			subcmdShutdown,
 			subcmdRestart,
 			subcmdFlushQueues,
+			subcmdQueues,
 			subcmdLogging,
 		},
 	}
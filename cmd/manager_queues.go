@@ -0,0 +1,202 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"code.gitea.io/gitea/modules/private"
+
+	"github.com/urfave/cli"
+)
+
+var (
+	debugFlag = cli.BoolFlag{
+		Name: "debug",
+	}
+	subcmdQueues = cli.Command{
+		Name:  "queues",
+		Usage: "Inspect and manage the queues of the running process",
+		Description: `The queues are named by their name or their QID as listed by "queues list".
+The items can only be listed and removed from the queues persisting them, the level and redis backed queues.`,
+		Subcommands: []cli.Command{
+			{
+				Name:   "list",
+				Usage:  "List the queues with their number of workers and waiting items",
+				Flags:  []cli.Flag{debugFlag},
+				Action: runListQueues,
+			}, {
+				Name:      "pause",
+				Usage:     "Stop the workers of a queue from taking new items, the queue keeps accepting them",
+				ArgsUsage: "[queue]",
+				Flags:     []cli.Flag{debugFlag},
+				Action:    runPauseQueue,
+			}, {
+				Name:      "resume",
+				Usage:     "Resume the workers of a paused queue",
+				ArgsUsage: "[queue]",
+				Flags:     []cli.Flag{debugFlag},
+				Action:    runResumeQueue,
+			}, {
+				Name:      "flush",
+				Usage:     "Flush a queue",
+				ArgsUsage: "[queue]",
+				Flags: []cli.Flag{
+					cli.DurationFlag{
+						Name:  "timeout",
+						Value: 60 * time.Second,
+						Usage: "Timeout for the flushing process",
+					}, cli.BoolFlag{
+						Name:  "non-blocking",
+						Usage: "Set to true to not wait for flush to complete before returning",
+					},
+					debugFlag,
+				},
+				Action: runFlushQueue,
+			}, {
+				Name:      "items",
+				Usage:     "Dump the persisted items of a queue, from the next to be handled",
+				ArgsUsage: "[queue]",
+				Flags: []cli.Flag{
+					cli.IntFlag{
+						Name:  "skip",
+						Usage: "Number of items to skip",
+					}, cli.IntFlag{
+						Name:  "limit",
+						Value: 50,
+						Usage: "Maximum number of items to dump, 0 for all of them",
+					},
+					debugFlag,
+				},
+				Action: runQueueItems,
+			}, {
+				Name:      "remove",
+				Usage:     "Remove a persisted item from a queue by its index as listed by \"items\"",
+				ArgsUsage: "[queue] [index]",
+				Flags:     []cli.Flag{debugFlag},
+				Action:    runRemoveQueueItem,
+			}, {
+				Name:      "purge",
+				Usage:     "Remove all the waiting items of a queue",
+				ArgsUsage: "[queue]",
+				Flags:     []cli.Flag{debugFlag},
+				Action:    runPurgeQueue,
+			},
+		},
+	}
+)
+
+func queueArg(c *cli.Context) (string, error) {
+	queue := c.Args().First()
+	if len(queue) == 0 {
+		return "", errors.New("the name or the QID of the queue must be given")
+	}
+	return queue, nil
+}
+
+func printQueueResult(statusCode int, msg string) error {
+	if statusCode != http.StatusOK {
+		fail(msg, "")
+	}
+	fmt.Fprintln(os.Stdout, msg)
+	return nil
+}
+
+func runListQueues(c *cli.Context) error {
+	setup("manager", c.Bool("debug"))
+	queues, statusCode, msg := private.ListQueues()
+	if statusCode != http.StatusOK {
+		fail(msg, "")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 5, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "QID\tName\tType\tWorkers\tItems\tPaused\tInspectable\n")
+	for _, queue := range queues {
+		workers, items := "-", "-"
+		if queue.NumberOfWorkers >= 0 {
+			workers = fmt.Sprintf("%d/%d", queue.NumberOfWorkers, queue.MaxNumberOfWorkers)
+		}
+		if queue.NumberInQueue >= 0 {
+			items = strconv.FormatInt(queue.NumberInQueue, 10)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%t\t%t\n", queue.ID, queue.Name, queue.Type, workers, items, queue.Paused, queue.Inspectable)
+	}
+	return w.Flush()
+}
+
+func runPauseQueue(c *cli.Context) error {
+	setup("manager", c.Bool("debug"))
+	queue, err := queueArg(c)
+	if err != nil {
+		return err
+	}
+	return printQueueResult(private.PauseQueue(queue))
+}
+
+func runResumeQueue(c *cli.Context) error {
+	setup("manager", c.Bool("debug"))
+	queue, err := queueArg(c)
+	if err != nil {
+		return err
+	}
+	return printQueueResult(private.ResumeQueue(queue))
+}
+
+func runFlushQueue(c *cli.Context) error {
+	setup("manager", c.Bool("debug"))
+	queue, err := queueArg(c)
+	if err != nil {
+		return err
+	}
+	statusCode, msg := private.FlushQueue(queue, c.Duration("timeout"), c.Bool("non-blocking"))
+	if statusCode == http.StatusAccepted {
+		statusCode = http.StatusOK
+	}
+	return printQueueResult(statusCode, msg)
+}
+
+func runQueueItems(c *cli.Context) error {
+	setup("manager", c.Bool("debug"))
+	queue, err := queueArg(c)
+	if err != nil {
+		return err
+	}
+	items, statusCode, msg := private.QueueItems(queue, c.Int("skip"), c.Int("limit"))
+	if statusCode != http.StatusOK {
+		fail(msg, "")
+	}
+	for _, item := range items {
+		fmt.Fprintf(os.Stdout, "%d\t%s\n", item.Index, item.Data)
+	}
+	return nil
+}
+
+func runRemoveQueueItem(c *cli.Context) error {
+	setup("manager", c.Bool("debug"))
+	queue, err := queueArg(c)
+	if err != nil {
+		return err
+	}
+	index, err := strconv.Atoi(c.Args().Get(1))
+	if err != nil {
+		return fmt.Errorf("the index of the item must be given: %v", err)
+	}
+	return printQueueResult(private.RemoveQueueItem(queue, index))
+}
+
+func runPurgeQueue(c *cli.Context) error {
+	setup("manager", c.Bool("debug"))
+	queue, err := queueArg(c)
+	if err != nil {
+		return err
+	}
+	return printQueueResult(private.PurgeQueue(queue))
+}
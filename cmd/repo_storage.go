@@ -0,0 +1,200 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/urfave/cli"
+)
+
+// CmdRepoStorage represents the available repo-storage sub-command.
+var CmdRepoStorage = cli.Command{
+	Name:  "repo-storage",
+	Usage: "Manage the storage of the git repositories",
+	Description: `The git repositories are stored in volumes, the repository ROOT and the [repository.volume.*] sections,
+with the name layout (<owner>/<name>.git) or the id layout sharded by the ID of the repositories.
+The repositories should not be used while they are relocated.`,
+	Subcommands: []cli.Command{
+		subcmdRepoStorageVolumes,
+		subcmdRepoStorageRelocate,
+		subcmdRepoStorageRebalance,
+	},
+}
+
+var (
+	subcmdRepoStorageVolumes = cli.Command{
+		Name:   "volumes",
+		Usage:  "List the repository volumes and their usage",
+		Action: runRepoStorageVolumes,
+	}
+
+	subcmdRepoStorageRelocate = cli.Command{
+		Name:   "relocate",
+		Usage:  "Move repositories to another volume or layout",
+		Action: runRepoStorageRelocate,
+		Flags: []cli.Flag{
+			cli.StringSliceFlag{
+				Name:  "repo, r",
+				Usage: "Full name (owner/name) of a repository to relocate, may be repeated",
+			},
+			cli.BoolFlag{
+				Name:  "all",
+				Usage: "Relocate all the repositories",
+			},
+			cli.StringFlag{
+				Name:  "volume",
+				Usage: "Volume to move the repositories to, they stay in their volume if empty",
+			},
+			cli.StringFlag{
+				Name:  "layout",
+				Usage: "Layout to store the repositories with, 'name' or 'id', they keep their layout if empty",
+			},
+		},
+	}
+
+	subcmdRepoStorageRebalance = cli.Command{
+		Name:   "rebalance",
+		Usage:  "Move repositories out of the volumes not accepting new repositories and balance the size of the others",
+		Action: runRepoStorageRebalance,
+		Flags: []cli.Flag{
+			cli.IntFlag{
+				Name:  "max-moves",
+				Usage: "Maximum number of repositories to move, 0 for no limit",
+			},
+			cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Only print the planned moves",
+			},
+		},
+	}
+)
+
+func runRepoStorageVolumes(c *cli.Context) error {
+	if err := initDB(); err != nil {
+		return err
+	}
+
+	stats, err := models.GetRepoStorageVolumeStats()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 5, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "Name\tPath\tAcceptNewRepositories\tRepositories\tSize\n")
+	for _, stat := range stats {
+		path := stat.Path
+		if !stat.Configured {
+			path = "(not configured)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%d\t%s\n", stat.Name, path, stat.AcceptNewRepositories, stat.NumRepos, base.FileSize(stat.Size))
+	}
+	w.Flush()
+
+	fmt.Printf("\nLayout of the new repositories: %s, volume placement: %s\n", setting.RepoStorage.Layout, setting.RepoStorage.Placement)
+	return nil
+}
+
+func runRepoStorageRelocate(c *cli.Context) error {
+	names := c.StringSlice("repo")
+	if len(names) == 0 && !c.Bool("all") {
+		return errors.New("either --repo or --all must be given")
+	}
+	if !c.IsSet("volume") && !c.IsSet("layout") {
+		return errors.New("--volume or --layout must be given")
+	}
+
+	if err := initDB(); err != nil {
+		return err
+	}
+
+	var repos []*models.Repository
+	if c.Bool("all") {
+		// the repositories are listed first as their relocation updates the rows being iterated
+		if err := models.IterateRepository(func(repo *models.Repository) error {
+			repos = append(repos, repo)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	for _, name := range names {
+		parts := strings.SplitN(name, "/", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid repository name: %s", name)
+		}
+		repo, err := models.GetRepositoryByOwnerAndName(parts[0], parts[1])
+		if err != nil {
+			return err
+		}
+		repos = append(repos, repo)
+	}
+
+	var failed int
+	for _, repo := range repos {
+		volume, layout := repo.StorageVolume, repo.StorageLayout
+		if c.IsSet("volume") {
+			volume = c.String("volume")
+		}
+		if c.IsSet("layout") {
+			layout = c.String("layout")
+		}
+		oldPath := repo.RepoPath()
+		if err := models.RelocateRepository(repo, volume, layout); err != nil {
+			if models.IsErrRepoStorageVolumeNotExist(err) || models.IsErrRepoStorageLayoutNotExist(err) {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Failed to relocate %s: %v\n", repo.FullName(), err)
+			failed++
+			continue
+		}
+		if newPath := repo.RepoPath(); newPath != oldPath {
+			fmt.Printf("%s: %s -> %s\n", repo.FullName(), oldPath, newPath)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d repositories failed to be relocated", failed)
+	}
+	return nil
+}
+
+func runRepoStorageRebalance(c *cli.Context) error {
+	if err := initDB(); err != nil {
+		return err
+	}
+
+	moves, err := models.PlanRepoStorageRebalance(c.Int("max-moves"))
+	if err != nil {
+		return err
+	}
+	if len(moves) == 0 {
+		fmt.Println("The volumes are balanced")
+		return nil
+	}
+
+	var failed int
+	for _, move := range moves {
+		fmt.Printf("%s (%s): %s -> %s\n", move.Repo.FullName(), base.FileSize(move.Repo.Size), move.From, move.To)
+		if c.Bool("dry-run") {
+			continue
+		}
+		if err := models.RelocateRepository(move.Repo, move.To, move.Repo.StorageLayout); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to relocate %s: %v\n", move.Repo.FullName(), err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d repositories failed to be relocated", failed)
+	}
+	return nil
+}
@@ -107,6 +107,12 @@ func runServ(c *cli.Context) error {
 	}
 	keyID := com.StrTo(keys[1]).MustInt64()
 
+	// Give this operation a request ID, unless the builtin SSH server has already done so, which will be
+	// passed on to the internal API and through git to the hooks
+	if !log.IsValidRequestID(os.Getenv(log.RequestIDEnv)) {
+		os.Setenv(log.RequestIDEnv, log.NewRequestID())
+	}
+
 	cmd := os.Getenv("SSH_ORIGINAL_COMMAND")
 	if len(cmd) == 0 {
 		key, user, err := private.ServNoCommand(keyID)
@@ -266,6 +272,11 @@ func runServ(c *cli.Context) error {
 		verb = strings.Replace(verb, "-", " ", 1)
 	}
 
+	// The repositories aren't always stored at their owner/name path in the repository root
+	if results.RepoPath != "" {
+		repoPath = results.RepoPath
+	}
+
 	var gitcmd *exec.Cmd
 	verbs := strings.Split(verb, " ")
 	if len(verbs) == 2 {
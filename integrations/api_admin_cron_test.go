@@ -0,0 +1,90 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminCronTask(t *testing.T) {
+	defer prepareTestEnv(t)()
+	// user1 is an admin user
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequest(t, "GET", "/api/v1/admin/cron/update_mirrors?token="+token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var task api.Cron
+	DecodeJSON(t, resp, &task)
+	assert.EqualValues(t, "update_mirrors", task.Name)
+	assert.False(t, task.Overridden)
+
+	enabled, schedule := false, "@every 3h"
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/admin/cron/update_mirrors?token="+token, &api.EditCronOption{
+		Enabled:  &enabled,
+		Schedule: &schedule,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &task)
+	assert.False(t, task.Enabled)
+	assert.True(t, task.Overridden)
+	assert.EqualValues(t, schedule, task.Schedule)
+	models.AssertExistsAndLoadBean(t, &models.CronTask{Name: "update_mirrors", Schedule: schedule})
+
+	invalid := "not a schedule"
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/admin/cron/update_mirrors?token="+token, &api.EditCronOption{
+		Schedule: &invalid,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "POST", "/api/v1/admin/cron/update_mirrors/reset?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &task)
+	assert.False(t, task.Overridden)
+	models.AssertNotExistsBean(t, &models.CronTask{Name: "update_mirrors"})
+
+	req = NewRequest(t, "GET", "/api/v1/admin/cron/update_mirrors/runs?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var runs []*api.CronRun
+	DecodeJSON(t, resp, &runs)
+
+	req = NewRequest(t, "GET", "/api/v1/admin/cron/no_such_task?token="+token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAdminCronTask(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user1")
+
+	run := &models.CronTaskRun{Name: "update_mirrors", DoerID: -1}
+	assert.NoError(t, models.CreateCronTaskRun(run))
+	assert.NoError(t, models.FinishCronTaskRun(run, models.CronTaskRunSucceeded, "", 10))
+
+	req := NewRequest(t, "GET", "/admin/monitor")
+	session.MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequest(t, "GET", "/admin/monitor/cron/update_mirrors")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+
+	req = NewRequestWithValues(t, "POST", "/admin/monitor/cron/update_mirrors/set", map[string]string{
+		"_csrf":    htmlDoc.GetCSRF(),
+		"schedule": "@every 2h",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertExistsAndLoadBean(t, &models.CronTask{Name: "update_mirrors", Schedule: "@every 2h"})
+
+	req = NewRequestWithValues(t, "POST", "/admin/monitor/cron/update_mirrors/reset", map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertNotExistsBean(t, &models.CronTask{Name: "update_mirrors"})
+}
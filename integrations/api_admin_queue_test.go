@@ -0,0 +1,100 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/queue"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminQueues(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	req := NewRequestf(t, "GET", "/api/v1/admin/queues?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	// user1 is an admin user
+	session = loginUser(t, "user1")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/admin/queues?limit=50&token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var queues []*api.Queue
+	DecodeJSON(t, resp, &queues)
+
+	var levelQueue, channelQueue *api.Queue
+	for _, q := range queues {
+		switch q.Name {
+		case "task-level":
+			levelQueue = q
+		case "task-channel":
+			channelQueue = q
+		}
+	}
+	if !assert.NotNil(t, levelQueue) || !assert.NotNil(t, channelQueue) {
+		return
+	}
+	assert.True(t, levelQueue.Inspectable)
+	assert.False(t, channelQueue.Inspectable)
+	assert.True(t, channelQueue.Pausable)
+
+	queueURL := fmt.Sprintf("/api/v1/admin/queues/%d", levelQueue.ID)
+	req = NewRequestf(t, "POST", "%s/pause?token=%s", queueURL, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	defer func() {
+		req = NewRequestf(t, "POST", "%s/resume?token=%s", queueURL, token)
+		session.MakeRequest(t, req, http.StatusNoContent)
+	}()
+
+	// the paused queue keeps the items in its fifo
+	mq := queue.GetManager().GetManagedQueue(levelQueue.ID)
+	assert.NoError(t, mq.Managed.(queue.Queue).Push(&models.Task{ID: 1001}))
+	assert.NoError(t, mq.Managed.(queue.Queue).Push(&models.Task{ID: 1002}))
+
+	req = NewRequestf(t, "GET", "%s?token=%s", queueURL, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var apiQueue api.Queue
+	DecodeJSON(t, resp, &apiQueue)
+	assert.True(t, apiQueue.Paused)
+	assert.EqualValues(t, 2, apiQueue.NumberInQueue)
+
+	req = NewRequestf(t, "GET", "%s/items?token=%s", queueURL, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var items []*api.QueueItem
+	DecodeJSON(t, resp, &items)
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, 1, items[1].Index)
+		assert.Contains(t, items[1].Data, `"ID":1002`)
+	}
+
+	req = NewRequestf(t, "DELETE", "%s/items/1?token=%s", queueURL, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestf(t, "DELETE", "%s/items/1?token=%s", queueURL, token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestf(t, "GET", "%s/items?token=%s", queueURL, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &items)
+	if assert.Len(t, items, 1) {
+		assert.Contains(t, items[0].Data, `"ID":1001`)
+	}
+
+	req = NewRequestf(t, "DELETE", "%s/items?token=%s", queueURL, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	assert.EqualValues(t, 0, mq.NumberInQueue())
+
+	req = NewRequestf(t, "GET", "/api/v1/admin/queues/%d/items?token=%s", channelQueue.ID, token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestf(t, "GET", "/api/v1/admin/queues/%d?token=%s", 100000, token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
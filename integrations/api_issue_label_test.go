@@ -109,6 +109,51 @@ func TestAPIAddIssueLabels(t *testing.T) {
 	models.AssertExistsAndLoadBean(t, &models.IssueLabel{IssueID: issue.ID, LabelID: label.ID})
 }
 
+func TestAPIAddExclusiveIssueLabels(t *testing.T) {
+	assert.NoError(t, models.LoadFixtures())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID}).(*models.Issue)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	labelIDs := make([]int64, 0, 2)
+	for _, name := range []string{"priority/high", "priority/low"} {
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/labels?token=%s", owner.Name, repo.Name, token), &api.CreateLabelOption{
+			Name:      name,
+			Color:     "abcdef",
+			Exclusive: true,
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		apiLabel := new(api.Label)
+		DecodeJSON(t, resp, &apiLabel)
+		assert.True(t, apiLabel.Exclusive)
+		labelIDs = append(labelIDs, apiLabel.ID)
+	}
+
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/labels?token=%s",
+		owner.Name, repo.Name, issue.Index, token)
+	for _, labelID := range labelIDs {
+		req := NewRequestWithJSON(t, "POST", urlStr, &api.IssueLabelsOption{
+			Labels: []int64{labelID},
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+	}
+	models.AssertNotExistsBean(t, &models.IssueLabel{IssueID: issue.ID, LabelID: labelIDs[0]})
+	models.AssertExistsAndLoadBean(t, &models.IssueLabel{IssueID: issue.ID, LabelID: labelIDs[1]})
+
+	// the labels of the same exclusive scope conflict when they are set together
+	for _, method := range []string{"POST", "PUT"} {
+		req := NewRequestWithJSON(t, method, urlStr, &api.IssueLabelsOption{
+			Labels: labelIDs,
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	}
+	models.AssertNotExistsBean(t, &models.IssueLabel{IssueID: issue.ID, LabelID: labelIDs[0]})
+	models.AssertExistsAndLoadBean(t, &models.IssueLabel{IssueID: issue.ID, LabelID: labelIDs[1]})
+}
+
 func TestAPIReplaceIssueLabels(t *testing.T) {
 	assert.NoError(t, models.LoadFixtures())
 
@@ -75,3 +75,33 @@ func TestAPIIssuesMilestone(t *testing.T) {
 	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/repos/%s/%s/milestones/%d?token=%s", owner.Name, repo.Name, apiMilestone.ID, token))
 	resp = session.MakeRequest(t, req, http.StatusNoContent)
 }
+
+func TestAPIMilestoneBurndownAndVelocity(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/milestones/%s/burndown?token=%s", owner.Name, repo.Name, "milestone1", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var snapshots []*structs.MilestoneSnapshot
+	DecodeJSON(t, resp, &snapshots)
+	if assert.Len(t, snapshots, 1) {
+		assert.Equal(t, 1, snapshots[0].OpenIssues)
+		assert.EqualValues(t, 3682, snapshots[0].TrackedTime)
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/milestones/%d/burndown?token=%s", owner.Name, repo.Name, 999, token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/velocity?token=%s", owner.Name, repo.Name, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var velocities []*structs.MilestoneVelocity
+	DecodeJSON(t, resp, &velocities)
+	if assert.Len(t, velocities, 1) {
+		assert.EqualValues(t, 3, velocities[0].Milestone.ID)
+		assert.Equal(t, structs.StateClosed, velocities[0].Milestone.State)
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"code.gitea.io/gitea/models"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -247,3 +248,166 @@ func TestAPISearchIssuesWithLabels(t *testing.T) {
 	DecodeJSON(t, resp, &apiIssues)
 	assert.Len(t, apiIssues, 2)
 }
+
+func TestAPISearchIssuesWithQuery(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	link, _ := url.Parse("/api/v1/repos/issues/search")
+	query := url.Values{}
+	query.Add("token", token)
+
+	// org and repo label which share the same issue
+	query.Set("q", "label:label1 label:orglabel4")
+	link.RawQuery = query.Encode()
+	req := NewRequest(t, "GET", link.String())
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiIssues []*api.Issue
+	DecodeJSON(t, resp, &apiIssues)
+	if assert.Len(t, apiIssues, 1) {
+		assert.EqualValues(t, 2, apiIssues[0].ID)
+	}
+
+	// the state of the query applies without a state parameter
+	query.Set("q", "is:closed author:user2 -label:label2")
+	link.RawQuery = query.Encode()
+	req = NewRequest(t, "GET", link.String())
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiIssues)
+	if assert.Len(t, apiIssues, 1) {
+		assert.EqualValues(t, 4, apiIssues[0].ID)
+	}
+
+	query.Set("q", "assignee:@me is:issue")
+	link.RawQuery = query.Encode()
+	req = NewRequest(t, "GET", link.String())
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiIssues)
+	assert.Len(t, apiIssues, 2)
+	for _, issue := range apiIssues {
+		if assert.NotNil(t, issue.Assignee) {
+			assert.NotEmpty(t, issue.Assignees)
+		}
+	}
+
+	// repository issues
+	link, _ = url.Parse("/api/v1/repos/user2/repo1/issues")
+	query.Set("q", "milestone:milestone1")
+	link.RawQuery = query.Encode()
+	req = NewRequest(t, "GET", link.String())
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiIssues)
+	if assert.Len(t, apiIssues, 1) {
+		assert.EqualValues(t, 2, apiIssues[0].ID)
+	}
+}
+
+func TestAPIIssueParent(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo1.OwnerID}).(*models.User)
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	// issue #4 becomes a sub-issue of issue #1
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/4/parent?token=%s", owner.Name, repo1.Name, token)
+	req := NewRequestWithJSON(t, "PUT", urlStr, &api.IssueParentOption{Index: 1})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	assert.EqualValues(t, 1, apiIssue.Index)
+	models.AssertExistsAndLoadBean(t, &models.Issue{ID: 5, ParentID: 1})
+
+	req = NewRequest(t, "GET", urlStr)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiIssue)
+	assert.EqualValues(t, 1, apiIssue.Index)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/issues/1/children?token=%s", owner.Name, repo1.Name, token))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var apiIssues []*api.Issue
+	DecodeJSON(t, resp, &apiIssues)
+	if assert.Len(t, apiIssues, 1) {
+		assert.EqualValues(t, 4, apiIssues[0].Index)
+	}
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/issues?state=all&parent=1&token=%s", owner.Name, repo1.Name, token))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiIssues)
+	if assert.Len(t, apiIssues, 1) {
+		assert.EqualValues(t, 4, apiIssues[0].Index)
+	}
+
+	// issue #1 can't be a sub-issue of its sub-issue
+	req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/repos/%s/%s/issues/1/parent?token=%s", owner.Name, repo1.Name, token),
+		&api.IssueParentOption{Index: 4})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "DELETE", urlStr)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 5}).(*models.Issue)
+	assert.EqualValues(t, 0, issue.ParentID)
+
+	req = NewRequest(t, "GET", urlStr)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAPIIssuePinAndTransfer(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo1.OwnerID}).(*models.User)
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/1/pin?token=%s", owner.Name, repo1.Name, token)
+	req := NewRequest(t, "POST", urlStr)
+	session.MakeRequest(t, req, http.StatusNoContent)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/issues/pinned?token=%s", owner.Name, repo1.Name, token))
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiIssues []*api.Issue
+	DecodeJSON(t, resp, &apiIssues)
+	if assert.Len(t, apiIssues, 1) {
+		assert.EqualValues(t, 1, apiIssues[0].Index)
+		assert.EqualValues(t, 1, apiIssues[0].PinOrder)
+	}
+
+	req = NewRequest(t, "DELETE", urlStr)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	assert.EqualValues(t, 0, issue.PinOrder)
+
+	// transfer issue #1 to repo2
+	urlStr = fmt.Sprintf("/api/v1/repos/%s/%s/issues/1/transfer?token=%s", owner.Name, repo1.Name, token)
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.TransferIssueOption{Owner: owner.Name, Repo: "not-exist"})
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.TransferIssueOption{Owner: owner.Name, Repo: "repo2"})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	assert.EqualValues(t, 3, apiIssue.Index)
+	assert.EqualValues(t, "repo2", apiIssue.Repo.Name)
+
+	// the old issue URL redirects to the transferred issue
+	req = NewRequest(t, "GET", fmt.Sprintf("/%s/%s/issues/1", owner.Name, repo1.Name))
+	resp = session.MakeRequest(t, req, http.StatusMovedPermanently)
+	assert.EqualValues(t, fmt.Sprintf("/%s/repo2/issues/3", owner.Name), test.RedirectURL(resp))
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/issues/1?token=%s", owner.Name, repo1.Name, token))
+	resp = session.MakeRequest(t, req, http.StatusMovedPermanently)
+	assert.EqualValues(t, fmt.Sprintf("/api/v1/repos/%s/repo2/issues/3?token=%s", owner.Name, token), test.RedirectURL(resp))
+
+	// the private repository the issue has been transferred to isn't disclosed to the users who can't read it
+	session = loginUser(t, "user4")
+	req = NewRequest(t, "GET", fmt.Sprintf("/%s/%s/issues/1", owner.Name, repo1.Name))
+	session.MakeRequest(t, req, http.StatusNotFound)
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/issues/1?token=%s", owner.Name, repo1.Name, token))
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
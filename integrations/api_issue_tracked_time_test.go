@@ -119,3 +119,21 @@ func TestAPIAddTrackedTimes(t *testing.T) {
 	assert.EqualValues(t, user2.ID, apiNewTime.UserID)
 	assert.EqualValues(t, 947688818, apiNewTime.Created.Unix())
 }
+
+func TestAPIEditIssueEstimatedTime(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	session := loginUser(t, user2.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	estimatedTime := int64(5400)
+	req := NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/repos/%s/repo1/issues/1?token=%s", user2.Name, token), &api.EditIssueOption{
+		EstimatedTime: &estimatedTime,
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	assert.EqualValues(t, 5400, apiIssue.EstimatedTime)
+	models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1, EstimatedTime: 5400})
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPISearchRepoCode(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo, err := models.GetRepositoryByOwnerAndName("user2", "repo1")
+	assert.NoError(t, err)
+	executeIndexer(t, repo, code_indexer.UpdateRepoIndexer)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/search/code?q=Description")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var results api.CodeSearchResults
+	DecodeJSON(t, resp, &results)
+	assert.EqualValues(t, 1, results.TotalCount)
+	if assert.Len(t, results.Data, 1) {
+		assert.EqualValues(t, repo.ID, results.Data[0].RepoID)
+		assert.EqualValues(t, "user2/repo1", results.Data[0].RepoFullName)
+		assert.EqualValues(t, "README.md", results.Data[0].Filename)
+		assert.Contains(t, results.Data[0].Content, "Description for repo1")
+		assert.Contains(t, results.Data[0].LineNumbers, 3)
+		assert.NotEmpty(t, results.Data[0].CommitID)
+	}
+	assert.Equal(t, "1", resp.Header().Get("X-Total-Count"))
+
+	req = NewRequest(t, "GET", "/api/v1/repos/search/code?q=Description")
+	resp = MakeRequest(t, req, http.StatusOK)
+	results = api.CodeSearchResults{}
+	DecodeJSON(t, resp, &results)
+	var filenames []string
+	for _, result := range results.Data {
+		if result.RepoID == repo.ID {
+			filenames = append(filenames, result.Filename)
+		}
+	}
+	assert.EqualValues(t, []string{"README.md"}, filenames)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/search/code")
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// ref not configured for the code indexer
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/search/code?q=Description&ref=branch2")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	// private repository
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo2/search/code?q=Description")
+	MakeRequest(t, req, http.StatusNotFound)
+}
@@ -13,8 +13,10 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/test"
 
@@ -131,3 +133,88 @@ func TestGetAttachment(t *testing.T) {
 		})
 	}
 }
+
+func TestCompleteDirectAttachmentUpload(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer func(uploadDirect bool) {
+		setting.Attachment.UploadDirect = uploadDirect
+	}(setting.Attachment.UploadDirect)
+
+	const repoURL = "/user2/repo1"
+	session := loginUser(t, "user2")
+	csrf := GetCSRF(t, session, repoURL)
+
+	complete := func(uuid, name string, expectedStatus int) {
+		req := NewRequestWithValues(t, "POST", repoURL+"/issues/attachments/complete", map[string]string{
+			"_csrf": csrf,
+			"file":  uuid,
+			"name":  name,
+		})
+		session.MakeRequest(t, req, expectedStatus)
+	}
+
+	const uuid = "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	img := generateImg()
+	upload := func(uuid string, content []byte, uploaderID int64) {
+		_, err := storage.Attachments.Save(models.AttachmentUploadRelativePath(uuid), bytes.NewReader(content))
+		assert.NoError(t, err)
+		assert.NoError(t, models.NewPendingUpload(models.PendingUploadAttachments, models.AttachmentUploadRelativePath(uuid), uploaderID, time.Minute))
+	}
+	exists := func(p string) bool {
+		_, err := storage.Attachments.Stat(p)
+		return err == nil
+	}
+	upload(uuid, img.Bytes(), 2)
+
+	setting.Attachment.UploadDirect = false
+	complete(uuid, "image.png", http.StatusNotFound)
+
+	setting.Attachment.UploadDirect = true
+	complete("invalid", "image.png", http.StatusBadRequest)
+	complete("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", "image.png", http.StatusBadRequest)
+
+	// only the user allowed to upload the content can complete the upload
+	const otherUUID = "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14"
+	upload(otherUUID, img.Bytes(), 1)
+	complete(otherUUID, "image.png", http.StatusBadRequest)
+
+	complete(uuid, "image.png", http.StatusOK)
+	// the uploaded content is copied to the attachment
+	assert.False(t, exists(models.AttachmentUploadRelativePath(uuid)))
+	assert.True(t, exists(models.AttachmentRelativePath(uuid)))
+
+	attach, err := models.GetAttachmentByUUID(uuid)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, attach.UploaderID)
+	assert.EqualValues(t, img.Len(), attach.Size)
+	assert.Equal(t, "image.png", attach.Name)
+
+	// an attachment can't be created twice
+	complete(uuid, "image.png", http.StatusBadRequest)
+
+	// the content of the types which aren't allowed is deleted
+	const exeUUID = "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a13"
+	upload(exeUUID, []byte("MZ"), 2)
+	complete(exeUUID, "virus.exe", http.StatusBadRequest)
+	assert.False(t, exists(models.AttachmentUploadRelativePath(exeUUID)))
+	assert.False(t, exists(models.AttachmentRelativePath(exeUUID)))
+
+	// the content bigger than the maximum size is deleted
+	defer func(maxSize int64) {
+		setting.Attachment.MaxSize = maxSize
+	}(setting.Attachment.MaxSize)
+	setting.Attachment.MaxSize = 1
+	const bigUUID = "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a15"
+	upload(bigUUID, append(img.Bytes(), make([]byte, 1<<20)...), 2)
+	complete(bigUUID, "image.png", http.StatusBadRequest)
+	assert.False(t, exists(models.AttachmentUploadRelativePath(bigUUID)))
+	assert.False(t, exists(models.AttachmentRelativePath(bigUUID)))
+
+	// the local storage doesn't support the direct uploads
+	req := NewRequestWithValues(t, "POST", repoURL+"/issues/attachments/presign", map[string]string{
+		"_csrf": csrf,
+		"name":  "image.png",
+		"size":  "100",
+	})
+	session.MakeRequest(t, req, http.StatusInternalServerError)
+}
@@ -340,3 +340,124 @@ func TestIssueRedirect(t *testing.T) {
 	resp = session.MakeRequest(t, req, http.StatusFound)
 	assert.Equal(t, "/"+path.Join("org26", "repo_external_tracker_alpha", "pulls", "1"), test.RedirectURL(resp))
 }
+
+func TestSaveIssueFilter(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/user2/repo1/issues?q=label:label1")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+
+	req = NewRequestWithValues(t, "POST", "/issues/filters", map[string]string{
+		"_csrf":     htmlDoc.GetCSRF(),
+		"name":      "Labelled",
+		"query":     "label:label1",
+		"repos":     "[1]",
+		"is_pinned": "on",
+	})
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	filter := models.AssertExistsAndLoadBean(t, &models.IssueFilter{OwnerID: 2, Name: "Labelled"}).(*models.IssueFilter)
+	assert.Equal(t, []int64{1}, filter.RepoIDs)
+	assert.True(t, filter.IsPinned)
+
+	req = NewRequest(t, "GET", test.RedirectURL(resp))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.Contains(t, htmlDoc.doc.Find(".filter.menu").Text(), "Labelled")
+
+	req = NewRequest(t, "GET", "/issues/filters")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.Contains(t, htmlDoc.doc.Find(".milestone.list").Text(), "label:label1")
+
+	// user4 is neither the owner nor a member of an owning organization
+	session4 := loginUser(t, "user4")
+	req = NewRequestWithValues(t, "POST", fmt.Sprintf("/issues/filters/%d/action/delete", filter.ID), map[string]string{
+		"_csrf": GetCSRF(t, session4, "/user/settings"),
+	})
+	session4.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestWithValues(t, "POST", fmt.Sprintf("/issues/filters/%d/action/delete", filter.ID), map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertNotExistsBean(t, &models.IssueFilter{ID: filter.ID})
+}
+
+func TestIssueSubIssues(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/1/children/add", map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+		"child": "#4",
+	})
+	session.MakeRequest(t, req, http.StatusSeeOther)
+	models.AssertExistsAndLoadBean(t, &models.Issue{ID: 5, ParentID: 1})
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.Contains(t, htmlDoc.doc.Find(".issue-hierarchy .sub-issue").Text(), "issue5")
+	assert.EqualValues(t, "100", htmlDoc.doc.Find(".issue-hierarchy .progress").AttrOr("data-percent", ""))
+
+	// an issue can't be a sub-issue of its sub-issue
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/1/parent", map[string]string{
+		"_csrf":  htmlDoc.GetCSRF(),
+		"parent": "user2/repo1#4",
+	})
+	session.MakeRequest(t, req, http.StatusSeeOther)
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	assert.EqualValues(t, 0, issue.ParentID)
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/4/parent", map[string]string{
+		"_csrf":  htmlDoc.GetCSRF(),
+		"parent": "",
+	})
+	session.MakeRequest(t, req, http.StatusSeeOther)
+	issue = models.AssertExistsAndLoadBean(t, &models.Issue{ID: 5}).(*models.Issue)
+	assert.EqualValues(t, 0, issue.ParentID)
+}
+
+func TestIssuePinAndTransfer(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/1/pin", map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+	})
+	session.MakeRequest(t, req, http.StatusSeeOther)
+	models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1, PinOrder: 1})
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(".pinned-issues .card").Length())
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/1/transfer", map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+		"repo":  "user2/utf8",
+	})
+	session.MakeRequest(t, req, http.StatusSeeOther)
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	assert.EqualValues(t, 33, issue.RepoID)
+	assert.EqualValues(t, 0, issue.PinOrder)
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp = session.MakeRequest(t, req, http.StatusMovedPermanently)
+	assert.EqualValues(t, "/user2/utf8/issues/1", test.RedirectURL(resp))
+
+	// the transfer comment is shown in the new repository
+	req = NewRequest(t, "GET", "/user2/utf8/issues/1")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), "user2/repo1#1")
+}
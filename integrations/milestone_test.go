@@ -0,0 +1,30 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMilestoneBurndownAndVelocity(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/user2/repo1/milestone/1")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	htmlDoc.AssertElement(t, ".milestone-burndown polyline.actual", true)
+
+	req = NewRequest(t, "GET", "/user2/repo1/milestones/velocity")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	rows := htmlDoc.doc.Find(".velocity table tbody tr")
+	assert.EqualValues(t, 1, rows.Length())
+	href, _ := rows.Find("a").Attr("href")
+	assert.EqualValues(t, "/user2/repo1/milestone/3", href)
+}
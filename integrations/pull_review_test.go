@@ -6,6 +6,10 @@ package integrations
 import (
 	"net/http"
 	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestPullView_ReviewerMissed(t *testing.T) {
@@ -18,3 +22,17 @@ func TestPullView_ReviewerMissed(t *testing.T) {
 	req = NewRequest(t, "GET", "/user2/repo1/pulls/3")
 	session.MakeRequest(t, req, http.StatusOK)
 }
+
+func TestPullReviewStateDeletedWithRepo(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	assert.NoError(t, models.UpdateReviewState(1, 2, map[string]string{"README.md": "1234"}))
+	models.AssertExistsAndLoadBean(t, &models.ReviewState{UserID: 1, PullID: 2})
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	req := NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1?token="+token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+
+	models.AssertNotExistsBean(t, &models.ReviewState{PullID: 2})
+}
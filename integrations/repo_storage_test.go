@@ -0,0 +1,83 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unknwon/com"
+)
+
+func TestRepoStorageIDLayout(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		defer func(layout string) {
+			setting.RepoStorage.Layout = layout
+		}(setting.RepoStorage.Layout)
+		setting.RepoStorage.Layout = setting.RepoStorageLayoutID
+
+		ctx := NewAPITestContext(t, "user2", "repo-id-layout")
+		t.Run("CreateRepo", doAPICreateRepository(ctx, false))
+
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{OwnerID: 2, LowerName: "repo-id-layout"}).(*models.Repository)
+		assert.Equal(t, setting.RepoStorageLayoutID, repo.StorageLayout)
+		assert.True(t, com.IsDir(repo.RepoPath()))
+		assert.False(t, com.IsExist(filepath.Join(setting.RepoRootPath, "user2", "repo-id-layout.git")))
+
+		t.Run("HTTP", func(t *testing.T) {
+			dstPath, err := ioutil.TempDir("", "repo-id-layout")
+			assert.NoError(t, err)
+			defer util.RemoveAll(dstPath)
+
+			httpURL := *u
+			httpURL.Path = ctx.GitPath()
+			httpURL.User = url.UserPassword("user2", userPassword)
+			t.Run("Clone", doGitClone(dstPath, &httpURL))
+			doCommitAndPush(t, 128, dstPath, "data-file-")
+		})
+
+		t.Run("SSH", func(t *testing.T) {
+			withKeyFile(t, "repo-storage-key", func(keyFile string) {
+				t.Run("CreateUserKey", doAPICreateUserKey(ctx, "repo-storage-key", keyFile))
+
+				dstPath, err := ioutil.TempDir("", "repo-id-layout")
+				assert.NoError(t, err)
+				defer util.RemoveAll(dstPath)
+
+				t.Run("Clone", doGitClone(dstPath, createSSHUrl(ctx.GitPath(), u)))
+				doCommitAndPush(t, 128, dstPath, "data-file-")
+			})
+		})
+
+		session := loginUser(t, "user2")
+		req := NewRequest(t, "GET", "/user2/repo-id-layout")
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "data-file-")
+
+		// the repositories in the id layout stay where they are when they are renamed
+		repoPath := repo.RepoPath()
+		req = NewRequestWithValues(t, "POST", "/user2/repo-id-layout/settings", map[string]string{
+			"_csrf":     GetCSRF(t, session, "/user2/repo-id-layout/settings"),
+			"action":    "update",
+			"repo_name": "repo-id-layout-renamed",
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+		repo = models.AssertExistsAndLoadBean(t, &models.Repository{ID: repo.ID}).(*models.Repository)
+		assert.Equal(t, "repo-id-layout-renamed", repo.Name)
+		assert.Equal(t, repoPath, repo.RepoPath())
+		assert.True(t, com.IsDir(repoPath))
+
+		req = NewRequest(t, "GET", "/user2/repo-id-layout-renamed")
+		session.MakeRequest(t, req, http.StatusOK)
+	})
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/modules/log"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// A new request ID is generated for each request
+	resp := MakeRequest(t, NewRequest(t, "GET", "/api/v1/version"), http.StatusOK)
+	requestID := resp.Header().Get(log.RequestIDHeader)
+	assert.True(t, log.IsValidRequestID(requestID))
+
+	resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/version"), http.StatusOK)
+	assert.NotEqual(t, requestID, resp.Header().Get(log.RequestIDHeader))
+
+	// A valid request ID provided by the client is kept
+	req := NewRequest(t, "GET", "/user/login")
+	req.Header.Set(log.RequestIDHeader, "proxy-request-1")
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "proxy-request-1", resp.Header().Get(log.RequestIDHeader))
+
+	// But an invalid one is replaced
+	req = NewRequest(t, "GET", "/user/login")
+	req.Header.Set(log.RequestIDHeader, "not a valid request id")
+	resp = MakeRequest(t, req, http.StatusOK)
+	requestID = resp.Header().Get(log.RequestIDHeader)
+	assert.NotEqual(t, "not a valid request id", requestID)
+	assert.True(t, log.IsValidRequestID(requestID))
+}
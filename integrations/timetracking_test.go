@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/test"
 
 	"github.com/stretchr/testify/assert"
@@ -78,3 +79,60 @@ func testViewTimetrackingControls(t *testing.T, session *TestSession, user, repo
 		session.MakeRequest(t, req, http.StatusNotFound)
 	}
 }
+
+func TestTimeReport(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/user2/repo1/pulls/2")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	htmlDoc.AssertElement(t, ".time-estimate form", true)
+
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/2/times/estimate", map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+		"hours": "2",
+	})
+	session.MakeRequest(t, req, http.StatusSeeOther)
+	models.AssertExistsAndLoadBean(t, &models.Issue{ID: 2, EstimatedTime: 7200})
+
+	req = NewRequest(t, "GET", "/user2/repo1/times")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 4, htmlDoc.doc.Find(".time-report-entries tbody tr").Length())
+
+	var report struct {
+		TotalSeconds          int64 `json:"total_seconds"`
+		TotalEstimatedSeconds int64 `json:"total_estimated_seconds"`
+		Entries               []struct {
+			IssueIndex int64  `json:"issue_index"`
+			User       string `json:"user"`
+		} `json:"entries"`
+	}
+	req = NewRequest(t, "GET", "/user2/repo1/times?format=json")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &report)
+	assert.EqualValues(t, 4083, report.TotalSeconds)
+	assert.EqualValues(t, 7200, report.TotalEstimatedSeconds)
+	assert.Len(t, report.Entries, 4)
+
+	req = NewRequest(t, "GET", "/user2/repo1/times?format=csv&user=user1")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.EqualValues(t, "repository,issue,title,milestone,user,seconds,hours,estimated_seconds\n"+
+		"user2/repo1,1,issue1,,user1,400,0.11,0\n"+
+		"user2/repo1,2,issue2,milestone1,user1,20,0.01,7200\n", resp.Body.String())
+
+	// the users who are not administrators of the repository only see their own tracked times
+	session = loginUser(t, "user5")
+	req = NewRequest(t, "GET", "/user2/repo1/times?format=json&user=user1")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &report)
+	assert.EqualValues(t, 0, report.TotalSeconds)
+
+	// only the members of an organization can see its report
+	req = NewRequest(t, "GET", "/org/user3/times")
+	session.MakeRequest(t, req, http.StatusNotFound)
+	session = loginUser(t, "user2")
+	req = NewRequest(t, "GET", "/org/user3/times")
+	session.MakeRequest(t, req, http.StatusOK)
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSettingsEmailNotificationEvents(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	values := url.Values{
+		"_csrf":   {GetCSRF(t, session, "/user/settings/account")},
+		"_method": {"NOTIFICATION_EVENTS"},
+		"digest":  {models.EmailDigestDaily},
+		"events": {
+			string(models.EmailEventIssueActivity),
+			string(models.EmailEventOwnPullRequests),
+			string(models.EmailEventMention),
+			string(models.EmailEventAssigned),
+			string(models.EmailEventReviewRequested),
+		},
+	}
+	req := NewRequestWithBody(t, "POST", "/user/settings/account/email", bytes.NewBufferString(values.Encode()))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	session.MakeRequest(t, req, http.StatusFound)
+
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	assert.Equal(t, models.EmailDigestDaily, user.EmailNotificationsDigest)
+	optOuts, err := models.GetEmailNotificationOptOuts(2)
+	assert.NoError(t, err)
+	assert.Equal(t, map[models.EmailNotificationEvent]bool{models.EmailEventCIFailure: true, models.EmailEventRelease: true}, optOuts)
+
+	// the unchecked events are rendered as such
+	req = NewRequest(t, "GET", "/user/settings/account")
+	htmlDoc := NewHTMLParser(t, session.MakeRequest(t, req, http.StatusOK).Body)
+	assert.True(t, htmlDoc.doc.Find(`input[name="events"][value="mention"]`).Is("[checked]"))
+	assert.False(t, htmlDoc.doc.Find(`input[name="events"][value="release"]`).Is("[checked]"))
+	assert.Equal(t, models.EmailDigestDaily, htmlDoc.GetInputValueByName("digest"))
+
+	values.Set("digest", "weekly")
+	req = NewRequestWithBody(t, "POST", "/user/settings/account/email", bytes.NewBufferString(values.Encode()))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	session.MakeRequest(t, req, http.StatusInternalServerError)
+}
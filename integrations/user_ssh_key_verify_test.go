@@ -0,0 +1,78 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/pem"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+var tokenToSignRegexp = regexp.MustCompile(`echo -n '([0-9a-f]+)' \| ssh-keygen`)
+
+// signSSH creates an armored signature over message within namespace, as `ssh-keygen -Y sign` does
+func signSSH(t *testing.T, signer ssh.Signer, namespace string, message []byte) string {
+	hash := sha512.Sum512(message)
+	signed := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{namespace, "", "sha512", hash[:]})...)
+	signature, err := signer.Sign(rand.Reader, signed)
+	assert.NoError(t, err)
+
+	blob := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{1, signer.PublicKey().Marshal(), namespace, "", "sha512", ssh.Marshal(signature)})...)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "SSH SIGNATURE", Bytes: blob}))
+}
+
+func TestVerifySSHKeyWithDisplayedToken(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	assert.NoError(t, err)
+	key, err := models.AddPublicKey(2, "signing key", string(ssh.MarshalAuthorizedKey(signer.PublicKey())), 0)
+	assert.NoError(t, err)
+	assert.False(t, key.Verified)
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/user/settings/keys")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	matches := tokenToSignRegexp.FindStringSubmatch(htmlDoc.doc.Find("pre code").Text())
+	if !assert.Len(t, matches, 2) {
+		return
+	}
+
+	// The signature of the displayed token is accepted right away
+	req = NewRequestWithValues(t, "POST", "/user/settings/keys", map[string]string{
+		"_csrf":       htmlDoc.GetCSRF(),
+		"title":       key.Name,
+		"fingerprint": key.Fingerprint,
+		"type":        "verify_ssh",
+		"content":     signSSH(t, signer, "gitea", []byte(matches[1])),
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	key = models.AssertExistsAndLoadBean(t, &models.PublicKey{ID: key.ID}).(*models.PublicKey)
+	assert.True(t, key.Verified)
+}
@@ -71,6 +71,7 @@ arguments - which can alternatively be run by running the subcommand web.`
 		cmd.CmdManager,
 		cmd.Cmdembedded,
 		cmd.CmdMigrateStorage,
+		cmd.CmdRepoStorage,
 		cmd.CmdDocs,
 	}
 	// Now adjust these commands to add our global configuration options
@@ -47,6 +47,12 @@ func AttachmentRelativePath(uuid string) string {
 	return path.Join(uuid[0:1], uuid[1:2], uuid)
 }
 
+// AttachmentUploadRelativePath returns the relative path the content of an attachment is uploaded to
+// when it goes to the storage directly, the content is only copied to the attachment once it is checked
+func AttachmentUploadRelativePath(uuid string) string {
+	return path.Join("tmp", AttachmentRelativePath(uuid))
+}
+
 // RelativePath returns the relative path of the attachment
 func (a *Attachment) RelativePath() string {
 	return AttachmentRelativePath(a.UUID)
@@ -98,6 +104,14 @@ func NewAttachment(attach *Attachment, buf []byte, file io.Reader) (_ *Attachmen
 	return attach, nil
 }
 
+// NewUploadedAttachment creates a new attachment whose content was uploaded to the storage directly
+func NewUploadedAttachment(attach *Attachment) (*Attachment, error) {
+	if _, err := x.Insert(attach); err != nil {
+		return nil, err
+	}
+	return attach, nil
+}
+
 // GetAttachmentByID returns attachment by given id
 func GetAttachmentByID(id int64) (*Attachment, error) {
 	return getAttachmentByID(x, id)
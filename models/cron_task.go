@@ -0,0 +1,167 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// CronTask holds the settings of a cron task which have been changed at runtime by an admin.
+// They take precedence over the settings of app.ini until the row is deleted.
+type CronTask struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Name        string             `xorm:"VARCHAR(255) UNIQUE NOT NULL"`
+	Enabled     bool               `xorm:"NOT NULL DEFAULT false"`
+	Schedule    string             `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// GetCronTasks returns the settings of all the cron tasks changed at runtime
+func GetCronTasks() ([]*CronTask, error) {
+	tasks := make([]*CronTask, 0, 5)
+	return tasks, x.Asc("name").Find(&tasks)
+}
+
+// SaveCronTask inserts or updates the runtime settings of the cron task
+func SaveCronTask(task *CronTask) error {
+	existing := new(CronTask)
+	has, err := x.Where("name = ?", task.Name).Get(existing)
+	if err != nil {
+		return err
+	} else if !has {
+		_, err = x.Insert(task)
+		return err
+	}
+	task.ID = existing.ID
+	_, err = x.ID(task.ID).Cols("enabled", "schedule").Update(task)
+	return err
+}
+
+// DeleteCronTask deletes the runtime settings of the cron task so that app.ini applies again
+func DeleteCronTask(name string) error {
+	_, err := x.Where("name = ?", name).Delete(new(CronTask))
+	return err
+}
+
+// CronTaskRunStatus represents the status of a run of a cron task
+type CronTaskRunStatus int
+
+// CronTaskRunStatuses
+const (
+	CronTaskRunRunning CronTaskRunStatus = iota
+	CronTaskRunSucceeded
+	CronTaskRunFailed
+	CronTaskRunCancelled
+)
+
+var cronTaskRunStatusNames = map[CronTaskRunStatus]string{
+	CronTaskRunRunning:   "running",
+	CronTaskRunSucceeded: "succeeded",
+	CronTaskRunFailed:    "failed",
+	CronTaskRunCancelled: "cancelled",
+}
+
+func (s CronTaskRunStatus) String() string {
+	return cronTaskRunStatusNames[s]
+}
+
+// CronTaskRun represents a run of a cron task
+type CronTaskRun struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Name        string             `xorm:"VARCHAR(255) INDEX NOT NULL"`
+	DoerID      int64              `xorm:"NOT NULL DEFAULT 0"` // -1 if run by the scheduler
+	DoerName    string             `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+	Status      CronTaskRunStatus  `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Message     string             `xorm:"TEXT"`
+	Instance    string             `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"` // the Gitea instance which ran it
+	StartedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	EndedUnix   timeutil.TimeStamp
+}
+
+// Duration returns how long the run took, or has been running for
+func (r *CronTaskRun) Duration() time.Duration {
+	if r.Status == CronTaskRunRunning {
+		return time.Since(r.StartedUnix.AsTime()).Truncate(time.Second)
+	}
+	return r.EndedUnix.AsTime().Sub(r.StartedUnix.AsTime())
+}
+
+// CreateCronTaskRun records the start of a run of a cron task. As runs of a task never overlap,
+// the runs of the same task which are still marked as running were interrupted and are marked as failed.
+func CreateCronTaskRun(run *CronTaskRun) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	run.Status = CronTaskRunRunning
+	run.StartedUnix = timeutil.TimeStampNow()
+	if _, err := sess.Where("name = ? AND status = ?", run.Name, CronTaskRunRunning).
+		Cols("status", "message", "ended_unix").
+		Update(&CronTaskRun{
+			Status:    CronTaskRunFailed,
+			Message:   "interrupted",
+			EndedUnix: run.StartedUnix,
+		}); err != nil {
+		return err
+	}
+	if _, err := sess.Insert(run); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// FinishCronTaskRun records the end of a run of a cron task and deletes the oldest runs of the task,
+// keeping the last keep runs
+func FinishCronTaskRun(run *CronTaskRun, status CronTaskRunStatus, message string, keep int) error {
+	run.Status = status
+	run.Message = message
+	run.EndedUnix = timeutil.TimeStampNow()
+	if _, err := x.ID(run.ID).Cols("status", "message", "ended_unix").Update(run); err != nil {
+		return err
+	}
+
+	if keep <= 0 {
+		return nil
+	}
+	oldest := new(CronTaskRun)
+	has, err := x.Where("name = ?", run.Name).Desc("id").Limit(1, keep-1).Cols("id").Get(oldest)
+	if err != nil || !has {
+		return err
+	}
+	_, err = x.Where("name = ? AND id < ?", run.Name, oldest.ID).Delete(new(CronTaskRun))
+	return err
+}
+
+// GetCronTaskRuns returns the runs of the cron task, most recent first
+func GetCronTaskRuns(name string, opts ListOptions) ([]*CronTaskRun, int64, error) {
+	count, err := x.Where("name = ?", name).Count(new(CronTaskRun))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	runs := make([]*CronTaskRun, 0, opts.PageSize)
+	sess := x.Where("name = ?", name).Desc("id")
+	if opts.Page > 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	return runs, count, sess.Find(&runs)
+}
+
+// GetLastCronTaskRuns returns the last run of each cron task by task name
+func GetLastCronTaskRuns() (map[string]*CronTaskRun, error) {
+	runs := make([]*CronTaskRun, 0, 20)
+	if err := x.Where("id IN (SELECT MAX(id) FROM cron_task_run GROUP BY name)").Find(&runs); err != nil {
+		return nil, err
+	}
+	last := make(map[string]*CronTaskRun, len(runs))
+	for _, run := range runs {
+		last[run.Name] = run
+	}
+	return last, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCronTask(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, SaveCronTask(&CronTask{Name: "update_mirrors", Enabled: false, Schedule: "@every 1h"}))
+	assert.NoError(t, SaveCronTask(&CronTask{Name: "update_mirrors", Enabled: true, Schedule: "@every 2h"}))
+	assert.NoError(t, SaveCronTask(&CronTask{Name: "archive_cleanup", Enabled: false, Schedule: "@every 24h"}))
+
+	tasks, err := GetCronTasks()
+	assert.NoError(t, err)
+	if assert.Len(t, tasks, 2) {
+		assert.Equal(t, "archive_cleanup", tasks[0].Name)
+		assert.Equal(t, "update_mirrors", tasks[1].Name)
+		assert.True(t, tasks[1].Enabled)
+		assert.Equal(t, "@every 2h", tasks[1].Schedule)
+	}
+
+	assert.NoError(t, DeleteCronTask("update_mirrors"))
+	AssertNotExistsBean(t, &CronTask{Name: "update_mirrors"})
+}
+
+func TestCronTaskRuns(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	first := &CronTaskRun{Name: "update_mirrors", DoerID: -1, DoerName: "(Cron)", Instance: "test"}
+	assert.NoError(t, CreateCronTaskRun(first))
+	assert.Equal(t, CronTaskRunRunning, first.Status)
+
+	// a new run marks the runs left running as interrupted
+	second := &CronTaskRun{Name: "update_mirrors", DoerID: 1, DoerName: "user1", Instance: "test"}
+	assert.NoError(t, CreateCronTaskRun(second))
+	first = AssertExistsAndLoadBean(t, &CronTaskRun{ID: first.ID}).(*CronTaskRun)
+	assert.Equal(t, CronTaskRunFailed, first.Status)
+	assert.Equal(t, "interrupted", first.Message)
+
+	assert.NoError(t, FinishCronTaskRun(second, CronTaskRunSucceeded, "", 5))
+	second = AssertExistsAndLoadBean(t, &CronTaskRun{ID: second.ID}).(*CronTaskRun)
+	assert.Equal(t, CronTaskRunSucceeded, second.Status)
+	assert.True(t, second.EndedUnix >= second.StartedUnix)
+
+	other := &CronTaskRun{Name: "archive_cleanup", DoerID: -1, DoerName: "(Cron)", Instance: "test"}
+	assert.NoError(t, CreateCronTaskRun(other))
+	assert.NoError(t, FinishCronTaskRun(other, CronTaskRunFailed, "failure", 5))
+
+	last, err := GetLastCronTaskRuns()
+	assert.NoError(t, err)
+	assert.Len(t, last, 2)
+	assert.Equal(t, second.ID, last["update_mirrors"].ID)
+	assert.Equal(t, "failure", last["archive_cleanup"].Message)
+
+	runs, count, err := GetCronTaskRuns("update_mirrors", ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, runs, 2) {
+		assert.Equal(t, second.ID, runs[0].ID)
+		assert.Equal(t, first.ID, runs[1].ID)
+	}
+
+	// only the last runs are kept
+	run := &CronTaskRun{Name: "update_mirrors", DoerID: -1, DoerName: "(Cron)", Instance: "test"}
+	assert.NoError(t, CreateCronTaskRun(run))
+	assert.NoError(t, FinishCronTaskRun(run, CronTaskRunCancelled, "cancelled", 2))
+	runs, count, err = GetCronTaskRuns("update_mirrors", ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, runs, 2) {
+		assert.Equal(t, run.ID, runs[0].ID)
+		assert.Equal(t, second.ID, runs[1].ID)
+	}
+	AssertExistsAndLoadBean(t, &CronTaskRun{ID: other.ID})
+}
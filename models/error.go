@@ -405,6 +405,22 @@ func (err ErrKeyNameAlreadyUsed) Error() string {
 	return fmt.Sprintf("public key already exists [owner_id: %d, name: %s]", err.OwnerID, err.Name)
 }
 
+// ErrSSHInvalidTokenSignature represents a "ErrSSHInvalidTokenSignature" kind of error.
+type ErrSSHInvalidTokenSignature struct {
+	Wrapped     error
+	Fingerprint string
+}
+
+// IsErrSSHInvalidTokenSignature checks if an error is a ErrSSHInvalidTokenSignature.
+func IsErrSSHInvalidTokenSignature(err error) bool {
+	_, ok := err.(ErrSSHInvalidTokenSignature)
+	return ok
+}
+
+func (err ErrSSHInvalidTokenSignature) Error() string {
+	return fmt.Sprintf("the provided signature does not sign the token with the provided key [fingerprint: %s]: %v", err.Fingerprint, err.Wrapped)
+}
+
 // ErrGPGNoEmailFound represents a "ErrGPGNoEmailFound" kind of error.
 type ErrGPGNoEmailFound struct {
 	FailedEmails []string
@@ -774,6 +790,36 @@ func (err ErrRepoFilesAlreadyExist) Error() string {
 	return fmt.Sprintf("repository files already exist [uname: %s, name: %s]", err.Uname, err.Name)
 }
 
+// ErrRepoStorageVolumeNotExist represents a "RepoStorageVolumeNotExist" kind of error.
+type ErrRepoStorageVolumeNotExist struct {
+	Name string
+}
+
+// IsErrRepoStorageVolumeNotExist checks if an error is a ErrRepoStorageVolumeNotExist.
+func IsErrRepoStorageVolumeNotExist(err error) bool {
+	_, ok := err.(ErrRepoStorageVolumeNotExist)
+	return ok
+}
+
+func (err ErrRepoStorageVolumeNotExist) Error() string {
+	return fmt.Sprintf("repository storage volume does not exist [name: %s]", err.Name)
+}
+
+// ErrRepoStorageLayoutNotExist represents a "RepoStorageLayoutNotExist" kind of error.
+type ErrRepoStorageLayoutNotExist struct {
+	Name string
+}
+
+// IsErrRepoStorageLayoutNotExist checks if an error is a ErrRepoStorageLayoutNotExist.
+func IsErrRepoStorageLayoutNotExist(err error) bool {
+	_, ok := err.(ErrRepoStorageLayoutNotExist)
+	return ok
+}
+
+func (err ErrRepoStorageLayoutNotExist) Error() string {
+	return fmt.Sprintf("repository storage layout does not exist [name: %s]", err.Name)
+}
+
 // ErrForkAlreadyExist represents a "ForkAlreadyExist" kind of error.
 type ErrForkAlreadyExist struct {
 	Uname    string
@@ -1617,6 +1663,22 @@ func (err ErrLabelNotExist) Error() string {
 	return fmt.Sprintf("label does not exist [label_id: %d]", err.LabelID)
 }
 
+// ErrExclusiveLabelsConflict represents a "ExclusiveLabelsConflict" kind of error.
+type ErrExclusiveLabelsConflict struct {
+	Scope    string
+	LabelIDs []int64
+}
+
+// IsErrExclusiveLabelsConflict checks if an error is a ErrExclusiveLabelsConflict.
+func IsErrExclusiveLabelsConflict(err error) bool {
+	_, ok := err.(ErrExclusiveLabelsConflict)
+	return ok
+}
+
+func (err ErrExclusiveLabelsConflict) Error() string {
+	return fmt.Sprintf("labels of the same exclusive scope cannot be set together [scope: %s, label_ids: %v]", err.Scope, err.LabelIDs)
+}
+
 // __________                   __               __
 // \______   \_______  ____    |__| ____   _____/  |_  ______
 //  |     ___/\_  __ \/  _ \   |  |/ __ \_/ ___\   __\/  ___/
@@ -1682,6 +1744,21 @@ func (err ErrMilestoneNotExist) Error() string {
 	return fmt.Sprintf("milestone does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
 }
 
+// ErrIssueFilterNotExist represents a "IssueFilterNotExist" kind of error.
+type ErrIssueFilterNotExist struct {
+	ID int64
+}
+
+// IsErrIssueFilterNotExist checks if an error is a ErrIssueFilterNotExist.
+func IsErrIssueFilterNotExist(err error) bool {
+	_, ok := err.(ErrIssueFilterNotExist)
+	return ok
+}
+
+func (err ErrIssueFilterNotExist) Error() string {
+	return fmt.Sprintf("issue filter does not exist [id: %d]", err.ID)
+}
+
 //    _____   __    __                .__                           __
 //   /  _  \_/  |__/  |______    ____ |  |__   _____   ____   _____/  |_
 //  /  /_\  \   __\   __\__  \ _/ ___\|  |  \ /     \_/ __ \ /    \   __\
@@ -1987,6 +2064,88 @@ func (err ErrUnknownDependencyType) Error() string {
 	return fmt.Sprintf("unknown dependency type [type: %d]", err.Type)
 }
 
+// ErrCircularIssueParent represents an error where an issue would be an ancestor of itself.
+type ErrCircularIssueParent struct {
+	IssueID  int64
+	ParentID int64
+}
+
+// IsErrCircularIssueParent checks if an error is a ErrCircularIssueParent.
+func IsErrCircularIssueParent(err error) bool {
+	_, ok := err.(ErrCircularIssueParent)
+	return ok
+}
+
+func (err ErrCircularIssueParent) Error() string {
+	return fmt.Sprintf("issue would be an ancestor of itself [issue id: %d, parent id: %d]", err.IssueID, err.ParentID)
+}
+
+// ErrIssueParentNotAllowed represents an error where an issue cannot be a sub-issue of another issue,
+// the parent must be an issue of the same repository or of a repository of the same organization.
+type ErrIssueParentNotAllowed struct {
+	IssueID  int64
+	ParentID int64
+}
+
+// IsErrIssueParentNotAllowed checks if an error is a ErrIssueParentNotAllowed.
+func IsErrIssueParentNotAllowed(err error) bool {
+	_, ok := err.(ErrIssueParentNotAllowed)
+	return ok
+}
+
+func (err ErrIssueParentNotAllowed) Error() string {
+	return fmt.Sprintf("issue cannot be a sub-issue of the parent [issue id: %d, parent id: %d]", err.IssueID, err.ParentID)
+}
+
+// ErrIssueMaxPinReached represents an error where the maximum number of pinned issues of a repository is reached.
+type ErrIssueMaxPinReached struct {
+	RepoID int64
+	IsPull bool
+}
+
+// IsErrIssueMaxPinReached checks if an error is a ErrIssueMaxPinReached.
+func IsErrIssueMaxPinReached(err error) bool {
+	_, ok := err.(ErrIssueMaxPinReached)
+	return ok
+}
+
+func (err ErrIssueMaxPinReached) Error() string {
+	return fmt.Sprintf("maximum number of pinned issues reached [repo id: %d, is pull: %t]", err.RepoID, err.IsPull)
+}
+
+// ErrIssueTransferNotAllowed represents an error where an issue cannot be transferred to a repository.
+type ErrIssueTransferNotAllowed struct {
+	IssueID int64
+	RepoID  int64
+	Reason  string
+}
+
+// IsErrIssueTransferNotAllowed checks if an error is a ErrIssueTransferNotAllowed.
+func IsErrIssueTransferNotAllowed(err error) bool {
+	_, ok := err.(ErrIssueTransferNotAllowed)
+	return ok
+}
+
+func (err ErrIssueTransferNotAllowed) Error() string {
+	return fmt.Sprintf("issue cannot be transferred [issue id: %d, repo id: %d]: %s", err.IssueID, err.RepoID, err.Reason)
+}
+
+// ErrIssueRedirectNotExist represents a "IssueRedirectNotExist" kind of error.
+type ErrIssueRedirectNotExist struct {
+	RepoID int64
+	Index  int64
+}
+
+// IsErrIssueRedirectNotExist checks if an error is a ErrIssueRedirectNotExist.
+func IsErrIssueRedirectNotExist(err error) bool {
+	_, ok := err.(ErrIssueRedirectNotExist)
+	return ok
+}
+
+func (err ErrIssueRedirectNotExist) Error() string {
+	return fmt.Sprintf("issue redirect does not exist [repo id: %d, index: %d]", err.RepoID, err.Index)
+}
+
 //  __________            .__
 //  \______   \ _______  _|__| ______  _  __
 //  |       _// __ \  \/ /  |/ __ \ \/ \/ /
@@ -2029,6 +2188,38 @@ func (err ErrNotValidReviewRequest) Error() string {
 		err.RepoID)
 }
 
+// ErrInvalidSuggestion represents an error when a code comment has no suggestion which can be applied
+type ErrInvalidSuggestion struct {
+	CommentID int64
+	Reason    string
+}
+
+// IsErrInvalidSuggestion checks if an error is a ErrInvalidSuggestion.
+func IsErrInvalidSuggestion(err error) bool {
+	_, ok := err.(ErrInvalidSuggestion)
+	return ok
+}
+
+func (err ErrInvalidSuggestion) Error() string {
+	return fmt.Sprintf("invalid suggestion: %s [comment_id: %d]", err.Reason, err.CommentID)
+}
+
+// ErrSuggestionOutdated represents an error when the code a suggestion applies to has changed
+type ErrSuggestionOutdated struct {
+	CommentID int64
+	TreePath  string
+}
+
+// IsErrSuggestionOutdated checks if an error is a ErrSuggestionOutdated.
+func IsErrSuggestionOutdated(err error) bool {
+	_, ok := err.(ErrSuggestionOutdated)
+	return ok
+}
+
+func (err ErrSuggestionOutdated) Error() string {
+	return fmt.Sprintf("suggestion is outdated [comment_id: %d, tree_path: %s]", err.CommentID, err.TreePath)
+}
+
 //  ________      _____          __  .__
 //  \_____  \    /  _  \  __ ___/  |_|  |__
 //   /   |   \  /  /_\  \|  |  \   __\  |  \
@@ -383,6 +383,7 @@ type CommitVerification struct {
 	CommittingUser *User
 	SigningEmail   string
 	SigningKey     *GPGKey
+	SigningSSHKey  *PublicKey
 	TrustStatus    string
 }
 
@@ -646,6 +647,11 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 		}
 	}
 
+	// SSH signatures are verified against the committer's ssh keys
+	if IsSSHSignature(c.Signature.Signature) {
+		return ParseCommitWithSSHSignature(c, committer)
+	}
+
 	//Parsing signature
 	sig, err := extractSignature(c.Signature.Signature)
 	if err != nil { //Skipping failed to extract sign
@@ -890,13 +896,20 @@ func CalculateTrustStatus(verification *CommitVerification, repository *Reposito
 		return
 	}
 
+	var keyID string
+	if verification.SigningSSHKey != nil {
+		keyID = verification.SigningSSHKey.Fingerprint
+	} else if verification.SigningKey != nil {
+		keyID = verification.SigningKey.KeyID
+	}
+
 	var isMember bool
-	if keyMap != nil {
+	if keyMap != nil && keyID != "" {
 		var has bool
-		isMember, has = (*keyMap)[verification.SigningKey.KeyID]
+		isMember, has = (*keyMap)[keyID]
 		if !has {
 			isMember, err = repository.IsOwnerMemberCollaborator(verification.SigningUser.ID)
-			(*keyMap)[verification.SigningKey.KeyID] = isMember
+			(*keyMap)[keyID] = isMember
 		}
 	} else {
 		isMember, err = repository.IsOwnerMemberCollaborator(verification.SigningUser.ID)
@@ -68,6 +68,16 @@ type Issue struct {
 	// with write access
 	IsLocked bool `xorm:"NOT NULL DEFAULT false"`
 
+	// ParentID is the issue this issue is a sub-issue of
+	ParentID int64  `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Parent   *Issue `xorm:"-"`
+
+	// PinOrder is the position of the issue in the pinned issues of the repository, 0 if not pinned
+	PinOrder int `xorm:"NOT NULL DEFAULT 0"`
+
+	// EstimatedTime is the estimated time to resolve the issue in seconds, 0 if not estimated
+	EstimatedTime int64 `xorm:"NOT NULL DEFAULT 0"`
+
 	// For view issue page.
 	ShowTag CommentTag `xorm:"-"`
 }
@@ -514,6 +524,7 @@ func (issue *Issue) ReplaceLabels(labels []*Label, doer *User) (err error) {
 		return err
 	}
 
+	labels = RemoveDuplicateExclusiveLabels(labels)
 	sort.Sort(labelSorter(labels))
 	sort.Sort(labelSorter(issue.Labels))
 
@@ -894,6 +905,11 @@ func newIssue(e *xorm.Session, doer *User, opts NewIssueOptions) (err error) {
 	// Patch Index with the value calculated by the database
 	opts.Issue.Index = inserted.Index
 
+	// The index of an issue transferred to another repository may be reused
+	if err = deleteIssueRedirect(e, opts.Issue.RepoID, opts.Issue.Index); err != nil {
+		return err
+	}
+
 	if opts.Issue.MilestoneID > 0 {
 		if _, err = e.Exec("UPDATE `milestone` SET num_issues=num_issues+1 WHERE id=?", opts.Issue.MilestoneID); err != nil {
 			return err
@@ -933,7 +949,7 @@ func newIssue(e *xorm.Session, doer *User, opts NewIssueOptions) (err error) {
 			return err
 		}
 
-		for _, label := range labels {
+		for _, label := range RemoveDuplicateExclusiveLabels(labels) {
 			// Silently drop invalid labels.
 			if label.RepoID != opts.Repo.ID && label.OrgID != opts.Repo.OwnerID {
 				continue
@@ -1100,8 +1116,12 @@ type IssuesOptions struct {
 	ExcludedLabelNames []string
 	SortType           string
 	IssueIDs           []int64
+	// sub-issues of this issue only
+	ParentID int64
 	// prioritize issues from this repo
 	PriorityRepoID int64
+	// filters of a structured search query
+	SearchQuery *IssueSearchQuery
 }
 
 // sortIssuesSession sort an issues-related session based on the provided
@@ -1178,6 +1198,10 @@ func (opts *IssuesOptions) setupSession(sess *xorm.Session) {
 		sess.In("issue.milestone_id", opts.MilestoneIDs)
 	}
 
+	if opts.ParentID > 0 {
+		sess.And("issue.parent_id = ?", opts.ParentID)
+	}
+
 	if opts.ProjectID > 0 {
 		sess.Join("INNER", "project_issue", "issue.id = project_issue.issue_id").
 			And("project_issue.project_id=?", opts.ProjectID)
@@ -1216,6 +1240,10 @@ func (opts *IssuesOptions) setupSession(sess *xorm.Session) {
 	if len(opts.ExcludedLabelNames) > 0 {
 		sess.And(builder.NotIn("issue.id", BuildLabelNamesIssueIDsCondition(opts.ExcludedLabelNames)))
 	}
+
+	if opts.SearchQuery != nil {
+		sess.And(opts.SearchQuery.toCond())
+	}
 }
 
 // CountIssuesByRepo map from repoID to number of issues matching the options
@@ -1380,6 +1408,8 @@ type IssueStatsOptions struct {
 	PosterID    int64
 	IsPull      util.OptionalBool
 	IssueIDs    []int64
+	ParentID    int64
+	SearchQuery *IssueSearchQuery
 }
 
 // GetIssueStats returns issue statistic information by given conditions.
@@ -1444,6 +1474,10 @@ func getIssueStatsChunk(opts *IssueStatsOptions, issueIDs []int64) (*IssueStats,
 			sess.And("issue.milestone_id = ?", opts.MilestoneID)
 		}
 
+		if opts.ParentID > 0 {
+			sess.And("issue.parent_id = ?", opts.ParentID)
+		}
+
 		if opts.AssigneeID > 0 {
 			sess.Join("INNER", "issue_assignees", "issue.id = issue_assignees.issue_id").
 				And("issue_assignees.assignee_id = ?", opts.AssigneeID)
@@ -1466,6 +1500,10 @@ func getIssueStatsChunk(opts *IssueStatsOptions, issueIDs []int64) (*IssueStats,
 			sess.And("issue.is_pull=?", false)
 		}
 
+		if opts.SearchQuery != nil {
+			sess.And(opts.SearchQuery.toCond())
+		}
+
 		return sess
 	}
 
@@ -1491,6 +1529,7 @@ type UserIssueStatsOptions struct {
 	IsPull      bool
 	IsClosed    bool
 	IssueIDs    []int64
+	SearchQuery *IssueSearchQuery
 }
 
 // GetUserIssueStats returns issue statistic information for dashboard by given conditions.
@@ -1506,6 +1545,9 @@ func GetUserIssueStats(opts UserIssueStatsOptions) (*IssueStats, error) {
 	if len(opts.IssueIDs) > 0 {
 		cond = cond.And(builder.In("issue.id", opts.IssueIDs))
 	}
+	if opts.SearchQuery != nil {
+		cond = cond.And(opts.SearchQuery.toCond())
+	}
 
 	switch opts.FilterMode {
 	case FilterModeAll:
@@ -1993,6 +2035,11 @@ func deleteIssuesByRepoID(sess Engine, repoID int64) (attachmentPaths []string,
 		return
 	}
 
+	// Detach the sub-issues in other repositories
+	if err = detachCrossRepoSubIssues(sess, repoID); err != nil {
+		return
+	}
+
 	if _, err = sess.In("issue_id", deleteCond).
 		Delete(&IssueUser{}); err != nil {
 		return
@@ -2028,6 +2075,11 @@ func deleteIssuesByRepoID(sess Engine, repoID int64) (attachmentPaths []string,
 		return
 	}
 
+	if _, err = sess.Where(builder.Eq{"old_repo_id": repoID}.Or(builder.In("redirect_issue_id", deleteCond))).
+		Delete(&IssueRedirect{}); err != nil {
+		return
+	}
+
 	var attachments []*Attachment
 	if err = sess.In("issue_id", deleteCond).
 		Find(&attachments); err != nil {
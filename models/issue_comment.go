@@ -100,6 +100,18 @@ const (
 	CommentTypeProject
 	// Project board changed
 	CommentTypeProjectBoard
+	// Parent issue set
+	CommentTypeAddParentIssue
+	// Parent issue removed
+	CommentTypeRemoveParentIssue
+	// Issue pinned
+	CommentTypePin
+	// Issue unpinned
+	CommentTypeUnpin
+	// Issue transferred from another repository
+	CommentTypeTransferIssue
+	// Estimated time changed
+	CommentTypeChangeTimeEstimate
 )
 
 // CommentTag defines comment tag type
@@ -151,6 +163,7 @@ type Comment struct {
 
 	CommitID        int64
 	Line            int64 // - previous line / + proposed line
+	StartLine       int64 `xorm:"NOT NULL DEFAULT 0"` // first line of a multi-line comment, same sign convention as Line
 	TreePath        string
 	Content         string `xorm:"TEXT"`
 	RenderedContent string `xorm:"-"`
@@ -619,6 +632,41 @@ func (c *Comment) UnsignedLine() uint64 {
 	return uint64(c.Line)
 }
 
+// IsMultiLine returns true if the code comment applies to a range of lines ending at Line
+func (c *Comment) IsMultiLine() bool {
+	return c.StartLine != 0 && c.StartLine != c.Line
+}
+
+// UnsignedStartLine returns the first LOC of a multi-line code comment without + or -
+func (c *Comment) UnsignedStartLine() uint64 {
+	if c.StartLine < 0 {
+		return uint64(c.StartLine * -1)
+	}
+	return uint64(c.StartLine)
+}
+
+// suggestionPattern matches a fenced code block with the "suggestion" info string
+var suggestionPattern = regexp.MustCompile("(?ms)^[ \\t]*```suggestion[ \\t]*\\r?\\n(.*?)^[ \\t]*```[ \\t]*\\r?$")
+
+// Suggestion returns the lines proposed by the first suggestion block of a code comment
+// to replace the commented line. An empty suggestion proposes to remove the line.
+func (c *Comment) Suggestion() (string, bool) {
+	if c.Type != CommentTypeCode {
+		return "", false
+	}
+	m := suggestionPattern.FindStringSubmatch(c.Content)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// HasSuggestion returns true if the code comment proposes replacement lines
+func (c *Comment) HasSuggestion() bool {
+	_, ok := c.Suggestion()
+	return ok
+}
+
 // CodeCommentURL returns the url to a comment in code
 func (c *Comment) CodeCommentURL() string {
 	err := c.LoadIssue()
@@ -697,6 +745,7 @@ func createComment(e *xorm.Session, opts *CreateCommentOptions) (_ *Comment, err
 		CommitID:         opts.CommitID,
 		CommitSHA:        opts.CommitSHA,
 		Line:             opts.LineNum,
+		StartLine:        opts.StartLineNum,
 		Content:          opts.Content,
 		OldTitle:         opts.OldTitle,
 		NewTitle:         opts.NewTitle,
@@ -869,6 +918,7 @@ type CreateCommentOptions struct {
 	CommitSHA        string
 	Patch            string
 	LineNum          int64
+	StartLineNum     int64
 	TreePath         string
 	ReviewID         int64
 	Content          string
@@ -57,3 +57,25 @@ func TestFetchCodeComments(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, res, 1)
 }
+
+func TestComment_Suggestion(t *testing.T) {
+	comment := &Comment{
+		Type:    CommentTypeCode,
+		Content: "Better:\n```suggestion\nfoo := bar\nbaz()\n```\nThanks",
+	}
+	suggestion, ok := comment.Suggestion()
+	assert.True(t, ok)
+	assert.Equal(t, "foo := bar\nbaz()\n", suggestion)
+
+	comment.Content = "Remove this:\r\n```suggestion\r\n```\r\n"
+	suggestion, ok = comment.Suggestion()
+	assert.True(t, ok)
+	assert.Empty(t, suggestion)
+
+	comment.Content = "```go\nfoo := bar\n```"
+	assert.False(t, comment.HasSuggestion())
+
+	comment.Type = CommentTypeComment
+	comment.Content = "```suggestion\nfoo := bar\n```"
+	assert.False(t, comment.HasSuggestion())
+}
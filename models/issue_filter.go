@@ -0,0 +1,343 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// IssueFilter represents a saved issue search of a user, or of an organization
+// in which case it is shared with the members of the organization
+type IssueFilter struct {
+	ID      int64  `xorm:"pk autoincr"`
+	OwnerID int64  `xorm:"INDEX NOT NULL"`
+	Owner   *User  `xorm:"-"`
+	Name    string `xorm:"NOT NULL"`
+	// Query is a structured search query, see IssueSearchQuery
+	Query string `xorm:"TEXT"`
+	// RepoIDs restricts the search to some repositories, all the repositories of the owner are searched if empty
+	RepoIDs []int64 `xorm:"TEXT JSON"`
+	IsPull  bool    `xorm:"NOT NULL DEFAULT false"`
+	// IsPinned shows the filter on the issues dashboard of the owner
+	IsPinned bool `xorm:"NOT NULL DEFAULT false"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// IssueFilterSubscription represents a user notified of the new issues matching a filter
+type IssueFilterSubscription struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UserID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+	FilterID    int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// LoadOwner loads the owner of the filter
+func (f *IssueFilter) LoadOwner() (err error) {
+	if f.Owner == nil {
+		f.Owner, err = GetUserByID(f.OwnerID)
+	}
+	return err
+}
+
+// CanBeManagedBy returns whether the user can change or delete the filter
+func (f *IssueFilter) CanBeManagedBy(user *User) (bool, error) {
+	if user == nil {
+		return false, nil
+	}
+	if f.OwnerID == user.ID || user.IsAdmin {
+		return true, nil
+	}
+	return IsOrganizationOwner(f.OwnerID, user.ID)
+}
+
+// CanBeUsedBy returns whether the user can search with the filter and subscribe to it
+func (f *IssueFilter) CanBeUsedBy(user *User) (bool, error) {
+	if user == nil {
+		return false, nil
+	}
+	if f.OwnerID == user.ID {
+		return true, nil
+	}
+	return IsOrganizationMember(f.OwnerID, user.ID)
+}
+
+// SearchQuery parses the query of the filter for the user
+func (f *IssueFilter) SearchQuery(user *User) *IssueSearchQuery {
+	return ParseIssueSearchQuery(f.Query, user)
+}
+
+// IssuesOptions returns the options to search the issues of the filter in the repositories,
+// the keyword of the query must be searched with the issue indexer by the callers
+func (f *IssueFilter) IssuesOptions(user *User, repoIDs []int64) *IssuesOptions {
+	query := f.SearchQuery(user)
+	opts := &IssuesOptions{
+		RepoIDs:     repoIDs,
+		IsPull:      util.OptionalBoolOf(f.IsPull),
+		IsClosed:    query.IsClosed,
+		SortType:    query.SortType,
+		SearchQuery: query,
+	}
+	if opts.IsClosed.IsNone() {
+		opts.IsClosed = util.OptionalBoolFalse
+	}
+	if len(f.RepoIDs) > 0 {
+		opts.RepoIDs = make([]int64, 0, len(f.RepoIDs))
+		for _, repoID := range f.RepoIDs {
+			if util.IsInt64InSlice(repoID, repoIDs) {
+				opts.RepoIDs = append(opts.RepoIDs, repoID)
+			}
+		}
+	}
+	if len(opts.RepoIDs) == 0 {
+		// no repository matches nothing
+		opts.RepoIDs = []int64{-1}
+	}
+	return opts
+}
+
+// CreateIssueFilter creates a new issue filter
+func CreateIssueFilter(f *IssueFilter) error {
+	_, err := x.Insert(f)
+	return err
+}
+
+// GetIssueFilterByID returns the issue filter by given ID
+func GetIssueFilterByID(id int64) (*IssueFilter, error) {
+	f := new(IssueFilter)
+	has, err := x.ID(id).Get(f)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueFilterNotExist{ID: id}
+	}
+	return f, nil
+}
+
+// UpdateIssueFilterCols updates some columns of an issue filter
+func UpdateIssueFilterCols(f *IssueFilter, cols ...string) error {
+	_, err := x.ID(f.ID).Cols(cols...).Update(f)
+	return err
+}
+
+// FindIssueFiltersOptions represents the options to find issue filters
+type FindIssueFiltersOptions struct {
+	OwnerIDs []int64
+	IsPull   util.OptionalBool
+	IsPinned util.OptionalBool
+}
+
+func (opts *FindIssueFiltersOptions) toCond() builder.Cond {
+	cond := builder.NewCond()
+	if len(opts.OwnerIDs) > 0 {
+		cond = cond.And(builder.In("owner_id", opts.OwnerIDs))
+	}
+	if !opts.IsPull.IsNone() {
+		cond = cond.And(builder.Eq{"is_pull": opts.IsPull.IsTrue()})
+	}
+	if !opts.IsPinned.IsNone() {
+		cond = cond.And(builder.Eq{"is_pinned": opts.IsPinned.IsTrue()})
+	}
+	return cond
+}
+
+// FindIssueFilters returns the issue filters matching the options ordered by name
+func FindIssueFilters(opts *FindIssueFiltersOptions) ([]*IssueFilter, error) {
+	filters := make([]*IssueFilter, 0, 10)
+	return filters, x.Where(opts.toCond()).Asc("name").Find(&filters)
+}
+
+// DeleteIssueFilterByID deletes an issue filter and its subscriptions
+func DeleteIssueFilterByID(id int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	if _, err := sess.Delete(&IssueFilterSubscription{FilterID: id}); err != nil {
+		return err
+	}
+	if _, err := sess.ID(id).Delete(new(IssueFilter)); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+func deleteIssueFiltersByOwnerID(e Engine, ownerID int64) error {
+	if _, err := e.In("filter_id", builder.Select("id").From("issue_filter").Where(builder.Eq{"owner_id": ownerID})).
+		Delete(new(IssueFilterSubscription)); err != nil {
+		return err
+	}
+	_, err := e.Delete(&IssueFilter{OwnerID: ownerID})
+	return err
+}
+
+// IsIssueFilterSubscribed returns whether the user is subscribed to the filter
+func IsIssueFilterSubscribed(userID, filterID int64) (bool, error) {
+	return x.Exist(&IssueFilterSubscription{UserID: userID, FilterID: filterID})
+}
+
+// GetSubscribedIssueFilterIDs returns the ids of the filters the user is subscribed to
+func GetSubscribedIssueFilterIDs(userID int64) ([]int64, error) {
+	ids := make([]int64, 0, 10)
+	return ids, x.Table("issue_filter_subscription").Where("user_id = ?", userID).Cols("filter_id").Find(&ids)
+}
+
+// SubscribeIssueFilter subscribes or unsubscribes the user to the new issues matching the filter
+func SubscribeIssueFilter(userID, filterID int64, subscribe bool) error {
+	exist, err := IsIssueFilterSubscribed(userID, filterID)
+	if err != nil || exist == subscribe {
+		return err
+	}
+	if subscribe {
+		_, err = x.Insert(&IssueFilterSubscription{UserID: userID, FilterID: filterID})
+	} else {
+		_, err = x.Delete(&IssueFilterSubscription{UserID: userID, FilterID: filterID})
+	}
+	return err
+}
+
+// matchIssueSearchKeyword returns whether the title or the content of the issue contains all the words of the keyword,
+// it is used instead of the issue indexer which indexes the new issues asynchronously
+func matchIssueSearchKeyword(issue *Issue, keyword string) bool {
+	text := strings.ToLower(issue.Title + "\n" + issue.Content)
+	for _, word := range strings.Fields(strings.ToLower(keyword)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetIssueFilterSubscriberIDs returns the ids of the users subscribed to a filter matching the new issue
+// who can read the issue
+func GetIssueFilterSubscriberIDs(issue *Issue) ([]int64, error) {
+	if err := issue.loadRepo(x); err != nil {
+		return nil, err
+	}
+
+	// the filters without repositories only search the repositories of their owner, the repositories
+	// of the others are stored as JSON so the LIKE only narrows them down and they are checked below
+	filters := make([]*IssueFilter, 0, 10)
+	if err := x.Where("is_pull = ?", issue.IsPull).
+		And(builder.Eq{"owner_id": issue.Repo.OwnerID}.Or(builder.Like{"repo_i_ds", strconv.FormatInt(issue.RepoID, 10)})).
+		And(builder.In("id", builder.Select("filter_id").From("issue_filter_subscription"))).
+		Find(&filters); err != nil {
+		return nil, err
+	}
+	filtersByID := make(map[int64]*IssueFilter, len(filters))
+	for _, filter := range filters {
+		if len(filter.RepoIDs) > 0 {
+			if !util.IsInt64InSlice(issue.RepoID, filter.RepoIDs) {
+				continue
+			}
+		} else if filter.OwnerID != issue.Repo.OwnerID {
+			continue
+		}
+		filtersByID[filter.ID] = filter
+	}
+	if len(filtersByID) == 0 {
+		return nil, nil
+	}
+
+	filterIDs := make([]int64, 0, len(filtersByID))
+	for id := range filtersByID {
+		filterIDs = append(filterIDs, id)
+	}
+	subscriptions := make([]*IssueFilterSubscription, 0, 10)
+	if err := x.In("filter_id", filterIDs).And("user_id != ?", issue.PosterID).
+		Asc("id").Find(&subscriptions); err != nil {
+		return nil, err
+	}
+	userIDs := make([]int64, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		userIDs = append(userIDs, subscription.UserID)
+	}
+	users, err := GetUsersByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[int64]*User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	// the filters of an organization can be used by its members
+	orgUsers := make([]*OrgUser, 0, 10)
+	if err := x.In("org_id", builder.Select("owner_id").From("issue_filter").Where(builder.In("id", filterIDs))).
+		In("uid", userIDs).Find(&orgUsers); err != nil {
+		return nil, err
+	}
+	type orgMember struct{ orgID, uid int64 }
+	isOrgMember := make(map[orgMember]bool, len(orgUsers))
+	for _, orgUser := range orgUsers {
+		isOrgMember[orgMember{orgUser.OrgID, orgUser.UID}] = true
+	}
+
+	unitType := UnitTypeIssues
+	if issue.IsPull {
+		unitType = UnitTypePullRequests
+	}
+
+	// the issue matches a filter for all its subscribers unless the query refers to them with @me,
+	// so the issue is only searched once per filter and per subscriber of such filters
+	type matchKey struct{ filterID, userID int64 }
+	var (
+		subscriberIDs []int64
+		seen          = make(map[int64]bool)
+		hasAccess     = make(map[int64]bool)
+		matches       = make(map[matchKey]bool)
+	)
+	for _, subscription := range subscriptions {
+		if seen[subscription.UserID] {
+			continue
+		}
+		filter, user := filtersByID[subscription.FilterID], usersByID[subscription.UserID]
+		if user == nil || (filter.OwnerID != user.ID && !isOrgMember[orgMember{filter.OwnerID, user.ID}]) {
+			continue
+		}
+
+		access, ok := hasAccess[user.ID]
+		if !ok {
+			if access, err = hasAccessUnit(x, user, issue.Repo, unitType, AccessModeRead); err != nil {
+				return nil, err
+			}
+			hasAccess[user.ID] = access
+		}
+		if !access {
+			continue
+		}
+
+		key := matchKey{filterID: filter.ID}
+		if strings.Contains(filter.Query, "@me") {
+			key.userID = user.ID
+		}
+		match, ok := matches[key]
+		if !ok {
+			opts := filter.IssuesOptions(user, []int64{issue.RepoID})
+			if match = matchIssueSearchKeyword(issue, opts.SearchQuery.Keyword); match {
+				opts.IssueIDs = []int64{issue.ID}
+				count, err := CountIssues(opts)
+				if err != nil {
+					return nil, err
+				}
+				match = count > 0
+			}
+			matches[key] = match
+		}
+		if match {
+			seen[user.ID] = true
+			subscriberIDs = append(subscriberIDs, user.ID)
+		}
+	}
+	return subscriberIDs, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueFilter(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	filter := &IssueFilter{OwnerID: user.ID, Name: "labelled", Query: "label:label1", IsPinned: true}
+	assert.NoError(t, CreateIssueFilter(filter))
+	AssertExistsAndLoadBean(t, &IssueFilter{ID: filter.ID, Name: "labelled"})
+
+	opts := filter.IssuesOptions(user, []int64{1, 2})
+	assert.Equal(t, []int64{1, 2}, opts.RepoIDs)
+	assert.True(t, opts.IsClosed.IsFalse())
+	assert.True(t, opts.IsPull.IsFalse())
+	count, err := CountIssues(opts)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	filter.RepoIDs = []int64{1}
+	assert.Equal(t, []int64{-1}, filter.IssuesOptions(user, []int64{2}).RepoIDs)
+	assert.Equal(t, []int64{1}, filter.IssuesOptions(user, []int64{1, 2}).RepoIDs)
+
+	filters, err := FindIssueFilters(&FindIssueFiltersOptions{OwnerIDs: []int64{user.ID}, IsPinned: util.OptionalBoolTrue})
+	assert.NoError(t, err)
+	assert.Len(t, filters, 1)
+	filters, err = FindIssueFilters(&FindIssueFiltersOptions{OwnerIDs: []int64{user.ID}, IsPull: util.OptionalBoolTrue})
+	assert.NoError(t, err)
+	assert.Len(t, filters, 0)
+
+	assert.NoError(t, SubscribeIssueFilter(user.ID, filter.ID, true))
+	assert.NoError(t, SubscribeIssueFilter(user.ID, filter.ID, true))
+	ids, err := GetSubscribedIssueFilterIDs(user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{filter.ID}, ids)
+
+	assert.NoError(t, DeleteIssueFilterByID(filter.ID))
+	AssertNotExistsBean(t, &IssueFilter{ID: filter.ID})
+	AssertNotExistsBean(t, &IssueFilterSubscription{FilterID: filter.ID})
+
+	_, err = GetIssueFilterByID(filter.ID)
+	assert.True(t, IsErrIssueFilterNotExist(err))
+}
+
+func TestIssueFilterPermissions(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	admin := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+
+	filter := &IssueFilter{OwnerID: 3, Name: "org", Query: "is:issue"}
+	canManage, err := filter.CanBeManagedBy(user2)
+	assert.NoError(t, err)
+	assert.True(t, canManage)
+	canManage, err = filter.CanBeManagedBy(admin)
+	assert.NoError(t, err)
+	assert.True(t, canManage)
+	canManage, err = filter.CanBeManagedBy(user4)
+	assert.NoError(t, err)
+	assert.False(t, canManage)
+
+	canUse, err := filter.CanBeUsedBy(user4)
+	assert.NoError(t, err)
+	assert.True(t, canUse)
+	canUse, err = filter.CanBeUsedBy(nil)
+	assert.NoError(t, err)
+	assert.False(t, canUse)
+}
+
+func TestGetIssueFilterSubscriberIDs(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	userFilter := &IssueFilter{OwnerID: 2, Name: "bugs", Query: "label:label1 first"}
+	assert.NoError(t, CreateIssueFilter(userFilter))
+	assert.NoError(t, SubscribeIssueFilter(2, userFilter.ID, true))
+	orgFilter := &IssueFilter{OwnerID: 3, Name: "org issues", Query: "is:open"}
+	assert.NoError(t, CreateIssueFilter(orgFilter))
+	assert.NoError(t, SubscribeIssueFilter(2, orgFilter.ID, true))
+	// user28 is a member of the organization without access to its private repository
+	assert.NoError(t, SubscribeIssueFilter(28, orgFilter.ID, true))
+	// a filter without repositories only searches the repositories of its owner
+	otherFilter := &IssueFilter{OwnerID: 4, Name: "all issues", Query: "is:issue"}
+	assert.NoError(t, CreateIssueFilter(otherFilter))
+	assert.NoError(t, SubscribeIssueFilter(4, otherFilter.ID, true))
+	repoFilter := &IssueFilter{OwnerID: 5, Name: "repo1 issues", Query: "is:issue", RepoIDs: []int64{1}}
+	assert.NoError(t, CreateIssueFilter(repoFilter))
+	assert.NoError(t, SubscribeIssueFilter(5, repoFilter.ID, true))
+
+	for _, test := range []struct {
+		IssueID       int64
+		SubscriberIDs []int64
+	}{
+		{1, []int64{2, 5}},
+		{2, nil},
+		{5, nil},
+		{6, []int64{2}},
+	} {
+		issue := AssertExistsAndLoadBean(t, &Issue{ID: test.IssueID}).(*Issue)
+		subscriberIDs, err := GetIssueFilterSubscriberIDs(issue)
+		assert.NoError(t, err)
+		assert.Equal(t, test.SubscriberIDs, subscriberIDs, test.IssueID)
+	}
+}
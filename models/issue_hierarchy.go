@@ -0,0 +1,262 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"xorm.io/builder"
+)
+
+// maxIssueHierarchyDepth is the maximum depth of the sub-issues loaded in a tree
+const maxIssueHierarchyDepth = 10
+
+func (issue *Issue) loadParent(e Engine) (err error) {
+	if issue.ParentID == 0 || (issue.Parent != nil && issue.Parent.ID == issue.ParentID) {
+		return nil
+	}
+	if issue.Parent, err = getIssueByID(e, issue.ParentID); err != nil {
+		return err
+	}
+	return issue.Parent.loadRepo(e)
+}
+
+// LoadParent loads the parent issue of a sub-issue
+func (issue *Issue) LoadParent() error {
+	return issue.loadParent(x)
+}
+
+// checkIssueParent checks that the parent can be the parent issue of the issue:
+// it must not be a pull request, it must be in the repository of the issue or in a repository of the same organization
+// and the issue must not be one of its ancestors.
+func checkIssueParent(e Engine, issue, parent *Issue) error {
+	if issue.ID == parent.ID {
+		return ErrCircularIssueParent{IssueID: issue.ID, ParentID: parent.ID}
+	}
+	if parent.IsPull {
+		return ErrIssueParentNotAllowed{IssueID: issue.ID, ParentID: parent.ID}
+	}
+
+	if issue.RepoID != parent.RepoID {
+		if err := issue.loadRepo(e); err != nil {
+			return err
+		}
+		if err := parent.loadRepo(e); err != nil {
+			return err
+		}
+		if issue.Repo.OwnerID != parent.Repo.OwnerID {
+			return ErrIssueParentNotAllowed{IssueID: issue.ID, ParentID: parent.ID}
+		}
+		if err := issue.Repo.getOwner(e); err != nil {
+			return err
+		}
+		if !issue.Repo.Owner.IsOrganization() {
+			return ErrIssueParentNotAllowed{IssueID: issue.ID, ParentID: parent.ID}
+		}
+	}
+
+	seen := map[int64]bool{parent.ID: true}
+	for ancestorID := parent.ParentID; ancestorID > 0 && !seen[ancestorID]; {
+		if ancestorID == issue.ID {
+			return ErrCircularIssueParent{IssueID: issue.ID, ParentID: parent.ID}
+		}
+		seen[ancestorID] = true
+
+		ancestor := new(Issue)
+		if has, err := e.ID(ancestorID).Cols("parent_id").Get(ancestor); err != nil {
+			return err
+		} else if !has {
+			break
+		}
+		ancestorID = ancestor.ParentID
+	}
+	return nil
+}
+
+// SetIssueParent makes the issue a sub-issue of the parent, or removes the parent of the issue if parent is nil
+func SetIssueParent(issue, parent *Issue, doer *User) (err error) {
+	var parentID int64
+	if parent != nil {
+		parentID = parent.ID
+	}
+	if issue.ParentID == parentID {
+		return nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if parent != nil {
+		if err = checkIssueParent(sess, issue, parent); err != nil {
+			return err
+		}
+	}
+	if err = issue.loadRepo(sess); err != nil {
+		return err
+	}
+
+	oldParentID := issue.ParentID
+	issue.ParentID = parentID
+	if _, err = sess.ID(issue.ID).Cols("parent_id").NoAutoTime().Update(issue); err != nil {
+		return err
+	}
+
+	if oldParentID > 0 {
+		if _, err = createComment(sess, &CreateCommentOptions{
+			Type:             CommentTypeRemoveParentIssue,
+			Doer:             doer,
+			Repo:             issue.Repo,
+			Issue:            issue,
+			DependentIssueID: oldParentID,
+		}); err != nil {
+			return err
+		}
+	}
+	if parent != nil {
+		if _, err = createComment(sess, &CreateCommentOptions{
+			Type:             CommentTypeAddParentIssue,
+			Doer:             doer,
+			Repo:             issue.Repo,
+			Issue:            issue,
+			DependentIssueID: parent.ID,
+		}); err != nil {
+			return err
+		}
+	}
+	issue.Parent = parent
+
+	return sess.Commit()
+}
+
+func getIssueChildren(e Engine, parentIDs []int64) (IssueList, error) {
+	issues := make(IssueList, 0, 10)
+	if err := e.In("parent_id", parentIDs).
+		Asc("repo_id").
+		Asc("`index`").
+		Find(&issues); err != nil {
+		return nil, err
+	}
+	if _, err := issues.loadRepositories(e); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// GetIssueChildren returns the sub-issues of an issue
+func GetIssueChildren(issueID int64) (IssueList, error) {
+	return getIssueChildren(x, []int64{issueID})
+}
+
+// detachCrossRepoSubIssues removes the parents in other repositories of the issues of the repository
+// and the parents in the repository of the issues of other repositories
+func detachCrossRepoSubIssues(e Engine, repoID int64) error {
+	issueIDs := make([]int64, 0, 10)
+	if err := e.Table("issue").Where("repo_id = ?", repoID).Cols("id").Find(&issueIDs); err != nil {
+		return err
+	}
+	if len(issueIDs) == 0 {
+		return nil
+	}
+
+	_, err := e.Where(builder.And(
+		builder.In("parent_id", issueIDs),
+		builder.Neq{"repo_id": repoID},
+	).Or(builder.And(
+		builder.Eq{"repo_id": repoID},
+		builder.Gt{"parent_id": 0},
+		builder.NotIn("parent_id", issueIDs),
+	))).Cols("parent_id").NoAutoTime().Update(&Issue{ParentID: 0})
+	return err
+}
+
+// IssueTreeNode represents an issue with its sub-issues
+type IssueTreeNode struct {
+	Issue    *Issue
+	Children []*IssueTreeNode
+}
+
+func (node *IssueTreeNode) countDescendants() (total, closed int) {
+	for _, child := range node.Children {
+		childTotal, childClosed := child.countDescendants()
+		total += childTotal + 1
+		closed += childClosed
+		if child.Issue.IsClosed {
+			closed++
+		}
+	}
+	return total, closed
+}
+
+// NumDescendants returns the number of sub-issues of the tree at all levels
+func (node *IssueTreeNode) NumDescendants() int {
+	total, _ := node.countDescendants()
+	return total
+}
+
+// NumClosedDescendants returns the number of closed sub-issues of the tree at all levels
+func (node *IssueTreeNode) NumClosedDescendants() int {
+	_, closed := node.countDescendants()
+	return closed
+}
+
+// Progress returns the percentage of closed sub-issues of the tree at all levels
+func (node *IssueTreeNode) Progress() int {
+	total, closed := node.countDescendants()
+	if total == 0 {
+		return 0
+	}
+	return closed * 100 / total
+}
+
+// GetIssueTree returns the tree of the sub-issues of the issue the user can read
+func GetIssueTree(issue *Issue, doer *User) (*IssueTreeNode, error) {
+	type repoUnit struct {
+		RepoID int64
+		IsPull bool
+	}
+	canRead := make(map[repoUnit]bool)
+
+	root := &IssueTreeNode{Issue: issue}
+	level := []*IssueTreeNode{root}
+	for depth := 0; depth < maxIssueHierarchyDepth && len(level) > 0; depth++ {
+		nodes := make(map[int64]*IssueTreeNode, len(level))
+		parentIDs := make([]int64, 0, len(level))
+		for _, node := range level {
+			nodes[node.Issue.ID] = node
+			parentIDs = append(parentIDs, node.Issue.ID)
+		}
+
+		children, err := getIssueChildren(x, parentIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		level = level[:0:0]
+		for _, child := range children {
+			key := repoUnit{RepoID: child.RepoID, IsPull: child.IsPull}
+			allowed, ok := canRead[key]
+			if !ok {
+				unitType := UnitTypeIssues
+				if child.IsPull {
+					unitType = UnitTypePullRequests
+				}
+				if allowed, err = hasAccessUnit(x, doer, child.Repo, unitType, AccessModeRead); err != nil {
+					return nil, err
+				}
+				canRead[key] = allowed
+			}
+			if !allowed {
+				continue
+			}
+
+			node := &IssueTreeNode{Issue: child}
+			parent := nodes[child.ParentID]
+			parent.Children = append(parent.Children, node)
+			level = append(level, node)
+		}
+	}
+	return root, nil
+}
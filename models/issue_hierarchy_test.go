@@ -0,0 +1,99 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetIssueParent(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue5 := AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+
+	assert.NoError(t, SetIssueParent(issue5, issue1, user2))
+	AssertExistsAndLoadBean(t, &Issue{ID: 5, ParentID: 1})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeAddParentIssue, PosterID: 2, IssueID: 5, DependentIssueID: 1})
+
+	children, err := GetIssueChildren(issue1.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, children, 1) {
+		assert.EqualValues(t, 5, children[0].ID)
+	}
+
+	// circular parents
+	err = SetIssueParent(issue1, issue5, user2)
+	assert.True(t, IsErrCircularIssueParent(err))
+	err = SetIssueParent(issue1, issue1, user2)
+	assert.True(t, IsErrCircularIssueParent(err))
+
+	// a pull request can't be a parent
+	pull2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	err = SetIssueParent(issue1, pull2, user2)
+	assert.True(t, IsErrIssueParentNotAllowed(err))
+
+	// issues of the repositories of a user can't be linked
+	issue4 := AssertExistsAndLoadBean(t, &Issue{ID: 4}).(*Issue)
+	err = SetIssueParent(issue4, issue1, user2)
+	assert.True(t, IsErrIssueParentNotAllowed(err))
+
+	assert.NoError(t, SetIssueParent(issue5, nil, user2))
+	issue5 = AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+	assert.EqualValues(t, 0, issue5.ParentID)
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeRemoveParentIssue, PosterID: 2, IssueID: 5, DependentIssueID: 1})
+}
+
+func TestGetIssueTree(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue5 := AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+	pull3 := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+
+	assert.NoError(t, SetIssueParent(issue5, issue1, user2))
+	assert.NoError(t, SetIssueParent(pull3, issue5, user2))
+
+	tree, err := GetIssueTree(issue1, user2)
+	assert.NoError(t, err)
+	if assert.Len(t, tree.Children, 1) && assert.Len(t, tree.Children[0].Children, 1) {
+		assert.EqualValues(t, 5, tree.Children[0].Issue.ID)
+		assert.EqualValues(t, 3, tree.Children[0].Children[0].Issue.ID)
+	}
+	assert.EqualValues(t, 2, tree.NumDescendants())
+	assert.EqualValues(t, 1, tree.NumClosedDescendants())
+	assert.EqualValues(t, 50, tree.Progress())
+
+	// the sub-issues of a private repository are hidden to the users without access
+	issue4 := AssertExistsAndLoadBean(t, &Issue{ID: 4}).(*Issue)
+	issue7 := AssertExistsAndLoadBean(t, &Issue{ID: 7}).(*Issue)
+	assert.NoError(t, SetIssueParent(issue7, issue4, user2))
+
+	tree, err = GetIssueTree(issue4, user2)
+	assert.NoError(t, err)
+	assert.Len(t, tree.Children, 1)
+
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	tree, err = GetIssueTree(issue4, user4)
+	assert.NoError(t, err)
+	assert.Len(t, tree.Children, 0)
+	assert.EqualValues(t, 0, tree.Progress())
+}
+
+func TestDetachCrossRepoSubIssues(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, UpdateIssuesParentID(1, []int64{5, 6}))
+	AssertExistsAndLoadBean(t, &Issue{ID: 6, ParentID: 1})
+
+	assert.NoError(t, detachCrossRepoSubIssues(x, 1))
+	AssertExistsAndLoadBean(t, &Issue{ID: 5, ParentID: 1})
+	issue6 := AssertExistsAndLoadBean(t, &Issue{ID: 6}).(*Issue)
+	assert.EqualValues(t, 0, issue6.ParentID)
+}
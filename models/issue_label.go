@@ -29,6 +29,7 @@ type Label struct {
 	Name            string
 	Description     string
 	Color           string `xorm:"VARCHAR(7)"`
+	Exclusive       bool   `xorm:"NOT NULL DEFAULT false"`
 	NumIssues       int
 	NumClosedIssues int
 	CreatedUnix     timeutil.TimeStamp `xorm:"INDEX created"`
@@ -138,6 +139,72 @@ func (label *Label) BelongsToRepo() bool {
 	return label.RepoID > 0
 }
 
+// Scope returns the part of the name of the label before the last "/",
+// or an empty string if the label is not scoped
+func (label *Label) Scope() string {
+	if i := strings.LastIndex(label.Name, "/"); i > 0 && i < len(label.Name)-1 {
+		return label.Name[:i]
+	}
+	return ""
+}
+
+// ScopedName returns the name of the label without its scope
+func (label *Label) ScopedName() string {
+	if scope := label.Scope(); len(scope) > 0 {
+		return label.Name[len(scope)+1:]
+	}
+	return label.Name
+}
+
+// ExclusiveScope returns the scope of an exclusive label, or an empty string if the label is not exclusive,
+// an issue has at most one of the exclusive labels of a scope
+func (label *Label) ExclusiveScope() string {
+	if !label.Exclusive {
+		return ""
+	}
+	return label.Scope()
+}
+
+// RemoveDuplicateExclusiveLabels keeps only the last label of each exclusive scope,
+// the other labels are kept in their order
+func RemoveDuplicateExclusiveLabels(labels []*Label) []*Label {
+	lastInScope := make(map[string]int64)
+	for _, label := range labels {
+		if scope := label.ExclusiveScope(); len(scope) > 0 {
+			lastInScope[scope] = label.ID
+		}
+	}
+
+	result := make([]*Label, 0, len(labels))
+	for _, label := range labels {
+		if scope := label.ExclusiveScope(); len(scope) > 0 && lastInScope[scope] != label.ID {
+			continue
+		}
+		result = append(result, label)
+	}
+	return result
+}
+
+// CheckExclusiveLabels returns an ErrExclusiveLabelsConflict if several labels share an exclusive scope
+func CheckExclusiveLabels(labels []*Label) error {
+	inScope := make(map[string][]int64)
+	var scopes []string
+	for _, label := range labels {
+		if scope := label.ExclusiveScope(); len(scope) > 0 {
+			if len(inScope[scope]) == 0 {
+				scopes = append(scopes, scope)
+			}
+			inScope[scope] = append(inScope[scope], label.ID)
+		}
+	}
+	for _, scope := range scopes {
+		if len(inScope[scope]) > 1 {
+			return ErrExclusiveLabelsConflict{Scope: scope, LabelIDs: inScope[scope]}
+		}
+	}
+	return nil
+}
+
 // ForegroundColor calculates the text color for labels based
 // on their background color.
 func (label *Label) ForegroundColor() template.CSS {
@@ -251,7 +318,7 @@ func UpdateLabel(l *Label) error {
 	if !LabelColorPattern.MatchString(l.Color) {
 		return fmt.Errorf("bad color code: %s", l.Color)
 	}
-	return updateLabelCols(x, l, "name", "description", "color")
+	return updateLabelCols(x, l, "name", "description", "color", "exclusive")
 }
 
 // DeleteLabel delete a label
@@ -321,7 +388,6 @@ func GetLabelsByIDs(labelIDs []int64) ([]*Label, error) {
 	return labels, x.Table("label").
 		In("id", labelIDs).
 		Asc("name").
-		Cols("id").
 		Find(&labels)
 }
 
@@ -632,7 +698,33 @@ func HasIssueLabel(issueID, labelID int64) bool {
 	return hasIssueLabel(x, issueID, labelID)
 }
 
+// removeDuplicateExclusiveIssueLabels removes from the issue the other labels of the exclusive scope of the label
+func removeDuplicateExclusiveIssueLabels(e *xorm.Session, issue *Issue, label *Label, doer *User) error {
+	scope := label.ExclusiveScope()
+	if len(scope) == 0 {
+		return nil
+	}
+
+	labels, err := getLabelsByIssueID(e, issue.ID)
+	if err != nil {
+		return err
+	}
+	for _, l := range labels {
+		if l.ID == label.ID || l.ExclusiveScope() != scope {
+			continue
+		}
+		if err = deleteIssueLabel(e, issue, l, doer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func newIssueLabel(e *xorm.Session, issue *Issue, label *Label, doer *User) (err error) {
+	if err = removeDuplicateExclusiveIssueLabels(e, issue, label, doer); err != nil {
+		return err
+	}
+
 	if _, err = e.Insert(&IssueLabel{
 		IssueID: issue.ID,
 		LabelID: label.ID,
@@ -659,7 +751,8 @@ func newIssueLabel(e *xorm.Session, issue *Issue, label *Label, doer *User) (err
 	return updateLabelCols(e, label, "num_issues", "num_closed_issue")
 }
 
-// NewIssueLabel creates a new issue-label relation.
+// NewIssueLabel creates a new issue-label relation,
+// the other labels of the exclusive scope of the label are removed from the issue.
 func NewIssueLabel(issue *Issue, label *Label, doer *User) (err error) {
 	if HasIssueLabel(issue.ID, label.ID) {
 		return nil
@@ -684,6 +777,7 @@ func NewIssueLabel(issue *Issue, label *Label, doer *User) (err error) {
 }
 
 func newIssueLabels(e *xorm.Session, issue *Issue, labels []*Label, doer *User) (err error) {
+	labels = RemoveDuplicateExclusiveLabels(labels)
 	for i := range labels {
 		if hasIssueLabel(e, issue.ID, labels[i].ID) {
 			continue
@@ -697,7 +791,8 @@ func newIssueLabels(e *xorm.Session, issue *Issue, labels []*Label, doer *User)
 	return nil
 }
 
-// NewIssueLabels creates a list of issue-label relations.
+// NewIssueLabels creates a list of issue-label relations,
+// the other labels of the exclusive scopes of the labels are removed from the issue.
 func NewIssueLabels(issue *Issue, labels []*Label, doer *User) (err error) {
 	sess := x.NewSession()
 	defer sess.Close()
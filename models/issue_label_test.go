@@ -29,6 +29,49 @@ func TestLabel_ForegroundColor(t *testing.T) {
 	assert.Equal(t, template.CSS("#fff"), label.ForegroundColor())
 }
 
+func TestLabel_Scope(t *testing.T) {
+	for _, test := range []struct {
+		Name, Scope, ScopedName string
+		Exclusive               bool
+		ExclusiveScope          string
+	}{
+		{"bug", "", "bug", true, ""},
+		{"priority/high", "priority", "high", false, ""},
+		{"priority/high", "priority", "high", true, "priority"},
+		{"area/ui/forms", "area/ui", "forms", true, "area/ui"},
+		{"/leading", "", "/leading", true, ""},
+		{"trailing/", "", "trailing/", true, ""},
+	} {
+		label := &Label{Name: test.Name, Exclusive: test.Exclusive}
+		assert.Equal(t, test.Scope, label.Scope(), test.Name)
+		assert.Equal(t, test.ScopedName, label.ScopedName(), test.Name)
+		assert.Equal(t, test.ExclusiveScope, label.ExclusiveScope(), test.Name)
+	}
+}
+
+func TestRemoveDuplicateExclusiveLabels(t *testing.T) {
+	high := &Label{ID: 1, Name: "priority/high", Exclusive: true}
+	low := &Label{ID: 2, Name: "priority/low", Exclusive: true}
+	bug := &Label{ID: 3, Name: "kind/bug"}
+	feature := &Label{ID: 4, Name: "kind/feature"}
+	assert.Equal(t, []*Label{bug, feature, low}, RemoveDuplicateExclusiveLabels([]*Label{high, bug, feature, low}))
+	assert.Equal(t, []*Label{high}, RemoveDuplicateExclusiveLabels([]*Label{high}))
+	assert.Empty(t, RemoveDuplicateExclusiveLabels(nil))
+}
+
+func TestCheckExclusiveLabels(t *testing.T) {
+	high := &Label{ID: 1, Name: "priority/high", Exclusive: true}
+	low := &Label{ID: 2, Name: "priority/low", Exclusive: true}
+	bug := &Label{ID: 3, Name: "kind/bug"}
+	feature := &Label{ID: 4, Name: "kind/feature"}
+	assert.NoError(t, CheckExclusiveLabels([]*Label{high, bug, feature}))
+	assert.NoError(t, CheckExclusiveLabels(nil))
+
+	err := CheckExclusiveLabels([]*Label{low, bug, high})
+	assert.True(t, IsErrExclusiveLabelsConflict(err))
+	assert.Equal(t, ErrExclusiveLabelsConflict{Scope: "priority", LabelIDs: []int64{2, 1}}, err)
+}
+
 func TestNewLabels(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	labels := []*Label{
@@ -345,6 +388,41 @@ func TestNewIssueLabels(t *testing.T) {
 	CheckConsistencyFor(t, &Issue{}, &Label{})
 }
 
+func TestNewIssueLabel_Exclusive(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 6}).(*Issue)
+	orgLabel := AssertExistsAndLoadBean(t, &Label{ID: 3}).(*Label)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	high := &Label{RepoID: 3, Name: "priority/high", Color: "#ff0000", Exclusive: true}
+	low := &Label{RepoID: 3, Name: "priority/low", Color: "#00ff00", Exclusive: true}
+	// an exclusive label of the organization shares the scope of the repository labels
+	medium := &Label{OrgID: 3, Name: "priority/medium", Color: "#0000ff", Exclusive: true}
+	assert.NoError(t, NewLabels(high, low, medium))
+
+	assert.NoError(t, NewIssueLabel(issue, orgLabel, doer))
+	assert.NoError(t, NewIssueLabel(issue, high, doer))
+	AssertExistsAndLoadBean(t, &IssueLabel{IssueID: issue.ID, LabelID: high.ID})
+
+	assert.NoError(t, NewIssueLabel(issue, low, doer))
+	AssertExistsAndLoadBean(t, &IssueLabel{IssueID: issue.ID, LabelID: low.ID})
+	AssertNotExistsBean(t, &IssueLabel{IssueID: issue.ID, LabelID: high.ID})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeLabel, IssueID: issue.ID, LabelID: high.ID, Content: ""})
+	// the other labels of the issue are kept
+	AssertExistsAndLoadBean(t, &IssueLabel{IssueID: issue.ID, LabelID: orgLabel.ID})
+
+	assert.NoError(t, NewIssueLabels(issue, []*Label{high, medium}, doer))
+	AssertExistsAndLoadBean(t, &IssueLabel{IssueID: issue.ID, LabelID: medium.ID})
+	AssertNotExistsBean(t, &IssueLabel{IssueID: issue.ID, LabelID: high.ID})
+	AssertNotExistsBean(t, &IssueLabel{IssueID: issue.ID, LabelID: low.ID})
+
+	assert.NoError(t, issue.ReplaceLabels([]*Label{low, high}, doer))
+	if assert.Len(t, issue.Labels, 1) {
+		assert.EqualValues(t, high.ID, issue.Labels[0].ID)
+	}
+
+	CheckConsistencyFor(t, &Issue{}, &Label{})
+}
+
 func TestDeleteIssueLabel(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	testSuccess := func(labelID, issueID, doerID int64) {
@@ -339,6 +339,10 @@ func DeleteMilestoneByRepoID(repoID, id int64) error {
 		return err
 	}
 
+	if err = deleteMilestoneSnapshots(sess, m.ID); err != nil {
+		return err
+	}
+
 	numMilestones, err := countRepoMilestones(sess, repo.ID)
 	if err != nil {
 		return err
@@ -0,0 +1,199 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// MilestoneSnapshot represents the daily state of a milestone, used to draw burndown charts
+type MilestoneSnapshot struct {
+	ID              int64              `xorm:"pk autoincr"`
+	MilestoneID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Day             timeutil.TimeStamp `xorm:"UNIQUE(s) NOT NULL"` // start of the day
+	NumIssues       int
+	NumClosedIssues int
+	NumOpenIssues   int   `xorm:"-"`
+	TrackedTime     int64 `xorm:"NOT NULL DEFAULT 0"`
+}
+
+// AfterLoad is invoked from XORM after setting the values of all fields of this object.
+func (s *MilestoneSnapshot) AfterLoad() {
+	s.NumOpenIssues = s.NumIssues - s.NumClosedIssues
+}
+
+// snapshotDay returns the start of the day of t in the default UI location
+func snapshotDay(t time.Time) timeutil.TimeStamp {
+	t = t.In(setting.DefaultUILocation)
+	return timeutil.TimeStamp(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).Unix())
+}
+
+// newMilestoneSnapshot returns the current state of the milestone as a snapshot of the given day
+func newMilestoneSnapshot(e Engine, m *Milestone, day timeutil.TimeStamp) (*MilestoneSnapshot, error) {
+	trackedTime, err := getTrackedSeconds(e, FindTrackedTimesOptions{MilestoneID: m.ID})
+	if err != nil {
+		return nil, err
+	}
+	s := &MilestoneSnapshot{
+		MilestoneID:     m.ID,
+		Day:             day,
+		NumIssues:       m.NumIssues,
+		NumClosedIssues: m.NumClosedIssues,
+		TrackedTime:     trackedTime,
+	}
+	s.AfterLoad()
+	return s, nil
+}
+
+// saveMilestoneSnapshot inserts the snapshot or replaces the one already stored for the same day
+func saveMilestoneSnapshot(e Engine, s *MilestoneSnapshot) error {
+	existing := new(MilestoneSnapshot)
+	has, err := e.Where("milestone_id = ? AND day = ?", s.MilestoneID, s.Day).Get(existing)
+	if err != nil {
+		return err
+	} else if !has {
+		_, err = e.Insert(s)
+		return err
+	}
+	s.ID = existing.ID
+	_, err = e.ID(s.ID).Cols("num_issues", "num_closed_issues", "tracked_time").Update(s)
+	return err
+}
+
+// SnapshotMilestones stores the daily snapshot of all open milestones and of those closed since the previous run
+func SnapshotMilestones(ctx context.Context) error {
+	log.Trace("Doing: SnapshotMilestones")
+
+	day := snapshotDay(time.Now())
+	cond := builder.Eq{"is_closed": false}.
+		Or(builder.Gte{"closed_date_unix": day.AddDuration(-24 * time.Hour)})
+	if err := x.Where(cond).Iterate(new(Milestone), func(idx int, bean interface{}) error {
+		m := bean.(*Milestone)
+		select {
+		case <-ctx.Done():
+			return ErrCancelledf("before snapshotting milestone %d", m.ID)
+		default:
+		}
+
+		s, err := newMilestoneSnapshot(x, m, day)
+		if err != nil {
+			return err
+		}
+		return saveMilestoneSnapshot(x, s)
+	}); err != nil {
+		log.Trace("Error: SnapshotMilestones: %v", err)
+		return err
+	}
+
+	log.Trace("Finished: SnapshotMilestones")
+	return nil
+}
+
+// GetMilestoneSnapshots returns the snapshots of a milestone ordered by day
+func GetMilestoneSnapshots(milestoneID int64) ([]*MilestoneSnapshot, error) {
+	snapshots := make([]*MilestoneSnapshot, 0, 30)
+	return snapshots, x.
+		Where("milestone_id = ?", milestoneID).
+		Asc("day").
+		Find(&snapshots)
+}
+
+// GetMilestoneBurndown returns the stored snapshots of a milestone completed by its current state
+// for today when the milestone is still open
+func GetMilestoneBurndown(m *Milestone) ([]*MilestoneSnapshot, error) {
+	snapshots, err := GetMilestoneSnapshots(m.ID)
+	if err != nil {
+		return nil, err
+	}
+	if m.IsClosed {
+		return snapshots, nil
+	}
+
+	current, err := newMilestoneSnapshot(x, m, snapshotDay(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	if n := len(snapshots); n > 0 && snapshots[n-1].Day == current.Day {
+		current.ID = snapshots[n-1].ID
+		snapshots[n-1] = current
+	} else {
+		snapshots = append(snapshots, current)
+	}
+	return snapshots, nil
+}
+
+func deleteMilestoneSnapshots(e Engine, milestoneID int64) error {
+	_, err := e.Where("milestone_id = ?", milestoneID).Delete(new(MilestoneSnapshot))
+	return err
+}
+
+// MilestoneVelocity represents how fast the issues of a closed milestone were completed
+type MilestoneVelocity struct {
+	Milestone       *Milestone
+	Days            int64
+	NumClosedIssues int
+	TrackedTime     int64
+}
+
+// IssuesPerWeek returns the number of issues closed per week during the milestone
+func (v *MilestoneVelocity) IssuesPerWeek() float64 {
+	if v.Days <= 0 {
+		return float64(v.NumClosedIssues)
+	}
+	return float64(v.NumClosedIssues) * 7 / float64(v.Days)
+}
+
+// MilestoneVelocityList is a list of milestone velocities
+type MilestoneVelocityList []*MilestoneVelocity
+
+// AverageIssuesPerWeek returns the mean number of issues closed per week over the milestones
+func (list MilestoneVelocityList) AverageIssuesPerWeek() float64 {
+	if len(list) == 0 {
+		return 0
+	}
+	var total float64
+	for _, v := range list {
+		total += v.IssuesPerWeek()
+	}
+	return total / float64(len(list))
+}
+
+// GetMilestonesVelocity returns the velocity of the last closed milestones of a repository,
+// most recently closed first
+func GetMilestonesVelocity(repoID int64, limit int) (MilestoneVelocityList, error) {
+	milestones := make(MilestoneList, 0, limit)
+	sess := x.Where("repo_id = ? AND is_closed = ?", repoID, true).Desc("closed_date_unix")
+	if limit > 0 {
+		sess.Limit(limit)
+	}
+	if err := sess.Find(&milestones); err != nil {
+		return nil, err
+	}
+	if err := milestones.loadTotalTrackedTimes(x); err != nil {
+		return nil, err
+	}
+
+	list := make(MilestoneVelocityList, 0, len(milestones))
+	for _, m := range milestones {
+		days := int64(m.ClosedDateUnix-m.CreatedUnix) / 86400
+		if days < 1 {
+			days = 1
+		}
+		list = append(list, &MilestoneVelocity{
+			Milestone:       m,
+			Days:            days,
+			NumClosedIssues: m.NumClosedIssues,
+			TrackedTime:     m.TotalTrackedTime,
+		})
+	}
+	return list, nil
+}
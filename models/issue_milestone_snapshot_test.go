@@ -0,0 +1,83 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotMilestones(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, SnapshotMilestones(context.Background()))
+	// running twice the same day updates the snapshots of the day
+	assert.NoError(t, SnapshotMilestones(context.Background()))
+
+	day := snapshotDay(time.Now())
+	snapshots, err := GetMilestoneSnapshots(1)
+	assert.NoError(t, err)
+	if assert.Len(t, snapshots, 1) {
+		assert.Equal(t, day, snapshots[0].Day)
+		assert.Equal(t, 1, snapshots[0].NumIssues)
+		assert.Equal(t, 1, snapshots[0].NumOpenIssues)
+		assert.EqualValues(t, 3682, snapshots[0].TrackedTime)
+	}
+	AssertCount(t, &MilestoneSnapshot{}, 3)
+
+	// closed milestones are not snapshotted
+	snapshots, err = GetMilestoneSnapshots(3)
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 0)
+
+	assert.NoError(t, DeleteMilestoneByRepoID(1, 1))
+	AssertNotExistsBean(t, &MilestoneSnapshot{MilestoneID: 1})
+}
+
+func TestGetMilestoneBurndown(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	milestone := AssertExistsAndLoadBean(t, &Milestone{ID: 1}).(*Milestone)
+	day := snapshotDay(time.Now())
+	assert.NoError(t, saveMilestoneSnapshot(x, &MilestoneSnapshot{MilestoneID: 1, Day: day.AddDuration(-24 * time.Hour), NumIssues: 3, NumClosedIssues: 1}))
+
+	snapshots, err := GetMilestoneBurndown(milestone)
+	assert.NoError(t, err)
+	if assert.Len(t, snapshots, 2) {
+		assert.Equal(t, 2, snapshots[0].NumOpenIssues)
+		// the current state is appended for today without being stored
+		assert.Equal(t, day, snapshots[1].Day)
+		assert.Equal(t, 1, snapshots[1].NumOpenIssues)
+	}
+	AssertCount(t, &MilestoneSnapshot{}, 1)
+
+	closed := AssertExistsAndLoadBean(t, &Milestone{ID: 3}).(*Milestone)
+	snapshots, err = GetMilestoneBurndown(closed)
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 0)
+}
+
+func TestGetMilestonesVelocity(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	velocities, err := GetMilestonesVelocity(1, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, velocities, 1) {
+		assert.EqualValues(t, 3, velocities[0].Milestone.ID)
+		assert.EqualValues(t, 1, velocities[0].Days)
+		assert.Equal(t, 0, velocities[0].NumClosedIssues)
+	}
+
+	list := MilestoneVelocityList{
+		{Days: 14, NumClosedIssues: 4},
+		{Days: 7, NumClosedIssues: 4},
+	}
+	assert.EqualValues(t, 2, list[0].IssuesPerWeek())
+	assert.EqualValues(t, 3, list.AverageIssuesPerWeek())
+	assert.EqualValues(t, 0, MilestoneVelocityList{}.AverageIssuesPerWeek())
+}
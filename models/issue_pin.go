@@ -0,0 +1,105 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/xorm"
+)
+
+// IsPinned returns whether the issue is pinned to the top of the issues of its repository
+func (issue *Issue) IsPinned() bool {
+	return issue.PinOrder > 0
+}
+
+// Pin pins the issue to the top of the issues of its repository
+func (issue *Issue) Pin(doer *User) (err error) {
+	if issue.IsPinned() {
+		return nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	count, err := sess.Where("repo_id = ? AND is_pull = ? AND pin_order > 0", issue.RepoID, issue.IsPull).Count(new(Issue))
+	if err != nil {
+		return err
+	}
+	if count >= int64(setting.Repository.Issue.MaxPinned) {
+		return ErrIssueMaxPinReached{RepoID: issue.RepoID, IsPull: issue.IsPull}
+	}
+
+	issue.PinOrder = int(count) + 1
+	if _, err = sess.ID(issue.ID).Cols("pin_order").NoAutoTime().Update(issue); err != nil {
+		return err
+	}
+	if err = issue.createPinComment(sess, doer, CommentTypePin); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// Unpin unpins the issue
+func (issue *Issue) Unpin(doer *User) (err error) {
+	if !issue.IsPinned() {
+		return nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if err = issue.unpin(sess); err != nil {
+		return err
+	}
+	if err = issue.createPinComment(sess, doer, CommentTypeUnpin); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// unpin unpins the issue and moves up the issues pinned after it
+func (issue *Issue) unpin(e Engine) error {
+	if !issue.IsPinned() {
+		return nil
+	}
+	if _, err := e.Exec("UPDATE `issue` SET pin_order = pin_order - 1 WHERE repo_id = ? AND is_pull = ? AND pin_order > ?",
+		issue.RepoID, issue.IsPull, issue.PinOrder); err != nil {
+		return err
+	}
+	issue.PinOrder = 0
+	_, err := e.ID(issue.ID).Cols("pin_order").NoAutoTime().Update(issue)
+	return err
+}
+
+func (issue *Issue) createPinComment(e *xorm.Session, doer *User, commentType CommentType) error {
+	if err := issue.loadRepo(e); err != nil {
+		return err
+	}
+	_, err := createComment(e, &CreateCommentOptions{
+		Type:  commentType,
+		Doer:  doer,
+		Repo:  issue.Repo,
+		Issue: issue,
+	})
+	return err
+}
+
+// GetPinnedIssues returns the pinned issues or pull requests of a repository in their pinned order
+func GetPinnedIssues(repoID int64, isPull bool) (IssueList, error) {
+	issues := make(IssueList, 0, setting.Repository.Issue.MaxPinned)
+	if err := x.Where("repo_id = ? AND is_pull = ? AND pin_order > 0", repoID, isPull).
+		Asc("pin_order").
+		Find(&issues); err != nil {
+		return nil, err
+	}
+	return issues, issues.LoadAttributes()
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssuePin(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	oldMaxPinned := setting.Repository.Issue.MaxPinned
+	setting.Repository.Issue.MaxPinned = 1
+	defer func() {
+		setting.Repository.Issue.MaxPinned = oldMaxPinned
+	}()
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue5 := AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+
+	assert.NoError(t, issue1.Pin(user2))
+	AssertExistsAndLoadBean(t, &Issue{ID: 1, PinOrder: 1})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypePin, PosterID: 2, IssueID: 1})
+
+	err := issue5.Pin(user2)
+	assert.True(t, IsErrIssueMaxPinReached(err))
+
+	// pull requests are pinned separately
+	pull2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	assert.NoError(t, pull2.Pin(user2))
+
+	setting.Repository.Issue.MaxPinned = 2
+	assert.NoError(t, issue5.Pin(user2))
+	AssertExistsAndLoadBean(t, &Issue{ID: 5, PinOrder: 2})
+
+	issues, err := GetPinnedIssues(1, false)
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 2) {
+		assert.EqualValues(t, 1, issues[0].ID)
+		assert.EqualValues(t, 5, issues[1].ID)
+	}
+
+	// the issues pinned after an unpinned issue move up
+	assert.NoError(t, issue1.Unpin(user2))
+	issue1 = AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.EqualValues(t, 0, issue1.PinOrder)
+	AssertExistsAndLoadBean(t, &Issue{ID: 5, PinOrder: 1})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeUnpin, PosterID: 2, IssueID: 1})
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+// IssueRedirect represents that an issue index of a repository should be redirected to an issue
+// which has been transferred to another repository
+type IssueRedirect struct {
+	ID              int64 `xorm:"pk autoincr"`
+	OldRepoID       int64 `xorm:"UNIQUE(s)"`
+	OldIndex        int64 `xorm:"UNIQUE(s)"`
+	RedirectIssueID int64 `xorm:"INDEX"` // issueID to redirect to
+}
+
+// LookupIssueRedirect look up if an issue index of a repository has a redirect
+func LookupIssueRedirect(repoID, index int64) (int64, error) {
+	redirect := &IssueRedirect{OldRepoID: repoID, OldIndex: index}
+	if has, err := x.Get(redirect); err != nil {
+		return 0, err
+	} else if !has {
+		return 0, ErrIssueRedirectNotExist{RepoID: repoID, Index: index}
+	}
+	return redirect.RedirectIssueID, nil
+}
+
+// newIssueRedirect create a new issue redirect
+func newIssueRedirect(e Engine, repoID, index, issueID int64) error {
+	if err := deleteIssueRedirect(e, repoID, index); err != nil {
+		return err
+	}
+	_, err := e.Insert(&IssueRedirect{
+		OldRepoID:       repoID,
+		OldIndex:        index,
+		RedirectIssueID: issueID,
+	})
+	return err
+}
+
+// deleteIssueRedirect delete any redirect from the specified issue index to
+// another issue
+func deleteIssueRedirect(e Engine, repoID, index int64) error {
+	_, err := e.Delete(&IssueRedirect{OldRepoID: repoID, OldIndex: index})
+	return err
+}
@@ -0,0 +1,263 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// IssueSearchQuery represents a structured issue search query like
+// `is:open label:bug -label:wontfix assignee:@me milestone:"v1.2" author:x updated:>2020-01-01 sort:comments`
+//
+// The supported qualifiers are:
+//
+//	is:open, is:closed, is:issue, is:pr
+//	label:<name>, -label:<name>
+//	assignee:<user>, author:<user>, mentions:<user>, where @me is the signed in user
+//	milestone:<name>
+//	no:label, no:milestone, no:assignee
+//	created:<date range>, updated:<date range>, where a range is one of
+//	  YYYY-MM-DD, >YYYY-MM-DD, >=YYYY-MM-DD, <YYYY-MM-DD, <=YYYY-MM-DD or YYYY-MM-DD..YYYY-MM-DD
+//	sort:<created|updated|comments|due|priority>[-asc|-desc]
+//
+// Values containing spaces can be enclosed in double quotes. The rest of the query is
+// the keyword searched with the issue indexer.
+type IssueSearchQuery struct {
+	Keyword string
+
+	IsClosed util.OptionalBool
+	IsPull   util.OptionalBool
+	SortType string
+
+	Labels         []string
+	ExcludedLabels []string
+	Assignee       string
+	Author         string
+	Mentions       string
+	Milestone      string
+	NoLabel        bool
+	NoMilestone    bool
+	NoAssignee     bool
+
+	// the lower bounds are inclusive and the upper bounds exclusive, 0 means no bound
+	CreatedAfterUnix  int64
+	CreatedBeforeUnix int64
+	UpdatedAfterUnix  int64
+	UpdatedBeforeUnix int64
+}
+
+// issueSearchSortTypes maps the values of the sort qualifier to the issue sort types
+var issueSearchSortTypes = map[string]string{
+	"created":       "newest",
+	"created-desc":  "newest",
+	"created-asc":   "oldest",
+	"updated":       "recentupdate",
+	"updated-desc":  "recentupdate",
+	"updated-asc":   "leastupdate",
+	"comments":      "mostcomment",
+	"comments-desc": "mostcomment",
+	"comments-asc":  "leastcomment",
+	"due":           "nearduedate",
+	"due-asc":       "nearduedate",
+	"due-desc":      "farduedate",
+	"priority":      "priority",
+}
+
+// parseIssueSearchDate parses a date of a query in the default UI location
+func parseIssueSearchDate(value string) (int64, bool) {
+	t, err := time.ParseInLocation("2006-01-02", value, setting.DefaultUILocation)
+	if err != nil {
+		return 0, false
+	}
+	return t.Unix(), true
+}
+
+// parseIssueSearchDateRange parses a date range of a query into an inclusive lower bound and an exclusive upper bound
+func parseIssueSearchDateRange(value string) (after, before int64, ok bool) {
+	const day = 24 * 60 * 60
+	var date int64
+	switch {
+	case strings.Contains(value, ".."):
+		parts := strings.SplitN(value, "..", 2)
+		if after, ok = parseIssueSearchDate(parts[0]); !ok {
+			return 0, 0, false
+		}
+		if before, ok = parseIssueSearchDate(parts[1]); !ok {
+			return 0, 0, false
+		}
+		return after, before + day, true
+	case strings.HasPrefix(value, ">="):
+		after, ok = parseIssueSearchDate(value[2:])
+		return after, 0, ok
+	case strings.HasPrefix(value, ">"):
+		date, ok = parseIssueSearchDate(value[1:])
+		return date + day, 0, ok
+	case strings.HasPrefix(value, "<="):
+		date, ok = parseIssueSearchDate(value[2:])
+		return 0, date + day, ok
+	case strings.HasPrefix(value, "<"):
+		before, ok = parseIssueSearchDate(value[1:])
+		return 0, before, ok
+	}
+	date, ok = parseIssueSearchDate(value)
+	return date, date + day, ok
+}
+
+// ParseIssueSearchQuery parses a structured issue search query, @me refers to the doer which may be nil.
+// The terms which are not valid qualifiers are kept in the keyword.
+func ParseIssueSearchQuery(q string, doer *User) *IssueSearchQuery {
+	query := &IssueSearchQuery{}
+	userName := func(name string) string {
+		if name == "@me" && doer != nil {
+			return doer.LowerName
+		}
+		// user names cannot contain @, so @me of an anonymous user matches nobody
+		return strings.ToLower(strings.TrimPrefix(name, "@"))
+	}
+
+	var keywords []string
+	for _, term := range util.SplitQuotedTerms(q) {
+		idx := strings.IndexByte(term, ':')
+		if idx <= 0 || idx == len(term)-1 {
+			keywords = append(keywords, term)
+			continue
+		}
+		qualifier, value := strings.ToLower(term[:idx]), term[idx+1:]
+
+		valid := true
+		switch qualifier {
+		case "is":
+			switch strings.ToLower(value) {
+			case "open":
+				query.IsClosed = util.OptionalBoolFalse
+			case "closed":
+				query.IsClosed = util.OptionalBoolTrue
+			case "issue":
+				query.IsPull = util.OptionalBoolFalse
+			case "pr", "pull":
+				query.IsPull = util.OptionalBoolTrue
+			default:
+				valid = false
+			}
+		case "label":
+			query.Labels = append(query.Labels, value)
+		case "-label":
+			query.ExcludedLabels = append(query.ExcludedLabels, value)
+		case "assignee":
+			query.Assignee = userName(value)
+		case "author":
+			query.Author = userName(value)
+		case "mentions":
+			query.Mentions = userName(value)
+		case "milestone":
+			query.Milestone = value
+		case "no":
+			switch strings.ToLower(value) {
+			case "label":
+				query.NoLabel = true
+			case "milestone":
+				query.NoMilestone = true
+			case "assignee":
+				query.NoAssignee = true
+			default:
+				valid = false
+			}
+		case "created":
+			var after, before int64
+			if after, before, valid = parseIssueSearchDateRange(value); valid {
+				query.CreatedAfterUnix, query.CreatedBeforeUnix = after, before
+			}
+		case "updated":
+			var after, before int64
+			if after, before, valid = parseIssueSearchDateRange(value); valid {
+				query.UpdatedAfterUnix, query.UpdatedBeforeUnix = after, before
+			}
+		case "sort":
+			var sortType string
+			if sortType, valid = issueSearchSortTypes[strings.ToLower(value)]; valid {
+				query.SortType = sortType
+			}
+		default:
+			valid = false
+		}
+		if !valid {
+			keywords = append(keywords, term)
+		}
+	}
+	query.Keyword = strings.Join(keywords, " ")
+	return query
+}
+
+// toCond returns the condition on the issue table of the filters of the query,
+// except the state of the issues which is applied by the callers
+func (q *IssueSearchQuery) toCond() builder.Cond {
+	cond := builder.NewCond()
+	if q == nil {
+		return cond
+	}
+
+	switch q.IsPull {
+	case util.OptionalBoolTrue:
+		cond = cond.And(builder.Eq{"issue.is_pull": true})
+	case util.OptionalBoolFalse:
+		cond = cond.And(builder.Eq{"issue.is_pull": false})
+	}
+
+	for _, label := range q.Labels {
+		cond = cond.And(builder.In("issue.id", BuildLabelNamesIssueIDsCondition([]string{label})))
+	}
+	if len(q.ExcludedLabels) > 0 {
+		cond = cond.And(builder.NotIn("issue.id", BuildLabelNamesIssueIDsCondition(q.ExcludedLabels)))
+	}
+	if q.NoLabel {
+		cond = cond.And(builder.NotIn("issue.id", builder.Select("issue_id").From("issue_label")))
+	}
+
+	userIDs := func(name string) *builder.Builder {
+		return builder.Select("id").From("`user`").Where(builder.Eq{"lower_name": name})
+	}
+	if len(q.Assignee) > 0 {
+		cond = cond.And(builder.In("issue.id", builder.Select("issue_id").From("issue_assignees").
+			Where(builder.In("assignee_id", userIDs(q.Assignee)))))
+	}
+	if q.NoAssignee {
+		cond = cond.And(builder.NotIn("issue.id", builder.Select("issue_id").From("issue_assignees")))
+	}
+	if len(q.Author) > 0 {
+		cond = cond.And(builder.In("issue.poster_id", userIDs(q.Author)))
+	}
+	if len(q.Mentions) > 0 {
+		cond = cond.And(builder.In("issue.id", builder.Select("issue_id").From("issue_user").
+			Where(builder.Eq{"is_mentioned": true}.And(builder.In("uid", userIDs(q.Mentions))))))
+	}
+
+	if len(q.Milestone) > 0 {
+		cond = cond.And(builder.In("issue.milestone_id", builder.Select("id").From("milestone").
+			Where(builder.Eq{"name": q.Milestone})))
+	}
+	if q.NoMilestone {
+		cond = cond.And(builder.Eq{"issue.milestone_id": 0}.Or(builder.IsNull{"issue.milestone_id"}))
+	}
+
+	if q.CreatedAfterUnix > 0 {
+		cond = cond.And(builder.Gte{"issue.created_unix": q.CreatedAfterUnix})
+	}
+	if q.CreatedBeforeUnix > 0 {
+		cond = cond.And(builder.Lt{"issue.created_unix": q.CreatedBeforeUnix})
+	}
+	if q.UpdatedAfterUnix > 0 {
+		cond = cond.And(builder.Gte{"issue.updated_unix": q.UpdatedAfterUnix})
+	}
+	if q.UpdatedBeforeUnix > 0 {
+		cond = cond.And(builder.Lt{"issue.updated_unix": q.UpdatedBeforeUnix})
+	}
+	return cond
+}
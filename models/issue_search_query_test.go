@@ -0,0 +1,113 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIssueSearchQuery(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	query := ParseIssueSearchQuery(`is:open fix label:bug -label:wontfix assignee:@me milestone:"v1.2 beta" author:User1 crash sort:comments is:unknown`, user)
+	assert.Equal(t, "fix crash is:unknown", query.Keyword)
+	assert.True(t, query.IsClosed.IsFalse())
+	assert.True(t, query.IsPull.IsNone())
+	assert.Equal(t, []string{"bug"}, query.Labels)
+	assert.Equal(t, []string{"wontfix"}, query.ExcludedLabels)
+	assert.Equal(t, "user2", query.Assignee)
+	assert.Equal(t, "user1", query.Author)
+	assert.Equal(t, "v1.2 beta", query.Milestone)
+	assert.Equal(t, "mostcomment", query.SortType)
+
+	query = ParseIssueSearchQuery("assignee:@me", nil)
+	assert.Equal(t, "me", query.Assignee)
+
+	query = ParseIssueSearchQuery("just words", nil)
+	assert.Equal(t, "just words", query.Keyword)
+
+	day := time.Date(2020, 1, 1, 0, 0, 0, 0, setting.DefaultUILocation).Unix()
+	const oneDay = 24 * 60 * 60
+	for _, c := range []struct {
+		Value         string
+		After, Before int64
+	}{
+		{"2020-01-01", day, day + oneDay},
+		{">2020-01-01", day + oneDay, 0},
+		{">=2020-01-01", day, 0},
+		{"<2020-01-01", 0, day},
+		{"<=2020-01-01", 0, day + oneDay},
+		{"2020-01-01..2020-01-02", day, day + 2*oneDay},
+	} {
+		query = ParseIssueSearchQuery("updated:"+c.Value, nil)
+		assert.EqualValues(t, c.After, query.UpdatedAfterUnix, c.Value)
+		assert.EqualValues(t, c.Before, query.UpdatedBeforeUnix, c.Value)
+		assert.Empty(t, query.Keyword)
+	}
+
+	query = ParseIssueSearchQuery("created:yesterday", nil)
+	assert.Equal(t, "created:yesterday", query.Keyword)
+	assert.EqualValues(t, 0, query.CreatedAfterUnix)
+}
+
+func TestIssuesWithSearchQuery(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+
+	for _, test := range []struct {
+		Query    string
+		IssueIDs []int64
+	}{
+		{"label:label1", []int64{1, 2}},
+		{"label:label1 label:orglabel4", []int64{2}},
+		{"-label:label1", []int64{3, 5, 11}},
+		{"no:label", []int64{3, 11}},
+		{"assignee:@me", []int64{1}},
+		{"no:assignee is:issue", []int64{5}},
+		{"author:user2", []int64{5}},
+		{"milestone:milestone1", []int64{2}},
+		{"no:milestone", []int64{1, 5, 11}},
+		{"is:pr", []int64{2, 3, 11}},
+		{"created:>2019-01-01", []int64{11}},
+		{"created:<2001-01-01 -label:label2", []int64{1, 2, 3}},
+		{"author:nobody", []int64{}},
+	} {
+		issues, err := Issues(&IssuesOptions{
+			RepoIDs:     []int64{1},
+			SearchQuery: ParseIssueSearchQuery(test.Query, user),
+		})
+		assert.NoError(t, err)
+		issueIDs := make([]int64, 0, len(issues))
+		for _, issue := range issues {
+			issueIDs = append(issueIDs, issue.ID)
+		}
+		assert.ElementsMatch(t, test.IssueIDs, issueIDs, test.Query)
+	}
+
+	stats, err := GetIssueStats(&IssueStatsOptions{
+		RepoID:      1,
+		SearchQuery: ParseIssueSearchQuery("author:user2", user),
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, stats.OpenCount)
+	assert.EqualValues(t, 1, stats.ClosedCount)
+
+	stats, err = GetUserIssueStats(UserIssueStatsOptions{
+		UserID:      1,
+		UserRepoIDs: []int64{1},
+		FilterMode:  FilterModeAll,
+		IsPull:      true,
+		SearchQuery: ParseIssueSearchQuery("milestone:milestone3", user),
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, stats.OpenCount)
+	assert.EqualValues(t, 0, stats.ClosedCount)
+}
@@ -0,0 +1,215 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SetIssueEstimatedTime sets the estimated time of an issue in seconds and adds a comment. Setting it to 0 means deleting it.
+func SetIssueEstimatedTime(issue *Issue, doer *User, seconds int64) (err error) {
+	if issue.EstimatedTime == seconds {
+		return nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	issue.EstimatedTime = seconds
+	if err = updateIssueCols(sess, issue, "estimated_time"); err != nil {
+		return err
+	}
+
+	if err = issue.loadRepo(sess); err != nil {
+		return err
+	}
+	var content string
+	if seconds > 0 {
+		content = SecToTime(seconds)
+	}
+	if _, err = createComment(sess, &CreateCommentOptions{
+		Type:    CommentTypeChangeTimeEstimate,
+		Doer:    doer,
+		Repo:    issue.Repo,
+		Issue:   issue,
+		Content: content,
+	}); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
+// TrackedTimeGroup is the grouping of a tracked time report
+type TrackedTimeGroup string
+
+// The groupings of a tracked time report
+const (
+	TrackedTimeGroupByIssue     TrackedTimeGroup = "issue"
+	TrackedTimeGroupByUser      TrackedTimeGroup = "user"
+	TrackedTimeGroupByRepo      TrackedTimeGroup = "repo"
+	TrackedTimeGroupByMilestone TrackedTimeGroup = "milestone"
+)
+
+// TrackedTimeReportEntry represents the time tracked by a user on an issue
+type TrackedTimeReportEntry struct {
+	IssueID int64
+	Issue   *Issue `xorm:"-"`
+	UserID  int64
+	User    *User `xorm:"-"`
+	Seconds int64
+}
+
+// TrackedTimeSummary represents the tracked time of a group of a report
+type TrackedTimeSummary struct {
+	ID      int64
+	Name    string
+	Link    string
+	Seconds int64
+	// EstimatedSeconds is the sum of the estimated times of the issues of the group
+	EstimatedSeconds int64
+}
+
+// TrackedTimeReport represents the tracked times aggregated by issue and user
+type TrackedTimeReport struct {
+	Entries      []*TrackedTimeReportEntry
+	TotalSeconds int64
+}
+
+// GetTrackedTimeReport returns the tracked times matching the options aggregated by issue and user
+func GetTrackedTimeReport(opts FindTrackedTimesOptions) (*TrackedTimeReport, error) {
+	opts.ListOptions = ListOptions{}
+
+	report := &TrackedTimeReport{Entries: make([]*TrackedTimeReportEntry, 0, 10)}
+	if err := opts.ToSession(x).Table("tracked_time").
+		Select("tracked_time.issue_id, tracked_time.user_id, SUM(tracked_time.time) AS seconds").
+		GroupBy("tracked_time.issue_id, tracked_time.user_id").
+		Find(&report.Entries); err != nil {
+		return nil, err
+	}
+	if len(report.Entries) == 0 {
+		return report, nil
+	}
+
+	issueIDs := make(map[int64]struct{}, len(report.Entries))
+	userIDs := make(map[int64]struct{}, len(report.Entries))
+	for _, entry := range report.Entries {
+		issueIDs[entry.IssueID] = struct{}{}
+		userIDs[entry.UserID] = struct{}{}
+	}
+
+	issues, err := getIssuesByIDs(x, keysInt64(issueIDs))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = IssueList(issues).loadRepositories(x); err != nil {
+		return nil, err
+	}
+	if err = IssueList(issues).loadMilestones(x); err != nil {
+		return nil, err
+	}
+	issuesMap := make(map[int64]*Issue, len(issues))
+	for _, issue := range issues {
+		issuesMap[issue.ID] = issue
+	}
+
+	users, err := GetUsersByIDs(keysInt64(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	usersMap := make(map[int64]*User, len(users))
+	for _, user := range users {
+		usersMap[user.ID] = user
+	}
+
+	entries := report.Entries[:0]
+	for _, entry := range report.Entries {
+		entry.Issue = issuesMap[entry.IssueID]
+		if entry.Issue == nil || entry.Issue.Repo == nil {
+			continue
+		}
+		if entry.User = usersMap[entry.UserID]; entry.User == nil {
+			entry.User = NewGhostUser()
+		}
+		report.TotalSeconds += entry.Seconds
+		entries = append(entries, entry)
+	}
+	report.Entries = entries
+
+	sort.Slice(report.Entries, func(i, j int) bool {
+		a, b := report.Entries[i], report.Entries[j]
+		if a.Issue.RepoID != b.Issue.RepoID {
+			return a.Issue.Repo.FullName() < b.Issue.Repo.FullName()
+		}
+		if a.Issue.Index != b.Issue.Index {
+			return a.Issue.Index < b.Issue.Index
+		}
+		return a.User.Name < b.User.Name
+	})
+	return report, nil
+}
+
+// TotalEstimatedSeconds returns the sum of the estimated times of the issues of the report
+func (report *TrackedTimeReport) TotalEstimatedSeconds() int64 {
+	var total int64
+	seen := make(map[int64]struct{}, len(report.Entries))
+	for _, entry := range report.Entries {
+		if _, ok := seen[entry.IssueID]; !ok {
+			seen[entry.IssueID] = struct{}{}
+			total += entry.Issue.EstimatedTime
+		}
+	}
+	return total
+}
+
+// Summarize returns the tracked times of the report grouped by issue, user, repository or milestone
+func (report *TrackedTimeReport) Summarize(group TrackedTimeGroup) []*TrackedTimeSummary {
+	summaries := make([]*TrackedTimeSummary, 0, len(report.Entries))
+	summariesMap := make(map[int64]*TrackedTimeSummary, len(report.Entries))
+	seenIssues := make(map[int64]map[int64]struct{}, len(report.Entries))
+	for _, entry := range report.Entries {
+		var id int64
+		var name, link string
+		switch group {
+		case TrackedTimeGroupByUser:
+			id, name, link = entry.UserID, entry.User.Name, entry.User.HTMLURL()
+		case TrackedTimeGroupByRepo:
+			id, name, link = entry.Issue.RepoID, entry.Issue.Repo.FullName(), entry.Issue.Repo.HTMLURL()
+		case TrackedTimeGroupByMilestone:
+			id = entry.Issue.MilestoneID
+			if entry.Issue.Milestone != nil {
+				name = entry.Issue.Milestone.Name
+				link = fmt.Sprintf("%s/milestone/%d", entry.Issue.Repo.HTMLURL(), id)
+			}
+		default:
+			id = entry.IssueID
+			name = fmt.Sprintf("%s#%d %s", entry.Issue.Repo.FullName(), entry.Issue.Index, entry.Issue.Title)
+			link = entry.Issue.HTMLURL()
+		}
+
+		summary, ok := summariesMap[id]
+		if !ok {
+			summary = &TrackedTimeSummary{ID: id, Name: name, Link: link}
+			summariesMap[id] = summary
+			summaries = append(summaries, summary)
+			seenIssues[id] = make(map[int64]struct{})
+		}
+		summary.Seconds += entry.Seconds
+		// the estimate of an issue is not a per-user value
+		if _, seen := seenIssues[id][entry.IssueID]; !seen && group != TrackedTimeGroupByUser {
+			seenIssues[id][entry.IssueID] = struct{}{}
+			summary.EstimatedSeconds += entry.Issue.EstimatedTime
+		}
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Seconds > summaries[j].Seconds
+	})
+	return summaries
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetIssueEstimatedTime(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+
+	assert.NoError(t, SetIssueEstimatedTime(issue1, user2, 3600))
+	AssertExistsAndLoadBean(t, &Issue{ID: 1, EstimatedTime: 3600})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeChangeTimeEstimate, IssueID: 1, Content: "1h"})
+
+	assert.NoError(t, SetIssueEstimatedTime(issue1, user2, 0))
+	issue1 = AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.EqualValues(t, 0, issue1.EstimatedTime)
+}
+
+func TestGetTrackedTimeReport(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	assert.NoError(t, SetIssueEstimatedTime(issue2, user2, 7200))
+
+	report, err := GetTrackedTimeReport(FindTrackedTimesOptions{RepositoryID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, report.Entries, 4)
+	assert.EqualValues(t, 4083, report.TotalSeconds)
+	assert.EqualValues(t, 7200, report.TotalEstimatedSeconds())
+
+	byUser := report.Summarize(TrackedTimeGroupByUser)
+	if assert.Len(t, byUser, 2) {
+		assert.EqualValues(t, 2, byUser[0].ID)
+		assert.EqualValues(t, 3663, byUser[0].Seconds)
+		assert.EqualValues(t, 0, byUser[0].EstimatedSeconds)
+		assert.EqualValues(t, 1, byUser[1].ID)
+		assert.EqualValues(t, 420, byUser[1].Seconds)
+	}
+
+	byIssue := report.Summarize(TrackedTimeGroupByIssue)
+	if assert.Len(t, byIssue, 3) {
+		assert.EqualValues(t, 2, byIssue[0].ID)
+		assert.EqualValues(t, 3682, byIssue[0].Seconds)
+		assert.EqualValues(t, 7200, byIssue[0].EstimatedSeconds)
+	}
+
+	byRepo := report.Summarize(TrackedTimeGroupByRepo)
+	if assert.Len(t, byRepo, 1) {
+		assert.EqualValues(t, "user2/repo1", byRepo[0].Name)
+		assert.EqualValues(t, 4083, byRepo[0].Seconds)
+		assert.EqualValues(t, 7200, byRepo[0].EstimatedSeconds)
+	}
+
+	// filter by user and date range over several repositories
+	report, err = GetTrackedTimeReport(FindTrackedTimesOptions{
+		RepositoryIDs:     []int64{1, 2},
+		UserID:            2,
+		CreatedAfterUnix:  946684802,
+		CreatedBeforeUnix: 946684813,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, report.TotalSeconds)
+	assert.Len(t, report.Summarize(TrackedTimeGroupByRepo), 2)
+}
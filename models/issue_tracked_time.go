@@ -74,6 +74,7 @@ type FindTrackedTimesOptions struct {
 	IssueID           int64
 	UserID            int64
 	RepositoryID      int64
+	RepositoryIDs     []int64
 	MilestoneID       int64
 	CreatedAfterUnix  int64
 	CreatedBeforeUnix int64
@@ -91,6 +92,9 @@ func (opts *FindTrackedTimesOptions) ToCond() builder.Cond {
 	if opts.RepositoryID != 0 {
 		cond = cond.And(builder.Eq{"issue.repo_id": opts.RepositoryID})
 	}
+	if len(opts.RepositoryIDs) > 0 {
+		cond = cond.And(builder.In("issue.repo_id", opts.RepositoryIDs))
+	}
 	if opts.MilestoneID != 0 {
 		cond = cond.And(builder.Eq{"issue.milestone_id": opts.MilestoneID})
 	}
@@ -106,7 +110,7 @@ func (opts *FindTrackedTimesOptions) ToCond() builder.Cond {
 // ToSession will convert the given options to a xorm Session by using the conditions from ToCond and joining with issue table if required
 func (opts *FindTrackedTimesOptions) ToSession(e Engine) Engine {
 	sess := e
-	if opts.RepositoryID > 0 || opts.MilestoneID > 0 {
+	if opts.RepositoryID > 0 || len(opts.RepositoryIDs) > 0 || opts.MilestoneID > 0 {
 		sess = e.Join("INNER", "issue", "issue.id = tracked_time.issue_id")
 	}
 
@@ -0,0 +1,221 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/builder"
+)
+
+// transferIssueLabels replaces the labels of the issue by the labels with the same names
+// in the new repository or in its organization, the labels without match are dropped
+func transferIssueLabels(e Engine, issue *Issue, newRepo *Repository) ([]*Label, error) {
+	labels, err := getLabelsByIssueID(e, issue.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var newLabels []*Label
+	for _, label := range labels {
+		if label.OrgID > 0 && label.OrgID == newRepo.OwnerID {
+			newLabels = append(newLabels, label)
+			continue
+		}
+
+		newLabel, err := getLabelInRepoByName(e, newRepo.ID, label.Name)
+		if IsErrRepoLabelNotExist(err) && newRepo.Owner.IsOrganization() {
+			newLabel, err = getLabelInOrgByName(e, newRepo.OwnerID, label.Name)
+		}
+		if err != nil {
+			if IsErrRepoLabelNotExist(err) || IsErrOrgLabelNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		newLabels = append(newLabels, newLabel)
+	}
+	newLabels = RemoveDuplicateExclusiveLabels(newLabels)
+
+	if _, err = e.Delete(&IssueLabel{IssueID: issue.ID}); err != nil {
+		return nil, err
+	}
+	for _, label := range newLabels {
+		if _, err = e.Insert(&IssueLabel{IssueID: issue.ID, LabelID: label.ID}); err != nil {
+			return nil, err
+		}
+	}
+
+	// update the counters of the old and new labels
+	for _, label := range append(labels, newLabels...) {
+		if err = updateLabelCols(e, label, "num_issues", "num_closed_issue"); err != nil {
+			return nil, err
+		}
+	}
+	return newLabels, nil
+}
+
+// transferIssueMilestone returns the id of the milestone with the same name in the new repository
+func transferIssueMilestone(e Engine, issue *Issue, newRepo *Repository) (int64, error) {
+	if issue.MilestoneID == 0 {
+		return 0, nil
+	}
+	milestone, err := getMilestoneByRepoID(e, issue.RepoID, issue.MilestoneID)
+	if err != nil {
+		if IsErrMilestoneNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	newMilestone := new(Milestone)
+	if has, err := e.Where("repo_id = ? AND name = ?", newRepo.ID, milestone.Name).Get(newMilestone); err != nil || !has {
+		return 0, err
+	}
+	return newMilestone.ID, nil
+}
+
+// transferIssueAssignees removes the assignees who cannot be assigned in the new repository
+func transferIssueAssignees(e Engine, issue *Issue, newRepo *Repository) error {
+	if err := issue.loadAssignees(e); err != nil {
+		return err
+	}
+	for _, assignee := range issue.Assignees {
+		canBeAssigned, err := canBeAssigned(e, assignee, newRepo, issue.IsPull)
+		if err != nil {
+			return err
+		}
+		if !canBeAssigned {
+			if _, err = e.Delete(&IssueAssignees{IssueID: issue.ID, AssigneeID: assignee.ID}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func updateRepoIssueNums(e Engine, repoID int64) error {
+	_, err := e.Exec("UPDATE `repository` SET num_issues=(SELECT count(*) FROM issue WHERE repo_id=? AND is_pull=?),num_closed_issues=(SELECT count(*) FROM issue WHERE repo_id=? AND is_pull=? AND is_closed=?) WHERE id=?",
+		repoID,
+		false,
+		repoID,
+		false,
+		true,
+		repoID,
+	)
+	return err
+}
+
+// TransferIssue moves an issue with its comments, reactions, attachments and tracked times to another repository,
+// the labels and the milestone are replaced by the ones with the same names in the new repository
+// and the old index of the issue is redirected to the issue.
+func TransferIssue(doer *User, issue *Issue, newRepo *Repository) (err error) {
+	if issue.IsPull {
+		return ErrIssueTransferNotAllowed{IssueID: issue.ID, RepoID: newRepo.ID, Reason: "pull requests cannot be transferred"}
+	}
+	if issue.RepoID == newRepo.ID {
+		return ErrIssueTransferNotAllowed{IssueID: issue.ID, RepoID: newRepo.ID, Reason: "the issue is already in the repository"}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if err = issue.loadRepo(sess); err != nil {
+		return err
+	}
+	if err = newRepo.getOwner(sess); err != nil {
+		return err
+	}
+	oldRepo := issue.Repo
+	oldIndex := issue.Index
+	oldMilestoneID := issue.MilestoneID
+
+	var maxIndex int64
+	if _, err = sess.Table("issue").Where("repo_id = ?", newRepo.ID).Select("coalesce(MAX(`index`),0)").Get(&maxIndex); err != nil {
+		return err
+	}
+
+	if err = issue.unpin(sess); err != nil {
+		return err
+	}
+	if issue.Labels, err = transferIssueLabels(sess, issue, newRepo); err != nil {
+		return err
+	}
+	if issue.MilestoneID, err = transferIssueMilestone(sess, issue, newRepo); err != nil {
+		return err
+	}
+	if err = transferIssueAssignees(sess, issue, newRepo); err != nil {
+		return err
+	}
+	if _, err = sess.Delete(&ProjectIssue{IssueID: issue.ID}); err != nil {
+		return err
+	}
+
+	// sub-issues can only be linked to issues of repositories of the same organization
+	if oldRepo.OwnerID != newRepo.OwnerID || !newRepo.Owner.IsOrganization() {
+		issue.ParentID = 0
+		if _, err = sess.Where("parent_id = ?", issue.ID).Cols("parent_id").NoAutoTime().Update(&Issue{ParentID: 0}); err != nil {
+			return err
+		}
+	}
+	if !setting.Service.AllowCrossRepositoryDependencies {
+		if _, err = sess.Where(builder.Eq{"issue_id": issue.ID}.Or(builder.Eq{"dependency_id": issue.ID})).
+			Delete(new(IssueDependency)); err != nil {
+			return err
+		}
+	}
+
+	issue.RepoID = newRepo.ID
+	issue.Repo = newRepo
+	issue.Index = maxIndex + 1
+	issue.Ref = ""
+	if err = updateIssueCols(sess, issue, "repo_id", "index", "milestone_id", "parent_id", "ref"); err != nil {
+		return err
+	}
+	if _, err = sess.Exec("UPDATE `notification` SET repo_id = ? WHERE issue_id = ?", newRepo.ID, issue.ID); err != nil {
+		return err
+	}
+
+	for _, repoID := range []int64{oldRepo.ID, newRepo.ID} {
+		if err = updateRepoIssueNums(sess, repoID); err != nil {
+			return err
+		}
+	}
+	for _, milestoneID := range []int64{oldMilestoneID, issue.MilestoneID} {
+		if milestoneID == 0 {
+			continue
+		}
+		if err = updateMilestoneTotalNum(sess, milestoneID); err != nil {
+			return err
+		}
+		if err = updateMilestoneClosedNum(sess, milestoneID); err != nil {
+			return err
+		}
+	}
+
+	if err = deleteIssueRedirect(sess, newRepo.ID, issue.Index); err != nil {
+		return err
+	}
+	if err = newIssueRedirect(sess, oldRepo.ID, oldIndex, issue.ID); err != nil {
+		return err
+	}
+
+	if _, err = createComment(sess, &CreateCommentOptions{
+		Type:    CommentTypeTransferIssue,
+		Doer:    doer,
+		Repo:    newRepo,
+		Issue:   issue,
+		Content: fmt.Sprintf("%s#%d", oldRepo.FullName(), oldIndex),
+	}); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferIssue(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo2 := AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+
+	label := &Label{RepoID: repo2.ID, Name: "label1", Color: "#abcdef"}
+	assert.NoError(t, NewLabel(label))
+
+	assert.NoError(t, TransferIssue(user2, issue1, repo2))
+
+	issue1 = AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.EqualValues(t, repo2.ID, issue1.RepoID)
+	assert.EqualValues(t, 3, issue1.Index)
+
+	// the labels are remapped by name
+	AssertExistsAndLoadBean(t, &IssueLabel{IssueID: 1, LabelID: label.ID})
+	AssertNotExistsBean(t, &IssueLabel{IssueID: 1, LabelID: 1})
+
+	// the comments stay attached to the issue
+	AssertExistsAndLoadBean(t, &Comment{ID: 2, IssueID: 1})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeTransferIssue, PosterID: 2, IssueID: 1, Content: "user2/repo1#1"})
+
+	issueID, err := LookupIssueRedirect(1, 1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, issueID)
+
+	repo1 := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.EqualValues(t, 1, repo1.NumIssues)
+	repo2 = AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+	assert.EqualValues(t, 3, repo2.NumIssues)
+
+	// a new issue at the old index replaces the redirect
+	assert.NoError(t, newIssueRedirect(x, 2, 4, 1))
+	issue := &Issue{RepoID: repo2.ID, PosterID: 2, Title: "new issue"}
+	assert.NoError(t, NewIssue(repo2, issue, nil, nil))
+	assert.EqualValues(t, 4, issue.Index)
+	_, err = LookupIssueRedirect(2, 4)
+	assert.True(t, IsErrIssueRedirectNotExist(err))
+
+	// pull requests cannot be transferred
+	pull2 := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	err = TransferIssue(user2, pull2, repo2)
+	assert.True(t, IsErrIssueTransferNotAllowed(err))
+}
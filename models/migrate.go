@@ -51,6 +51,12 @@ func InsertIssues(issues ...*Issue) error {
 	return sess.Commit()
 }
 
+// UpdateIssuesParentID sets the parent of migrated issues without creating comments
+func UpdateIssuesParentID(parentID int64, issueIDs []int64) error {
+	_, err := x.In("id", issueIDs).Cols("parent_id").NoAutoTime().Update(&Issue{ParentID: parentID})
+	return err
+}
+
 func insertIssue(sess *xorm.Session, issue *Issue) error {
 	if _, err := sess.NoAutoTime().Insert(issue); err != nil {
 		return err
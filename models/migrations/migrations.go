@@ -254,6 +254,34 @@ var migrations = []Migration{
 	NewMigration("code comment replies should have the commitID of the review they are replying to", updateCodeCommentReplies),
 	// v159 -> v160
 	NewMigration("update reactions constraint", updateReactionConstraint),
+	// v160 -> v161
+	NewMigration("add verified column to public_key table", addVerifiedToPublicKey),
+	// v161 -> v162
+	NewMigration("add review_state table", addReviewStateTable),
+	// v162 -> v163
+	NewMigration("add start_line column to comment table", addStartLineToComment),
+	// v163 -> v164
+	NewMigration("add code indexer refs", addCodeIndexerRefs),
+	// v164 -> v165
+	NewMigration("add issue filters", addIssueFilters),
+	// v165 -> v166
+	NewMigration("add exclusive column to label table", addLabelExclusive),
+	// v166 -> v167
+	NewMigration("add parent_id column to issue table", addIssueParentID),
+	// v167 -> v168
+	NewMigration("add pin_order column to issue table and create issue_redirect table", addIssuePinOrderAndRedirect),
+	// v168 -> v169
+	NewMigration("add estimated_time column to issue table", addIssueEstimatedTime),
+	// v169 -> v170
+	NewMigration("create milestone_snapshot table", createMilestoneSnapshotTable),
+	// v170 -> v171
+	NewMigration("add email notification opt-outs and digests", addEmailNotificationEventsAndDigests),
+	// v171 -> v172
+	NewMigration("add storage_volume and storage_layout columns to repository table", addRepositoryStorageLocation),
+	// v172 -> v173
+	NewMigration("create cron task settings, cron task run and resource lock tables", createCronTaskTables),
+	// v173 -> v174
+	NewMigration("create pending_upload table", createPendingUploadTable),
 }
 
 // GetCurrentDBVersion returns the current db version
@@ -0,0 +1,28 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addReviewStateTable(x *xorm.Engine) error {
+	type ReviewState struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UserID      int64              `xorm:"NOT NULL UNIQUE(pull_user)"`
+		PullID      int64              `xorm:"NOT NULL UNIQUE(pull_user)"`
+		ViewedFiles map[string]string  `xorm:"TEXT JSON"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(ReviewState)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
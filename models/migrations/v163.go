@@ -0,0 +1,26 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addCodeIndexerRefs(x *xorm.Engine) error {
+	type RepoIndexerStatus struct {
+		Ref string `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+	}
+
+	type Repository struct {
+		CodeIndexerRefs []string `xorm:"TEXT JSON"`
+	}
+
+	if err := x.Sync2(new(RepoIndexerStatus)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return x.Sync2(new(Repository))
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueFilters(x *xorm.Engine) error {
+	type IssueFilter struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OwnerID     int64              `xorm:"INDEX NOT NULL"`
+		Name        string             `xorm:"NOT NULL"`
+		Query       string             `xorm:"TEXT"`
+		RepoIDs     []int64            `xorm:"TEXT JSON"`
+		IsPull      bool               `xorm:"NOT NULL DEFAULT false"`
+		IsPinned    bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	type IssueFilterSubscription struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UserID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		FilterID    int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	if err := x.Sync2(new(IssueFilter)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return x.Sync2(new(IssueFilterSubscription))
+}
@@ -0,0 +1,25 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"xorm.io/xorm"
+)
+
+func addIssuePinOrderAndRedirect(x *xorm.Engine) error {
+	type Issue struct {
+		PinOrder int `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	// IssueRedirect represents that an issue index of a repository should be redirected to an issue
+	type IssueRedirect struct {
+		ID              int64 `xorm:"pk autoincr"`
+		OldRepoID       int64 `xorm:"UNIQUE(s)"`
+		OldIndex        int64 `xorm:"UNIQUE(s)"`
+		RedirectIssueID int64 `xorm:"INDEX"`
+	}
+
+	return x.Sync2(new(Issue), new(IssueRedirect))
+}
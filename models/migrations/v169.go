@@ -0,0 +1,24 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createMilestoneSnapshotTable(x *xorm.Engine) error {
+	type MilestoneSnapshot struct {
+		ID              int64              `xorm:"pk autoincr"`
+		MilestoneID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Day             timeutil.TimeStamp `xorm:"UNIQUE(s) NOT NULL"`
+		NumIssues       int
+		NumClosedIssues int
+		TrackedTime     int64 `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(MilestoneSnapshot))
+}
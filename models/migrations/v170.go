@@ -0,0 +1,34 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addEmailNotificationEventsAndDigests(x *xorm.Engine) error {
+	type User struct {
+		EmailNotificationsDigest string `xorm:"VARCHAR(20) NOT NULL DEFAULT 'none'"`
+	}
+
+	type EmailNotificationOptOut struct {
+		ID     int64  `xorm:"pk autoincr"`
+		UserID int64  `xorm:"UNIQUE(s) NOT NULL"`
+		Event  string `xorm:"VARCHAR(50) UNIQUE(s) NOT NULL"`
+	}
+
+	type EmailDigestItem struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UserID      int64              `xorm:"INDEX NOT NULL"`
+		Event       string             `xorm:"VARCHAR(50) NOT NULL"`
+		Subject     string             `xorm:"TEXT"`
+		Link        string             `xorm:"TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync2(new(User), new(EmailNotificationOptOut), new(EmailDigestItem))
+}
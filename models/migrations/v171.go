@@ -0,0 +1,18 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"xorm.io/xorm"
+)
+
+func addRepositoryStorageLocation(x *xorm.Engine) error {
+	type Repository struct {
+		StorageVolume string `xorm:"VARCHAR(255) INDEX NOT NULL DEFAULT ''"`
+		StorageLayout string `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+	}
+
+	return x.Sync2(new(Repository))
+}
@@ -0,0 +1,41 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createCronTaskTables(x *xorm.Engine) error {
+	type CronTask struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Name        string             `xorm:"VARCHAR(255) UNIQUE NOT NULL"`
+		Enabled     bool               `xorm:"NOT NULL DEFAULT false"`
+		Schedule    string             `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type CronTaskRun struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Name        string             `xorm:"VARCHAR(255) INDEX NOT NULL"`
+		DoerID      int64              `xorm:"NOT NULL DEFAULT 0"`
+		DoerName    string             `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+		Status      int                `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Message     string             `xorm:"TEXT"`
+		Instance    string             `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+		StartedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+		EndedUnix   timeutil.TimeStamp
+	}
+
+	type ResourceLock struct {
+		Name        string             `xorm:"pk VARCHAR(255)"`
+		Owner       string             `xorm:"VARCHAR(255) NOT NULL"`
+		ExpiresUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	}
+
+	return x.Sync2(new(CronTask), new(CronTaskRun), new(ResourceLock))
+}
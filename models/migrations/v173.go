@@ -0,0 +1,24 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createPendingUploadTable(x *xorm.Engine) error {
+	type PendingUpload struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Storage     string             `xorm:"VARCHAR(255) NOT NULL"`
+		Path        string             `xorm:"VARCHAR(255) INDEX NOT NULL"`
+		UploaderID  int64              `xorm:"NOT NULL DEFAULT 0"`
+		ExpiresUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync2(new(PendingUpload))
+}
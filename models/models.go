@@ -122,6 +122,7 @@ func init() {
 		new(U2FRegistration),
 		new(TeamUnit),
 		new(Review),
+		new(ReviewState),
 		new(OAuth2Application),
 		new(OAuth2AuthorizationCode),
 		new(OAuth2Grant),
@@ -131,6 +132,16 @@ func init() {
 		new(Project),
 		new(ProjectBoard),
 		new(ProjectIssue),
+		new(IssueFilter),
+		new(IssueFilterSubscription),
+		new(IssueRedirect),
+		new(MilestoneSnapshot),
+		new(EmailNotificationOptOut),
+		new(EmailDigestItem),
+		new(CronTask),
+		new(CronTaskRun),
+		new(ResourceLock),
+		new(PendingUpload),
 	)
 
 	gonicNames := []string{"SSL", "UID"}
@@ -196,6 +207,7 @@ func SetEngine() (err error) {
 	x.SetMaxOpenConns(setting.Database.MaxOpenConns)
 	x.SetMaxIdleConns(setting.Database.MaxIdleConns)
 	x.SetConnMaxLifetime(setting.Database.ConnMaxLifetime)
+	x.AddHook(tracingHook{})
 	return nil
 }
 
@@ -0,0 +1,97 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// The storages the clients can upload to directly
+const (
+	PendingUploadAttachments = "attachments"
+	PendingUploadLFS         = "lfs"
+)
+
+// PendingUpload represents content a client has been allowed to upload to a storage directly,
+// the content left at the path is deleted by the cron task once the upload has expired
+// as the upload URL can still be used after the upload has been completed
+type PendingUpload struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Storage     string             `xorm:"VARCHAR(255) NOT NULL"`
+	Path        string             `xorm:"VARCHAR(255) INDEX NOT NULL"`
+	UploaderID  int64              `xorm:"NOT NULL DEFAULT 0"`
+	ExpiresUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func (u *PendingUpload) objectStorage() storage.ObjectStorage {
+	switch u.Storage {
+	case PendingUploadAttachments:
+		return storage.Attachments
+	case PendingUploadLFS:
+		return storage.LFS
+	}
+	return nil
+}
+
+// NewPendingUpload records content being uploaded to the storage directly until the expiry
+func NewPendingUpload(storageName, path string, uploaderID int64, expiry time.Duration) error {
+	_, err := x.Insert(&PendingUpload{
+		Storage:     storageName,
+		Path:        path,
+		UploaderID:  uploaderID,
+		ExpiresUnix: timeutil.TimeStampNow().AddDuration(expiry),
+	})
+	return err
+}
+
+// IsPendingUpload returns whether the uploader has been allowed to upload the content to the storage
+// and the upload hasn't been deleted yet
+func IsPendingUpload(storageName, path string, uploaderID int64) (bool, error) {
+	return x.Exist(&PendingUpload{Storage: storageName, Path: path, UploaderID: uploaderID})
+}
+
+// DeleteExpiredPendingUploads deletes the content and the pending uploads expired for more than olderThan
+func DeleteExpiredPendingUploads(ctx context.Context, olderThan time.Duration) error {
+	expired := timeutil.TimeStampNow().AddDuration(-olderThan)
+	for {
+		uploads := make([]*PendingUpload, 0, 50)
+		if err := x.Where("expires_unix < ?", expired).Asc("id").Limit(50).Find(&uploads); err != nil {
+			return err
+		} else if len(uploads) == 0 {
+			return nil
+		}
+
+		for _, upload := range uploads {
+			select {
+			case <-ctx.Done():
+				return ErrCancelledf("before deleting the pending upload %s of %s", upload.Path, upload.Storage)
+			default:
+			}
+
+			// the content may have been allowed to be uploaded again since
+			pending, err := x.Where("storage = ? AND path = ? AND expires_unix >= ?", upload.Storage, upload.Path, expired).
+				Exist(new(PendingUpload))
+			if err != nil {
+				return err
+			}
+			if objectStorage := upload.objectStorage(); !pending && objectStorage != nil {
+				if err := objectStorage.Delete(upload.Path); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+				log.Trace("Deleted the content of the expired pending upload %s of %s", upload.Path, upload.Storage)
+			}
+			if _, err := x.ID(upload.ID).Delete(new(PendingUpload)); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteExpiredPendingUploads(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	save := func(p string) {
+		_, err := storage.Attachments.Save(p, strings.NewReader("content"))
+		assert.NoError(t, err)
+	}
+	exists := func(p string) bool {
+		_, err := storage.Attachments.Stat(p)
+		return err == nil
+	}
+
+	const expiredPath, pendingPath, reallowedPath = "pending/expired", "pending/pending", "pending/reallowed"
+	for _, p := range []string{expiredPath, pendingPath, reallowedPath} {
+		save(p)
+	}
+	assert.NoError(t, NewPendingUpload(PendingUploadAttachments, expiredPath, 2, -2*time.Hour))
+	assert.NoError(t, NewPendingUpload(PendingUploadAttachments, pendingPath, 2, 15*time.Minute))
+	assert.NoError(t, NewPendingUpload(PendingUploadAttachments, reallowedPath, 2, -2*time.Hour))
+	assert.NoError(t, NewPendingUpload(PendingUploadAttachments, reallowedPath, 2, 15*time.Minute))
+
+	assert.NoError(t, DeleteExpiredPendingUploads(context.Background(), time.Hour))
+	assert.False(t, exists(expiredPath))
+	assert.True(t, exists(pendingPath))
+	assert.True(t, exists(reallowedPath))
+
+	pending, err := IsPendingUpload(PendingUploadAttachments, expiredPath, 2)
+	assert.NoError(t, err)
+	assert.False(t, pending)
+	pending, err = IsPendingUpload(PendingUploadAttachments, pendingPath, 2)
+	assert.NoError(t, err)
+	assert.True(t, pending)
+	pending, err = IsPendingUpload(PendingUploadAttachments, pendingPath, 1)
+	assert.NoError(t, err)
+	assert.False(t, pending)
+	AssertCount(t, &PendingUpload{Path: reallowedPath}, 1)
+}
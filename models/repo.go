@@ -231,11 +231,17 @@ type Repository struct {
 	TemplateRepo                    *Repository        `xorm:"-"`
 	Size                            int64              `xorm:"NOT NULL DEFAULT 0"`
 	CodeIndexerStatus               *RepoIndexerStatus `xorm:"-"`
+	CodeIndexerRefs                 []string           `xorm:"TEXT JSON"`
 	StatsIndexerStatus              *RepoIndexerStatus `xorm:"-"`
 	IsFsckEnabled                   bool               `xorm:"NOT NULL DEFAULT true"`
 	CloseIssuesViaCommitInAnyBranch bool               `xorm:"NOT NULL DEFAULT false"`
 	Topics                          []string           `xorm:"TEXT JSON"`
 
+	// StorageVolume and StorageLayout locate the git repositories,
+	// empty values are the default volume and the name layout
+	StorageVolume string `xorm:"VARCHAR(255) INDEX NOT NULL DEFAULT ''"`
+	StorageLayout string `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+
 	TrustModel TrustModelType
 
 	// Avatar: ID(10-20)-md5(32) - must fit into 64 symbols
@@ -819,11 +825,6 @@ func (repo *Repository) getTemplateRepo(e Engine) (err error) {
 	return err
 }
 
-// RepoPath returns the repository path
-func (repo *Repository) RepoPath() string {
-	return RepoPath(repo.OwnerName, repo.Name)
-}
-
 // GitConfigPath returns the path to a repository's git config/ directory
 func GitConfigPath(repoPath string) string {
 	return filepath.Join(repoPath, "config")
@@ -1000,11 +1001,12 @@ func (repo *Repository) DescriptionHTML() template.HTML {
 }
 
 func isRepositoryExist(e Engine, u *User, repoName string) (bool, error) {
-	has, err := e.Get(&Repository{
+	repo := &Repository{
 		OwnerID:   u.ID,
 		LowerName: strings.ToLower(repoName),
-	})
-	return has && com.IsDir(RepoPath(u.Name, repoName)), err
+	}
+	has, err := e.Get(repo)
+	return has && com.IsDir(repo.RepoPath()), err
 }
 
 // IsRepositoryExist returns true if the repository with given name under user has already existed.
@@ -1078,7 +1080,7 @@ func CheckCreateRepository(doer, u *User, name string, overwriteOrAdopt bool) er
 		return ErrRepoAlreadyExist{u.Name, name}
 	}
 
-	if !overwriteOrAdopt && com.IsExist(RepoPath(u.Name, name)) {
+	if !overwriteOrAdopt && newRepoStorageExists(u.Name, name) {
 		return ErrRepoFilesAlreadyExist{u.Name, name}
 	}
 	return nil
@@ -1155,7 +1157,23 @@ func CreateRepository(ctx DBContext, doer, u *User, repo *Repository, overwriteO
 		return ErrRepoAlreadyExist{u.Name, repo.Name}
 	}
 
-	repoPath := RepoPath(u.Name, repo.Name)
+	// The adopted repositories stay where they were found, in the name layout of the default volume
+	if !overwriteOrAdopt {
+		volume, err := pickRepoStorageVolume(ctx.e)
+		if err != nil {
+			return fmt.Errorf("pickRepoStorageVolume: %v", err)
+		}
+		if repo.StorageVolume, repo.StorageLayout, err = normalizeRepoStorage(volume, setting.RepoStorage.Layout); err != nil {
+			return err
+		}
+	}
+
+	if _, err = ctx.e.Insert(repo); err != nil {
+		return err
+	}
+
+	// The path of the repositories in the id layout is only known once they are inserted
+	repoPath := repo.RepoPath()
 	if !overwriteOrAdopt && com.IsExist(repoPath) {
 		log.Error("Files already exist in %s and we are not going to adopt or delete.", repoPath)
 		return ErrRepoFilesAlreadyExist{
@@ -1163,10 +1181,6 @@ func CreateRepository(ctx DBContext, doer, u *User, repo *Repository, overwriteO
 			Name:  repo.Name,
 		}
 	}
-
-	if _, err = ctx.e.Insert(repo); err != nil {
-		return err
-	}
 	if err = deleteRepoRedirect(ctx.e, u.ID, repo.Name); err != nil {
 		return err
 	}
@@ -1286,11 +1300,6 @@ func CountUserRepositories(userID int64, private bool) int64 {
 	return countRepositories(userID, private)
 }
 
-// RepoPath returns repository path by given user and repository name.
-func RepoPath(userName, repoName string) string {
-	return filepath.Join(UserPath(userName), strings.ToLower(repoName)+".git")
-}
-
 // IncrementRepoForkNum increment repository fork number
 func IncrementRepoForkNum(ctx DBContext, repoID int64) error {
 	_, err := ctx.e.Exec("UPDATE `repository` SET num_forks=num_forks+1 WHERE id=?", repoID)
@@ -1319,6 +1328,7 @@ func TransferOwnership(doer *User, newOwnerName string, repo *Repository) error
 	}
 
 	oldOwner := repo.Owner
+	oldRepoPath, oldWikiPath := repo.storagePaths()
 
 	// Note: we have to set value here to make sure recalculate accesses is based on
 	// new owner.
@@ -1331,6 +1341,11 @@ func TransferOwnership(doer *User, newOwnerName string, repo *Repository) error
 		return fmt.Errorf("update owner: %v", err)
 	}
 
+	// Sub-issues can only be linked to issues of repositories of the same owner.
+	if err := detachCrossRepoSubIssues(sess, repo.ID); err != nil {
+		return fmt.Errorf("detachCrossRepoSubIssues: %v", err)
+	}
+
 	// Remove redundant collaborators.
 	collaborators, err := repo.getCollaborators(sess, ListOptions{})
 	if err != nil {
@@ -1395,23 +1410,10 @@ func TransferOwnership(doer *User, newOwnerName string, repo *Repository) error
 		}
 	}
 
-	// Rename remote repository to new path and delete local copy.
-	dir := UserPath(newOwner.Name)
-
-	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-		return fmt.Errorf("Failed to create dir %s: %v", dir, err)
-	}
-
-	if err = os.Rename(RepoPath(oldOwner.Name, repo.Name), RepoPath(newOwner.Name, repo.Name)); err != nil {
-		return fmt.Errorf("rename repository directory: %v", err)
-	}
-
-	// Rename remote wiki repository to new path and delete local copy.
-	wikiPath := WikiPath(oldOwner.Name, repo.Name)
-	if com.IsExist(wikiPath) {
-		if err = os.Rename(wikiPath, WikiPath(newOwner.Name, repo.Name)); err != nil {
-			return fmt.Errorf("rename repository wiki: %v", err)
-		}
+	// Rename remote repository and wiki to new path, the repositories in the id layout don't move.
+	newRepoPath, newWikiPath := repo.storagePaths()
+	if err = moveRepoStorage(oldRepoPath, oldWikiPath, newRepoPath, newWikiPath); err != nil {
+		return err
 	}
 
 	// If there was previously a redirect at this location, remove it.
@@ -1445,16 +1447,12 @@ func ChangeRepositoryName(doer *User, repo *Repository, newRepoName string) (err
 		return ErrRepoAlreadyExist{repo.Owner.Name, newRepoName}
 	}
 
-	newRepoPath := RepoPath(repo.Owner.Name, newRepoName)
-	if err = os.Rename(repo.RepoPath(), newRepoPath); err != nil {
-		return fmt.Errorf("rename repository directory: %v", err)
-	}
-
-	wikiPath := repo.WikiPath()
-	if com.IsExist(wikiPath) {
-		if err = os.Rename(wikiPath, WikiPath(repo.Owner.Name, newRepoName)); err != nil {
-			return fmt.Errorf("rename repository wiki: %v", err)
-		}
+	oldRepoPath, oldWikiPath := repo.storagePaths()
+	renamed := *repo
+	renamed.Name = newRepoName
+	newRepoPath, newWikiPath := renamed.storagePaths()
+	if err = moveRepoStorage(oldRepoPath, oldWikiPath, newRepoPath, newWikiPath); err != nil {
+		return err
 	}
 
 	sess := x.NewSession()
@@ -1676,6 +1674,16 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		return err
 	}
 
+	if _, err = sess.In("milestone_id", builder.Select("id").From("milestone").Where(builder.Eq{"repo_id": repoID})).
+		Delete(new(MilestoneSnapshot)); err != nil {
+		return err
+	}
+
+	if _, err = sess.In("pull_id", builder.Select("id").From("pull_request").Where(builder.Eq{"base_repo_id": repoID})).
+		Delete(new(ReviewState)); err != nil {
+		return err
+	}
+
 	if err = deleteBeans(sess,
 		&Access{RepoID: repo.ID},
 		&Action{RepoID: repo.ID},
@@ -21,12 +21,13 @@ const (
 )
 
 // RepoIndexerStatus status of a repo's entry in the repo indexer
-// For now, implicitly refers to default branch
+// An empty Ref refers to the default branch
 type RepoIndexerStatus struct {
 	ID          int64           `xorm:"pk autoincr"`
 	RepoID      int64           `xorm:"INDEX(s)"`
 	CommitSha   string          `xorm:"VARCHAR(40)"`
 	IndexerType RepoIndexerType `xorm:"INDEX(s) NOT NULL DEFAULT 0"`
+	Ref         string          `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
 }
 
 // GetUnindexedRepos returns repos which do not have an indexer status
@@ -37,7 +38,7 @@ func GetUnindexedRepos(indexerType RepoIndexerType, maxRepoID int64, page, pageS
 	}).And(builder.Eq{
 		"repository.is_empty": false,
 	})
-	sess := x.Table("repository").Join("LEFT OUTER", "repo_indexer_status", "repository.id = repo_indexer_status.repo_id AND repo_indexer_status.indexer_type = ?", indexerType)
+	sess := x.Table("repository").Join("LEFT OUTER", "repo_indexer_status", "repository.id = repo_indexer_status.repo_id AND repo_indexer_status.indexer_type = ? AND repo_indexer_status.ref = ''", indexerType)
 	if maxRepoID > 0 {
 		cond = builder.And(cond, builder.Lte{
 			"repository.id": maxRepoID,
@@ -69,7 +70,7 @@ func (repo *Repository) getIndexerStatus(e Engine, indexerType RepoIndexerType)
 		}
 	}
 	status := &RepoIndexerStatus{RepoID: repo.ID}
-	if has, err := e.Where("`indexer_type` = ?", indexerType).Get(status); err != nil {
+	if has, err := e.Where("`indexer_type` = ? AND `ref` = ''", indexerType).Get(status); err != nil {
 		return nil, err
 	} else if !has {
 		status.IndexerType = indexerType
@@ -117,3 +118,46 @@ func (repo *Repository) updateIndexerStatus(e Engine, indexerType RepoIndexerTyp
 func (repo *Repository) UpdateIndexerStatus(indexerType RepoIndexerType, sha string) error {
 	return repo.updateIndexerStatus(x, indexerType, sha)
 }
+
+// GetIndexerRefStatuses returns the indexer statuses of the refs other than the default branch
+func (repo *Repository) GetIndexerRefStatuses(indexerType RepoIndexerType) ([]*RepoIndexerStatus, error) {
+	statuses := make([]*RepoIndexerStatus, 0, 5)
+	return statuses, x.
+		Where("repo_id = ? AND indexer_type = ? AND `ref` != ''", repo.ID, indexerType).
+		Asc("`ref`").
+		Find(&statuses)
+}
+
+// UpdateIndexerRefStatus updates the indexer status of a ref other than the default branch
+func (repo *Repository) UpdateIndexerRefStatus(indexerType RepoIndexerType, ref, sha string) error {
+	status := &RepoIndexerStatus{
+		RepoID:      repo.ID,
+		IndexerType: indexerType,
+		Ref:         ref,
+	}
+	has, err := x.Where("indexer_type = ?", indexerType).Get(status)
+	if err != nil {
+		return fmt.Errorf("UpdateIndexerRefStatus: Unable to get repoIndexerStatus for repo: %s Ref: %s Error: %v", repo.FullName(), ref, err)
+	}
+	status.CommitSha = sha
+	if !has {
+		if _, err = x.Insert(status); err != nil {
+			return fmt.Errorf("UpdateIndexerRefStatus: Unable to insert repoIndexerStatus for repo: %s Ref: %s Error: %v", repo.FullName(), ref, err)
+		}
+		return nil
+	}
+	if _, err = x.ID(status.ID).Cols("commit_sha").Update(status); err != nil {
+		return fmt.Errorf("UpdateIndexerRefStatus: Unable to update repoIndexerStatus for repo: %s Ref: %s Error: %v", repo.FullName(), ref, err)
+	}
+	return nil
+}
+
+// DeleteIndexerRefStatus deletes the indexer status of a ref other than the default branch
+func (repo *Repository) DeleteIndexerRefStatus(indexerType RepoIndexerType, ref string) error {
+	if len(ref) == 0 {
+		return nil
+	}
+	_, err := x.Where("repo_id = ? AND indexer_type = ? AND `ref` = ?", repo.ID, indexerType, ref).
+		Delete(new(RepoIndexerStatus))
+	return err
+}
@@ -0,0 +1,472 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/unknwon/com"
+)
+
+// RepoStorageLayout computes where the git repositories are stored in their volume
+type RepoStorageLayout interface {
+	// RepoRelPath returns the path of the repository relative to the root of its volume
+	RepoRelPath(repo *Repository) string
+	// WikiRelPath returns the path of the wiki relative to the root of its volume
+	WikiRelPath(repo *Repository) string
+}
+
+// nameRepoStorageLayout stores the repositories as <owner>/<name>.git
+type nameRepoStorageLayout struct{}
+
+func (nameRepoStorageLayout) RepoRelPath(repo *Repository) string {
+	return filepath.Join(strings.ToLower(repo.OwnerName), strings.ToLower(repo.Name)+".git")
+}
+
+func (nameRepoStorageLayout) WikiRelPath(repo *Repository) string {
+	return filepath.Join(strings.ToLower(repo.OwnerName), strings.ToLower(repo.Name)+".wiki.git")
+}
+
+// idRepoStorageLayout stores the repositories as repo/<xx>/<yy>/<id>.git, sharded by the bytes of their ID.
+// The repositories are neither moved when they are renamed or transferred, "repo" is a reserved user name
+// so the shards never collide with the directories of the name layout.
+type idRepoStorageLayout struct{}
+
+func (idRepoStorageLayout) shard(repo *Repository) string {
+	return filepath.Join("repo", fmt.Sprintf("%02x", repo.ID&0xff), fmt.Sprintf("%02x", (repo.ID>>8)&0xff))
+}
+
+func (l idRepoStorageLayout) RepoRelPath(repo *Repository) string {
+	return filepath.Join(l.shard(repo), fmt.Sprintf("%d.git", repo.ID))
+}
+
+func (l idRepoStorageLayout) WikiRelPath(repo *Repository) string {
+	return filepath.Join(l.shard(repo), fmt.Sprintf("%d.wiki.git", repo.ID))
+}
+
+var repoStorageLayouts = map[string]RepoStorageLayout{
+	setting.RepoStorageLayoutName: nameRepoStorageLayout{},
+	setting.RepoStorageLayoutID:   idRepoStorageLayout{},
+}
+
+// GetRepoStorageLayout returns the layout of the given name, the empty name is the name layout
+func GetRepoStorageLayout(name string) (RepoStorageLayout, error) {
+	if name == "" {
+		name = setting.RepoStorageLayoutName
+	}
+	layout, ok := repoStorageLayouts[name]
+	if !ok {
+		return nil, ErrRepoStorageLayoutNotExist{name}
+	}
+	return layout, nil
+}
+
+// normalizeRepoStorage returns the values stored for the volume and the layout,
+// the default volume and the name layout are stored as empty strings
+func normalizeRepoStorage(volume, layout string) (string, string, error) {
+	if volume == setting.DefaultRepoStorageVolume {
+		volume = ""
+	}
+	if layout == setting.RepoStorageLayoutName {
+		layout = ""
+	}
+	if _, ok := setting.RepoStorageVolumePath(volume); !ok {
+		return "", "", ErrRepoStorageVolumeNotExist{volume}
+	}
+	if _, err := GetRepoStorageLayout(layout); err != nil {
+		return "", "", err
+	}
+	return volume, layout, nil
+}
+
+// storagePaths returns the paths of the repository and its wiki
+func (repo *Repository) storagePaths() (string, string) {
+	root, ok := setting.RepoStorageVolumePath(repo.StorageVolume)
+	if !ok {
+		log.Error("Repository %d is stored in the volume %q which isn't configured", repo.ID, repo.StorageVolume)
+		root = setting.RepoRootPath
+	}
+	layout, err := GetRepoStorageLayout(repo.StorageLayout)
+	if err != nil {
+		log.Error("Repository %d is stored with the unknown layout %q", repo.ID, repo.StorageLayout)
+		layout = nameRepoStorageLayout{}
+	}
+	return filepath.Join(root, layout.RepoRelPath(repo)), filepath.Join(root, layout.WikiRelPath(repo))
+}
+
+// RepoPath returns the repository path
+func (repo *Repository) RepoPath() string {
+	repoPath, _ := repo.storagePaths()
+	return repoPath
+}
+
+// WikiPath returns wiki data path for given repository.
+func (repo *Repository) WikiPath() string {
+	_, wikiPath := repo.storagePaths()
+	return wikiPath
+}
+
+// RepoPath returns repository path by given user and repository name in the name layout of the default volume,
+// the path of an existing repository must be taken from Repository.RepoPath as it may be stored elsewhere.
+func RepoPath(userName, repoName string) string {
+	return filepath.Join(UserPath(userName), strings.ToLower(repoName)+".git")
+}
+
+// WikiPath returns wiki data path by given user and repository name in the name layout of the default volume,
+// the path of an existing wiki must be taken from Repository.WikiPath.
+func WikiPath(userName, repoName string) string {
+	return filepath.Join(UserPath(userName), strings.ToLower(repoName)+".wiki.git")
+}
+
+// newRepoStorageExists returns whether files already exist where a new repository of the given name may be stored.
+// The repositories in the id layout can't collide with existing files as their path is only known once they are inserted.
+func newRepoStorageExists(userName, repoName string) bool {
+	if setting.RepoStorage.Layout != "" && setting.RepoStorage.Layout != setting.RepoStorageLayoutName {
+		return false
+	}
+	var volumes []string
+	for _, name := range setting.RepoStorageVolumeNames() {
+		if setting.RepoStorageVolumeAcceptsNew(name) {
+			volumes = append(volumes, name)
+		}
+	}
+	if len(volumes) == 0 {
+		volumes = []string{setting.DefaultRepoStorageVolume}
+	}
+	for _, name := range volumes {
+		root, _ := setting.RepoStorageVolumePath(name)
+		if com.IsExist(filepath.Join(root, strings.ToLower(userName), strings.ToLower(repoName)+".git")) {
+			return true
+		}
+	}
+	return false
+}
+
+// userStoragePaths returns the directories of the user in the name layout of all the volumes
+func userStoragePaths(userName string) []string {
+	names := setting.RepoStorageVolumeNames()
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		root, _ := setting.RepoStorageVolumePath(name)
+		paths = append(paths, filepath.Join(root, strings.ToLower(userName)))
+	}
+	return paths
+}
+
+// renameUserStoragePaths renames the directories of the user in all the volumes
+func renameUserStoragePaths(oldUserName, newUserName string) error {
+	oldPaths, newPaths := userStoragePaths(oldUserName), userStoragePaths(newUserName)
+	for i := range oldPaths {
+		// Do not fail if directory does not exist
+		if err := os.Rename(oldPaths[i], newPaths[i]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeUserStoragePaths removes the directories of the user in all the volumes
+func removeUserStoragePaths(userName string) error {
+	for _, path := range userStoragePaths(userName) {
+		if err := util.RemoveAll(path); err != nil {
+			return fmt.Errorf("Failed to RemoveAll %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// moveRepoStorageDir moves a git repository, copying it when the paths are on different file systems
+func moveRepoStorageDir(oldPath, newPath string) error {
+	if err := os.MkdirAll(filepath.Dir(newPath), os.ModePerm); err != nil {
+		return fmt.Errorf("Failed to create dir %s: %v", filepath.Dir(newPath), err)
+	}
+	if err := os.Rename(oldPath, newPath); err == nil {
+		return nil
+	} else if _, ok := err.(*os.LinkError); !ok {
+		return err
+	}
+	if err := com.CopyDir(oldPath, newPath); err != nil {
+		if err2 := util.RemoveAll(newPath); err2 != nil {
+			log.Error("Unable to remove the partial copy %s: %v", newPath, err2)
+		}
+		return fmt.Errorf("copy %s to %s: %v", oldPath, newPath, err)
+	}
+	return util.RemoveAll(oldPath)
+}
+
+// moveRepoStorage moves the repository and its wiki, the moves are reverted on failure
+func moveRepoStorage(oldRepoPath, oldWikiPath, newRepoPath, newWikiPath string) error {
+	if oldRepoPath == newRepoPath {
+		return nil
+	}
+	if com.IsExist(newRepoPath) {
+		return fmt.Errorf("repository directory already exists: %s", newRepoPath)
+	}
+	if err := moveRepoStorageDir(oldRepoPath, newRepoPath); err != nil {
+		return fmt.Errorf("move repository directory: %v", err)
+	}
+	if !com.IsExist(oldWikiPath) {
+		return nil
+	}
+	if err := moveRepoStorageDir(oldWikiPath, newWikiPath); err != nil {
+		if err2 := moveRepoStorageDir(newRepoPath, oldRepoPath); err2 != nil {
+			log.Critical("Unable to move back the repository from %s to %s: %v", newRepoPath, oldRepoPath, err2)
+		}
+		return fmt.Errorf("move repository wiki: %v", err)
+	}
+	return nil
+}
+
+// RelocateRepository moves the git repositories of the repository to the given volume and layout.
+// It should be run while the repository isn't used as its git data is moved before the database is updated.
+func RelocateRepository(repo *Repository, volume, layout string) error {
+	volume, layout, err := normalizeRepoStorage(volume, layout)
+	if err != nil {
+		return err
+	}
+	if volume == repo.StorageVolume && layout == repo.StorageLayout {
+		return nil
+	}
+
+	oldRepoPath, oldWikiPath := repo.storagePaths()
+	moved := *repo
+	moved.StorageVolume, moved.StorageLayout = volume, layout
+	newRepoPath, newWikiPath := moved.storagePaths()
+
+	if err := moveRepoStorage(oldRepoPath, oldWikiPath, newRepoPath, newWikiPath); err != nil {
+		return err
+	}
+
+	repo.StorageVolume, repo.StorageLayout = volume, layout
+	if _, err := x.ID(repo.ID).Cols("storage_volume", "storage_layout").Update(repo); err != nil {
+		if err2 := moveRepoStorage(newRepoPath, newWikiPath, oldRepoPath, oldWikiPath); err2 != nil {
+			log.Critical("Unable to move back the repository %d to %s: %v", repo.ID, oldRepoPath, err2)
+		}
+		repo.StorageVolume, repo.StorageLayout = moved.StorageVolume, moved.StorageLayout
+		return fmt.Errorf("update repository storage: %v", err)
+	}
+	log.Trace("Repository %d relocated from %s to %s", repo.ID, oldRepoPath, newRepoPath)
+	return nil
+}
+
+// RepoStorageVolumeStat is the usage of a repository volume
+type RepoStorageVolumeStat struct {
+	Name                  string
+	Path                  string
+	AcceptNewRepositories bool
+	// Configured is false for the volumes which still have repositories but were removed from the configuration
+	Configured bool
+	NumRepos   int64
+	Size       int64
+}
+
+func getRepoStorageVolumeStats(e Engine) ([]*RepoStorageVolumeStat, error) {
+	var rows []struct {
+		StorageVolume string
+		NumRepos      int64
+		Size          int64
+	}
+	if err := e.Table("repository").
+		Select("storage_volume, COUNT(*) AS num_repos, SUM(size) AS size").
+		GroupBy("storage_volume").
+		Find(&rows); err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]*RepoStorageVolumeStat)
+	names := setting.RepoStorageVolumeNames()
+	for _, name := range names {
+		path, _ := setting.RepoStorageVolumePath(name)
+		stats[name] = &RepoStorageVolumeStat{
+			Name:                  name,
+			Path:                  path,
+			AcceptNewRepositories: setting.RepoStorageVolumeAcceptsNew(name),
+			Configured:            true,
+		}
+	}
+	for _, row := range rows {
+		name := row.StorageVolume
+		if name == "" {
+			name = setting.DefaultRepoStorageVolume
+		}
+		stat, ok := stats[name]
+		if !ok {
+			stat = &RepoStorageVolumeStat{Name: name}
+			stats[name] = stat
+			names = append(names, name)
+		}
+		stat.NumRepos += row.NumRepos
+		stat.Size += row.Size
+	}
+
+	results := make([]*RepoStorageVolumeStat, 0, len(names))
+	for _, name := range names {
+		results = append(results, stats[name])
+	}
+	return results, nil
+}
+
+// GetRepoStorageVolumeStats returns the usage of the configured volumes and of the volumes still storing repositories
+func GetRepoStorageVolumeStats() ([]*RepoStorageVolumeStat, error) {
+	return getRepoStorageVolumeStats(x)
+}
+
+// pickRepoStorageVolume returns the volume of a new repository following the placement policy
+func pickRepoStorageVolume(e Engine) (string, error) {
+	var candidates []string
+	for _, name := range setting.RepoStorageVolumeNames() {
+		if setting.RepoStorageVolumeAcceptsNew(name) {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 {
+		log.Warn("No repository volume accepts new repositories, the default volume is used")
+		return "", nil
+	}
+
+	var picked string
+	switch setting.RepoStorage.Placement {
+	case setting.RepoVolumePlacementLeastRepositories, setting.RepoVolumePlacementLeastSize:
+		stats, err := getRepoStorageVolumeStats(e)
+		if err != nil {
+			return "", err
+		}
+		usage := make(map[string]int64, len(stats))
+		for _, stat := range stats {
+			if setting.RepoStorage.Placement == setting.RepoVolumePlacementLeastSize {
+				usage[stat.Name] = stat.Size
+			} else {
+				usage[stat.Name] = stat.NumRepos
+			}
+		}
+		picked = candidates[0]
+		for _, name := range candidates[1:] {
+			if usage[name] < usage[picked] {
+				picked = name
+			}
+		}
+	default:
+		picked = candidates[0]
+	}
+
+	if picked == setting.DefaultRepoStorageVolume {
+		return "", nil
+	}
+	return picked, nil
+}
+
+// RepoStorageMove is a move of a repository planned to rebalance the volumes
+type RepoStorageMove struct {
+	Repo *Repository
+	From string
+	To   string
+}
+
+// planRepoStorageRebalance plans the moves of the repositories out of the volumes which don't accept new repositories,
+// then from the largest volume to the smallest one as long as a move reduces their difference
+func planRepoStorageRebalance(stats []*RepoStorageVolumeStat, repos []*Repository, maxMoves int) []*RepoStorageMove {
+	sizes := make(map[string]int64)
+	var targets []string
+	for _, stat := range stats {
+		if stat.Configured && stat.AcceptNewRepositories {
+			targets = append(targets, stat.Name)
+			sizes[stat.Name] = stat.Size
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	smallest := func() string {
+		picked := targets[0]
+		for _, name := range targets[1:] {
+			if sizes[name] < sizes[picked] {
+				picked = name
+			}
+		}
+		return picked
+	}
+
+	// the largest repositories are moved first
+	sort.SliceStable(repos, func(i, j int) bool {
+		return repos[i].Size > repos[j].Size
+	})
+	byVolume := make(map[string][]*Repository)
+	for _, repo := range repos {
+		volume := repo.StorageVolume
+		if volume == "" {
+			volume = setting.DefaultRepoStorageVolume
+		}
+		byVolume[volume] = append(byVolume[volume], repo)
+	}
+
+	var moves []*RepoStorageMove
+	full := func() bool {
+		return maxMoves > 0 && len(moves) >= maxMoves
+	}
+	for _, stat := range stats {
+		if !stat.Configured || stat.AcceptNewRepositories {
+			continue
+		}
+		for _, repo := range byVolume[stat.Name] {
+			if full() {
+				return moves
+			}
+			to := smallest()
+			moves = append(moves, &RepoStorageMove{Repo: repo, From: stat.Name, To: to})
+			sizes[to] += repo.Size
+		}
+	}
+
+	for !full() {
+		largest, to := targets[0], smallest()
+		for _, name := range targets[1:] {
+			if sizes[name] > sizes[largest] {
+				largest = name
+			}
+		}
+		diff := sizes[largest] - sizes[to]
+
+		// moving a repository smaller than the difference always brings the volumes closer
+		candidates := byVolume[largest]
+		idx := -1
+		for i, repo := range candidates {
+			if repo.Size > 0 && repo.Size < diff {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			break
+		}
+		repo := candidates[idx]
+		byVolume[largest] = append(candidates[:idx:idx], candidates[idx+1:]...)
+		moves = append(moves, &RepoStorageMove{Repo: repo, From: largest, To: to})
+		sizes[largest] -= repo.Size
+		sizes[to] += repo.Size
+	}
+	return moves
+}
+
+// PlanRepoStorageRebalance returns the moves of the repositories which balance the sizes of the volumes,
+// a maxMoves of 0 plans all the moves
+func PlanRepoStorageRebalance(maxMoves int) ([]*RepoStorageMove, error) {
+	stats, err := GetRepoStorageVolumeStats()
+	if err != nil {
+		return nil, err
+	}
+	repos := make([]*Repository, 0, 50)
+	if err := x.Cols("id", "owner_name", "name", "size", "storage_volume", "storage_layout").Find(&repos); err != nil {
+		return nil, err
+	}
+	return planRepoStorageRebalance(stats, repos, maxMoves), nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unknwon/com"
+)
+
+func addTestRepoStorageVolume(t *testing.T, name string, acceptNew bool) string {
+	dir, err := ioutil.TempDir("", "repo-volume")
+	assert.NoError(t, err)
+	setting.RepoStorage.Volumes[name] = &setting.RepoStorageVolume{Name: name, Path: dir, AcceptNewRepositories: acceptNew}
+	t.Cleanup(func() {
+		delete(setting.RepoStorage.Volumes, name)
+		os.RemoveAll(dir)
+	})
+	return dir
+}
+
+func TestRepoStorageLayouts(t *testing.T) {
+	repo := &Repository{ID: 70000, OwnerName: "User2", Name: "Repo1"}
+	assert.Equal(t, filepath.Join(setting.RepoRootPath, "user2", "repo1.git"), repo.RepoPath())
+	assert.Equal(t, filepath.Join(setting.RepoRootPath, "user2", "repo1.wiki.git"), repo.WikiPath())
+
+	// 70000 is 0x11170
+	repo.StorageLayout = setting.RepoStorageLayoutID
+	assert.Equal(t, filepath.Join(setting.RepoRootPath, "repo", "70", "11", "70000.git"), repo.RepoPath())
+	assert.Equal(t, filepath.Join(setting.RepoRootPath, "repo", "70", "11", "70000.wiki.git"), repo.WikiPath())
+
+	dir := addTestRepoStorageVolume(t, "second", true)
+	repo.StorageVolume = "second"
+	assert.Equal(t, filepath.Join(dir, "repo", "70", "11", "70000.git"), repo.RepoPath())
+
+	_, err := GetRepoStorageLayout("unknown")
+	assert.True(t, IsErrRepoStorageLayoutNotExist(err))
+}
+
+func TestRelocateRepository(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	dir := addTestRepoStorageVolume(t, "second", true)
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	oldPath := repo.RepoPath()
+	assert.True(t, com.IsDir(oldPath))
+
+	assert.True(t, IsErrRepoStorageVolumeNotExist(RelocateRepository(repo, "missing", "")))
+	assert.True(t, IsErrRepoStorageLayoutNotExist(RelocateRepository(repo, "", "missing")))
+
+	assert.NoError(t, RelocateRepository(repo, "second", setting.RepoStorageLayoutID))
+	assert.Equal(t, filepath.Join(dir, "repo", "01", "00", "1.git"), repo.RepoPath())
+	assert.True(t, com.IsDir(repo.RepoPath()))
+	assert.False(t, com.IsExist(oldPath))
+	AssertExistsAndLoadBean(t, &Repository{ID: 1, StorageVolume: "second", StorageLayout: setting.RepoStorageLayoutID})
+	assert.Equal(t, filepath.Join(setting.RepoRootPath, "user2", "repo1.git"), RepoPath("user2", "repo1"))
+
+	// the repositories in the id layout don't move when they are renamed
+	assert.NoError(t, repo.GetOwner())
+	idPath := repo.RepoPath()
+	assert.NoError(t, ChangeRepositoryName(repo.Owner, repo, "renamed"))
+	assert.True(t, com.IsDir(idPath))
+
+	// the default volume and the name layout are stored as empty values
+	assert.NoError(t, RelocateRepository(repo, setting.DefaultRepoStorageVolume, setting.RepoStorageLayoutName))
+	assert.Equal(t, oldPath, repo.RepoPath())
+	assert.True(t, com.IsDir(oldPath))
+	assert.False(t, com.IsExist(idPath))
+	AssertExistsAndLoadBean(t, &Repository{ID: 1, StorageVolume: "", StorageLayout: ""})
+}
+
+func TestPickRepoStorageVolume(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defer func(placement string, acceptNew bool) {
+		setting.RepoStorage.Placement = placement
+		setting.RepoStorage.DefaultAcceptNewRepos = acceptNew
+	}(setting.RepoStorage.Placement, setting.RepoStorage.DefaultAcceptNewRepos)
+
+	addTestRepoStorageVolume(t, "second", true)
+	addTestRepoStorageVolume(t, "third", false)
+
+	volume, err := pickRepoStorageVolume(x)
+	assert.NoError(t, err)
+	assert.Equal(t, "", volume)
+
+	setting.RepoStorage.Placement = setting.RepoVolumePlacementLeastRepositories
+	volume, err = pickRepoStorageVolume(x)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", volume)
+
+	setting.RepoStorage.Placement = setting.RepoVolumePlacementDefault
+	setting.RepoStorage.DefaultAcceptNewRepos = false
+	volume, err = pickRepoStorageVolume(x)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", volume)
+}
+
+func TestNewRepoStorageExists(t *testing.T) {
+	defer func(layout string) {
+		setting.RepoStorage.Layout = layout
+	}(setting.RepoStorage.Layout)
+
+	dir := addTestRepoStorageVolume(t, "second", true)
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "user2", "leftover.git"), os.ModePerm))
+	assert.True(t, newRepoStorageExists("User2", "Leftover"))
+	assert.False(t, newRepoStorageExists("user2", "missing"))
+
+	setting.RepoStorage.Layout = setting.RepoStorageLayoutID
+	assert.False(t, newRepoStorageExists("user2", "leftover"))
+}
+
+func TestPlanRepoStorageRebalance(t *testing.T) {
+	addTestRepoStorageVolume(t, "second", true)
+	addTestRepoStorageVolume(t, "drained", false)
+
+	stats := []*RepoStorageVolumeStat{
+		{Name: setting.DefaultRepoStorageVolume, AcceptNewRepositories: true, Configured: true, Size: 100},
+		{Name: "second", AcceptNewRepositories: true, Configured: true, Size: 0},
+		{Name: "drained", Configured: true, Size: 15},
+		{Name: "removed", Size: 5},
+	}
+	repos := []*Repository{
+		{ID: 1, Size: 60},
+		{ID: 2, Size: 30},
+		{ID: 3, Size: 10},
+		{ID: 4, Size: 15, StorageVolume: "drained"},
+		{ID: 5, Size: 5, StorageVolume: "removed"},
+	}
+
+	moves := planRepoStorageRebalance(stats, repos, 0)
+	type move struct {
+		ID       int64
+		From, To string
+	}
+	var planned []move
+	for _, m := range moves {
+		planned = append(planned, move{m.Repo.ID, m.From, m.To})
+	}
+	// the drained volume is emptied first, then the repositories smaller than the difference are moved
+	assert.Equal(t, []move{
+		{4, "drained", "second"},
+		{1, "default", "second"},
+	}, planned)
+
+	assert.Len(t, planRepoStorageRebalance(stats, repos, 1), 1)
+}
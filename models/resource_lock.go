@@ -0,0 +1,63 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ResourceLock represents a lease on a named resource which is shared by all the
+// Gitea instances using the database. The lease is held by its owner until it expires.
+type ResourceLock struct {
+	Name        string             `xorm:"pk VARCHAR(255)"`
+	Owner       string             `xorm:"VARCHAR(255) NOT NULL"`
+	ExpiresUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+}
+
+// AcquireResourceLock takes the lease on the named resource for the owner if it is free or expired,
+// or extends it if it is already held by the owner, and returns whether the owner now holds it
+func AcquireResourceLock(name, owner string, ttl time.Duration) (bool, error) {
+	now := timeutil.TimeStampNow()
+	lock := &ResourceLock{
+		Name:        name,
+		Owner:       owner,
+		ExpiresUnix: now.AddDuration(ttl),
+	}
+
+	affected, err := x.Where("name = ? AND (owner = ? OR expires_unix < ?)", name, owner, now).
+		Cols("owner", "expires_unix").
+		Update(lock)
+	if err != nil {
+		return false, err
+	} else if affected > 0 {
+		return true, nil
+	}
+
+	existing := new(ResourceLock)
+	has, err := x.ID(name).Get(existing)
+	if err != nil {
+		return false, err
+	} else if has {
+		// Some databases do not count rows updated with unchanged values as affected
+		return existing.Owner == owner && existing.ExpiresUnix >= now, nil
+	}
+
+	if _, err = x.Insert(lock); err != nil {
+		// Another instance may have taken the lease in the meantime
+		if has, _ = x.ID(name).Get(existing); has {
+			return existing.Owner == owner, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseResourceLock releases the lease on the named resource if it is held by the owner
+func ReleaseResourceLock(name, owner string) error {
+	_, err := x.Where("name = ? AND owner = ?", name, owner).Delete(new(ResourceLock))
+	return err
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceLock(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	acquired, err := AcquireResourceLock("test", "instance1", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	// the lock is held by instance1 until it expires
+	acquired, err = AcquireResourceLock("test", "instance2", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	// but instance1 can extend it
+	acquired, err = AcquireResourceLock("test", "instance1", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	// releasing a lock held by another owner does nothing
+	assert.NoError(t, ReleaseResourceLock("test", "instance2"))
+	AssertExistsAndLoadBean(t, &ResourceLock{Name: "test", Owner: "instance1"})
+
+	assert.NoError(t, ReleaseResourceLock("test", "instance1"))
+	acquired, err = AcquireResourceLock("test", "instance2", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	// an expired lock can be taken by another owner
+	_, err = x.ID("test").Cols("expires_unix").Update(&ResourceLock{ExpiresUnix: timeutil.TimeStampNow().AddDuration(-time.Minute)})
+	assert.NoError(t, err)
+	acquired, err = AcquireResourceLock("test", "instance1", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	AssertExistsAndLoadBean(t, &ResourceLock{Name: "test", Owner: "instance1"})
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ReviewState stores which files of a pull request a user has marked as viewed.
+// Every viewed file is stored with the blob SHA it had when it was marked,
+// so the file is no longer considered viewed once it changes.
+type ReviewState struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UserID      int64              `xorm:"NOT NULL UNIQUE(pull_user)"`
+	PullID      int64              `xorm:"NOT NULL UNIQUE(pull_user)"`
+	ViewedFiles map[string]string  `xorm:"TEXT JSON"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// IsViewed returns whether the file has been marked as viewed with the given blob SHA
+func (rs *ReviewState) IsViewed(name, blobSHA string) bool {
+	if rs == nil || len(blobSHA) == 0 {
+		return false
+	}
+	sha, ok := rs.ViewedFiles[name]
+	return ok && sha == blobSHA
+}
+
+func getReviewState(e Engine, userID, pullID int64) (*ReviewState, bool, error) {
+	rs := new(ReviewState)
+	has, err := e.
+		Where("user_id = ?", userID).
+		And("pull_id = ?", pullID).
+		Get(rs)
+	if err != nil {
+		return nil, false, err
+	}
+	if rs.ViewedFiles == nil {
+		rs.ViewedFiles = make(map[string]string)
+	}
+	return rs, has, nil
+}
+
+// GetReviewState returns the review state of a user for a pull request,
+// an empty state is returned if the user has not viewed any file yet
+func GetReviewState(userID, pullID int64) (*ReviewState, error) {
+	rs, has, err := getReviewState(x, userID, pullID)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		rs.UserID = userID
+		rs.PullID = pullID
+	}
+	return rs, nil
+}
+
+// UpdateReviewState marks the given files as viewed by the user at the given blob SHAs.
+// Files mapped to an empty SHA are marked as not viewed.
+func UpdateReviewState(userID, pullID int64, files map[string]string) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	rs, has, err := getReviewState(sess, userID, pullID)
+	if err != nil {
+		return err
+	}
+
+	for name, sha := range files {
+		if len(sha) == 0 {
+			delete(rs.ViewedFiles, name)
+		} else {
+			rs.ViewedFiles[name] = sha
+		}
+	}
+
+	if !has {
+		rs.UserID = userID
+		rs.PullID = pullID
+		if _, err = sess.Insert(rs); err != nil {
+			return err
+		}
+	} else if _, err = sess.ID(rs.ID).Cols("viewed_files", "updated_unix").Update(rs); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
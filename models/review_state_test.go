@@ -0,0 +1,46 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateReviewState(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	rs, err := GetReviewState(1, 2)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, rs.ID)
+	assert.False(t, rs.IsViewed("README.md", "1234"))
+
+	assert.NoError(t, UpdateReviewState(1, 2, map[string]string{
+		"README.md": "1234",
+		"main.go":   "abcd",
+	}))
+	rs, err = GetReviewState(1, 2)
+	assert.NoError(t, err)
+	assert.NotZero(t, rs.ID)
+	assert.True(t, rs.IsViewed("README.md", "1234"))
+	assert.True(t, rs.IsViewed("main.go", "abcd"))
+
+	// the file has changed since it has been viewed
+	assert.False(t, rs.IsViewed("README.md", "5678"))
+
+	assert.NoError(t, UpdateReviewState(1, 2, map[string]string{
+		"main.go": "",
+	}))
+	rs, err = GetReviewState(1, 2)
+	assert.NoError(t, err)
+	assert.True(t, rs.IsViewed("README.md", "1234"))
+	assert.False(t, rs.IsViewed("main.go", "abcd"))
+
+	// states are kept per user
+	rs, err = GetReviewState(2, 2)
+	assert.NoError(t, err)
+	assert.False(t, rs.IsViewed("README.md", "1234"))
+}
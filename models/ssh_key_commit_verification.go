@@ -0,0 +1,199 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/pem"
+	"fmt"
+	"hash"
+	"strings"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	sshSignatureStart = "-----BEGIN SSH SIGNATURE-----"
+	sshSignatureType  = "SSH SIGNATURE"
+	sshSignatureMagic = "SSHSIG"
+
+	// sshCommitNamespace is the namespace git uses when signing commits and tags with ssh-keygen
+	sshCommitNamespace = "git"
+	// sshTokenNamespace is the namespace users have to sign the verification token with
+	sshTokenNamespace = "gitea"
+)
+
+// sshSignature represents a parsed armored signature as created by `ssh-keygen -Y sign`.
+// See https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig
+type sshSignature struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// sshSignedData represents the blob which is actually signed by the key
+type sshSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// IsSSHSignature returns true if the provided armored signature is an SSH signature
+func IsSSHSignature(signature string) bool {
+	return strings.HasPrefix(strings.TrimSpace(signature), sshSignatureStart)
+}
+
+func parseSSHSignature(armored string) (*sshSignature, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(armored)))
+	if block == nil || block.Type != sshSignatureType {
+		return nil, fmt.Errorf("expected '%s' armor", sshSignatureType)
+	}
+	if !bytes.HasPrefix(block.Bytes, []byte(sshSignatureMagic)) {
+		return nil, fmt.Errorf("invalid signature magic")
+	}
+
+	sig := &sshSignature{}
+	if err := ssh.Unmarshal(block.Bytes[len(sshSignatureMagic):], sig); err != nil {
+		return nil, err
+	}
+	if sig.Version != 1 {
+		return nil, fmt.Errorf("unsupported signature version: %d", sig.Version)
+	}
+	return sig, nil
+}
+
+// publicKey returns the public key embedded in the signature
+func (sig *sshSignature) publicKey() (ssh.PublicKey, error) {
+	return ssh.ParsePublicKey(sig.PublicKey)
+}
+
+// verify checks that the signature has been made by key over the provided message within namespace
+func (sig *sshSignature) verify(key ssh.PublicKey, namespace string, message []byte) error {
+	if sig.Namespace != namespace {
+		return fmt.Errorf("unexpected signature namespace: %s", sig.Namespace)
+	}
+	if !bytes.Equal(key.Marshal(), sig.PublicKey) {
+		return fmt.Errorf("signature was not made by the provided key")
+	}
+
+	var h hash.Hash
+	switch sig.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported hash algorithm: %s", sig.HashAlgorithm)
+	}
+	if _, err := h.Write(message); err != nil {
+		return err
+	}
+
+	signature := &ssh.Signature{}
+	if err := ssh.Unmarshal(sig.Signature, signature); err != nil {
+		return err
+	}
+
+	signed := append([]byte(sshSignatureMagic), ssh.Marshal(sshSignedData{
+		Namespace:     sig.Namespace,
+		Reserved:      sig.Reserved,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          h.Sum(nil),
+	})...)
+	return key.Verify(signed, signature)
+}
+
+// verifySSHSignature verifies an armored ssh signature over message against the provided authorized key content
+func verifySSHSignature(content, namespace, armored string, message []byte) error {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(content))
+	if err != nil {
+		return err
+	}
+	sig, err := parseSSHSignature(armored)
+	if err != nil {
+		return err
+	}
+	return sig.verify(key, namespace, message)
+}
+
+// ParseCommitWithSSHSignature checks if the ssh signature of a commit matches one of the verified ssh keys of its committer.
+func ParseCommitWithSSHSignature(c *git.Commit, committer *User) *CommitVerification {
+	sig, err := parseSSHSignature(c.Signature.Signature)
+	if err != nil {
+		log.Error("SSH SignatureRead err: %v", err)
+		return &CommitVerification{
+			CommittingUser: committer,
+			Verified:       false,
+			Reason:         "gpg.error.extract_sign",
+		}
+	}
+
+	pubKey, err := sig.publicKey()
+	if err != nil {
+		log.Error("Unable to parse public key from ssh signature: %v", err)
+		return &CommitVerification{
+			CommittingUser: committer,
+			Verified:       false,
+			Reason:         "gpg.error.extract_sign",
+		}
+	}
+	fingerprint := ssh.FingerprintSHA256(pubKey)
+
+	// Only keys belonging to the committer which have been verified can sign commits
+	if committer.ID != 0 {
+		keys, err := SearchPublicKey(committer.ID, fingerprint)
+		if err != nil {
+			log.Error("SearchPublicKey: %v", err)
+			return &CommitVerification{
+				CommittingUser: committer,
+				Verified:       false,
+				Reason:         "gpg.error.failed_retrieval_gpg_keys",
+			}
+		}
+
+		for _, k := range keys {
+			if !k.Verified || k.Type != KeyTypeUser {
+				continue
+			}
+			if err := verifySSHSignature(k.Content, sshCommitNamespace, c.Signature.Signature, []byte(c.Signature.Payload)); err != nil {
+				log.Debug("Unable to verify commit %s with ssh key %s: %v", c.ID.String(), k.Fingerprint, err)
+				// This is a bad situation ... We have a known key that embeds itself in the signature but doesn't verify it.
+				return &CommitVerification{
+					CommittingUser: committer,
+					Verified:       false,
+					Warning:        true,
+					Reason:         BadSignature,
+					SigningSSHKey:  k,
+				}
+			}
+			return &CommitVerification{
+				CommittingUser: committer,
+				Verified:       true,
+				Reason:         fmt.Sprintf("%s / %s", committer.Name, k.Fingerprint),
+				SigningUser:    committer,
+				SigningSSHKey:  k,
+				SigningEmail:   c.Committer.Email,
+			}
+		}
+	}
+
+	return &CommitVerification{
+		CommittingUser: committer,
+		Verified:       false,
+		Reason:         NoKeyFound,
+		SigningSSHKey: &PublicKey{
+			Fingerprint: fingerprint,
+		},
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSSHSigningKey         = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBXxP6ZYcmv/Y/bR2GZ5UIfi7lPlp3WE3aH+PjQTVqhr user2@example.com"
+	testSSHSigningFingerprint = "SHA256:X6sA91niAedqzAOaFuUwSTAvlsMGnrkhnWH4kCRQivE"
+	testSSHTokenSignature     = `-----BEGIN SSH SIGNATURE-----
+U1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgFfE/plhya/9j9tHYZnlQh+LuU+
+WndYTdof4+NBNWqGsAAAAFZ2l0ZWEAAAAAAAAABnNoYTUxMgAAAFMAAAALc3NoLWVkMjU1
+MTkAAABAhAMZgKhaKBlvwsXVM6zD/t+af5V7DGLoKAWtxOOZTcOYo7jUqKPbHVAPpiXjDz
+yG09E3D5GrZyi8ZNVgk82+Bw==
+-----END SSH SIGNATURE-----
+`
+	testSSHSignedCommit = `tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904
+author User Two <user2@example.com> 1609459200 +0000
+committer User Two <user2@example.com> 1792333041 +0000
+gpgsig -----BEGIN SSH SIGNATURE-----
+ U1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgFfE/plhya/9j9tHYZnlQh+LuU+
+ WndYTdof4+NBNWqGsAAAADZ2l0AAAAAAAAAAZzaGE1MTIAAABTAAAAC3NzaC1lZDI1NTE5
+ AAAAQMzMXwfmMeozJBEpqotSZuRYvaKd9TPNddDJxkQQ18/YOTComdWaFTGSREcBCfpowd
+ tcNsvcKaP0fklXl6dO5QM=
+ -----END SSH SIGNATURE-----
+
+signed commit
+`
+)
+
+func TestVerifySSHSignature(t *testing.T) {
+	assert.True(t, IsSSHSignature(testSSHTokenSignature))
+	assert.False(t, IsSSHSignature("-----BEGIN PGP SIGNATURE-----"))
+
+	assert.NoError(t, verifySSHSignature(testSSHSigningKey, sshTokenNamespace, testSSHTokenSignature, []byte("gitea-token")))
+	// wrong message
+	assert.Error(t, verifySSHSignature(testSSHSigningKey, sshTokenNamespace, testSSHTokenSignature, []byte("gitea-token2")))
+	// wrong namespace
+	assert.Error(t, verifySSHSignature(testSSHSigningKey, sshCommitNamespace, testSSHTokenSignature, []byte("gitea-token")))
+	// wrong key
+	assert.Error(t, verifySSHSignature("ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIP1VSbvRYOGIl46mfaNzhP76tpm6ddScTtNRSb3ppH4T", sshTokenNamespace, testSSHTokenSignature, []byte("gitea-token")))
+	// not an ssh signature
+	assert.Error(t, verifySSHSignature(testSSHSigningKey, sshTokenNamespace, "-----BEGIN PGP SIGNATURE-----\n-----END PGP SIGNATURE-----\n", []byte("gitea-token")))
+}
+
+func TestParseCommitWithSSHSignature(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	commit, err := git.CommitFromReader(nil, git.MustIDFromString("0000000000000000000000000000000000000001"), strings.NewReader(testSSHSignedCommit))
+	assert.NoError(t, err)
+	assert.NotNil(t, commit.Signature)
+
+	// the key is unknown
+	verification := ParseCommitWithSignature(commit)
+	assert.False(t, verification.Verified)
+	assert.Equal(t, NoKeyFound, verification.Reason)
+	assert.Equal(t, testSSHSigningFingerprint, verification.SigningSSHKey.Fingerprint)
+
+	key := &PublicKey{
+		OwnerID:     2,
+		Name:        "signing",
+		Fingerprint: testSSHSigningFingerprint,
+		Content:     testSSHSigningKey,
+		Mode:        AccessModeWrite,
+		Type:        KeyTypeUser,
+	}
+	_, err = x.Insert(key)
+	assert.NoError(t, err)
+
+	// the key is known but has not been verified
+	verification = ParseCommitWithSignature(commit)
+	assert.False(t, verification.Verified)
+	assert.Equal(t, NoKeyFound, verification.Reason)
+
+	key.Verified = true
+	_, err = x.ID(key.ID).Cols("verified").Update(key)
+	assert.NoError(t, err)
+
+	verification = ParseCommitWithSignature(commit)
+	assert.True(t, verification.Verified)
+	assert.EqualValues(t, 2, verification.SigningUser.ID)
+	assert.EqualValues(t, key.ID, verification.SigningSSHKey.ID)
+	assert.Equal(t, "user2@example.com", verification.SigningEmail)
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// VerificationToken returns the token of the user for the current minute offset by minutes.
+// The token shown to the user is the one of the current minute.
+func VerificationToken(user *User, minutes int) string {
+	return base.EncodeSha256(
+		time.Now().Truncate(1*time.Minute).Add(time.Duration(minutes)*time.Minute).Format(time.RFC1123Z) + ":" +
+			user.CreatedUnix.FormatLong() + ":" +
+			user.Name + ":" +
+			user.Email + ":" +
+			strconv.FormatInt(user.ID, 10))
+}
+
+// VerifySSHKey marks a SSH key as verified if the provided signature signs the current verification token of its owner
+func VerifySSHKey(owner *User, fingerprint, signature string) (string, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return "", err
+	}
+
+	key := new(PublicKey)
+	has, err := sess.Where("owner_id = ? AND fingerprint = ? AND type = ?", owner.ID, fingerprint, KeyTypeUser).Get(key)
+	if err != nil {
+		return "", err
+	} else if !has {
+		return "", ErrKeyNotExist{}
+	}
+
+	// The token is accepted during the minute it was shown in and the next one
+	for _, minutes := range []int{0, -1} {
+		if err = verifySSHSignature(key.Content, sshTokenNamespace, signature, []byte(VerificationToken(owner, minutes))); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		log.Debug("VerifySSHKey: %s for %s: %v", fingerprint, owner.Name, err)
+		return "", ErrSSHInvalidTokenSignature{
+			Fingerprint: fingerprint,
+			Wrapped:     err,
+		}
+	}
+
+	key.Verified = true
+	if _, err := sess.ID(key.ID).Cols("verified").Update(key); err != nil {
+		return "", err
+	}
+
+	if err := sess.Commit(); err != nil {
+		return "", err
+	}
+
+	return key.Fingerprint, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/tracing"
+
+	"xorm.io/xorm/contexts"
+)
+
+// tracingHook is a xorm hook creating a span for each SQL statement run with a traced context
+type tracingHook struct{}
+
+type tracingHookSpanKey struct{}
+
+// BeforeProcess starts the span for the statement
+func (tracingHook) BeforeProcess(c *contexts.ContextHook) (context.Context, error) {
+	if !tracing.IsEnabled() {
+		return c.Ctx, nil
+	}
+	ctx, span := tracing.StartChild(c.Ctx, "db.query", tracing.SpanKindClient)
+	if span == nil {
+		return c.Ctx, nil
+	}
+	span.SetAttribute("db.system", setting.Database.Type)
+	span.SetAttribute("db.statement", c.SQL)
+	return context.WithValue(ctx, tracingHookSpanKey{}, span), nil
+}
+
+// AfterProcess ends the span for the statement
+func (tracingHook) AfterProcess(c *contexts.ContextHook) error {
+	if c.Ctx == nil {
+		return nil
+	}
+	if span, ok := c.Ctx.Value(tracingHookSpanKey{}).(*tracing.Span); ok {
+		span.SetError(c.Err)
+		span.End()
+	}
+	return nil
+}
@@ -15,7 +15,6 @@ import (
 	"fmt"
 	_ "image/jpeg" // Needed for jpeg support
 	"net/mail"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -66,6 +65,13 @@ const (
 	EmailNotificationsOnMention = "onmention"
 	// EmailNotificationsDisabled indicates that the user would not like to be notified via email.
 	EmailNotificationsDisabled = "disabled"
+
+	// EmailDigestNone indicates that the user would like to receive the notification emails immediately
+	EmailDigestNone = "none"
+	// EmailDigestHourly indicates that the user would like to receive one summary email per hour
+	EmailDigestHourly = "hourly"
+	// EmailDigestDaily indicates that the user would like to receive one summary email per day
+	EmailDigestDaily = "daily"
 )
 
 var (
@@ -101,6 +107,7 @@ type User struct {
 	Email                        string `xorm:"NOT NULL"`
 	KeepEmailPrivate             bool
 	EmailNotificationsPreference string `xorm:"VARCHAR(20) NOT NULL DEFAULT 'enabled'"`
+	EmailNotificationsDigest     string `xorm:"VARCHAR(20) NOT NULL DEFAULT 'none'"`
 	Passwd                       string `xorm:"NOT NULL"`
 	PasswdHashAlgo               string `xorm:"NOT NULL DEFAULT 'argon2'"`
 
@@ -657,6 +664,16 @@ func (u *User) SetEmailNotifications(set string) error {
 	return nil
 }
 
+// SetEmailNotificationsDigest sets whether the user's notification emails are sent immediately or summarized
+func (u *User) SetEmailNotificationsDigest(digest string) error {
+	u.EmailNotificationsDigest = digest
+	if err := UpdateUserCols(u, "email_notifications_digest"); err != nil {
+		log.Error("SetEmailNotificationsDigest: %v", err)
+		return err
+	}
+	return nil
+}
+
 func isUserExist(e Engine, uid int64, name string) (bool, error) {
 	if len(name) == 0 {
 		return false, nil
@@ -834,6 +851,7 @@ func CreateUser(u *User) (err error) {
 	u.HashPassword(u.Passwd)
 	u.AllowCreateOrganization = setting.Service.DefaultAllowCreateOrganization && !setting.Admin.DisableRegularOrgCreation
 	u.EmailNotificationsPreference = setting.Admin.DefaultEmailNotification
+	u.EmailNotificationsDigest = EmailDigestNone
 	u.MaxRepoCreation = -1
 	u.Theme = setting.UI.DefaultTheme
 
@@ -932,8 +950,7 @@ func ChangeUserName(u *User, newUserName string) (err error) {
 		return fmt.Errorf("Change repo owner name: %v", err)
 	}
 
-	// Do not fail if directory does not exist
-	if err = os.Rename(UserPath(u.Name), UserPath(newUserName)); err != nil && !os.IsNotExist(err) {
+	if err = renameUserStoragePaths(u.Name, newUserName); err != nil {
 		return fmt.Errorf("Rename user directory: %v", err)
 	}
 
@@ -1077,10 +1094,18 @@ func deleteUser(e *xorm.Session, u *User) error {
 		&TeamUser{UID: u.ID},
 		&Collaboration{UserID: u.ID},
 		&Stopwatch{UserID: u.ID},
+		&ReviewState{UserID: u.ID},
+		&IssueFilterSubscription{UserID: u.ID},
+		&EmailNotificationOptOut{UserID: u.ID},
+		&EmailDigestItem{UserID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if err = deleteIssueFiltersByOwnerID(e, u.ID); err != nil {
+		return fmt.Errorf("deleteIssueFiltersByOwnerID: %v", err)
+	}
+
 	// ***** START: PublicKey *****
 	if _, err = e.Delete(&PublicKey{OwnerID: u.ID}); err != nil {
 		return fmt.Errorf("deletePublicKeys: %v", err)
@@ -1119,9 +1144,8 @@ func deleteUser(e *xorm.Session, u *User) error {
 	// FIXME: system notice
 	// Note: There are something just cannot be roll back,
 	//	so just keep error logs of those operations.
-	path := UserPath(u.Name)
-	if err := util.RemoveAll(path); err != nil {
-		return fmt.Errorf("Failed to RemoveAll %s: %v", path, err)
+	if err := removeUserStoragePaths(u.Name); err != nil {
+		return err
 	}
 
 	if len(u.Avatar) > 0 {
@@ -1194,7 +1218,7 @@ func DeleteInactiveUsers(ctx context.Context, olderThan time.Duration) (err erro
 	return err
 }
 
-// UserPath returns the path absolute path of user repositories.
+// UserPath returns the path absolute path of user repositories in the name layout of the default volume.
 func UserPath(userName string) string {
 	return filepath.Join(setting.RepoRootPath, strings.ToLower(userName))
 }
@@ -0,0 +1,170 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// EmailNotificationEvent is a kind of event users can choose to be emailed about
+type EmailNotificationEvent string
+
+const (
+	// EmailEventIssueActivity is the activity on the watched issues and pull requests and those the user participates in
+	EmailEventIssueActivity EmailNotificationEvent = "issue_activity"
+	// EmailEventOwnPullRequests is the activity on the pull requests opened by the user
+	EmailEventOwnPullRequests EmailNotificationEvent = "own_pulls"
+	// EmailEventMention is a mention of the user in an issue, a pull request or a comment
+	EmailEventMention EmailNotificationEvent = "mention"
+	// EmailEventAssigned is the assignment of an issue or a pull request to the user
+	EmailEventAssigned EmailNotificationEvent = "assigned"
+	// EmailEventReviewRequested is a review request of a pull request to the user
+	EmailEventReviewRequested EmailNotificationEvent = "review_requested"
+	// EmailEventCIFailure is a failed commit status of a commit authored by the user
+	EmailEventCIFailure EmailNotificationEvent = "ci_failure"
+	// EmailEventRelease is a new release of a watched repository
+	EmailEventRelease EmailNotificationEvent = "release"
+)
+
+// EmailNotificationEvents lists the events users can opt out of, in the order of the settings page
+var EmailNotificationEvents = []EmailNotificationEvent{
+	EmailEventIssueActivity,
+	EmailEventOwnPullRequests,
+	EmailEventMention,
+	EmailEventAssigned,
+	EmailEventReviewRequested,
+	EmailEventCIFailure,
+	EmailEventRelease,
+}
+
+// IsValid reports whether the event is a known event
+func (event EmailNotificationEvent) IsValid() bool {
+	for _, e := range EmailNotificationEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailNotificationOptOut records that a user doesn't want to be emailed about an event,
+// all the events are emailed by default
+type EmailNotificationOptOut struct {
+	ID     int64                  `xorm:"pk autoincr"`
+	UserID int64                  `xorm:"UNIQUE(s) NOT NULL"`
+	Event  EmailNotificationEvent `xorm:"VARCHAR(50) UNIQUE(s) NOT NULL"`
+}
+
+// GetEmailNotificationOptOuts returns the events the user doesn't want to be emailed about
+func GetEmailNotificationOptOuts(userID int64) (map[EmailNotificationEvent]bool, error) {
+	optOuts := make([]*EmailNotificationOptOut, 0, len(EmailNotificationEvents))
+	if err := x.Where("user_id = ?", userID).Find(&optOuts); err != nil {
+		return nil, err
+	}
+	events := make(map[EmailNotificationEvent]bool, len(optOuts))
+	for _, optOut := range optOuts {
+		events[optOut.Event] = true
+	}
+	return events, nil
+}
+
+// SetEmailNotificationOptOuts replaces the events the user doesn't want to be emailed about
+func SetEmailNotificationOptOuts(userID int64, events []EmailNotificationEvent) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Delete(&EmailNotificationOptOut{UserID: userID}); err != nil {
+		return err
+	}
+	for _, event := range events {
+		if !event.IsValid() {
+			continue
+		}
+		if _, err := sess.Insert(&EmailNotificationOptOut{UserID: userID, Event: event}); err != nil {
+			return err
+		}
+	}
+	return sess.Commit()
+}
+
+// FilterUsersByEmailEvent returns the users who didn't opt out of the emails of the event
+func FilterUsersByEmailEvent(users []*User, event EmailNotificationEvent) ([]*User, error) {
+	if len(users) == 0 {
+		return users, nil
+	}
+	ids := make([]int64, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+
+	optedOut := make([]int64, 0, len(users))
+	if err := x.Table("email_notification_opt_out").
+		Where(builder.In("user_id", ids).And(builder.Eq{"event": event})).
+		Cols("user_id").
+		Find(&optedOut); err != nil {
+		return nil, err
+	}
+	if len(optedOut) == 0 {
+		return users, nil
+	}
+
+	excluded := make(map[int64]bool, len(optedOut))
+	for _, id := range optedOut {
+		excluded[id] = true
+	}
+	filtered := make([]*User, 0, len(users)-len(optedOut))
+	for _, u := range users {
+		if !excluded[u.ID] {
+			filtered = append(filtered, u)
+		}
+	}
+	return filtered, nil
+}
+
+// EmailDigestItem is a notification email buffered until the digest of the user is sent
+type EmailDigestItem struct {
+	ID          int64                  `xorm:"pk autoincr"`
+	UserID      int64                  `xorm:"INDEX NOT NULL"`
+	Event       EmailNotificationEvent `xorm:"VARCHAR(50) NOT NULL"`
+	Subject     string                 `xorm:"TEXT"`
+	Link        string                 `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp     `xorm:"INDEX created"`
+}
+
+// AddEmailDigestItems buffers notifications until the digests of their users are sent
+func AddEmailDigestItems(items []*EmailDigestItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	_, err := x.Insert(&items)
+	return err
+}
+
+// GetEmailDigestUserIDs returns the users having buffered notifications and one of the digest modes
+func GetEmailDigestUserIDs(digests ...string) ([]int64, error) {
+	ids := make([]int64, 0, 10)
+	return ids, x.Table("email_digest_item").
+		Join("INNER", "`user`", "`user`.id = email_digest_item.user_id").
+		In("`user`.email_notifications_digest", digests).
+		Distinct("email_digest_item.user_id").
+		Find(&ids)
+}
+
+// GetEmailDigestItems returns the buffered notifications of the user, oldest first
+func GetEmailDigestItems(userID int64) ([]*EmailDigestItem, error) {
+	items := make([]*EmailDigestItem, 0, 10)
+	return items, x.Where("user_id = ?", userID).Asc("id").Find(&items)
+}
+
+// DeleteEmailDigestItems deletes the buffered notifications of the user up to maxID once they are sent
+func DeleteEmailDigestItems(userID, maxID int64) error {
+	_, err := x.Where("user_id = ? AND id <= ?", userID, maxID).Delete(new(EmailDigestItem))
+	return err
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailNotificationOptOuts(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	optOuts, err := GetEmailNotificationOptOuts(2)
+	assert.NoError(t, err)
+	assert.Empty(t, optOuts)
+
+	assert.NoError(t, SetEmailNotificationOptOuts(2, []EmailNotificationEvent{EmailEventRelease, EmailEventCIFailure, "unknown"}))
+	optOuts, err = GetEmailNotificationOptOuts(2)
+	assert.NoError(t, err)
+	assert.Equal(t, map[EmailNotificationEvent]bool{EmailEventRelease: true, EmailEventCIFailure: true}, optOuts)
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	users, err := FilterUsersByEmailEvent([]*User{user2, user4}, EmailEventRelease)
+	assert.NoError(t, err)
+	assert.Equal(t, []*User{user4}, users)
+	users, err = FilterUsersByEmailEvent([]*User{user2, user4}, EmailEventMention)
+	assert.NoError(t, err)
+	assert.Equal(t, []*User{user2, user4}, users)
+
+	// the opt-outs are replaced
+	assert.NoError(t, SetEmailNotificationOptOuts(2, []EmailNotificationEvent{EmailEventMention}))
+	optOuts, err = GetEmailNotificationOptOuts(2)
+	assert.NoError(t, err)
+	assert.Equal(t, map[EmailNotificationEvent]bool{EmailEventMention: true}, optOuts)
+}
+
+func TestEmailDigestItems(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, user2.SetEmailNotificationsDigest(EmailDigestDaily))
+	assert.NoError(t, AddEmailDigestItems([]*EmailDigestItem{
+		{UserID: 2, Event: EmailEventMention, Subject: "first"},
+		{UserID: 2, Event: EmailEventRelease, Subject: "second"},
+		{UserID: 4, Event: EmailEventMention, Subject: "other"},
+	}))
+
+	ids, err := GetEmailDigestUserIDs(EmailDigestDaily)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, ids)
+	ids, err = GetEmailDigestUserIDs(EmailDigestHourly, EmailDigestNone)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4}, ids)
+
+	items, err := GetEmailDigestItems(2)
+	assert.NoError(t, err)
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, "first", items[0].Subject)
+		assert.Equal(t, "second", items[1].Subject)
+	}
+
+	// only the sent items are deleted
+	assert.NoError(t, DeleteEmailDigestItems(2, items[0].ID))
+	items, err = GetEmailDigestItems(2)
+	assert.NoError(t, err)
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, "second", items[0].Subject)
+	}
+	AssertExistsAndLoadBean(t, &EmailDigestItem{UserID: 4})
+}
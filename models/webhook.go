@@ -781,6 +781,11 @@ func UpdateHookTask(t *HookTask) error {
 	return err
 }
 
+// IsHookTaskDelivered returns whether the hook task has been delivered
+func IsHookTaskDelivered(id int64) (bool, error) {
+	return x.Where("id = ? AND is_delivered = ?", id, true).Exist(new(HookTask))
+}
+
 // FindUndeliveredHookTasks represents find the undelivered hook tasks
 func FindUndeliveredHookTasks() ([]*HookTask, error) {
 	tasks := make([]*HookTask, 0, 10)
@@ -245,3 +245,23 @@ func TestUpdateHookTask(t *testing.T) {
 	assert.NoError(t, UpdateHookTask(hook))
 	AssertExistsAndLoadBean(t, hook)
 }
+
+func TestIsHookTaskDelivered(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	delivered, err := IsHookTaskDelivered(1)
+	assert.NoError(t, err)
+	assert.True(t, delivered)
+
+	hookTask := &HookTask{
+		RepoID:    3,
+		HookID:    3,
+		Type:      GITEA,
+		URL:       "http://www.example.com/unit_test",
+		Payloader: &api.PushPayload{},
+	}
+	assert.NoError(t, CreateHookTask(hookTask))
+	delivered, err = IsHookTaskDelivered(hookTask.ID)
+	assert.NoError(t, err)
+	assert.False(t, delivered)
+}
@@ -6,9 +6,6 @@
 package models
 
 import (
-	"path/filepath"
-	"strings"
-
 	"github.com/unknwon/com"
 )
 
@@ -17,16 +14,6 @@ func (repo *Repository) WikiCloneLink() *CloneLink {
 	return repo.cloneLink(true)
 }
 
-// WikiPath returns wiki data path by given user and repository name.
-func WikiPath(userName, repoName string) string {
-	return filepath.Join(UserPath(userName), strings.ToLower(repoName)+".wiki.git")
-}
-
-// WikiPath returns wiki data path for given repository.
-func (repo *Repository) WikiPath() string {
-	return WikiPath(repo.OwnerName, repo.Name)
-}
-
 // HasWiki returns true if repository has wiki.
 func (repo *Repository) HasWiki() bool {
 	return com.IsDir(repo.WikiPath())
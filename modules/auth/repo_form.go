@@ -127,6 +127,9 @@ type RepoSettingForm struct {
 	Template       bool
 	EnablePrune    bool
 
+	// Code indexer settings
+	CodeIndexerRefs string
+
 	// Advanced settings
 	EnableWiki                       bool
 	EnableExternalWiki               bool
@@ -483,6 +486,21 @@ func (f *CreateMilestoneForm) Validate(ctx *macaron.Context, errs binding.Errors
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// SaveIssueFilterForm form for saving an issue search as a filter
+type SaveIssueFilterForm struct {
+	Name     string `binding:"Required;MaxSize(255)"`
+	Query    string `binding:"Required"`
+	Repos    string
+	OwnerID  int64
+	IsPull   bool
+	IsPinned bool
+}
+
+// Validate validates the fields
+func (f *SaveIssueFilterForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // .____          ___.          .__
 // |    |   _____ \_ |__   ____ |  |
 // |    |   \__  \ | __ \_/ __ \|  |
@@ -496,6 +514,7 @@ type CreateLabelForm struct {
 	Title       string `binding:"Required;MaxSize(50)" locale:"repo.issues.label_title"`
 	Description string `binding:"MaxSize(200)" locale:"repo.issues.label_description"`
 	Color       string `binding:"Required;Size(7)" locale:"repo.issues.label_color"`
+	Exclusive   bool
 }
 
 // Validate validates the fields
@@ -541,6 +560,8 @@ type CodeCommentForm struct {
 	Content        string `binding:"Required"`
 	Side           string `binding:"Required;In(previous,proposed)"`
 	Line           int64
+	StartSide      string `binding:"OmitEmpty;In(previous,proposed)"`
+	StartLine      int64
 	TreePath       string `form:"path" binding:"Required"`
 	IsReview       bool   `form:"is_review"`
 	Reply          int64  `form:"reply"`
@@ -748,6 +769,17 @@ func (f *AddTimeManuallyForm) Validate(ctx *macaron.Context, errs binding.Errors
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// EstimatedTimeForm form that sets the estimated time of an issue.
+type EstimatedTimeForm struct {
+	Hours   int `binding:"Range(0,1000)"`
+	Minutes int `binding:"Range(0,1000)"`
+}
+
+// Validate validates the fields
+func (f *EstimatedTimeForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // SaveTopicForm form for save topics for repository
 type SaveTopicForm struct {
 	Topics []string `binding:"topics;Required;"`
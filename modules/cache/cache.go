@@ -5,10 +5,12 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/tracing"
 
 	mc "gitea.com/macaron/cache"
 
@@ -40,12 +42,30 @@ func NewContext() error {
 	return err
 }
 
+// startSpan starts a span for a lookup of the key if the context is traced, the span must be ended by the caller
+func startSpan(ctx context.Context, key string) *tracing.Span {
+	_, span := tracing.StartChild(ctx, "cache.get", tracing.SpanKindClient)
+	span.SetAttribute("cache.adapter", setting.CacheService.Adapter)
+	span.SetAttribute("cache.key", key)
+	return span
+}
+
 // GetString returns the key value from cache with callback when no key exists in cache
 func GetString(key string, getFunc func() (string, error)) (string, error) {
+	return GetStringContext(context.Background(), key, getFunc)
+}
+
+// GetStringContext is GetString tracing the lookup within the context
+func GetStringContext(ctx context.Context, key string, getFunc func() (string, error)) (string, error) {
 	if conn == nil || setting.CacheService.TTL == 0 {
 		return getFunc()
 	}
-	if !conn.IsExist(key) {
+	span := startSpan(ctx, key)
+	defer span.End()
+	hit := conn.IsExist(key)
+	span.SetAttribute("cache.hit", hit)
+	observeLookup("default", hit)
+	if !hit {
 		var (
 			value string
 			err   error
@@ -70,10 +90,20 @@ func GetString(key string, getFunc func() (string, error)) (string, error) {
 
 // GetInt returns key value from cache with callback when no key exists in cache
 func GetInt(key string, getFunc func() (int, error)) (int, error) {
+	return GetIntContext(context.Background(), key, getFunc)
+}
+
+// GetIntContext is GetInt tracing the lookup within the context
+func GetIntContext(ctx context.Context, key string, getFunc func() (int, error)) (int, error) {
 	if conn == nil || setting.CacheService.TTL == 0 {
 		return getFunc()
 	}
-	if !conn.IsExist(key) {
+	span := startSpan(ctx, key)
+	defer span.End()
+	hit := conn.IsExist(key)
+	span.SetAttribute("cache.hit", hit)
+	observeLookup("default", hit)
+	if !hit {
 		var (
 			value int
 			err   error
@@ -102,10 +132,20 @@ func GetInt(key string, getFunc func() (int, error)) (int, error) {
 
 // GetInt64 returns key value from cache with callback when no key exists in cache
 func GetInt64(key string, getFunc func() (int64, error)) (int64, error) {
+	return GetInt64Context(context.Background(), key, getFunc)
+}
+
+// GetInt64Context is GetInt64 tracing the lookup within the context
+func GetInt64Context(ctx context.Context, key string, getFunc func() (int64, error)) (int64, error) {
 	if conn == nil || setting.CacheService.TTL == 0 {
 		return getFunc()
 	}
-	if !conn.IsExist(key) {
+	span := startSpan(ctx, key)
+	defer span.End()
+	hit := conn.IsExist(key)
+	span.SetAttribute("cache.hit", hit)
+	observeLookup("default", hit)
+	if !hit {
 		var (
 			value int64
 			err   error
@@ -43,7 +43,9 @@ func (c LastCommitCache) getCacheKey(repoPath, ref, entryPath string) string {
 // Get get the last commit information by commit id and entry path
 func (c LastCommitCache) Get(ref, entryPath string) (*object.Commit, error) {
 	v := c.Cache.Get(c.getCacheKey(c.repoPath, ref, entryPath))
-	if vs, ok := v.(string); ok {
+	vs, ok := v.(string)
+	observeLookup("last_commit", ok)
+	if ok {
 		log.Trace("LastCommitCache hit level 1: [%s:%s:%s]", ref, entryPath, vs)
 		if commit, ok := c.commitCache[vs]; ok {
 			log.Trace("LastCommitCache hit level 2: [%s:%s:%s]", ref, entryPath, vs)
@@ -0,0 +1,29 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var lookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gitea",
+	Subsystem: "cache",
+	Name:      "lookups_total",
+	Help:      "Number of cache lookups by cache and result (hit or miss)",
+}, []string{"cache", "result"})
+
+func init() {
+	prometheus.MustRegister(lookups)
+}
+
+// observeLookup records whether a lookup in the named cache was a hit or a miss
+func observeLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	lookups.WithLabelValues(cache, result).Inc()
+}
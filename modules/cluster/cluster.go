@@ -0,0 +1,112 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package cluster coordinates the Gitea instances which share the same database,
+// so that background jobs run exactly once whichever instance picks them up.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Locker provides the named locks shared by the Gitea instances.
+// A lock is held by its owner until it is released or its time to live has passed.
+type Locker interface {
+	// Acquire takes the named lock for the owner if it is free, or extends it if the owner
+	// already holds it, and returns whether the owner holds it
+	Acquire(name, owner string, ttl time.Duration) (bool, error)
+	// Release releases the named lock if it is held by the owner
+	Release(name, owner string) error
+}
+
+var (
+	locker   Locker = newMemoryLocker()
+	instance        = func() string {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "localhost"
+		}
+		return fmt.Sprintf("%s:%d", hostname, os.Getpid())
+	}()
+	ownerCounter int64
+	leaderOwner  = newOwner()
+	// leader is 1 while this instance is the leader, a lone instance is always the leader
+	leader int32 = 1
+)
+
+const leaderLockName = "leader"
+
+// Instance returns the name identifying this Gitea process
+func Instance() string {
+	return instance
+}
+
+// newOwner returns a new owner for a lock, which is unique across the instances
+func newOwner() string {
+	return fmt.Sprintf("%s#%d", instance, atomic.AddInt64(&ownerCounter, 1))
+}
+
+// Init sets up the locker configured in [cluster] and starts the election of the leader
+func Init() error {
+	switch setting.Cluster.LockType {
+	case "memory":
+		locker = newMemoryLocker()
+	case "redis":
+		l, err := newRedisLocker(setting.Cluster.LockConnStr)
+		if err != nil {
+			return err
+		}
+		locker = l
+	default:
+		locker = databaseLocker{}
+	}
+
+	elect()
+	go graceful.GetManager().RunWithShutdownContext(runElection)
+	return nil
+}
+
+// IsLeader returns whether this instance is the leader, which runs the scheduled jobs on behalf of all the instances
+func IsLeader() bool {
+	return atomic.LoadInt32(&leader) == 1
+}
+
+func elect() {
+	acquired, err := locker.Acquire(leaderLockName, leaderOwner, setting.Cluster.LockTTL)
+	if err != nil {
+		log.Error("Unable to take the leadership: %v", err)
+	}
+	if acquired && atomic.SwapInt32(&leader, 1) == 0 {
+		log.Info("Instance %s is now the leader", instance)
+	} else if !acquired && atomic.SwapInt32(&leader, 0) == 1 {
+		log.Info("Instance %s is no longer the leader", instance)
+	}
+}
+
+func runElection(ctx context.Context) {
+	ticker := time.NewTicker(setting.Cluster.LockTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Let another instance take over without waiting for the lock to expire
+			if atomic.SwapInt32(&leader, 0) == 1 {
+				if err := locker.Release(leaderLockName, leaderOwner); err != nil {
+					log.Error("Unable to give up the leadership: %v", err)
+				}
+			}
+			return
+		case <-ticker.C:
+			elect()
+		}
+	}
+}
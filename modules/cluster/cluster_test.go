@@ -0,0 +1,111 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLocker(t *testing.T) {
+	l := newMemoryLocker()
+
+	acquired, err := l.Acquire("test", "owner1", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	// the owner extends the lock, no one else can take it
+	acquired, _ = l.Acquire("test", "owner1", time.Minute)
+	assert.True(t, acquired)
+	acquired, _ = l.Acquire("test", "owner2", time.Minute)
+	assert.False(t, acquired)
+
+	// only the owner releases the lock
+	assert.NoError(t, l.Release("test", "owner2"))
+	acquired, _ = l.Acquire("test", "owner2", time.Minute)
+	assert.False(t, acquired)
+	assert.NoError(t, l.Release("test", "owner1"))
+	acquired, _ = l.Acquire("test", "owner2", time.Millisecond)
+	assert.True(t, acquired)
+
+	// an expired lock can be taken
+	time.Sleep(5 * time.Millisecond)
+	acquired, _ = l.Acquire("test", "owner1", time.Minute)
+	assert.True(t, acquired)
+}
+
+func TestTryAcquire(t *testing.T) {
+	locker = newMemoryLocker()
+
+	l, err := TryAcquire(context.Background(), "test")
+	assert.NoError(t, err)
+	if assert.NotNil(t, l) {
+		other, err := TryAcquire(context.Background(), "test")
+		assert.NoError(t, err)
+		assert.Nil(t, other)
+
+		l.Release()
+		assert.Error(t, l.Context().Err())
+	}
+
+	l, err = TryAcquire(context.Background(), "test")
+	assert.NoError(t, err)
+	assert.NotNil(t, l)
+	l.Release()
+}
+
+func TestAcquire(t *testing.T) {
+	locker = newMemoryLocker()
+
+	l, err := TryAcquire(context.Background(), "test")
+	assert.NoError(t, err)
+	assert.NotNil(t, l)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = Acquire(ctx, "test")
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		l.Release()
+	}()
+	l, err = Acquire(context.Background(), "test")
+	assert.NoError(t, err)
+	if assert.NotNil(t, l) {
+		l.Release()
+	}
+}
+
+func TestLockLost(t *testing.T) {
+	locker = newMemoryLocker()
+	defer func(ttl time.Duration) {
+		setting.Cluster.LockTTL = ttl
+	}(setting.Cluster.LockTTL)
+	setting.Cluster.LockTTL = 30 * time.Millisecond
+
+	l, err := TryAcquire(context.Background(), "test")
+	assert.NoError(t, err)
+	if !assert.NotNil(t, l) {
+		return
+	}
+	defer l.Release()
+
+	// another instance takes the lock from under us
+	assert.NoError(t, locker.Release("test", l.owner))
+	acquired, _ := locker.Acquire("test", "other", time.Minute)
+	assert.True(t, acquired)
+
+	select {
+	case <-l.Context().Done():
+	case <-time.After(time.Second):
+		assert.Fail(t, "the context of the lost lock has not been cancelled")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Lock is a named lock taken by this instance. It is extended in the background until
+// it is released, and its context is cancelled if it gets lost to another instance.
+type Lock struct {
+	name   string
+	owner  string
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// TryAcquire takes the named lock if no one holds it, returning nil if it is held by someone else
+func TryAcquire(ctx context.Context, name string) (*Lock, error) {
+	owner := newOwner()
+	acquired, err := locker.Acquire(name, owner, setting.Cluster.LockTTL)
+	if err != nil || !acquired {
+		return nil, err
+	}
+
+	l := &Lock{
+		name:  name,
+		owner: owner,
+		done:  make(chan struct{}),
+	}
+	l.ctx, l.cancel = context.WithCancel(ctx)
+	go l.keep()
+	return l, nil
+}
+
+// Acquire waits until it takes the named lock or the context is done
+func Acquire(ctx context.Context, name string) (*Lock, error) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		l, err := TryAcquire(ctx, name)
+		if err != nil || l != nil {
+			return l, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Context returns a context which is cancelled when the lock is released or lost
+func (l *Lock) Context() context.Context {
+	return l.ctx
+}
+
+// Release releases the lock
+func (l *Lock) Release() {
+	l.cancel()
+	<-l.done
+	if err := locker.Release(l.name, l.owner); err != nil {
+		log.Error("Unable to release lock: %s Error: %v", l.name, err)
+	}
+}
+
+// keep extends the lock until it is released, cancelling its context if it has been lost
+func (l *Lock) keep() {
+	defer close(l.done)
+	ttl := setting.Cluster.LockTTL
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+	extended := time.Now()
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-ticker.C:
+			acquired, err := locker.Acquire(l.name, l.owner, ttl)
+			switch {
+			case err != nil && time.Since(extended) < ttl:
+				log.Error("Unable to extend lock: %s Error: %v", l.name, err)
+			case err != nil || !acquired:
+				log.Warn("Lock: %s has been lost", l.name)
+				l.cancel()
+				return
+			default:
+				extended = time.Now()
+			}
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/models"
+)
+
+// databaseLocker is a Locker whose locks are stored in the resource_lock table
+type databaseLocker struct{}
+
+// Acquire takes or extends the named lock for the owner
+func (databaseLocker) Acquire(name, owner string, ttl time.Duration) (bool, error) {
+	return models.AcquireResourceLock(name, owner, ttl)
+}
+
+// Release releases the named lock if it is held by the owner
+func (databaseLocker) Release(name, owner string) error {
+	return models.ReleaseResourceLock(name, owner)
+}
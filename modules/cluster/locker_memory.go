@@ -0,0 +1,52 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"sync"
+	"time"
+)
+
+type memoryLock struct {
+	owner   string
+	expires time.Time
+}
+
+// memoryLocker is a Locker whose locks are only seen by this instance
+type memoryLocker struct {
+	mutex sync.Mutex
+	locks map[string]memoryLock
+}
+
+func newMemoryLocker() *memoryLocker {
+	return &memoryLocker{
+		locks: make(map[string]memoryLock),
+	}
+}
+
+// Acquire takes or extends the named lock for the owner
+func (m *memoryLocker) Acquire(name, owner string, ttl time.Duration) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	now := time.Now()
+	if l, ok := m.locks[name]; ok && l.owner != owner && l.expires.After(now) {
+		return false, nil
+	}
+	m.locks[name] = memoryLock{
+		owner:   owner,
+		expires: now.Add(ttl),
+	}
+	return true, nil
+}
+
+// Release releases the named lock if it is held by the owner
+func (m *memoryLocker) Release(name, owner string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if l, ok := m.locks[name]; ok && l.owner == owner {
+		delete(m.locks, name)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/nosql"
+
+	"github.com/go-redis/redis/v7"
+)
+
+const redisKeyPrefix = "gitea:lock:"
+
+var (
+	acquireScript = redis.NewScript(`
+local owner = redis.call("GET", KEYS[1])
+if owner == false or owner == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+return 0`)
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// redisLocker is a Locker whose locks are stored in redis
+type redisLocker struct {
+	client redis.UniversalClient
+}
+
+func newRedisLocker(connStr string) (*redisLocker, error) {
+	client := nosql.GetManager().GetRedisClient(connStr)
+	if err := client.Ping().Err(); err != nil {
+		return nil, err
+	}
+	return &redisLocker{client: client}, nil
+}
+
+// Acquire takes or extends the named lock for the owner
+func (r *redisLocker) Acquire(name, owner string, ttl time.Duration) (bool, error) {
+	acquired, err := acquireScript.Run(r.client, []string{redisKeyPrefix + name}, owner, ttl.Milliseconds()).Int()
+	return acquired == 1, err
+}
+
+// Release releases the named lock if it is held by the owner
+func (r *redisLocker) Release(name, owner string) error {
+	return releaseScript.Run(r.client, []string{redisKeyPrefix + name}, owner).Err()
+}
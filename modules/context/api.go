@@ -84,7 +84,7 @@ func (ctx *APIContext) Error(status int, title string, obj interface{}) {
 	}
 
 	if status == http.StatusInternalServerError {
-		log.ErrorWithSkip(1, "%s: %s", title, message)
+		log.FromContext(ctx.Req.Context()).ErrorWithSkip(1, "%s: %s", title, message)
 
 		if macaron.Env == macaron.PROD && !(ctx.User != nil && ctx.User.IsAdmin) {
 			message = ""
@@ -100,7 +100,7 @@ func (ctx *APIContext) Error(status int, title string, obj interface{}) {
 // InternalServerError responds with an error message to the client with the error as a message
 // and the file and line of the caller.
 func (ctx *APIContext) InternalServerError(err error) {
-	log.ErrorWithSkip(1, "InternalServerError: %v", err)
+	log.FromContext(ctx.Req.Context()).ErrorWithSkip(1, "InternalServerError: %v", err)
 
 	var message string
 	if macaron.Env != macaron.PROD || (ctx.User != nil && ctx.User.IsAdmin) {
@@ -215,7 +215,7 @@ func ReferencesGitRepo(allowEmpty bool) macaron.Handler {
 
 		// For API calls.
 		if ctx.Repo.GitRepo == nil {
-			repoPath := models.RepoPath(ctx.Repo.Owner.Name, ctx.Repo.Repository.Name)
+			repoPath := ctx.Repo.Repository.RepoPath()
 			gitRepo, err := git.OpenRepository(repoPath)
 			if err != nil {
 				ctx.Error(500, "RepoRef Invalid repo "+repoPath, err)
@@ -271,7 +271,7 @@ func RepoRefForAPI() macaron.Handler {
 		var err error
 
 		if ctx.Repo.GitRepo == nil {
-			repoPath := models.RepoPath(ctx.Repo.Owner.Name, ctx.Repo.Repository.Name)
+			repoPath := ctx.Repo.Repository.RepoPath()
 			ctx.Repo.GitRepo, err = git.OpenRepository(repoPath)
 			if err != nil {
 				ctx.InternalServerError(err)
@@ -157,7 +157,7 @@ func (ctx *Context) NotFound(title string, err error) {
 
 func (ctx *Context) notFoundInternal(title string, err error) {
 	if err != nil {
-		log.ErrorWithSkip(2, "%s: %v", title, err)
+		log.FromContext(ctx.Req.Context()).ErrorWithSkip(2, "%s: %v", title, err)
 		if macaron.Env != macaron.PROD {
 			ctx.Data["ErrorMsg"] = err
 		}
@@ -176,7 +176,7 @@ func (ctx *Context) ServerError(title string, err error) {
 
 func (ctx *Context) serverErrorInternal(title string, err error) {
 	if err != nil {
-		log.ErrorWithSkip(2, "%s: %v", title, err)
+		log.FromContext(ctx.Req.Context()).ErrorWithSkip(2, "%s: %v", title, err)
 		if macaron.Env != macaron.PROD {
 			ctx.Data["ErrorMsg"] = err
 		}
@@ -201,7 +201,7 @@ func (ctx *Context) NotFoundOrServerError(title string, errck func(error) bool,
 // HandleText handles HTTP status code
 func (ctx *Context) HandleText(status int, title string) {
 	if (status/100 == 4) || (status/100 == 5) {
-		log.Error("%s", title)
+		log.FromContext(ctx.Req.Context()).Error("%s", title)
 	}
 	ctx.PlainText(status, []byte(title))
 }
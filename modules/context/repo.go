@@ -6,6 +6,7 @@
 package context
 
 import (
+	gocontext "context"
 	"fmt"
 	"io/ioutil"
 	"net/url"
@@ -157,7 +158,7 @@ func (r *Repository) CanCreateIssueDependencies(user *models.User, isPull bool)
 }
 
 // GetCommitsCount returns cached commit count for current view
-func (r *Repository) GetCommitsCount() (int64, error) {
+func (r *Repository) GetCommitsCount(ctx gocontext.Context) (int64, error) {
 	var contextName string
 	if r.IsViewBranch {
 		contextName = r.BranchName
@@ -166,16 +167,16 @@ func (r *Repository) GetCommitsCount() (int64, error) {
 	} else {
 		contextName = r.CommitID
 	}
-	return cache.GetInt64(r.Repository.GetCommitsCountCacheKey(contextName, r.IsViewBranch || r.IsViewTag), func() (int64, error) {
+	return cache.GetInt64Context(ctx, r.Repository.GetCommitsCountCacheKey(contextName, r.IsViewBranch || r.IsViewTag), func() (int64, error) {
 		return r.Commit.CommitsCount()
 	})
 }
 
 // GetCommitGraphsCount returns cached commit count for current view
-func (r *Repository) GetCommitGraphsCount(hidePRRefs bool, branches []string, files []string) (int64, error) {
+func (r *Repository) GetCommitGraphsCount(ctx gocontext.Context, hidePRRefs bool, branches []string, files []string) (int64, error) {
 	cacheKey := fmt.Sprintf("commits-count-%d-graph-%t-%s-%s", r.Repository.ID, hidePRRefs, branches, files)
 
-	return cache.GetInt64(cacheKey, func() (int64, error) {
+	return cache.GetInt64Context(ctx, cacheKey, func() (int64, error) {
 		if len(branches) == 0 {
 			return git.AllCommitsCount(r.Repository.RepoPath(), hidePRRefs, files...)
 		}
@@ -521,9 +522,9 @@ func RepoAssignment() macaron.Handler {
 			return
 		}
 
-		gitRepo, err := git.OpenRepository(models.RepoPath(userName, repoName))
+		gitRepo, err := git.OpenRepository(repo.RepoPath())
 		if err != nil {
-			ctx.ServerError("RepoAssignment Invalid repo "+models.RepoPath(userName, repoName), err)
+			ctx.ServerError("RepoAssignment Invalid repo "+repo.RepoPath(), err)
 			return
 		}
 		ctx.Repo.GitRepo = gitRepo
@@ -717,7 +718,7 @@ func RepoRefByType(refType RepoRefType) macaron.Handler {
 		)
 
 		if ctx.Repo.GitRepo == nil {
-			repoPath := models.RepoPath(ctx.Repo.Owner.Name, ctx.Repo.Repository.Name)
+			repoPath := ctx.Repo.Repository.RepoPath()
 			ctx.Repo.GitRepo, err = git.OpenRepository(repoPath)
 			if err != nil {
 				ctx.ServerError("RepoRef Invalid repo "+repoPath, err)
@@ -812,7 +813,7 @@ func RepoRefByType(refType RepoRefType) macaron.Handler {
 		ctx.Data["IsViewCommit"] = ctx.Repo.IsViewCommit
 		ctx.Data["CanCreateBranch"] = ctx.Repo.CanCreateBranch()
 
-		ctx.Repo.CommitsCount, err = ctx.Repo.GetCommitsCount()
+		ctx.Repo.CommitsCount, err = ctx.Repo.GetCommitsCount(ctx.Req.Context())
 		if err != nil {
 			ctx.ServerError("GetCommitsCount", err)
 			return
@@ -0,0 +1,41 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ToCodeSearchResult convert a code_indexer.Result of repo to api.CodeSearchResult
+func ToCodeSearchResult(repo *models.Repository, r *code_indexer.Result) *api.CodeSearchResult {
+	return &api.CodeSearchResult{
+		RepoID:       repo.ID,
+		RepoFullName: repo.FullName(),
+		Ref:          r.Ref,
+		Filename:     r.Filename,
+		CommitID:     r.CommitID,
+		Language:     r.Language,
+		LineNumbers:  r.LineNumbers,
+		Content:      r.Lines,
+		HTMLURL:      repo.HTMLURL() + "/src/commit/" + r.CommitID + "/" + util.PathEscapeSegments(r.Filename),
+		Updated:      r.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToCodeSearchLanguages convert a list of code_indexer.SearchResultLanguages to api.CodeSearchLanguage
+func ToCodeSearchLanguages(languages []*code_indexer.SearchResultLanguages) []*api.CodeSearchLanguage {
+	result := make([]*api.CodeSearchLanguage, len(languages))
+	for i, language := range languages {
+		result[i] = &api.CodeSearchLanguage{
+			Language: language.Language,
+			Color:    language.Color,
+			Count:    language.Count,
+		}
+	}
+	return result
+}
@@ -27,19 +27,21 @@ func ToAPIIssue(issue *models.Issue) *api.Issue {
 	}
 
 	apiIssue := &api.Issue{
-		ID:       issue.ID,
-		URL:      issue.APIURL(),
-		HTMLURL:  issue.HTMLURL(),
-		Index:    issue.Index,
-		Poster:   ToUser(issue.Poster, false, false),
-		Title:    issue.Title,
-		Body:     issue.Content,
-		Labels:   ToLabelList(issue.Labels),
-		State:    issue.State(),
-		IsLocked: issue.IsLocked,
-		Comments: issue.NumComments,
-		Created:  issue.CreatedUnix.AsTime(),
-		Updated:  issue.UpdatedUnix.AsTime(),
+		ID:            issue.ID,
+		URL:           issue.APIURL(),
+		HTMLURL:       issue.HTMLURL(),
+		Index:         issue.Index,
+		Poster:        ToUser(issue.Poster, false, false),
+		Title:         issue.Title,
+		Body:          issue.Content,
+		Labels:        ToLabelList(issue.Labels),
+		State:         issue.State(),
+		IsLocked:      issue.IsLocked,
+		Comments:      issue.NumComments,
+		PinOrder:      issue.PinOrder,
+		EstimatedTime: issue.EstimatedTime,
+		Created:       issue.CreatedUnix.AsTime(),
+		Updated:       issue.UpdatedUnix.AsTime(),
 	}
 
 	apiIssue.Repo = &api.RepositoryMeta{
@@ -171,6 +173,7 @@ func ToLabel(label *models.Label) *api.Label {
 		Name:        label.Name,
 		Color:       strings.TrimLeft(label.Color, "#"),
 		Description: label.Description,
+		Exclusive:   label.Exclusive,
 	}
 }
 
@@ -203,3 +206,24 @@ func ToAPIMilestone(m *models.Milestone) *api.Milestone {
 	}
 	return apiMilestone
 }
+
+// ToAPIMilestoneSnapshot converts MilestoneSnapshot into API Format
+func ToAPIMilestoneSnapshot(s *models.MilestoneSnapshot) *api.MilestoneSnapshot {
+	return &api.MilestoneSnapshot{
+		Day:          s.Day.AsTime(),
+		OpenIssues:   s.NumOpenIssues,
+		ClosedIssues: s.NumClosedIssues,
+		TrackedTime:  s.TrackedTime,
+	}
+}
+
+// ToAPIMilestoneVelocity converts MilestoneVelocity into API Format
+func ToAPIMilestoneVelocity(v *models.MilestoneVelocity) *api.MilestoneVelocity {
+	return &api.MilestoneVelocity{
+		Milestone:     ToAPIMilestone(v.Milestone),
+		Days:          v.Days,
+		ClosedIssues:  v.NumClosedIssues,
+		TrackedTime:   v.TrackedTime,
+		IssuesPerWeek: v.IssuesPerWeek(),
+	}
+}
@@ -112,6 +112,13 @@ func ToPullReviewCommentList(review *models.Review, doer *models.User) ([]*api.P
 				} else {
 					apiComment.LineNum = comment.UnsignedLine()
 				}
+				if comment.IsMultiLine() {
+					if comment.StartLine < 0 {
+						apiComment.OldStartLineNum = comment.UnsignedStartLine()
+					} else {
+						apiComment.StartLineNum = comment.UnsignedStartLine()
+					}
+				}
 				apiComments = append(apiComments, apiComment)
 			}
 		}
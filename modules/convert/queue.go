@@ -0,0 +1,42 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/modules/queue"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToQueue convert queue.ManagedQueue to api.Queue
+func ToQueue(mq *queue.ManagedQueue) *api.Queue {
+	apiQueue := &api.Queue{
+		ID:                 mq.QID,
+		Name:               mq.Name,
+		Type:               string(mq.Type),
+		ExemplarType:       mq.ExemplarType,
+		NumberOfWorkers:    mq.NumberOfWorkers(),
+		MaxNumberOfWorkers: mq.MaxNumberOfWorkers(),
+		NumberInQueue:      mq.NumberInQueue(),
+		Pausable:           mq.IsPausable(),
+		Paused:             mq.IsPaused(),
+		Inspectable:        mq.IsInspectable(),
+	}
+	if apiQueue.NumberOfWorkers < 0 {
+		apiQueue.MaxNumberOfWorkers = -1
+	}
+	return apiQueue
+}
+
+// ToQueueItems convert the items of a queue starting at the index skip to api.QueueItem
+func ToQueueItems(skip int, items [][]byte) []*api.QueueItem {
+	apiItems := make([]*api.QueueItem, len(items))
+	for i, item := range items {
+		apiItems[i] = &api.QueueItem{
+			Index: skip + i,
+			Data:  string(item),
+		}
+	}
+	return apiItems
+}
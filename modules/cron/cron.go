@@ -7,9 +7,12 @@ package cron
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/sync"
 
 	"github.com/gogs/cron"
@@ -20,6 +23,9 @@ var c = cron.New()
 // Prevent duplicate running tasks.
 var taskStatusTable = sync.NewStatusTable()
 
+// settingsSyncInterval is how often the settings changed at runtime by the other instances are loaded
+const settingsSyncInterval = time.Minute
+
 // NewContext begins cron tasks
 // Each cron task is run within the shutdown context as a running server
 // AtShutdown the cron server is stopped
@@ -28,6 +34,9 @@ func NewContext() {
 	initExtendedTasks()
 
 	lock.Lock()
+	if err := loadTaskSettings(); err != nil {
+		log.Error("Unable to load the runtime settings of the cron tasks: %v", err)
+	}
 	for _, task := range tasks {
 		if task.IsEnabled() && task.DoRunAtStart() {
 			go task.Run()
@@ -37,22 +46,150 @@ func NewContext() {
 	c.Start()
 	started = true
 	lock.Unlock()
+
+	go graceful.GetManager().RunWithShutdownContext(syncTaskSettings)
 	graceful.GetManager().RunAtShutdown(context.Background(), func() {
-		c.Stop()
 		lock.Lock()
+		c.Stop()
 		started = false
 		lock.Unlock()
 	})
 
 }
 
+// syncTaskSettings periodically loads the runtime settings so that the changes made on another instance apply
+func syncTaskSettings(ctx context.Context) {
+	ticker := time.NewTicker(settingsSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			lock.Lock()
+			if err := loadTaskSettings(); err != nil {
+				log.Error("Unable to load the runtime settings of the cron tasks: %v", err)
+			}
+			lock.Unlock()
+		}
+	}
+}
+
+// loadTaskSettings applies the runtime settings stored in the database to the tasks,
+// rescheduling them if any changed. The lock must be held.
+func loadTaskSettings() error {
+	settings, err := models.GetCronTasks()
+	if err != nil {
+		return err
+	}
+	settingsMap := make(map[string]*models.CronTask, len(settings))
+	for _, setting := range settings {
+		settingsMap[setting.Name] = setting
+	}
+
+	changed := false
+	for _, task := range tasks {
+		if task.setSetting(settingsMap[task.Name]) {
+			changed = true
+		}
+	}
+	if changed {
+		reschedule()
+	}
+	return nil
+}
+
+// reschedule replaces the scheduler by one scheduling the enabled tasks with their current schedules,
+// as jobs cannot be removed from a scheduler. The lock must be held.
+func reschedule() {
+	newC := cron.New()
+	for _, task := range tasks {
+		if !task.IsEnabled() {
+			continue
+		}
+		if _, err := newC.AddJob(task.Name, task.Schedule(), task); err != nil {
+			log.Error("Unable to schedule cron task with name: %s Error: %v", task.Name, err)
+		}
+	}
+	if started {
+		c.Stop()
+		newC.Start()
+	}
+	c = newC
+}
+
+// UpdateTask changes at runtime whether the named task is enabled and its schedule, an empty schedule keeps the current one
+func UpdateTask(name string, enabled bool, schedule string) error {
+	lock.Lock()
+	defer lock.Unlock()
+	task, ok := tasksMap[name]
+	if !ok {
+		return fmt.Errorf("unknown cron task: %s", name)
+	}
+	if schedule == "" {
+		schedule = task.Schedule()
+	}
+	if _, err := cron.Parse(schedule); err != nil {
+		return ErrInvalidSchedule{Schedule: schedule, Err: err}
+	}
+
+	setting := &models.CronTask{
+		Name:     name,
+		Enabled:  enabled,
+		Schedule: schedule,
+	}
+	if err := models.SaveCronTask(setting); err != nil {
+		return err
+	}
+	if task.setSetting(setting) {
+		reschedule()
+	}
+	return nil
+}
+
+// ResetTask removes the runtime settings of the named task so that its configuration applies again
+func ResetTask(name string) error {
+	lock.Lock()
+	defer lock.Unlock()
+	task, ok := tasksMap[name]
+	if !ok {
+		return fmt.Errorf("unknown cron task: %s", name)
+	}
+	if err := models.DeleteCronTask(name); err != nil {
+		return err
+	}
+	if task.setSetting(nil) {
+		reschedule()
+	}
+	return nil
+}
+
+// ErrInvalidSchedule represents an invalid cron schedule
+type ErrInvalidSchedule struct {
+	Schedule string
+	Err      error
+}
+
+// IsErrInvalidSchedule checks if an error is an ErrInvalidSchedule
+func IsErrInvalidSchedule(err error) bool {
+	_, ok := err.(ErrInvalidSchedule)
+	return ok
+}
+
+func (err ErrInvalidSchedule) Error() string {
+	return fmt.Sprintf("invalid schedule %q: %v", err.Schedule, err.Err)
+}
+
 // TaskTableRow represents a task row in the tasks table
 type TaskTableRow struct {
-	Name      string
-	Spec      string
-	Next      time.Time
-	Prev      time.Time
-	ExecTimes int64
+	Name       string
+	Spec       string
+	Enabled    bool
+	Overridden bool
+	Next       time.Time
+	Prev       time.Time
+	ExecTimes  int64
+	LastRun    *models.CronTaskRun
 }
 
 // TaskTable represents a table of tasks
@@ -60,35 +197,61 @@ type TaskTable []*TaskTableRow
 
 // ListTasks returns all running cron tasks.
 func ListTasks() TaskTable {
+	lastRuns, err := models.GetLastCronTaskRuns()
+	if err != nil {
+		log.Error("Unable to get the last runs of the cron tasks: %v", err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
 	entries := c.Entries()
 	eMap := map[string]*cron.Entry{}
 	for _, e := range entries {
 		eMap[e.Description] = e
 	}
-	lock.Lock()
-	defer lock.Unlock()
 	tTable := make([]*TaskTableRow, 0, len(tasks))
 	for _, task := range tasks {
-		spec := "-"
-		var (
-			next time.Time
-			prev time.Time
-		)
-		if e, ok := eMap[task.Name]; ok {
-			spec = e.Spec
-			next = e.Next
-			prev = e.Prev
-		}
-		task.lock.Lock()
-		tTable = append(tTable, &TaskTableRow{
-			Name:      task.Name,
-			Spec:      spec,
-			Next:      next,
-			Prev:      prev,
-			ExecTimes: task.ExecTimes,
-		})
-		task.lock.Unlock()
+		tTable = append(tTable, newTaskTableRow(task, eMap[task.Name], lastRuns[task.Name]))
 	}
 
 	return tTable
 }
+
+// GetTaskTableRow returns the row of the named task or nil if there is no such task
+func GetTaskTableRow(name string) *TaskTableRow {
+	lastRuns, err := models.GetLastCronTaskRuns()
+	if err != nil {
+		log.Error("Unable to get the last runs of the cron tasks: %v", err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	task, ok := tasksMap[name]
+	if !ok {
+		return nil
+	}
+	for _, e := range c.Entries() {
+		if e.Description == name {
+			return newTaskTableRow(task, e, lastRuns[name])
+		}
+	}
+	return newTaskTableRow(task, nil, lastRuns[name])
+}
+
+func newTaskTableRow(task *Task, e *cron.Entry, lastRun *models.CronTaskRun) *TaskTableRow {
+	row := &TaskTableRow{
+		Name:       task.Name,
+		Spec:       task.Schedule(),
+		Enabled:    task.IsEnabled(),
+		Overridden: task.IsOverridden(),
+		LastRun:    lastRun,
+	}
+	if e != nil {
+		row.Next = e.Next
+		row.Prev = e.Prev
+	}
+	task.lock.Lock()
+	row.ExecTimes = task.ExecTimes
+	task.lock.Unlock()
+	return row
+}
@@ -11,6 +11,7 @@ import (
 	"sync"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/cluster"
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
@@ -29,6 +30,8 @@ type Task struct {
 	config    Config
 	fun       func(context.Context, *models.User, Config) error
 	ExecTimes int64
+	// setting holds the runtime settings overriding the config, nil if there are none
+	setting *models.CronTask
 }
 
 // DoRunAtStart returns if this task should run at the start
@@ -38,9 +41,41 @@ func (t *Task) DoRunAtStart() bool {
 
 // IsEnabled returns if this task is enabled as cron task
 func (t *Task) IsEnabled() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.setting != nil {
+		return t.setting.Enabled
+	}
 	return t.config.IsEnabled()
 }
 
+// Schedule returns the schedule of this task
+func (t *Task) Schedule() string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.setting != nil {
+		return t.setting.Schedule
+	}
+	return t.config.GetSchedule()
+}
+
+// IsOverridden returns if the settings of this task have been changed at runtime
+func (t *Task) IsOverridden() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.setting != nil
+}
+
+// setSetting replaces the runtime settings of the task and returns whether they changed
+func (t *Task) setSetting(setting *models.CronTask) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	changed := (t.setting == nil) != (setting == nil) ||
+		(setting != nil && (t.setting.Enabled != setting.Enabled || t.setting.Schedule != setting.Schedule))
+	t.setting = setting
+	return changed
+}
+
 // GetConfig will return a copy of the task's config
 func (t *Task) GetConfig() Config {
 	if reflect.TypeOf(t.config).Kind() == reflect.Ptr {
@@ -51,8 +86,13 @@ func (t *Task) GetConfig() Config {
 	return reflect.New(reflect.TypeOf(t.config)).Elem().Interface().(Config)
 }
 
-// Run will run the task incrementing the cron counter with no user defined
+// Run will run the task incrementing the cron counter with no user defined.
+// Only the leader instance runs the scheduled tasks.
 func (t *Task) Run() {
+	if !cluster.IsLeader() {
+		log.Debug("Task: %s is left to the leader instance", t.Name)
+		return
+	}
 	t.RunWithUser(&models.User{
 		ID:        -1,
 		Name:      "(Cron)",
@@ -65,39 +105,73 @@ func (t *Task) RunWithUser(doer *models.User, config Config) {
 	if !taskStatusTable.StartIfNotRunning(t.Name) {
 		return
 	}
+	defer taskStatusTable.Stop(t.Name)
+
+	// Prevent the other instances from running the task at the same time
+	taskLock, err := cluster.TryAcquire(graceful.GetManager().ShutdownContext(), "cron:"+t.Name)
+	if err != nil {
+		log.Error("Unable to lock task: %s Error: %v", t.Name, err)
+		return
+	} else if taskLock == nil {
+		log.Debug("Task: %s is already running on another instance", t.Name)
+		return
+	}
+	defer taskLock.Release()
+
 	t.lock.Lock()
 	if config == nil {
 		config = t.config
 	}
 	t.ExecTimes++
 	t.lock.Unlock()
+
+	run := &models.CronTaskRun{
+		Name:     t.Name,
+		DoerID:   doer.ID,
+		DoerName: doer.Name,
+		Instance: cluster.Instance(),
+	}
+	if err := models.CreateCronTaskRun(run); err != nil {
+		log.Error("Unable to record the run of task: %s Error: %v", t.Name, err)
+	}
+	status, message := models.CronTaskRunFailed, ""
 	defer func() {
-		taskStatusTable.Stop(t.Name)
 		if err := recover(); err != nil {
 			// Recover a panic within the
 			combinedErr := fmt.Errorf("%s\n%s", err, log.Stack(2))
 			log.Error("PANIC whilst running task: %s Value: %v", t.Name, combinedErr)
+			status, message = models.CronTaskRunFailed, fmt.Sprintf("PANIC: %v", err)
+		}
+		if run.ID == 0 {
+			return
+		}
+		if err := models.FinishCronTaskRun(run, status, message, setting.Cron.HistoryLength); err != nil {
+			log.Error("Unable to record the end of the run of task: %s Error: %v", t.Name, err)
 		}
 	}()
-	graceful.GetManager().RunWithShutdownContext(func(baseCtx context.Context) {
-		ctx, cancel := context.WithCancel(baseCtx)
+
+	graceful.GetManager().RunWithShutdownContext(func(context.Context) {
+		// The run is cancelled at shutdown or if the lock gets lost to another instance
+		ctx, cancel := context.WithCancel(taskLock.Context())
 		defer cancel()
 		pm := process.GetManager()
 		pid := pm.Add(config.FormatMessage(t.Name, "process", doer), cancel)
 		defer pm.Remove(pid)
 		if err := t.fun(ctx, doer, config); err != nil {
 			if models.IsErrCancelled(err) {
-				message := err.(models.ErrCancelled).Message
+				status, message = models.CronTaskRunCancelled, err.(models.ErrCancelled).Message
 				if err := models.CreateNotice(models.NoticeTask, config.FormatMessage(t.Name, "aborted", doer, message)); err != nil {
 					log.Error("CreateNotice: %v", err)
 				}
 				return
 			}
+			message = err.Error()
 			if err := models.CreateNotice(models.NoticeTask, config.FormatMessage(t.Name, "error", doer, err)); err != nil {
 				log.Error("CreateNotice: %v", err)
 			}
 			return
 		}
+		status = models.CronTaskRunSucceeded
 		if config.DoNoticeOnSuccess() {
 			if err := models.CreateNotice(models.NoticeTask, config.FormatMessage(t.Name, "finished", doer)); err != nil {
 				log.Error("CreateNotice: %v", err)
@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/migrations"
 	repository_service "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/services/mailer"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 )
 
@@ -108,6 +109,48 @@ func registerUpdateMigrationPosterID() {
 	})
 }
 
+func registerMilestoneSnapshots() {
+	RegisterTaskFatal("milestone_snapshots", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return models.SnapshotMilestones(ctx)
+	})
+}
+
+func registerSendEmailDigests() {
+	RegisterTaskFatal("send_hourly_email_digests", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		// the notifications buffered before their users turned the digest off are flushed too
+		return mailer.SendEmailDigests(ctx, models.EmailDigestHourly, models.EmailDigestNone)
+	})
+	RegisterTaskFatal("send_daily_email_digests", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 24h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return mailer.SendEmailDigests(ctx, models.EmailDigestDaily)
+	})
+}
+
+func registerPendingUploadsCleanup() {
+	RegisterTaskFatal("pending_uploads_cleanup", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 1h",
+		},
+		OlderThan: 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		pucConfig := config.(*OlderThanConfig)
+		return models.DeleteExpiredPendingUploads(ctx, pucConfig.OlderThan)
+	})
+}
+
 func initBasicTasks() {
 	registerUpdateMirrorTask()
 	registerRepoHealthCheck()
@@ -116,4 +159,7 @@ func initBasicTasks() {
 	registerSyncExternalUsers()
 	registerDeletedBranchesCleanup()
 	registerUpdateMigrationPosterID()
+	registerMilestoneSnapshots()
+	registerSendEmailDigests()
+	registerPendingUploadsCleanup()
 }
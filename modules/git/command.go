@@ -15,7 +15,9 @@ import (
 	"strings"
 	"time"
 
+	gitealog "code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/tracing"
 )
 
 var (
@@ -44,6 +46,20 @@ func (c *Command) String() string {
 	return fmt.Sprintf("%s %s", c.name, strings.Join(c.args, " "))
 }
 
+// subcommand returns the git subcommand run by this command, skipping any options before it
+func (c *Command) subcommand() string {
+	for i := 0; i < len(c.args); i++ {
+		switch arg := c.args[i]; {
+		case arg == "-c" || arg == "-C":
+			i++
+		case strings.HasPrefix(arg, "-"):
+		default:
+			return arg
+		}
+	}
+	return ""
+}
+
 // NewCommand creates and returns a new Git Command based on given command and arguments.
 func NewCommand(args ...string) *Command {
 	return NewCommandContext(DefaultContext, args...)
@@ -108,7 +124,7 @@ func (c *Command) RunInDirTimeoutEnvFullPipeline(env []string, timeout time.Dura
 
 // RunInDirTimeoutEnvFullPipelineFunc executes the command in given directory with given timeout,
 // it pipes stdout and stderr to given io.Writer and passes in an io.Reader as stdin. Between cmd.Start and cmd.Wait the passed in function is run.
-func (c *Command) RunInDirTimeoutEnvFullPipelineFunc(env []string, timeout time.Duration, dir string, stdout, stderr io.Writer, stdin io.Reader, fn func(context.Context, context.CancelFunc) error) error {
+func (c *Command) RunInDirTimeoutEnvFullPipelineFunc(env []string, timeout time.Duration, dir string, stdout, stderr io.Writer, stdin io.Reader, fn func(context.Context, context.CancelFunc) error) (err error) {
 	if timeout == -1 {
 		timeout = DefaultCommandExecutionTimeout
 	}
@@ -119,7 +135,17 @@ func (c *Command) RunInDirTimeoutEnvFullPipelineFunc(env []string, timeout time.
 		log("%s: %v", dir, c)
 	}
 
-	ctx, cancel := context.WithTimeout(c.parentContext, timeout)
+	subcommand := c.subcommand()
+	start := time.Now()
+	spanCtx, span := tracing.StartChild(c.parentContext, strings.TrimSpace("git "+subcommand), tracing.SpanKindInternal)
+	span.SetAttribute("git.dir", dir)
+	defer func() {
+		observeCommand(subcommand, start, err)
+		span.SetError(err)
+		span.End()
+	}()
+
+	ctx, cancel := context.WithTimeout(spanCtx, timeout)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, c.name, c.args...)
@@ -129,6 +155,13 @@ func (c *Command) RunInDirTimeoutEnvFullPipelineFunc(env []string, timeout time.
 		cmd.Env = env
 		cmd.Env = append(cmd.Env, fmt.Sprintf("LC_ALL=%s", DefaultLocale))
 	}
+	if requestID := gitealog.RequestIDFromContext(c.parentContext); requestID != "" {
+		// Pass the request ID on so that any hooks run by this command can be correlated with it
+		cmd.Env = append(cmd.Env, gitealog.RequestIDEnv+"="+requestID)
+	}
+	if traceParent := tracing.TraceParent(spanCtx); traceParent != "" {
+		cmd.Env = append(cmd.Env, tracing.TraceParentEnv+"="+traceParent)
+	}
 
 	// TODO: verify if this is still needed in golang 1.15
 	if goVersionLessThan115 {
@@ -10,6 +10,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math"
 	"os/exec"
 	"regexp"
 	"strconv"
@@ -258,3 +260,70 @@ func CutDiffAroundLine(originalDiff io.Reader, line int64, old bool, numbersOfLi
 		oldBegin, oldNumOfLines, newBegin, newNumOfLines)
 	return strings.Join(newHunk, "\n")
 }
+
+// CutDiffAroundLineRange cuts a diff of a file in way that the lines from startLine to line will be shown,
+// at least numberOfLine lines are shown like in CutDiffAroundLine.
+// Lines are signed: negative lines are lines of the old file and positive ones lines of the new file.
+// Warning: Only one-file diffs are allowed.
+func CutDiffAroundLineRange(originalDiff io.Reader, startLine, line int64, numbersOfLine int) (string, error) {
+	diff, err := ioutil.ReadAll(originalDiff)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := func(l int64) int64 {
+		if l < 0 {
+			return -l
+		}
+		return l
+	}
+
+	if startLine != 0 && startLine != line {
+		// Cut the whole hunk up to the line to count the lines of the range
+		hunk := CutDiffAroundLine(bytes.NewReader(diff), unsigned(line), line < 0, math.MaxInt32)
+		var oldLine, newLine int64
+		startRow, row := -1, -1
+		for _, lof := range strings.Split(hunk, "\n") {
+			if strings.HasPrefix(lof, "@@") {
+				submatches := hunkRegex.FindStringSubmatch(lof)
+				if submatches == nil {
+					continue
+				}
+				oldLine, _ = strconv.ParseInt(submatches[1], 10, 64)
+				newLine, _ = strconv.ParseInt(submatches[4], 10, 64)
+				row = 0
+				continue
+			}
+			if row < 0 || len(lof) == 0 {
+				continue
+			}
+			switch lof[0] {
+			case '+':
+				if newLine == startLine {
+					startRow = row
+				}
+				newLine++
+			case '-':
+				if -oldLine == startLine {
+					startRow = row
+				}
+				oldLine++
+			case '\\':
+				continue
+			default:
+				if newLine == startLine || -oldLine == startLine {
+					startRow = row
+				}
+				oldLine++
+				newLine++
+			}
+			row++
+		}
+		// numbersOfLine includes the line itself
+		if startRow >= 0 && row-startRow > numbersOfLine {
+			numbersOfLine = row - startRow
+		}
+	}
+
+	return CutDiffAroundLine(bytes.NewReader(diff), unsigned(line), line < 0, numbersOfLine), nil
+}
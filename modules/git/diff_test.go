@@ -51,6 +51,27 @@ func TestCutDiffAroundLine(t *testing.T) {
 	assert.Empty(t, emptyResult)
 }
 
+func TestCutDiffAroundLineRange(t *testing.T) {
+	// the range fits in the default number of lines
+	result, err := CutDiffAroundLineRange(strings.NewReader(exampleDiff), -2, 5, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, CutDiffAroundLine(strings.NewReader(exampleDiff), 5, false, 3), result)
+
+	// the range starts at the first line of the hunk
+	result, err = CutDiffAroundLineRange(strings.NewReader(exampleDiff), 1, 5, 3)
+	assert.NoError(t, err)
+	resultByLine := strings.Split(result, "\n")
+	assert.Len(t, resultByLine, 10)
+	assert.Equal(t, "@@ -1,3 +1,6 @@", resultByLine[3])
+	assert.Equal(t, " # gitea-github-migrator", resultByLine[4])
+	assert.Equal(t, "+ cut off", resultByLine[9])
+
+	// single line comments are cut as before
+	result, err = CutDiffAroundLineRange(strings.NewReader(exampleDiff), 0, 4, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, CutDiffAroundLine(strings.NewReader(exampleDiff), 4, false, 3), result)
+}
+
 func BenchmarkCutDiffAroundLine(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		CutDiffAroundLine(strings.NewReader(exampleDiff), 3, true, 3)
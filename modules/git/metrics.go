@@ -0,0 +1,38 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var commandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "gitea",
+	Subsystem: "git",
+	Name:      "command_duration_seconds",
+	Help:      "Duration of git commands by subcommand and status",
+	Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300},
+}, []string{"subcommand", "status"})
+
+func init() {
+	prometheus.MustRegister(commandDuration)
+}
+
+// observeCommand records the duration and outcome of a git command
+func observeCommand(subcommand string, start time.Time, err error) {
+	status := "success"
+	if err == context.DeadlineExceeded {
+		status = "timeout"
+	} else if err != nil {
+		status = "error"
+	}
+	if subcommand == "" {
+		subcommand = "none"
+	}
+	commandDuration.WithLabelValues(subcommand, status).Observe(time.Since(start).Seconds())
+}
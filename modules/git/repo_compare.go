@@ -8,6 +8,7 @@ package git
 import (
 	"bytes"
 	"container/list"
+	"context"
 	"fmt"
 	"io"
 	"regexp"
@@ -27,6 +28,10 @@ type CompareInfo struct {
 
 // GetMergeBase checks and returns merge base of two branches and the reference used as base.
 func (repo *Repository) GetMergeBase(tmpRemote string, base, head string) (string, string, error) {
+	return repo.getMergeBase(DefaultContext, tmpRemote, base, head)
+}
+
+func (repo *Repository) getMergeBase(ctx context.Context, tmpRemote string, base, head string) (string, string, error) {
 	if tmpRemote == "" {
 		tmpRemote = "origin"
 	}
@@ -34,18 +39,19 @@ func (repo *Repository) GetMergeBase(tmpRemote string, base, head string) (strin
 	if tmpRemote != "origin" {
 		tmpBaseName := "refs/remotes/" + tmpRemote + "/tmp_" + base
 		// Fetch commit into a temporary branch in order to be able to handle commits and tags
-		_, err := NewCommand("fetch", tmpRemote, base+":"+tmpBaseName).RunInDir(repo.Path)
+		_, err := NewCommandContext(ctx, "fetch", tmpRemote, base+":"+tmpBaseName).RunInDir(repo.Path)
 		if err == nil {
 			base = tmpBaseName
 		}
 	}
 
-	stdout, err := NewCommand("merge-base", "--", base, head).RunInDir(repo.Path)
+	stdout, err := NewCommandContext(ctx, "merge-base", "--", base, head).RunInDir(repo.Path)
 	return strings.TrimSpace(stdout), base, err
 }
 
-// GetCompareInfo generates and returns compare information between base and head branches of repositories.
-func (repo *Repository) GetCompareInfo(basePath, baseBranch, headBranch string) (_ *CompareInfo, err error) {
+// GetCompareInfo generates and returns compare information between base and head branches of repositories,
+// the commands reading the repositories are run with the provided context.
+func (repo *Repository) GetCompareInfo(ctx context.Context, basePath, baseBranch, headBranch string) (_ *CompareInfo, err error) {
 	var (
 		remoteBranch string
 		tmpRemote    string
@@ -66,10 +72,10 @@ func (repo *Repository) GetCompareInfo(basePath, baseBranch, headBranch string)
 	}
 
 	compareInfo := new(CompareInfo)
-	compareInfo.MergeBase, remoteBranch, err = repo.GetMergeBase(tmpRemote, baseBranch, headBranch)
+	compareInfo.MergeBase, remoteBranch, err = repo.getMergeBase(ctx, tmpRemote, baseBranch, headBranch)
 	if err == nil {
 		// We have a common base - therefore we know that ... should work
-		logs, err := NewCommand("log", compareInfo.MergeBase+"..."+headBranch, prettyLogFormat).RunInDirBytes(repo.Path)
+		logs, err := NewCommandContext(ctx, "log", compareInfo.MergeBase+"..."+headBranch, prettyLogFormat).RunInDirBytes(repo.Path)
 		if err != nil {
 			return nil, err
 		}
@@ -88,7 +94,7 @@ func (repo *Repository) GetCompareInfo(basePath, baseBranch, headBranch string)
 	// Count number of changed files.
 	// This probably should be removed as we need to use shortstat elsewhere
 	// Now there is git diff --shortstat but this appears to be slower than simply iterating with --nameonly
-	compareInfo.NumFiles, err = repo.GetDiffNumChangedFiles(remoteBranch, headBranch)
+	compareInfo.NumFiles, err = repo.getDiffNumChangedFiles(ctx, remoteBranch, headBranch)
 	if err != nil {
 		return nil, err
 	}
@@ -109,18 +115,22 @@ func (l *lineCountWriter) Write(p []byte) (n int, err error) {
 // GetDiffNumChangedFiles counts the number of changed files
 // This is substantially quicker than shortstat but...
 func (repo *Repository) GetDiffNumChangedFiles(base, head string) (int, error) {
+	return repo.getDiffNumChangedFiles(DefaultContext, base, head)
+}
+
+func (repo *Repository) getDiffNumChangedFiles(ctx context.Context, base, head string) (int, error) {
 	// Now there is git diff --shortstat but this appears to be slower than simply iterating with --nameonly
 	w := &lineCountWriter{}
 	stderr := new(bytes.Buffer)
 
-	if err := NewCommand("diff", "-z", "--name-only", base+"..."+head).
+	if err := NewCommandContext(ctx, "diff", "-z", "--name-only", base+"..."+head).
 		RunInDirPipeline(repo.Path, w, stderr); err != nil {
 		if strings.Contains(stderr.String(), "no merge base") {
 			// git >= 2.28 now returns an error if base and head have become unrelated.
 			// previously it would return the results of git diff -z --name-only base head so let's try that...
 			w = &lineCountWriter{}
 			stderr.Reset()
-			if err = NewCommand("diff", "-z", "--name-only", base, head).RunInDirPipeline(repo.Path, w, stderr); err == nil {
+			if err = NewCommandContext(ctx, "diff", "-z", "--name-only", base, head).RunInDirPipeline(repo.Path, w, stderr); err == nil {
 				return w.numLines, nil
 			}
 		}
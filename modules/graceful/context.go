@@ -59,6 +59,26 @@ func (ctx *ChannelContext) Value(key interface{}) interface{} {
 	return nil
 }
 
+// ValuesContext is a context that is cancelled with its parent but carries the values of another context
+type ValuesContext struct {
+	context.Context
+	values context.Context
+}
+
+// NewValuesContext creates a ValuesContext cancelled with the parent and carrying the values of the provided context.
+// It lets work started on behalf of a request keep the values of the request, like its ID, without being cancelled with it.
+func NewValuesContext(parent, values context.Context) *ValuesContext {
+	return &ValuesContext{
+		Context: parent,
+		values:  values,
+	}
+}
+
+// Value returns the value associated with the key by the values context
+func (ctx *ValuesContext) Value(key interface{}) interface{} {
+	return ctx.values.Value(key)
+}
+
 // ShutdownContext returns a context.Context that is Done at shutdown
 // Callers using this context should ensure that they are registered as a running server
 // in order that they are waited for.
@@ -0,0 +1,32 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package graceful
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testContextKey struct{}
+
+func TestValuesContext(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.Background())
+	defer cancelParent()
+	values, cancelValues := context.WithCancel(context.WithValue(context.Background(), testContextKey{}, "request"))
+
+	ctx := NewValuesContext(parent, values)
+	assert.Equal(t, "request", ctx.Value(testContextKey{}))
+
+	// the values context going away doesn't cancel the context
+	cancelValues()
+	assert.NoError(t, ctx.Err())
+	assert.Equal(t, "request", ctx.Value(testContextKey{}))
+
+	cancelParent()
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
@@ -46,6 +46,13 @@ func numericEqualityQuery(value int64, field string) *query.NumericRangeQuery {
 	return q
 }
 
+// termQuery an exact match query for the given value and field
+func termQuery(value, field string) *query.TermQuery {
+	q := bleve.NewTermQuery(value)
+	q.SetField(field)
+	return q
+}
+
 func addUnicodeNormalizeTokenFilter(m *mapping.IndexMappingImpl) error {
 	return m.AddCustomTokenFilter(unicodeNormalizeName, map[string]interface{}{
 		"type": unicodenorm.Name,
@@ -88,6 +95,7 @@ func openBleveIndexer(path string, latestVersion int) (bleve.Index, error) {
 // RepoIndexerData data stored in the repo indexer
 type RepoIndexerData struct {
 	RepoID    int64
+	Ref       string
 	CommitID  string
 	Content   string
 	Language  string
@@ -102,7 +110,7 @@ func (d *RepoIndexerData) Type() string {
 const (
 	repoIndexerAnalyzer      = "repoIndexerAnalyzer"
 	repoIndexerDocType       = "repoIndexerDocType"
-	repoIndexerLatestVersion = 5
+	repoIndexerLatestVersion = 6
 )
 
 // createBleveIndexer create a bleve repo indexer if one does not already exist
@@ -121,6 +129,7 @@ func createBleveIndexer(path string, latestVersion int) (bleve.Index, error) {
 	termFieldMapping.Analyzer = analyzer_keyword.Name
 	docMapping.AddFieldMappingsAt("Language", termFieldMapping)
 	docMapping.AddFieldMappingsAt("CommitID", termFieldMapping)
+	docMapping.AddFieldMappingsAt("Ref", termFieldMapping)
 
 	timeFieldMapping := bleve.NewDateTimeFieldMapping()
 	timeFieldMapping.IncludeInAll = false
@@ -173,7 +182,7 @@ func NewBleveIndexer(indexDir string) (*BleveIndexer, bool, error) {
 	return indexer, created, err
 }
 
-func (b *BleveIndexer) addUpdate(commitSha string, update fileUpdate, repo *models.Repository, batch rupture.FlushingBatch) error {
+func (b *BleveIndexer) addUpdate(ref, commitSha string, update fileUpdate, repo *models.Repository, batch rupture.FlushingBatch) error {
 	// Ignore vendored files in code search
 	if setting.Indexer.ExcludeVendored && enry.IsVendor(update.Filename) {
 		return nil
@@ -187,7 +196,7 @@ func (b *BleveIndexer) addUpdate(commitSha string, update fileUpdate, repo *mode
 	if size, err := strconv.Atoi(strings.TrimSpace(stdout)); err != nil {
 		return fmt.Errorf("Misformatted git cat-file output: %v", err)
 	} else if int64(size) > setting.Indexer.MaxIndexerFileSize {
-		return b.addDelete(update.Filename, repo, batch)
+		return b.addDelete(ref, update.Filename, repo, batch)
 	}
 
 	fileContents, err := git.NewCommand("cat-file", "blob", update.BlobSha).
@@ -199,9 +208,10 @@ func (b *BleveIndexer) addUpdate(commitSha string, update fileUpdate, repo *mode
 		return nil
 	}
 
-	id := filenameIndexerID(repo.ID, update.Filename)
+	id := filenameIndexerID(repo.ID, ref, update.Filename)
 	return batch.Index(id, &RepoIndexerData{
 		RepoID:    repo.ID,
+		Ref:       ref,
 		CommitID:  commitSha,
 		Content:   string(charset.ToUTF8DropErrors(fileContents)),
 		Language:  analyze.GetCodeLanguage(update.Filename, fileContents),
@@ -209,8 +219,8 @@ func (b *BleveIndexer) addUpdate(commitSha string, update fileUpdate, repo *mode
 	})
 }
 
-func (b *BleveIndexer) addDelete(filename string, repo *models.Repository, batch rupture.FlushingBatch) error {
-	id := filenameIndexerID(repo.ID, filename)
+func (b *BleveIndexer) addDelete(ref, filename string, repo *models.Repository, batch rupture.FlushingBatch) error {
+	id := filenameIndexerID(repo.ID, ref, filename)
 	return batch.Delete(id)
 }
 
@@ -246,15 +256,15 @@ func (b *BleveIndexer) Close() {
 }
 
 // Index indexes the data
-func (b *BleveIndexer) Index(repo *models.Repository, sha string, changes *repoChanges) error {
+func (b *BleveIndexer) Index(repo *models.Repository, ref, sha string, changes *repoChanges) error {
 	batch := rupture.NewFlushingBatch(b.indexer, maxBatchSize)
 	for _, update := range changes.Updates {
-		if err := b.addUpdate(sha, update, repo, batch); err != nil {
+		if err := b.addUpdate(ref, sha, update, repo, batch); err != nil {
 			return err
 		}
 	}
 	for _, filename := range changes.RemovedFilenames {
-		if err := b.addDelete(filename, repo, batch); err != nil {
+		if err := b.addDelete(ref, filename, repo, batch); err != nil {
 			return err
 		}
 	}
@@ -263,8 +273,19 @@ func (b *BleveIndexer) Index(repo *models.Repository, sha string, changes *repoC
 
 // Delete deletes indexes by ids
 func (b *BleveIndexer) Delete(repoID int64) error {
-	query := numericEqualityQuery(repoID, "RepoID")
-	searchRequest := bleve.NewSearchRequestOptions(query, 2147483647, 0, false)
+	return b.deleteByQuery(numericEqualityQuery(repoID, "RepoID"))
+}
+
+// DeleteRef deletes the indexes of a ref of a repository
+func (b *BleveIndexer) DeleteRef(repoID int64, ref string) error {
+	return b.deleteByQuery(bleve.NewConjunctionQuery(
+		numericEqualityQuery(repoID, "RepoID"),
+		termQuery(ref, "Ref"),
+	))
+}
+
+func (b *BleveIndexer) deleteByQuery(q query.Query) error {
+	searchRequest := bleve.NewSearchRequestOptions(q, 2147483647, 0, false)
 	result, err := b.indexer.Search(searchRequest)
 	if err != nil {
 		return err
@@ -280,7 +301,7 @@ func (b *BleveIndexer) Delete(repoID int64) error {
 
 // Search searches for files in the specified repo.
 // Returns the matching file-paths
-func (b *BleveIndexer) Search(repoIDs []int64, language, keyword string, page, pageSize int) (int64, []*SearchResult, []*SearchResultLanguages, error) {
+func (b *BleveIndexer) Search(repoIDs []int64, ref, language, keyword string, page, pageSize int) (int64, []*SearchResult, []*SearchResultLanguages, error) {
 	phraseQuery := bleve.NewMatchPhraseQuery(keyword)
 	phraseQuery.FieldVal = "Content"
 	phraseQuery.Analyzer = repoIndexerAnalyzer
@@ -294,10 +315,14 @@ func (b *BleveIndexer) Search(repoIDs []int64, language, keyword string, page, p
 
 		indexerQuery = bleve.NewConjunctionQuery(
 			bleve.NewDisjunctionQuery(repoQueries...),
+			termQuery(ref, "Ref"),
 			phraseQuery,
 		)
 	} else {
-		indexerQuery = phraseQuery
+		indexerQuery = bleve.NewConjunctionQuery(
+			termQuery(ref, "Ref"),
+			phraseQuery,
+		)
 	}
 
 	// Save for reuse without language filter
@@ -315,7 +340,7 @@ func (b *BleveIndexer) Search(repoIDs []int64, language, keyword string, page, p
 
 	from := (page - 1) * pageSize
 	searchRequest := bleve.NewSearchRequestOptions(indexerQuery, pageSize, from, false)
-	searchRequest.Fields = []string{"Content", "RepoID", "Language", "CommitID", "UpdatedAt"}
+	searchRequest.Fields = []string{"Content", "RepoID", "Ref", "Language", "CommitID", "UpdatedAt"}
 	searchRequest.IncludeLocations = true
 
 	if len(language) == 0 {
@@ -348,11 +373,13 @@ func (b *BleveIndexer) Search(repoIDs []int64, language, keyword string, page, p
 		if t, err := time.Parse(time.RFC3339, hit.Fields["UpdatedAt"].(string)); err == nil {
 			updatedUnix = timeutil.TimeStamp(t.Unix())
 		}
+		repoID, ref, filename := parseIndexerID(hit.ID)
 		searchResults[i] = &SearchResult{
-			RepoID:      int64(hit.Fields["RepoID"].(float64)),
+			RepoID:      repoID,
+			Ref:         ref,
 			StartIndex:  startIndex,
 			EndIndex:    endIndex,
-			Filename:    filenameOfIndexerID(hit.ID),
+			Filename:    filename,
 			Content:     hit.Fields["Content"].(string),
 			CommitID:    hit.Fields["CommitID"].(string),
 			UpdatedUnix: updatedUnix,
@@ -365,7 +392,7 @@ func (b *BleveIndexer) Search(repoIDs []int64, language, keyword string, page, p
 	if len(language) > 0 {
 		// Use separate query to go get all language counts
 		facetRequest := bleve.NewSearchRequestOptions(facetQuery, 1, 0, false)
-		facetRequest.Fields = []string{"Content", "RepoID", "Language", "CommitID", "UpdatedAt"}
+		facetRequest.Fields = []string{"Content", "RepoID", "Ref", "Language", "CommitID", "UpdatedAt"}
 		facetRequest.IncludeLocations = true
 		facetRequest.AddFacet("languages", bleve.NewFacetRequest("Language", 10))
 
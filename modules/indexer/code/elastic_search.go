@@ -26,7 +26,7 @@ import (
 )
 
 const (
-	esRepoIndexerLatestVersion = 1
+	esRepoIndexerLatestVersion = 2
 )
 
 var (
@@ -97,6 +97,10 @@ const (
 					"type": "keyword",
 					"index": true
 				},
+				"ref": {
+					"type": "keyword",
+					"index": true
+				},
 				"language": {
 					"type": "keyword",
 					"index": true
@@ -168,7 +172,7 @@ func (b *ElasticSearchIndexer) init() (bool, error) {
 	return exists, nil
 }
 
-func (b *ElasticSearchIndexer) addUpdate(sha string, update fileUpdate, repo *models.Repository) ([]elastic.BulkableRequest, error) {
+func (b *ElasticSearchIndexer) addUpdate(ref, sha string, update fileUpdate, repo *models.Repository) ([]elastic.BulkableRequest, error) {
 	// Ignore vendored files in code search
 	if setting.Indexer.ExcludeVendored && enry.IsVendor(update.Filename) {
 		return nil, nil
@@ -182,7 +186,7 @@ func (b *ElasticSearchIndexer) addUpdate(sha string, update fileUpdate, repo *mo
 	if size, err := strconv.Atoi(strings.TrimSpace(stdout)); err != nil {
 		return nil, fmt.Errorf("Misformatted git cat-file output: %v", err)
 	} else if int64(size) > setting.Indexer.MaxIndexerFileSize {
-		return []elastic.BulkableRequest{b.addDelete(update.Filename, repo)}, nil
+		return []elastic.BulkableRequest{b.addDelete(ref, update.Filename, repo)}, nil
 	}
 
 	fileContents, err := git.NewCommand("cat-file", "blob", update.BlobSha).
@@ -194,7 +198,7 @@ func (b *ElasticSearchIndexer) addUpdate(sha string, update fileUpdate, repo *mo
 		return nil, nil
 	}
 
-	id := filenameIndexerID(repo.ID, update.Filename)
+	id := filenameIndexerID(repo.ID, ref, update.Filename)
 
 	return []elastic.BulkableRequest{
 		elastic.NewBulkIndexRequest().
@@ -202,6 +206,7 @@ func (b *ElasticSearchIndexer) addUpdate(sha string, update fileUpdate, repo *mo
 			Id(id).
 			Doc(map[string]interface{}{
 				"repo_id":    repo.ID,
+				"ref":        ref,
 				"content":    string(charset.ToUTF8DropErrors(fileContents)),
 				"commit_id":  sha,
 				"language":   analyze.GetCodeLanguage(update.Filename, fileContents),
@@ -210,18 +215,18 @@ func (b *ElasticSearchIndexer) addUpdate(sha string, update fileUpdate, repo *mo
 	}, nil
 }
 
-func (b *ElasticSearchIndexer) addDelete(filename string, repo *models.Repository) elastic.BulkableRequest {
-	id := filenameIndexerID(repo.ID, filename)
+func (b *ElasticSearchIndexer) addDelete(ref, filename string, repo *models.Repository) elastic.BulkableRequest {
+	id := filenameIndexerID(repo.ID, ref, filename)
 	return elastic.NewBulkDeleteRequest().
 		Index(b.indexerAliasName).
 		Id(id)
 }
 
 // Index will save the index data
-func (b *ElasticSearchIndexer) Index(repo *models.Repository, sha string, changes *repoChanges) error {
+func (b *ElasticSearchIndexer) Index(repo *models.Repository, ref, sha string, changes *repoChanges) error {
 	reqs := make([]elastic.BulkableRequest, 0)
 	for _, update := range changes.Updates {
-		updateReqs, err := b.addUpdate(sha, update, repo)
+		updateReqs, err := b.addUpdate(ref, sha, update, repo)
 		if err != nil {
 			return err
 		}
@@ -231,7 +236,7 @@ func (b *ElasticSearchIndexer) Index(repo *models.Repository, sha string, change
 	}
 
 	for _, filename := range changes.RemovedFilenames {
-		reqs = append(reqs, b.addDelete(filename, repo))
+		reqs = append(reqs, b.addDelete(ref, filename, repo))
 	}
 
 	if len(reqs) > 0 {
@@ -252,6 +257,17 @@ func (b *ElasticSearchIndexer) Delete(repoID int64) error {
 	return err
 }
 
+// DeleteRef deletes the indexes of a ref of a repository
+func (b *ElasticSearchIndexer) DeleteRef(repoID int64, ref string) error {
+	_, err := b.client.DeleteByQuery(b.indexerAliasName).
+		Query(elastic.NewBoolQuery().Must(
+			elastic.NewTermsQuery("repo_id", repoID),
+			elastic.NewTermQuery("ref", ref),
+		)).
+		Do(context.Background())
+	return err
+}
+
 // indexPos find words positions for start and the following end on content. It will
 // return the beginning position of the frist start and the ending position of the
 // first end following the start string.
@@ -288,7 +304,7 @@ func convertResult(searchResult *elastic.SearchResult, kw string, pageSize int)
 			panic(fmt.Sprintf("2===%#v", hit.Highlight))
 		}
 
-		repoID, fileName := parseIndexerID(hit.Id)
+		repoID, ref, fileName := parseIndexerID(hit.Id)
 		var res = make(map[string]interface{})
 		if err := json.Unmarshal(hit.Source, &res); err != nil {
 			return 0, nil, nil, err
@@ -298,6 +314,7 @@ func convertResult(searchResult *elastic.SearchResult, kw string, pageSize int)
 
 		hits = append(hits, &SearchResult{
 			RepoID:      repoID,
+			Ref:         ref,
 			Filename:    fileName,
 			CommitID:    res["commit_id"].(string),
 			Content:     res["content"].(string),
@@ -330,10 +347,10 @@ func extractAggs(searchResult *elastic.SearchResult) []*SearchResultLanguages {
 }
 
 // Search searches for codes and language stats by given conditions.
-func (b *ElasticSearchIndexer) Search(repoIDs []int64, language, keyword string, page, pageSize int) (int64, []*SearchResult, []*SearchResultLanguages, error) {
+func (b *ElasticSearchIndexer) Search(repoIDs []int64, ref, language, keyword string, page, pageSize int) (int64, []*SearchResult, []*SearchResultLanguages, error) {
 	kwQuery := elastic.NewMultiMatchQuery(keyword, "content")
 	query := elastic.NewBoolQuery()
-	query = query.Must(kwQuery)
+	query = query.Must(kwQuery, elastic.NewTermQuery("ref", ref))
 	if len(repoIDs) > 0 {
 		var repoStrs = make([]interface{}, 0, len(repoIDs))
 		for _, repoID := range repoIDs {
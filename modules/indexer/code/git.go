@@ -33,17 +33,12 @@ func getDefaultBranchSha(repo *models.Repository) (string, error) {
 	return strings.TrimSpace(stdout), nil
 }
 
-// getRepoChanges returns changes to repo since last indexer update
-func getRepoChanges(repo *models.Repository, revision string) (*repoChanges, error) {
-	status, err := repo.GetIndexerStatus(models.RepoIndexerTypeCode)
-	if err != nil {
-		return nil, err
-	}
-
+// getRepoChanges returns changes to the ref of status since last indexer update
+func getRepoChanges(indexer Indexer, repo *models.Repository, status *models.RepoIndexerStatus, revision string) (*repoChanges, error) {
 	if len(status.CommitSha) == 0 {
 		return genesisChanges(repo, revision)
 	}
-	return nonGenesisChanges(repo, revision)
+	return nonGenesisChanges(indexer, repo, status, revision)
 }
 
 func isIndexable(entry *git.TreeEntry) bool {
@@ -97,15 +92,15 @@ func genesisChanges(repo *models.Repository, revision string) (*repoChanges, err
 }
 
 // nonGenesisChanges get changes since the previous indexer update
-func nonGenesisChanges(repo *models.Repository, revision string) (*repoChanges, error) {
+func nonGenesisChanges(indexer Indexer, repo *models.Repository, status *models.RepoIndexerStatus, revision string) (*repoChanges, error) {
 	diffCmd := git.NewCommand("diff", "--name-status",
-		repo.CodeIndexerStatus.CommitSha, revision)
+		status.CommitSha, revision)
 	stdout, err := diffCmd.RunInDir(repo.RepoPath())
 	if err != nil {
 		// previous commit sha may have been removed by a force push, so
 		// try rebuilding from scratch
 		log.Warn("git diff: %v", err)
-		if err = indexer.Delete(repo.ID); err != nil {
+		if err = indexer.DeleteRef(repo.ID, status.Ref); err != nil {
 			return nil, err
 		}
 		return genesisChanges(repo, revision)
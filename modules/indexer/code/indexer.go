@@ -22,6 +22,7 @@ import (
 // SearchResult result of performing a search in a repo
 type SearchResult struct {
 	RepoID      int64
+	Ref         string
 	StartIndex  int
 	EndIndex    int
 	Filename    string
@@ -39,37 +40,47 @@ type SearchResultLanguages struct {
 	Count    int
 }
 
-// Indexer defines an interface to index and search code contents
+// Indexer defines an interface to index and search code contents,
+// an empty ref refers to the default branch of the repository
 type Indexer interface {
-	Index(repo *models.Repository, sha string, changes *repoChanges) error
+	Index(repo *models.Repository, ref, sha string, changes *repoChanges) error
 	Delete(repoID int64) error
-	Search(repoIDs []int64, language, keyword string, page, pageSize int) (int64, []*SearchResult, []*SearchResultLanguages, error)
+	DeleteRef(repoID int64, ref string) error
+	Search(repoIDs []int64, ref, language, keyword string, page, pageSize int) (int64, []*SearchResult, []*SearchResultLanguages, error)
 	Close()
 }
 
-func filenameIndexerID(repoID int64, filename string) string {
-	return indexerID(repoID) + "_" + filename
+// filenameIndexerID returns the ID of a file in the indexer, git forbids ':' in ref names
+// so the files of the refs other than the default branch are identified by <repo>:<ref>:<filename>
+func filenameIndexerID(repoID int64, ref, filename string) string {
+	if len(ref) == 0 {
+		return indexerID(repoID) + "_" + filename
+	}
+	return indexerID(repoID) + ":" + ref + ":" + filename
 }
 
 func indexerID(id int64) string {
 	return strconv.FormatInt(id, 36)
 }
 
-func parseIndexerID(indexerID string) (int64, string) {
-	index := strings.IndexByte(indexerID, '_')
+// parseIndexerID returns the repository ID, the ref and the filename of an ID of the indexer
+func parseIndexerID(indexerID string) (int64, string, string) {
+	index := strings.IndexAny(indexerID, "_:")
 	if index == -1 {
 		log.Error("Unexpected ID in repo indexer: %s", indexerID)
+		return 0, "", indexerID
 	}
 	repoID, _ := strconv.ParseInt(indexerID[:index], 36, 64)
-	return repoID, indexerID[index+1:]
-}
-
-func filenameOfIndexerID(indexerID string) string {
-	index := strings.IndexByte(indexerID, '_')
+	if indexerID[index] == '_' {
+		return repoID, "", indexerID[index+1:]
+	}
+	ref := indexerID[index+1:]
+	index = strings.IndexByte(ref, ':')
 	if index == -1 {
 		log.Error("Unexpected ID in repo indexer: %s", indexerID)
+		return repoID, "", ref
 	}
-	return indexerID[index+1:]
+	return repoID, ref[:index], ref[index+1:]
 }
 
 // IndexerData represents data stored in the code indexer
@@ -92,18 +103,73 @@ func index(indexer Indexer, repoID int64) error {
 	if err != nil {
 		return err
 	}
-	changes, err := getRepoChanges(repo, sha)
+	status, err := repo.GetIndexerStatus(models.RepoIndexerTypeCode)
+	if err != nil {
+		return err
+	}
+	changes, err := getRepoChanges(indexer, repo, status, sha)
 	if err != nil {
 		return err
 	} else if changes == nil {
 		return nil
 	}
 
-	if err := indexer.Index(repo, sha, changes); err != nil {
+	if err := indexer.Index(repo, "", sha, changes); err != nil {
+		return err
+	}
+
+	if err := repo.UpdateIndexerStatus(models.RepoIndexerTypeCode, sha); err != nil {
 		return err
 	}
 
-	return repo.UpdateIndexerStatus(models.RepoIndexerTypeCode, sha)
+	return indexExtraRefs(indexer, repo)
+}
+
+// indexExtraRefs indexes the refs other than the default branch which are configured for the repository,
+// and removes the refs which are not configured anymore from the indexer
+func indexExtraRefs(indexer Indexer, repo *models.Repository) error {
+	refs, err := getExtraRefs(repo)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := repo.GetIndexerRefStatuses(models.RepoIndexerTypeCode)
+	if err != nil {
+		return err
+	}
+	indexed := make(map[string]*models.RepoIndexerStatus, len(statuses))
+	for _, status := range statuses {
+		if _, ok := refs[status.Ref]; ok {
+			indexed[status.Ref] = status
+			continue
+		}
+		if err := indexer.DeleteRef(repo.ID, status.Ref); err != nil {
+			return err
+		}
+		if err := repo.DeleteIndexerRefStatus(models.RepoIndexerTypeCode, status.Ref); err != nil {
+			return err
+		}
+	}
+
+	for ref, sha := range refs {
+		status, ok := indexed[ref]
+		if !ok {
+			status = &models.RepoIndexerStatus{RepoID: repo.ID, Ref: ref}
+		} else if status.CommitSha == sha {
+			continue
+		}
+		changes, err := getRepoChanges(indexer, repo, status, sha)
+		if err != nil {
+			return err
+		}
+		if err := indexer.Index(repo, ref, sha, changes); err != nil {
+			return err
+		}
+		if err := repo.UpdateIndexerRefStatus(models.RepoIndexerTypeCode, ref, sha); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Init initialize the repo indexer
@@ -125,7 +191,7 @@ func Init() {
 
 	// Create the Queue
 	switch setting.Indexer.RepoType {
-	case "bleve", "elasticsearch":
+	case "bleve", "elasticsearch", "trigram":
 		handler := func(data ...queue.Data) {
 			idx, err := indexer.get()
 			if idx == nil || err != nil {
@@ -190,6 +256,18 @@ func Init() {
 				close(waitChannel)
 				log.Fatal("PID: %d Unable to initialize the bleve Repository Indexer at path: %s Error: %v", os.Getpid(), setting.Indexer.RepoPath, err)
 			}
+		case "trigram":
+			log.Info("PID: %d Initializing Repository Indexer at: %s", os.Getpid(), setting.Indexer.RepoPath)
+			rIndexer, populate, err = NewTrigramIndexer(setting.Indexer.RepoPath)
+			if err != nil {
+				if rIndexer != nil {
+					rIndexer.Close()
+				}
+				cancel()
+				indexer.Close()
+				close(waitChannel)
+				log.Fatal("PID: %d Unable to initialize the trigram Repository Indexer at path: %s Error: %v", os.Getpid(), setting.Indexer.RepoPath, err)
+			}
 		case "elasticsearch":
 			log.Info("PID: %d Initializing Repository Indexer at: %s", os.Getpid(), setting.Indexer.RepoConnStr)
 			defer func() {
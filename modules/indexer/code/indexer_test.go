@@ -64,7 +64,7 @@ func testIndexer(name string, t *testing.T, indexer Indexer) {
 
 		for _, kw := range keywords {
 			t.Run(kw.Keyword, func(t *testing.T) {
-				total, res, langs, err := indexer.Search(kw.RepoIDs, "", kw.Keyword, 1, 10)
+				total, res, langs, err := indexer.Search(kw.RepoIDs, "", "", kw.Keyword, 1, 10)
 				assert.NoError(t, err)
 				assert.EqualValues(t, len(kw.IDs), total)
 				assert.EqualValues(t, kw.Langs, len(langs))
@@ -78,6 +78,69 @@ func testIndexer(name string, t *testing.T, indexer Indexer) {
 			})
 		}
 
+		t.Run("ExtraRefs", func(t *testing.T) {
+			repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: repoID}).(*models.Repository)
+			repo.CodeIndexerRefs = []string{"branch2", LatestTagRef}
+			assert.NoError(t, models.UpdateRepositoryCols(repo, "code_indexer_refs"))
+			assert.NoError(t, index(indexer, repoID))
+
+			total, res, _, err := indexer.Search(nil, "refs/heads/branch2", "", "branch2", 1, 10)
+			assert.NoError(t, err)
+			assert.EqualValues(t, 1, total)
+			if assert.Len(t, res, 1) {
+				assert.EqualValues(t, repoID, res[0].RepoID)
+				assert.EqualValues(t, "refs/heads/branch2", res[0].Ref)
+				assert.EqualValues(t, "README.md", res[0].Filename)
+			}
+
+			total, _, _, err = indexer.Search(nil, "", "", "branch2", 1, 10)
+			assert.NoError(t, err)
+			assert.EqualValues(t, 0, total)
+
+			total, _, _, err = indexer.Search([]int64{repoID}, "refs/tags/v1.1", "", "Description", 1, 10)
+			assert.NoError(t, err)
+			assert.EqualValues(t, 1, total)
+
+			statuses, err := repo.GetIndexerRefStatuses(models.RepoIndexerTypeCode)
+			assert.NoError(t, err)
+			assert.Len(t, statuses, 2)
+
+			repo.CodeIndexerRefs = nil
+			assert.NoError(t, models.UpdateRepositoryCols(repo, "code_indexer_refs"))
+			assert.NoError(t, index(indexer, repoID))
+
+			total, _, _, err = indexer.Search(nil, "refs/heads/branch2", "", "branch2", 1, 10)
+			assert.NoError(t, err)
+			assert.EqualValues(t, 0, total)
+
+			statuses, err = repo.GetIndexerRefStatuses(models.RepoIndexerTypeCode)
+			assert.NoError(t, err)
+			assert.Len(t, statuses, 0)
+		})
+
 		assert.NoError(t, indexer.Delete(repoID))
 	})
 }
+
+func TestParseIndexerID(t *testing.T) {
+	for _, ref := range []string{"", "refs/heads/release/v1.x", "refs/tags/v1_0"} {
+		repoID, parsedRef, filename := parseIndexerID(filenameIndexerID(42, ref, "dir/a_b:c.go"))
+		assert.EqualValues(t, 42, repoID)
+		assert.EqualValues(t, ref, parsedRef)
+		assert.EqualValues(t, "dir/a_b:c.go", filename)
+	}
+}
+
+func TestIsExtraRef(t *testing.T) {
+	repo := &models.Repository{CodeIndexerRefs: []string{"release/*", "refs/tags/v2.*"}}
+	assert.True(t, IsExtraRef(repo, "refs/heads/release/v1"))
+	assert.True(t, IsExtraRef(repo, "refs/tags/release/v1"))
+	assert.True(t, IsExtraRef(repo, "refs/tags/v2.1"))
+	assert.False(t, IsExtraRef(repo, "refs/heads/release/v1/fix"))
+	assert.False(t, IsExtraRef(repo, "refs/heads/v2.1"))
+	assert.False(t, IsExtraRef(repo, "refs/tags/v1.0"))
+
+	repo.CodeIndexerRefs = []string{LatestTagRef}
+	assert.True(t, IsExtraRef(repo, "refs/tags/v1.0"))
+	assert.False(t, IsExtraRef(repo, "refs/heads/master"))
+}
@@ -0,0 +1,153 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/gobwas/glob"
+)
+
+// LatestTagRef is the pattern of the extra refs of a repository matching its most recent tag
+const LatestTagRef = "latest-tag"
+
+// refPatterns the patterns of the extra refs to index of a repository
+type refPatterns struct {
+	globs     []glob.Glob
+	latestTag bool
+}
+
+// parseRefPatterns parses the extra refs patterns of a repository, patterns not starting with
+// refs/ match both the branches and the tags with that name
+func parseRefPatterns(patterns []string) *refPatterns {
+	result := &refPatterns{}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		switch {
+		case len(pattern) == 0:
+			continue
+		case pattern == LatestTagRef:
+			result.latestTag = true
+			continue
+		}
+
+		var expressions []string
+		if strings.HasPrefix(pattern, "refs/") {
+			expressions = []string{pattern}
+		} else {
+			expressions = []string{git.BranchPrefix + pattern, git.TagPrefix + pattern}
+		}
+		for _, expr := range expressions {
+			g, err := glob.Compile(expr, '/')
+			if err != nil {
+				log.Info("Invalid code indexer ref pattern '%s' (skipped): %v", pattern, err)
+				break
+			}
+			result.globs = append(result.globs, g)
+		}
+	}
+	return result
+}
+
+func (p *refPatterns) isEmpty() bool {
+	return len(p.globs) == 0 && !p.latestTag
+}
+
+func (p *refPatterns) match(ref string) bool {
+	for _, g := range p.globs {
+		if g.Match(ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExtraRef returns whether a change to the ref may change the extra refs indexed for the repository
+func IsExtraRef(repo *models.Repository, refFullName string) bool {
+	patterns := parseRefPatterns(repo.CodeIndexerRefs)
+	return (patterns.latestTag && strings.HasPrefix(refFullName, git.TagPrefix)) || patterns.match(refFullName)
+}
+
+// getExtraRefs returns the refs other than the default branch to index for the repository and their commits,
+// at most setting.Indexer.MaxExtraRefs refs are indexed
+func getExtraRefs(repo *models.Repository) (map[string]string, error) {
+	refs := make(map[string]string)
+	patterns := parseRefPatterns(repo.CodeIndexerRefs)
+	if patterns.isEmpty() {
+		return refs, nil
+	}
+
+	// the most recent refs first, the commit of an annotated tag is its peeled object
+	stdout, err := git.NewCommand("for-each-ref", "--sort=-creatordate",
+		"--format=%(refname)%09%(objectname)%09%(*objectname)", git.BranchPrefix, git.TagPrefix).
+		RunInDir(repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+
+	defaultRef := git.BranchPrefix + repo.DefaultBranch
+	latestTagFound := false
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		ref, sha := fields[0], fields[1]
+		if len(fields[2]) > 0 {
+			sha = fields[2]
+		}
+
+		isLatestTag := false
+		if !latestTagFound && strings.HasPrefix(ref, git.TagPrefix) {
+			latestTagFound = true
+			isLatestTag = patterns.latestTag
+		}
+		if ref == defaultRef || !(isLatestTag || patterns.match(ref)) {
+			continue
+		}
+		if len(refs) >= setting.Indexer.MaxExtraRefs {
+			log.Warn("Code indexer: more than %d extra refs match for repo %s, the older ones are not indexed", setting.Indexer.MaxExtraRefs, repo.FullName())
+			break
+		}
+		refs[ref] = sha
+	}
+	return refs, nil
+}
+
+// GetIndexedRefs returns the refs other than the default branch indexed for the repository
+func GetIndexedRefs(repo *models.Repository) ([]string, error) {
+	statuses, err := repo.GetIndexerRefStatuses(models.RepoIndexerTypeCode)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]string, len(statuses))
+	for i, status := range statuses {
+		refs[i] = status.Ref
+	}
+	return refs, nil
+}
+
+// ResolveIndexedRef returns the full name of an indexed ref of the repository given its full or short name,
+// the empty string is returned for the default branch. It returns false if the ref is not indexed.
+func ResolveIndexedRef(repo *models.Repository, ref string) (string, bool, error) {
+	if len(ref) == 0 || ref == repo.DefaultBranch || ref == git.BranchPrefix+repo.DefaultBranch {
+		return "", true, nil
+	}
+	refs, err := GetIndexedRefs(repo)
+	if err != nil {
+		return "", false, err
+	}
+	for _, indexed := range refs {
+		if indexed == ref || indexed == git.BranchPrefix+ref || indexed == git.TagPrefix+ref {
+			return indexed, true, nil
+		}
+	}
+	return "", false, nil
+}
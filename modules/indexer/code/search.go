@@ -16,12 +16,14 @@ import (
 // Result a search result to display
 type Result struct {
 	RepoID         int64
+	Ref            string
 	Filename       string
 	CommitID       string
 	UpdatedUnix    timeutil.TimeStamp
 	Language       string
 	Color          string
 	LineNumbers    []int
+	Lines          string
 	FormattedLines string
 }
 
@@ -95,23 +97,25 @@ func searchResult(result *SearchResult, startIndex, endIndex int) (*Result, erro
 	}
 	return &Result{
 		RepoID:         result.RepoID,
+		Ref:            result.Ref,
 		Filename:       result.Filename,
 		CommitID:       result.CommitID,
 		UpdatedUnix:    result.UpdatedUnix,
 		Language:       result.Language,
 		Color:          result.Color,
 		LineNumbers:    lineNumbers,
+		Lines:          formattedLinesBuffer.String(),
 		FormattedLines: highlight.Code(result.Filename, formattedLinesBuffer.String()),
 	}, nil
 }
 
-// PerformSearch perform a search on a repository
-func PerformSearch(repoIDs []int64, language, keyword string, page, pageSize int) (int, []*Result, []*SearchResultLanguages, error) {
+// PerformSearch perform a search on a repository, an empty ref searches the default branches
+func PerformSearch(repoIDs []int64, ref, language, keyword string, page, pageSize int) (int, []*Result, []*SearchResultLanguages, error) {
 	if len(keyword) == 0 {
 		return 0, nil, nil, nil
 	}
 
-	total, results, resultLanguages, err := indexer.Search(repoIDs, language, keyword, page, pageSize)
+	total, results, resultLanguages, err := indexer.Search(repoIDs, ref, language, keyword, page, pageSize)
 	if err != nil {
 		return 0, nil, nil, err
 	}
@@ -0,0 +1,133 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Symbol a symbol definition extracted from the content of a file
+type Symbol struct {
+	Name string
+	Kind string
+	// Offset of the name of the symbol in the content
+	Offset int
+}
+
+// symbolPattern matches the definitions of a kind of symbol, the first submatch being the name of the symbol
+type symbolPattern struct {
+	kind   string
+	regexp *regexp.Regexp
+}
+
+func newSymbolPatterns(patterns ...string) []symbolPattern {
+	result := make([]symbolPattern, 0, len(patterns)/2)
+	for i := 0; i+1 < len(patterns); i += 2 {
+		result = append(result, symbolPattern{
+			kind:   patterns[i],
+			regexp: regexp.MustCompile("(?m)" + patterns[i+1]),
+		})
+	}
+	return result
+}
+
+// symbolPatterns the patterns of the symbol definitions of the languages, in the manner of ctags
+// the patterns match the definitions at the start of a line only, which is good enough for idiomatic code
+var symbolPatterns = map[string][]symbolPattern{
+	"Go": newSymbolPatterns(
+		"function", `^func\s+(?:\([^)]*\)\s*)?([A-Za-z_]\w*)`,
+		"type", `^type\s+([A-Za-z_]\w*)`,
+		"type", `^\t([A-Za-z_]\w*)\s+(?:struct|interface)\s*{`,
+		"constant", `^const\s+([A-Za-z_]\w*)`,
+		"variable", `^var\s+([A-Za-z_]\w*)`,
+	),
+	"Python": newSymbolPatterns(
+		"function", `^\s*(?:async\s+)?def\s+([A-Za-z_]\w*)`,
+		"class", `^\s*class\s+([A-Za-z_]\w*)`,
+	),
+	"JavaScript": newSymbolPatterns(
+		"function", `^\s*(?:export\s+)?(?:default\s+)?(?:async\s+)?function\s*\*?\s*([A-Za-z_$][\w$]*)`,
+		"class", `^\s*(?:export\s+)?(?:default\s+)?class\s+([A-Za-z_$][\w$]*)`,
+		"variable", `^\s*(?:export\s+)?(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=`,
+	),
+	"TypeScript": newSymbolPatterns(
+		"function", `^\s*(?:export\s+)?(?:default\s+)?(?:async\s+)?function\s*\*?\s*([A-Za-z_$][\w$]*)`,
+		"class", `^\s*(?:export\s+)?(?:default\s+)?(?:abstract\s+)?class\s+([A-Za-z_$][\w$]*)`,
+		"interface", `^\s*(?:export\s+)?interface\s+([A-Za-z_$][\w$]*)`,
+		"type", `^\s*(?:export\s+)?type\s+([A-Za-z_$][\w$]*)\s*=`,
+		"variable", `^\s*(?:export\s+)?(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=`,
+	),
+	"Java": newSymbolPatterns(
+		"class", `^\s*(?:(?:public|protected|private|static|abstract|final)\s+)*(?:class|interface|enum|@interface)\s+([A-Za-z_]\w*)`,
+		"method", `^\s+(?:(?:public|protected|private|static|abstract|final|synchronized|native)\s+)+[\w<>\[\], ]+\s+([A-Za-z_]\w*)\s*\(`,
+	),
+	"C#": newSymbolPatterns(
+		"class", `^\s*(?:(?:public|protected|private|internal|static|abstract|sealed|partial)\s+)*(?:class|interface|enum|struct|record)\s+([A-Za-z_]\w*)`,
+		"method", `^\s+(?:(?:public|protected|private|internal|static|abstract|virtual|override|async)\s+)+[\w<>\[\], ]+\s+([A-Za-z_]\w*)\s*\(`,
+	),
+	"C": newSymbolPatterns(
+		"function", `^[A-Za-z_][\w \t\*]*[\s\*]([A-Za-z_]\w*)\s*\([^;]*$`,
+		"macro", `^#\s*define\s+([A-Za-z_]\w*)`,
+		"type", `^(?:typedef\s+)?(?:struct|enum|union)\s+([A-Za-z_]\w*)\s*{`,
+	),
+	"C++": newSymbolPatterns(
+		"function", `^[A-Za-z_][\w \t\*&:<>]*[\s\*&]([A-Za-z_][\w:]*)\s*\([^;]*$`,
+		"macro", `^#\s*define\s+([A-Za-z_]\w*)`,
+		"class", `^\s*(?:class|struct|enum|union|namespace)\s+([A-Za-z_]\w*)\s*[:{]?`,
+	),
+	"Rust": newSymbolPatterns(
+		"function", `^\s*(?:pub(?:\([^)]*\))?\s+)?(?:const\s+)?(?:async\s+)?(?:unsafe\s+)?fn\s+([A-Za-z_]\w*)`,
+		"type", `^\s*(?:pub(?:\([^)]*\))?\s+)?(?:struct|enum|trait|type|union)\s+([A-Za-z_]\w*)`,
+		"module", `^\s*(?:pub(?:\([^)]*\))?\s+)?mod\s+([A-Za-z_]\w*)`,
+		"macro", `^\s*macro_rules!\s+([A-Za-z_]\w*)`,
+	),
+	"Ruby": newSymbolPatterns(
+		"method", `^\s*def\s+(?:self\.)?([A-Za-z_]\w*[?!=]?)`,
+		"class", `^\s*(?:class|module)\s+([A-Z]\w*)`,
+	),
+	"PHP": newSymbolPatterns(
+		"function", `^\s*(?:(?:public|protected|private|static|abstract|final)\s+)*function\s+&?([A-Za-z_]\w*)`,
+		"class", `^\s*(?:(?:abstract|final)\s+)?(?:class|interface|trait)\s+([A-Za-z_]\w*)`,
+	),
+	"Shell": newSymbolPatterns(
+		"function", `^\s*(?:function\s+)?([A-Za-z_][\w-]*)\s*\(\)`,
+	),
+}
+
+// extractSymbols extracts the symbol definitions from the content of a file in the given language
+func extractSymbols(language, content string) []Symbol {
+	patterns, ok := symbolPatterns[language]
+	if !ok {
+		return nil
+	}
+	var symbols []Symbol
+	seen := make(map[int]bool)
+	for _, pattern := range patterns {
+		for _, match := range pattern.regexp.FindAllStringSubmatchIndex(content, -1) {
+			if len(match) < 4 || match[2] < 0 || seen[match[2]] {
+				continue
+			}
+			seen[match[2]] = true
+			symbols = append(symbols, Symbol{
+				Name:   content[match[2]:match[3]],
+				Kind:   pattern.kind,
+				Offset: match[2],
+			})
+		}
+	}
+	return symbols
+}
+
+// matchSymbol returns the first symbol whose name contains the given name, ignoring case
+func matchSymbol(symbols []Symbol, name string) (Symbol, bool) {
+	name = strings.ToLower(name)
+	for _, symbol := range symbols {
+		if strings.Contains(strings.ToLower(symbol.Name), name) {
+			return symbol, true
+		}
+	}
+	return Symbol{}, false
+}
@@ -0,0 +1,455 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/analyze"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/charset"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/nosql"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/go-enry/go-enry/v2"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const trigramIndexerLatestVersion = 1
+
+// The keys of the trigram indexer:
+//
+//	v                     the version of the indexer
+//	d<document id>        the trigramDocument of a file
+//	t<trigram><doc id>    the trigrams of the lower cased content of the files, trigrams are 3 bytes long
+var (
+	trigramVersionKey = []byte("v")
+	trigramDocPrefix  = "d"
+	trigramPrefix     = "t"
+)
+
+var (
+	_ Indexer = &TrigramIndexer{}
+)
+
+// trigramDocument the data of a file stored in the trigram indexer
+type trigramDocument struct {
+	RepoID      int64
+	Ref         string
+	CommitID    string
+	Content     string
+	Language    string
+	Symbols     []Symbol
+	UpdatedUnix timeutil.TimeStamp
+}
+
+// TrigramIndexer implements Indexer interface with a trigram index stored in a local LevelDB,
+// it supports exact substring, regular expression and symbol searches
+type TrigramIndexer struct {
+	indexDir string
+	db       *leveldb.DB
+}
+
+// NewTrigramIndexer creates a new trigram local indexer
+func NewTrigramIndexer(indexDir string) (*TrigramIndexer, bool, error) {
+	indexer := &TrigramIndexer{
+		indexDir: indexDir,
+	}
+	created, err := indexer.init()
+	return indexer, created, err
+}
+
+// init opens the index, the index is emptied if it has been created by a previous version
+func (t *TrigramIndexer) init() (bool, error) {
+	var err error
+	t.db, err = nosql.GetManager().GetLevelDB(t.indexDir)
+	if err != nil {
+		return false, err
+	}
+
+	version, err := t.db.Get(trigramVersionKey, nil)
+	if err != nil && err != leveldb.ErrNotFound {
+		return false, err
+	}
+	if err == nil && string(version) == strconv.Itoa(trigramIndexerLatestVersion) {
+		return false, nil
+	}
+
+	if err := t.deletePrefix(""); err != nil {
+		return false, err
+	}
+	return true, t.db.Put(trigramVersionKey, []byte(strconv.Itoa(trigramIndexerLatestVersion)), nil)
+}
+
+// Close close the indexer
+func (t *TrigramIndexer) Close() {
+	log.Debug("Closing repo indexer")
+	if t.db != nil {
+		if err := nosql.GetManager().CloseLevelDB(t.indexDir); err != nil {
+			log.Error("Error whilst closing the repository indexer: %v", err)
+		}
+		t.db = nil
+	}
+	log.Info("PID: %d Repository Indexer closed", os.Getpid())
+}
+
+func (t *TrigramIndexer) getDocument(id string) (*trigramDocument, error) {
+	data, err := t.db.Get([]byte(trigramDocPrefix+id), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	doc := &trigramDocument{}
+	return doc, json.Unmarshal(data, doc)
+}
+
+// addDelete removes a file and its trigrams from the index
+func (t *TrigramIndexer) addDelete(id string, batch *leveldb.Batch) error {
+	doc, err := t.getDocument(id)
+	if err != nil || doc == nil {
+		return err
+	}
+	for trigram := range trigrams(strings.ToLower(doc.Content)) {
+		batch.Delete([]byte(trigramPrefix + trigram + id))
+	}
+	batch.Delete([]byte(trigramDocPrefix + id))
+	return nil
+}
+
+func (t *TrigramIndexer) addUpdate(ref, sha string, update fileUpdate, repo *models.Repository, batch *leveldb.Batch) error {
+	// Ignore vendored files in code search
+	if setting.Indexer.ExcludeVendored && enry.IsVendor(update.Filename) {
+		return nil
+	}
+
+	id := filenameIndexerID(repo.ID, ref, update.Filename)
+	if err := t.addDelete(id, batch); err != nil {
+		return err
+	}
+
+	stdout, err := git.NewCommand("cat-file", "-s", update.BlobSha).
+		RunInDir(repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	if size, err := strconv.Atoi(strings.TrimSpace(stdout)); err != nil {
+		return fmt.Errorf("Misformatted git cat-file output: %v", err)
+	} else if int64(size) > setting.Indexer.MaxIndexerFileSize {
+		return nil
+	}
+
+	fileContents, err := git.NewCommand("cat-file", "blob", update.BlobSha).
+		RunInDirBytes(repo.RepoPath())
+	if err != nil {
+		return err
+	} else if !base.IsTextFile(fileContents) {
+		// FIXME: UTF-16 files will probably fail here
+		return nil
+	}
+
+	content := string(charset.ToUTF8DropErrors(fileContents))
+	language := analyze.GetCodeLanguage(update.Filename, fileContents)
+	data, err := json.Marshal(&trigramDocument{
+		RepoID:      repo.ID,
+		Ref:         ref,
+		CommitID:    sha,
+		Content:     content,
+		Language:    language,
+		Symbols:     extractSymbols(language, content),
+		UpdatedUnix: timeutil.TimeStampNow(),
+	})
+	if err != nil {
+		return err
+	}
+	batch.Put([]byte(trigramDocPrefix+id), data)
+	for trigram := range trigrams(strings.ToLower(content)) {
+		batch.Put([]byte(trigramPrefix+trigram+id), nil)
+	}
+	return nil
+}
+
+// Index indexes the data
+func (t *TrigramIndexer) Index(repo *models.Repository, ref, sha string, changes *repoChanges) error {
+	// the batch is written for each file since the previous content of a file has to be read from the index
+	batch := new(leveldb.Batch)
+	for _, update := range changes.Updates {
+		if err := t.addUpdate(ref, sha, update, repo, batch); err != nil {
+			return err
+		}
+		if err := t.db.Write(batch, nil); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	for _, filename := range changes.RemovedFilenames {
+		if err := t.addDelete(filenameIndexerID(repo.ID, ref, filename), batch); err != nil {
+			return err
+		}
+	}
+	return t.db.Write(batch, nil)
+}
+
+// deletePrefix deletes the files whose ids start with the prefix
+func (t *TrigramIndexer) deletePrefix(prefix string) error {
+	batch := new(leveldb.Batch)
+	iter := t.db.NewIterator(util.BytesPrefix([]byte(trigramDocPrefix+prefix)), nil)
+	for iter.Next() {
+		if err := t.addDelete(string(iter.Key()[len(trigramDocPrefix):]), batch); err != nil {
+			iter.Release()
+			return err
+		}
+		if batch.Len() > 1024 {
+			if err := t.db.Write(batch, nil); err != nil {
+				iter.Release()
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return t.db.Write(batch, nil)
+}
+
+// Delete deletes indexes by ids
+func (t *TrigramIndexer) Delete(repoID int64) error {
+	if err := t.deletePrefix(indexerID(repoID) + "_"); err != nil {
+		return err
+	}
+	return t.deletePrefix(indexerID(repoID) + ":")
+}
+
+// DeleteRef deletes the indexes of a ref of a repository
+func (t *TrigramIndexer) DeleteRef(repoID int64, ref string) error {
+	return t.deletePrefix(filenameIndexerID(repoID, ref, ""))
+}
+
+// candidates returns the sorted ids of the files of the repositories and ref which contain all the trigrams
+func (t *TrigramIndexer) candidates(repoIDs []int64, ref string, trigrams []string) ([]string, error) {
+	inScope := func(id string) bool {
+		repoID, idRef, _ := parseIndexerID(id)
+		if idRef != ref {
+			return false
+		}
+		if len(repoIDs) == 0 {
+			return true
+		}
+		for _, scopeID := range repoIDs {
+			if scopeID == repoID {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(trigrams) == 0 {
+		var ids []string
+		prefixes := []string{""}
+		if len(repoIDs) > 0 {
+			prefixes = make([]string, len(repoIDs))
+			for i, repoID := range repoIDs {
+				prefixes[i] = filenameIndexerID(repoID, ref, "")
+			}
+		}
+		for _, prefix := range prefixes {
+			iter := t.db.NewIterator(util.BytesPrefix([]byte(trigramDocPrefix+prefix)), nil)
+			for iter.Next() {
+				if id := string(iter.Key()[len(trigramDocPrefix):]); inScope(id) {
+					ids = append(ids, id)
+				}
+			}
+			iter.Release()
+			if err := iter.Error(); err != nil {
+				return nil, err
+			}
+		}
+		sort.Strings(ids)
+		return ids, nil
+	}
+
+	var result map[string]struct{}
+	for _, trigram := range trigrams {
+		found := make(map[string]struct{})
+		prefix := trigramPrefix + trigram
+		iter := t.db.NewIterator(util.BytesPrefix([]byte(prefix)), nil)
+		for iter.Next() {
+			id := string(iter.Key()[len(prefix):])
+			if result != nil {
+				if _, ok := result[id]; !ok {
+					continue
+				}
+			} else if !inScope(id) {
+				continue
+			}
+			found[id] = struct{}{}
+		}
+		iter.Release()
+		if err := iter.Error(); err != nil {
+			return nil, err
+		}
+		result = found
+		if len(result) == 0 {
+			break
+		}
+	}
+
+	ids := make([]string, 0, len(result))
+	for id := range result {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Search searches for files in the specified repo.
+// Returns the matching file-paths
+func (t *TrigramIndexer) Search(repoIDs []int64, ref, language, keyword string, page, pageSize int) (int64, []*SearchResult, []*SearchResultLanguages, error) {
+	query, err := parseTrigramQuery(keyword)
+	if err != nil {
+		// an invalid regular expression matches nothing
+		log.Debug("Invalid code search query %q: %v", keyword, err)
+		return 0, nil, nil, nil
+	}
+	if query.Pattern == nil && len(query.Symbol) == 0 && len(query.File) == 0 {
+		return 0, nil, nil, nil
+	}
+
+	literals := query.Literals
+	if len(query.Symbol) > 0 {
+		literals = append(literals, strings.ToLower(query.Symbol))
+	}
+	ids, err := t.candidates(repoIDs, ref, queryTrigrams(literals))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	var repos map[int64]*models.Repository
+	if len(query.Repo) > 0 {
+		if repos, err = t.candidateRepos(ids); err != nil {
+			return 0, nil, nil, err
+		}
+	}
+
+	var (
+		total       int64
+		results     = make([]*SearchResult, 0, pageSize)
+		languageMap = make(map[string]int)
+		from        = (page - 1) * pageSize
+	)
+	for _, id := range ids {
+		repoID, ref, filename := parseIndexerID(id)
+		if len(query.File) > 0 && !strings.Contains(strings.ToLower(filename), query.File) {
+			continue
+		}
+		if len(query.Repo) > 0 && !matchRepo(repos[repoID], query.Repo) {
+			continue
+		}
+
+		doc, err := t.getDocument(id)
+		if err != nil {
+			return 0, nil, nil, err
+		} else if doc == nil {
+			continue
+		}
+		if len(query.Language) > 0 && !strings.EqualFold(query.Language, doc.Language) {
+			continue
+		}
+
+		startIndex, endIndex := 0, 0
+		if query.Pattern != nil {
+			loc := query.Pattern.FindStringIndex(doc.Content)
+			if loc == nil {
+				continue
+			}
+			startIndex, endIndex = loc[0], loc[1]
+		}
+		if len(query.Symbol) > 0 {
+			symbol, ok := matchSymbol(doc.Symbols, query.Symbol)
+			if !ok {
+				continue
+			}
+			startIndex, endIndex = symbol.Offset, symbol.Offset+len(symbol.Name)
+		}
+
+		if len(doc.Language) > 0 {
+			languageMap[doc.Language]++
+		}
+		if len(language) > 0 && language != doc.Language {
+			continue
+		}
+		if total >= int64(from) && len(results) < pageSize {
+			results = append(results, &SearchResult{
+				RepoID:      repoID,
+				Ref:         ref,
+				StartIndex:  startIndex,
+				EndIndex:    endIndex,
+				Filename:    filename,
+				Content:     doc.Content,
+				CommitID:    doc.CommitID,
+				UpdatedUnix: doc.UpdatedUnix,
+				Language:    doc.Language,
+				Color:       enry.GetColor(doc.Language),
+			})
+		}
+		total++
+	}
+
+	searchResultLanguages := make([]*SearchResultLanguages, 0, len(languageMap))
+	for language, count := range languageMap {
+		searchResultLanguages = append(searchResultLanguages, &SearchResultLanguages{
+			Language: language,
+			Color:    enry.GetColor(language),
+			Count:    count,
+		})
+	}
+	sort.Slice(searchResultLanguages, func(i, j int) bool {
+		if searchResultLanguages[i].Count != searchResultLanguages[j].Count {
+			return searchResultLanguages[i].Count > searchResultLanguages[j].Count
+		}
+		return searchResultLanguages[i].Language < searchResultLanguages[j].Language
+	})
+	if len(searchResultLanguages) > 10 {
+		searchResultLanguages = searchResultLanguages[:10]
+	}
+	return total, results, searchResultLanguages, nil
+}
+
+// candidateRepos loads the repositories of the files
+func (t *TrigramIndexer) candidateRepos(ids []string) (map[int64]*models.Repository, error) {
+	seen := make(map[int64]bool)
+	repoIDs := make([]int64, 0, 10)
+	for _, id := range ids {
+		repoID, _, _ := parseIndexerID(id)
+		if !seen[repoID] {
+			seen[repoID] = true
+			repoIDs = append(repoIDs, repoID)
+		}
+	}
+	return models.GetRepositoriesMapByIDs(repoIDs)
+}
+
+// matchRepo returns whether the repository matches a repo: qualifier, which is either a full name or a name
+func matchRepo(repo *models.Repository, name string) bool {
+	if repo == nil {
+		return false
+	}
+	if strings.Contains(name, "/") {
+		return strings.ToLower(repo.FullName()) == name
+	}
+	return repo.LowerName == name
+}
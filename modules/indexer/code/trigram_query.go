@@ -0,0 +1,143 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+
+	"code.gitea.io/gitea/modules/util"
+)
+
+// trigramQuery a parsed query of the trigram indexer
+//
+// The keyword is searched as an exact substring ignoring case, unless it is enclosed in slashes
+// in which case it is a multi-line regular expression. The keyword can be combined with qualifiers:
+//
+//	file:<substring>  the file names containing the substring
+//	lang:<language>   the files in the language
+//	repo:<owner/name> the files of the repository, the owner can be omitted
+//	sym:<name>        the files defining a symbol whose name contains name
+//
+// Terms containing spaces can be enclosed in double quotes.
+type trigramQuery struct {
+	Pattern  *regexp.Regexp
+	Literals []string
+	File     string
+	Language string
+	Repo     string
+	Symbol   string
+}
+
+// parseTrigramQuery parses the keyword of a search of the trigram indexer
+func parseTrigramQuery(keyword string) (*trigramQuery, error) {
+	query := &trigramQuery{}
+	var terms []string
+	for _, term := range util.SplitQuotedTerms(keyword) {
+		switch {
+		case strings.HasPrefix(term, "file:"):
+			query.File = strings.ToLower(term[len("file:"):])
+		case strings.HasPrefix(term, "lang:"):
+			query.Language = term[len("lang:"):]
+		case strings.HasPrefix(term, "repo:"):
+			query.Repo = strings.ToLower(term[len("repo:"):])
+		case strings.HasPrefix(term, "sym:"):
+			query.Symbol = term[len("sym:"):]
+		default:
+			terms = append(terms, term)
+		}
+	}
+
+	text := strings.Join(terms, " ")
+	if len(text) == 0 {
+		return query, nil
+	}
+
+	if len(text) > 2 && strings.HasPrefix(text, "/") && strings.HasSuffix(text, "/") {
+		expr := text[1 : len(text)-1]
+		re, err := syntax.Parse(expr, syntax.Perl)
+		if err != nil {
+			return nil, err
+		}
+		// ^ and $ match at the lines of the files like grep
+		if query.Pattern, err = regexp.Compile("(?m)" + expr); err != nil {
+			return nil, err
+		}
+		query.Literals = requiredLiterals(re.Simplify())
+		return query, nil
+	}
+
+	query.Pattern = regexp.MustCompile("(?i)" + regexp.QuoteMeta(text))
+	query.Literals = []string{strings.ToLower(text)}
+	return query, nil
+}
+
+// requiredLiterals returns the lower cased literals which must appear in any text matching the regular expression
+func requiredLiterals(re *syntax.Regexp) []string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return []string{strings.ToLower(string(re.Rune))}
+	case syntax.OpCapture:
+		return requiredLiterals(re.Sub[0])
+	case syntax.OpPlus:
+		return requiredLiterals(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min > 0 {
+			return requiredLiterals(re.Sub[0])
+		}
+	case syntax.OpConcat:
+		// adjacent literals form a single longer literal
+		var (
+			literals []string
+			current  strings.Builder
+		)
+		for _, sub := range re.Sub {
+			if sub.Op == syntax.OpLiteral {
+				current.WriteString(strings.ToLower(string(sub.Rune)))
+				continue
+			}
+			if current.Len() > 0 {
+				literals = append(literals, current.String())
+				current.Reset()
+			}
+			literals = append(literals, requiredLiterals(sub)...)
+		}
+		if current.Len() > 0 {
+			literals = append(literals, current.String())
+		}
+		return literals
+	}
+	return nil
+}
+
+// trigrams returns the distinct trigrams of a lower cased text, the trigrams
+// are made of bytes so that they have a fixed length in the index keys
+func trigrams(text string) map[string]struct{} {
+	result := make(map[string]struct{})
+	for i := 0; i+3 <= len(text); i++ {
+		result[text[i:i+3]] = struct{}{}
+	}
+	return result
+}
+
+// queryTrigrams returns the trigrams which must be in the files matching the literals
+func queryTrigrams(literals []string) []string {
+	seen := make(map[string]struct{})
+	var result []string
+	for _, literal := range literals {
+		if !utf8.ValidString(literal) {
+			continue
+		}
+		for trigram := range trigrams(literal) {
+			if _, ok := seen[trigram]; !ok {
+				seen[trigram] = struct{}{}
+				result = append(result, trigram)
+			}
+		}
+	}
+	return result
+}
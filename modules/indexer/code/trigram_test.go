@@ -0,0 +1,101 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrigramIndexAndSearch(t *testing.T) {
+	models.PrepareTestEnv(t)
+
+	dir, err := ioutil.TempDir("", "trigram.index")
+	assert.NoError(t, err)
+	if err != nil {
+		assert.Fail(t, "Unable to create temporary directory")
+		return
+	}
+	defer util.RemoveAll(dir)
+
+	idx, _, err := NewTrigramIndexer(dir)
+	if err != nil {
+		assert.Fail(t, "Unable to create trigram indexer Error: %v", err)
+		if idx != nil {
+			idx.Close()
+		}
+		return
+	}
+	defer idx.Close()
+
+	testIndexer("trigram", t, idx)
+
+	// regular expressions and qualifiers, testIndexer has deleted the index of the repository
+	assert.NoError(t, index(idx, 1))
+	for _, kw := range []struct {
+		Keyword string
+		Total   int64
+	}{
+		{"/Descr[a-z]+ion/", 1},
+		{"/^# repo1$/", 1},
+		{"/Descr[0-9]+ion/", 0},
+		{"Description file:readme", 1},
+		{"Description file:main", 0},
+		{"Description repo:user2/repo1", 1},
+		{"Description repo:repo1", 1},
+		{"Description repo:user2/repo2", 0},
+		{"Description lang:Markdown", 1},
+		{"Description lang:Go", 0},
+		{"/(/", 0},
+	} {
+		total, _, _, err := idx.Search(nil, "", "", kw.Keyword, 1, 10)
+		assert.NoError(t, err)
+		assert.EqualValues(t, kw.Total, total, kw.Keyword)
+	}
+}
+
+func TestParseTrigramQuery(t *testing.T) {
+	query, err := parseTrigramQuery(`"Hello World" file:Main lang:Go repo:User2/Repo1 sym:New`)
+	assert.NoError(t, err)
+	assert.Equal(t, "main", query.File)
+	assert.Equal(t, "Go", query.Language)
+	assert.Equal(t, "user2/repo1", query.Repo)
+	assert.Equal(t, "New", query.Symbol)
+	assert.Equal(t, []string{"hello world"}, query.Literals)
+	assert.True(t, query.Pattern.MatchString("say hello world"))
+
+	query, err = parseTrigramQuery(`/func\s+(New|Get)Indexer/`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"func", "indexer"}, query.Literals)
+	assert.True(t, query.Pattern.MatchString("func NewIndexer"))
+	assert.False(t, query.Pattern.MatchString("func newIndexer"))
+
+	_, err = parseTrigramQuery(`/(/`)
+	assert.Error(t, err)
+}
+
+func TestExtractSymbols(t *testing.T) {
+	content := "package main\n\ntype Server struct {\n}\n\nfunc (s *Server) Start() error {\n}\n\nfunc NewServer() *Server {\n}\n"
+	symbols := extractSymbols("Go", content)
+	names := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		names = append(names, symbol.Name)
+		assert.Equal(t, symbol.Name, content[symbol.Offset:symbol.Offset+len(symbol.Name)])
+	}
+	assert.ElementsMatch(t, []string{"Server", "Start", "NewServer"}, names)
+
+	symbol, ok := matchSymbol(symbols, "newserver")
+	assert.True(t, ok)
+	assert.Equal(t, "function", symbol.Kind)
+	_, ok = matchSymbol(symbols, "Stop")
+	assert.False(t, ok)
+
+	assert.Empty(t, extractSymbols("Markdown", content))
+}
@@ -57,12 +57,12 @@ func (w *wrappedIndexer) get() (Indexer, error) {
 	return w.internal, nil
 }
 
-func (w *wrappedIndexer) Index(repo *models.Repository, sha string, changes *repoChanges) error {
+func (w *wrappedIndexer) Index(repo *models.Repository, ref, sha string, changes *repoChanges) error {
 	indexer, err := w.get()
 	if err != nil {
 		return err
 	}
-	return indexer.Index(repo, sha, changes)
+	return indexer.Index(repo, ref, sha, changes)
 }
 
 func (w *wrappedIndexer) Delete(repoID int64) error {
@@ -73,12 +73,20 @@ func (w *wrappedIndexer) Delete(repoID int64) error {
 	return indexer.Delete(repoID)
 }
 
-func (w *wrappedIndexer) Search(repoIDs []int64, language, keyword string, page, pageSize int) (int64, []*SearchResult, []*SearchResultLanguages, error) {
+func (w *wrappedIndexer) DeleteRef(repoID int64, ref string) error {
+	indexer, err := w.get()
+	if err != nil {
+		return err
+	}
+	return indexer.DeleteRef(repoID, ref)
+}
+
+func (w *wrappedIndexer) Search(repoIDs []int64, ref, language, keyword string, page, pageSize int) (int64, []*SearchResult, []*SearchResultLanguages, error) {
 	indexer, err := w.get()
 	if err != nil {
 		return 0, nil, nil, err
 	}
-	return indexer.Search(repoIDs, language, keyword, page, pageSize)
+	return indexer.Search(repoIDs, ref, language, keyword, page, pageSize)
 
 }
 
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/log"
@@ -118,3 +119,43 @@ func (s *ContentStore) Verify(meta *models.LFSMetaObject) (bool, error) {
 
 	return true, nil
 }
+
+// uploadPath returns the path the content of the object is uploaded to when it goes to the storage directly,
+// the content is only moved to the object once it is verified
+func uploadPath(meta *models.LFSMetaObject) string {
+	return path.Join("tmp", meta.RelativePath())
+}
+
+// VerifyUpload moves the content uploaded to the storage directly to the object if it matches the OID
+// and returns true if the object exists in the content store. The uploaded content is deleted.
+func (s *ContentStore) VerifyUpload(meta *models.LFSMetaObject) (bool, error) {
+	if ok, err := s.Verify(meta); ok || err != nil {
+		return ok, err
+	}
+
+	p := uploadPath(meta)
+	if _, err := s.Stat(p); os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		log.Error("Unable stat file: %s for LFS OID[%s] Error: %v", p, meta.Oid, err)
+		return false, err
+	}
+	f, err := s.Open(p)
+	if err != nil {
+		log.Error("Unable to open file: %s for LFS OID[%s] Error: %v", p, meta.Oid, err)
+		return false, err
+	}
+	// Put checks the hash of the content while it is written to the object
+	err = s.Put(meta, f)
+	f.Close()
+	if err := s.Delete(p); err != nil {
+		log.Error("Cleaning the uploaded LFS OID[%s] failed: %v", meta.Oid, err)
+	}
+	if err == errSizeMismatch || err == errHashMismatch {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
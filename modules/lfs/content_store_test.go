@@ -0,0 +1,69 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentStoreVerifyUpload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lfs-content-store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	objectStorage, err := storage.NewLocalStorage(context.Background(), storage.LocalStorageConfig{Path: dir})
+	assert.NoError(t, err)
+	contentStore := &ContentStore{ObjectStorage: objectStorage}
+
+	const content = "gitea lfs content"
+	hash := sha256.Sum256([]byte(content))
+	meta := &models.LFSMetaObject{Oid: hex.EncodeToString(hash[:]), Size: int64(len(content))}
+
+	exists := func(p string) bool {
+		_, err := objectStorage.Stat(p)
+		return err == nil
+	}
+
+	// nothing has been uploaded
+	ok, err := contentStore.VerifyUpload(meta)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// the content not matching the OID is deleted and never becomes the object
+	_, err = objectStorage.Save(uploadPath(meta), strings.NewReader("gitea lfs CONTENT"))
+	assert.NoError(t, err)
+	assert.False(t, exists(meta.RelativePath()))
+	ok, err = contentStore.VerifyUpload(meta)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.False(t, exists(uploadPath(meta)))
+	assert.False(t, exists(meta.RelativePath()))
+
+	// the content matching the OID is moved to the object
+	_, err = objectStorage.Save(uploadPath(meta), strings.NewReader(content))
+	assert.NoError(t, err)
+	ok, err = contentStore.VerifyUpload(meta)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, exists(uploadPath(meta)))
+	ok, err = contentStore.Verify(meta)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// an object already verified is kept
+	ok, err = contentStore.VerifyUpload(meta)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
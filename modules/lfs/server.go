@@ -448,14 +448,18 @@ func VerifyHandler(ctx *context.Context) {
 
 	rv := unpack(ctx)
 
-	meta, _ := getAuthenticatedRepoAndMeta(ctx, rv, true)
+	meta, repository := getAuthenticatedRepoAndMeta(ctx, rv, true)
 	if meta == nil {
 		// Status already written in getAuthenticatedRepoAndMeta
 		return
 	}
 
 	contentStore := &ContentStore{ObjectStorage: storage.LFS}
-	ok, err := contentStore.Verify(meta)
+	verify := contentStore.Verify
+	if setting.LFS.UploadDirect {
+		verify = contentStore.VerifyUpload
+	}
+	ok, err := verify(meta)
 	if err != nil {
 		// Error will be logged in Verify
 		ctx.Resp.WriteHeader(500)
@@ -463,6 +467,12 @@ func VerifyHandler(ctx *context.Context) {
 		return
 	}
 	if !ok {
+		if setting.LFS.UploadDirect {
+			// the meta object of a rejected upload is removed as in PutHandler
+			if _, err = repository.RemoveLFSMetaObjectByOid(rv.Oid); err != nil {
+				log.Error("Whilst removing metaobject for LFS OID[%s] due to failed verification there was another Error: %v", rv.Oid, err)
+			}
+		}
 		writeStatus(ctx, 422)
 		return
 	}
@@ -494,6 +504,18 @@ func Represent(rv *RequestVars, meta *models.LFSMetaObject, download, upload boo
 
 	if upload {
 		rep.Actions["upload"] = &link{Href: rv.ObjectLink(), Header: header}
+
+		if setting.LFS.UploadDirect {
+			// The content goes to the storage directly, it is checked and moved to the object by the verify action
+			if u, err := storage.LFS.UploadURL(uploadPath(meta), setting.LFS.HTTPAuthExpiry); err != nil {
+				log.Error("Unable to get the upload URL of LFS OID[%s]: %v", meta.Oid, err)
+			} else if err := models.NewPendingUpload(models.PendingUploadLFS, uploadPath(meta), 0, setting.LFS.HTTPAuthExpiry); err != nil {
+				// the uploaded content would never be deleted if it isn't verified
+				log.Error("Unable to record the pending upload of LFS OID[%s]: %v", meta.Oid, err)
+			} else {
+				rep.Actions["upload"] = &link{Href: u.URL.String(), Header: u.Header, ExpiresAt: time.Now().Add(setting.LFS.HTTPAuthExpiry)}
+			}
+		}
 	}
 
 	if upload && !download {
@@ -19,6 +19,7 @@ type Event struct {
 	line       int
 	time       time.Time
 	stacktrace string
+	requestID  string
 }
 
 // EventLogger represents the behaviours of a logger
@@ -26,6 +26,7 @@ const (
 	LUTC                       // if Ldate or Ltime is set, use UTC rather than the local time zone
 	Llevelinitial              // Initial character of the provided level in brackets eg. [I] for info
 	Llevel                     // Provided level in brackets [INFO]
+	Lrequestid                 // The request ID, if there is one, in brackets eg. [4f3c...]
 
 	// Last 20 characters of the filename
 	Lmedfile = Lshortfile | Llongfile
@@ -46,6 +47,7 @@ var flagFromString = map[string]int{
 	"utc":           LUTC,
 	"levelinitial":  Llevelinitial,
 	"level":         Llevel,
+	"requestid":     Lrequestid,
 	"medfile":       Lmedfile,
 	"stdflags":      LstdFlags,
 }
@@ -54,8 +54,25 @@ func (l *MultiChannelledLogger) DelLogger(name string) (bool, error) {
 	return l.MultiChannelledLog.DelLogger(name), nil
 }
 
+// WithRequestID returns a Logger which marks the events it sends to this logger with the provided request ID
+func (l *MultiChannelledLogger) WithRequestID(requestID string) Logger {
+	if requestID == "" {
+		return l
+	}
+	return &LevelLoggerLogger{
+		LevelLogger: &requestIDLogger{
+			logger:    l,
+			requestID: requestID,
+		},
+	}
+}
+
 // Log msg at the provided level with the provided caller defined by skip (0 being the function that calls this function)
 func (l *MultiChannelledLogger) Log(skip int, level Level, format string, v ...interface{}) error {
+	return l.log(skip+1, "", level, format, v...)
+}
+
+func (l *MultiChannelledLogger) log(skip int, requestID string, level Level, format string, v ...interface{}) error {
 	if l.GetLevel() > level {
 		return nil
 	}
@@ -76,15 +93,25 @@ func (l *MultiChannelledLogger) Log(skip int, level Level, format string, v ...i
 	if l.GetStacktraceLevel() <= level {
 		stack = Stack(skip + 1)
 	}
-	return l.SendLog(level, caller, strings.TrimPrefix(filename, prefix), line, msg, stack)
+	return l.sendLog(requestID, level, caller, strings.TrimPrefix(filename, prefix), line, msg, stack)
 }
 
 // SendLog sends a log event at the provided level with the information given
 func (l *MultiChannelledLogger) SendLog(level Level, caller, filename string, line int, msg string, stack string) error {
+	return l.sendLog("", level, caller, filename, line, msg, stack)
+}
+
+// SendLogWithRequestID sends a log event marked with the provided request ID
+func (l *MultiChannelledLogger) SendLogWithRequestID(requestID string, level Level, caller, filename string, line int, msg string, stack string) error {
+	return l.sendLog(requestID, level, caller, filename, line, msg, stack)
+}
+
+func (l *MultiChannelledLogger) sendLog(requestID string, level Level, caller, filename string, line int, msg string, stack string) error {
 	if l.GetLevel() > level {
 		return nil
 	}
 	event := &Event{
+		requestID:  requestID,
 		level:      level,
 		caller:     caller,
 		filename:   filename,
@@ -0,0 +1,93 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+)
+
+const (
+	// RequestIDHeader is the HTTP header used to pass the request ID to and from Gitea
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDEnv is the environment variable used to pass the request ID to child processes and hooks
+	RequestIDEnv = "GITEA_REQUEST_ID"
+)
+
+// requestIDPattern restricts request IDs provided by clients to something safe to log and pass around
+var requestIDPattern = regexp.MustCompile(`^[a-zA-Z0-9._:-]{1,64}$`)
+
+type requestIDContextKey struct{}
+
+// NewRequestID generates a new random request ID
+func NewRequestID() string {
+	bs := make([]byte, 16)
+	if _, err := rand.Read(bs); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(bs)
+}
+
+// IsValidRequestID checks if the provided request ID is acceptable
+func IsValidRequestID(requestID string) bool {
+	return requestIDPattern.MatchString(requestID)
+}
+
+// ContextWithRequestID returns a copy of the parent context carrying the provided request ID
+func ContextWithRequestID(parent context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return parent
+	}
+	return context.WithValue(parent, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by the context or "" if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// WithRequestID returns a Logger for the default logger which will mark its events
+// with the provided request ID
+func WithRequestID(requestID string) Logger {
+	return GetLogger(DEFAULT).WithRequestID(requestID)
+}
+
+// FromContext returns a Logger for the default logger which will mark its events
+// with the request ID carried by the context
+func FromContext(ctx context.Context) Logger {
+	return WithRequestID(RequestIDFromContext(ctx))
+}
+
+// requestIDLogger is a LevelLogger which marks its events with a request ID
+type requestIDLogger struct {
+	logger    *MultiChannelledLogger
+	requestID string
+}
+
+// Log msg at the provided level with the provided caller defined by skip (0 being the function that calls this function)
+func (l *requestIDLogger) Log(skip int, level Level, format string, v ...interface{}) error {
+	return l.logger.log(skip+1, l.requestID, level, format, v...)
+}
+
+// Flush flushes the underlying logger
+func (l *requestIDLogger) Flush() {
+	l.logger.Flush()
+}
+
+// Close closes the underlying logger
+func (l *requestIDLogger) Close() {
+	l.logger.Close()
+}
+
+// GetLevel returns the level of the underlying logger
+func (l *requestIDLogger) GetLevel() Level {
+	return l.logger.GetLevel()
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDContext(t *testing.T) {
+	requestID := NewRequestID()
+	assert.Len(t, requestID, 32)
+	assert.True(t, IsValidRequestID(requestID))
+	assert.NotEqual(t, requestID, NewRequestID())
+
+	assert.False(t, IsValidRequestID(""))
+	assert.False(t, IsValidRequestID("bad id\n"))
+
+	ctx := context.Background()
+	assert.Equal(t, "", RequestIDFromContext(ctx))
+	assert.Equal(t, ctx, ContextWithRequestID(ctx, ""))
+	assert.Equal(t, requestID, RequestIDFromContext(ContextWithRequestID(ctx, requestID)))
+}
+
+func TestRequestIDLogger(t *testing.T) {
+	logger := newLogger("REQUESTID", 0)
+	assert.NoError(t, logger.SetLogger("console", "console", `{"level":"info","format":"json"}`))
+
+	written := make(chan []byte)
+	realCL := logger.MultiChannelledLog.GetEventLogger("console").(*ChannelledLog).loggerProvider.(*ConsoleLogger)
+	realCL.out = CallbackWriteCloser{
+		callback: func(p []byte, close bool) {
+			written <- p
+		},
+	}
+
+	var evt jsonEvent
+
+	logger.WithRequestID("abc-123").Info("test: %s", "A")
+	assert.NoError(t, json.Unmarshal(<-written, &evt))
+	assert.Equal(t, "abc-123", evt.RequestID)
+	assert.Equal(t, "test: A", evt.Message)
+	assert.Equal(t, "code.gitea.io/gitea/modules/log.TestRequestIDLogger()", evt.Caller)
+
+	evt = jsonEvent{}
+	logger.Info("test: %s", "B")
+	assert.NoError(t, json.Unmarshal(<-written, &evt))
+	assert.Equal(t, "", evt.RequestID)
+	assert.Equal(t, "test: B", evt.Message)
+	assert.Equal(t, "code.gitea.io/gitea/modules/log.TestRequestIDLogger()", evt.Caller)
+
+	go logger.Close()
+	<-written
+}
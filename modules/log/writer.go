@@ -6,11 +6,13 @@ package log
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 )
 
 type byteArrayWriter []byte
@@ -31,6 +33,7 @@ type WriterLogger struct {
 	Prefix          string `json:"prefix"`
 	Colorize        bool   `json:"colorize"`
 	Expression      string `json:"expression"`
+	Format          string `json:"format"`
 	regexp          *regexp.Regexp
 }
 
@@ -88,7 +91,65 @@ func itoa(buf *[]byte, i int, wid int) {
 	*buf = append(*buf, logger[bp:]...)
 }
 
+// jsonEvent is the representation of an Event written by the json format
+type jsonEvent struct {
+	Time       string `json:"time"`
+	Level      string `json:"level"`
+	Prefix     string `json:"prefix,omitempty"`
+	Caller     string `json:"caller,omitempty"`
+	Filename   string `json:"filename,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	RequestID  string `json:"request_id,omitempty"`
+	Message    string `json:"message"`
+	Stacktrace string `json:"stacktrace,omitempty"`
+}
+
+func (logger *WriterLogger) createJSONMsg(buf *[]byte, event *Event) {
+	t := event.time
+	if logger.Flags&LUTC != 0 {
+		t = t.UTC()
+	}
+
+	// JSON consumers have no use for the colors
+	var baw byteArrayWriter
+	(&protectedANSIWriter{
+		w:    &baw,
+		mode: removeColor,
+	}).Write([]byte(strings.TrimSuffix(event.msg, "\n")))
+
+	jsonEvt := jsonEvent{
+		Time:      t.Format(time.RFC3339Nano),
+		Level:     event.level.String(),
+		Prefix:    logger.Prefix,
+		Caller:    event.caller,
+		Filename:  event.filename,
+		Line:      event.line,
+		RequestID: event.requestID,
+		Message:   string(baw),
+	}
+	if event.stacktrace != "" && logger.StacktraceLevel <= event.level {
+		jsonEvt.Stacktrace = event.stacktrace
+	}
+
+	bs, err := json.Marshal(jsonEvt)
+	if err != nil {
+		// This should not happen - but if it does let's at least make a record of the message
+		bs, _ = json.Marshal(jsonEvent{
+			Time:    jsonEvt.Time,
+			Level:   jsonEvt.Level,
+			Message: fmt.Sprintf("Unable to marshal log event: %v", err),
+		})
+	}
+	*buf = append(*buf, bs...)
+	*buf = append(*buf, '\n')
+}
+
 func (logger *WriterLogger) createMsg(buf *[]byte, event *Event) {
+	if logger.Format == "json" {
+		logger.createJSONMsg(buf, event)
+		return
+	}
+
 	*buf = append(*buf, logger.Prefix...)
 	t := event.time
 	if logger.Flags&(Ldate|Ltime|Lmicroseconds) != 0 {
@@ -188,6 +249,11 @@ func (logger *WriterLogger) createMsg(buf *[]byte, event *Event) {
 		}
 		*buf = append(*buf, ' ')
 	}
+	if logger.Flags&Lrequestid != 0 && event.requestID != "" {
+		*buf = append(*buf, '[')
+		*buf = append(*buf, event.requestID...)
+		*buf = append(*buf, ']', ' ')
+	}
 
 	var msg = []byte(event.msg)
 	if len(msg) > 0 && msg[len(msg)-1] == '\n' {
@@ -275,3 +275,70 @@ func TestBrokenRegexp(t *testing.T) {
 	b.Close()
 	assert.Equal(t, true, closed)
 }
+
+func TestJSONLogger(t *testing.T) {
+	var written []byte
+
+	c := CallbackWriteCloser{
+		callback: func(p []byte, close bool) {
+			written = p
+		},
+	}
+	b := WriterLogger{
+		out:             c,
+		Level:           INFO,
+		StacktraceLevel: ERROR,
+		Flags:           LstdFlags | LUTC,
+		Prefix:          "TestPrefix",
+		Format:          "json",
+	}
+
+	date := time.Date(2019, time.January, 13, 22, 3, 30, 15, time.UTC)
+
+	event := Event{
+		level:      INFO,
+		msg:        "TEST " + ColorSprintf("%s", NewColoredValue("MSG")) + "\n",
+		caller:     "CALLER",
+		filename:   "FULL/FILENAME",
+		line:       1,
+		time:       date,
+		requestID:  "abc-123",
+		stacktrace: "STACK",
+	}
+
+	b.LogEvent(&event)
+	assert.Equal(t, `{"time":"2019-01-13T22:03:30.000000015Z","level":"info","prefix":"TestPrefix","caller":"CALLER","filename":"FULL/FILENAME","line":1,"request_id":"abc-123","message":"TEST MSG"}`+"\n", string(written))
+
+	event.level = ERROR
+	event.requestID = ""
+	b.LogEvent(&event)
+	assert.Equal(t, `{"time":"2019-01-13T22:03:30.000000015Z","level":"error","prefix":"TestPrefix","caller":"CALLER","filename":"FULL/FILENAME","line":1,"message":"TEST MSG","stacktrace":"STACK"}`+"\n", string(written))
+}
+
+func TestRequestIDFlag(t *testing.T) {
+	var written []byte
+
+	c := CallbackWriteCloser{
+		callback: func(p []byte, close bool) {
+			written = p
+		},
+	}
+	b := WriterLogger{
+		out:   c,
+		Level: INFO,
+		Flags: Llevelinitial | Lrequestid,
+	}
+
+	event := Event{
+		level:     INFO,
+		msg:       "TEST MSG",
+		time:      time.Now(),
+		requestID: "abc-123",
+	}
+	b.LogEvent(&event)
+	assert.Equal(t, "[I] [abc-123] TEST MSG\n", string(written))
+
+	event.requestID = ""
+	b.LogEvent(&event)
+	assert.Equal(t, "[I] TEST MSG\n", string(written))
+}
@@ -6,6 +6,7 @@ package metrics
 
 import (
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/queue"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -15,28 +16,31 @@ const namespace = "gitea_"
 // Collector implements the prometheus.Collector interface and
 // exposes gitea metrics for prometheus
 type Collector struct {
-	Accesses      *prometheus.Desc
-	Actions       *prometheus.Desc
-	Attachments   *prometheus.Desc
-	Comments      *prometheus.Desc
-	Follows       *prometheus.Desc
-	HookTasks     *prometheus.Desc
-	Issues        *prometheus.Desc
-	Labels        *prometheus.Desc
-	LoginSources  *prometheus.Desc
-	Milestones    *prometheus.Desc
-	Mirrors       *prometheus.Desc
-	Oauths        *prometheus.Desc
-	Organizations *prometheus.Desc
-	PublicKeys    *prometheus.Desc
-	Releases      *prometheus.Desc
-	Repositories  *prometheus.Desc
-	Stars         *prometheus.Desc
-	Teams         *prometheus.Desc
-	UpdateTasks   *prometheus.Desc
-	Users         *prometheus.Desc
-	Watches       *prometheus.Desc
-	Webhooks      *prometheus.Desc
+	Accesses        *prometheus.Desc
+	Actions         *prometheus.Desc
+	Attachments     *prometheus.Desc
+	Comments        *prometheus.Desc
+	Follows         *prometheus.Desc
+	HookTasks       *prometheus.Desc
+	Issues          *prometheus.Desc
+	Labels          *prometheus.Desc
+	LoginSources    *prometheus.Desc
+	Milestones      *prometheus.Desc
+	Mirrors         *prometheus.Desc
+	Oauths          *prometheus.Desc
+	Organizations   *prometheus.Desc
+	PublicKeys      *prometheus.Desc
+	QueueItems      *prometheus.Desc
+	QueueWorkers    *prometheus.Desc
+	QueueMaxWorkers *prometheus.Desc
+	Releases        *prometheus.Desc
+	Repositories    *prometheus.Desc
+	Stars           *prometheus.Desc
+	Teams           *prometheus.Desc
+	UpdateTasks     *prometheus.Desc
+	Users           *prometheus.Desc
+	Watches         *prometheus.Desc
+	Webhooks        *prometheus.Desc
 }
 
 // NewCollector returns a new Collector with all prometheus.Desc initialized
@@ -112,6 +116,21 @@ func NewCollector() Collector {
 			"Number of PublicKeys",
 			nil, nil,
 		),
+		QueueItems: prometheus.NewDesc(
+			namespace+"queue_items",
+			"Number of items waiting in a queue",
+			[]string{"queue"}, nil,
+		),
+		QueueWorkers: prometheus.NewDesc(
+			namespace+"queue_workers",
+			"Number of workers of a queue",
+			[]string{"queue"}, nil,
+		),
+		QueueMaxWorkers: prometheus.NewDesc(
+			namespace+"queue_max_workers",
+			"Maximum number of workers of a queue",
+			[]string{"queue"}, nil,
+		),
 		Releases: prometheus.NewDesc(
 			namespace+"releases",
 			"Number of Releases",
@@ -172,6 +191,9 @@ func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Oauths
 	ch <- c.Organizations
 	ch <- c.PublicKeys
+	ch <- c.QueueItems
+	ch <- c.QueueWorkers
+	ch <- c.QueueMaxWorkers
 	ch <- c.Releases
 	ch <- c.Repositories
 	ch <- c.Stars
@@ -296,4 +318,29 @@ func (c Collector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(stats.Counter.Webhook),
 	)
+
+	for _, mq := range queue.GetManager().ManagedQueues() {
+		if items := mq.NumberInQueue(); items >= 0 {
+			ch <- prometheus.MustNewConstMetric(
+				c.QueueItems,
+				prometheus.GaugeValue,
+				float64(items),
+				mq.Name,
+			)
+		}
+		if workers := mq.NumberOfWorkers(); workers >= 0 {
+			ch <- prometheus.MustNewConstMetric(
+				c.QueueWorkers,
+				prometheus.GaugeValue,
+				float64(workers),
+				mq.Name,
+			)
+			ch <- prometheus.MustNewConstMetric(
+				c.QueueMaxWorkers,
+				prometheus.GaugeValue,
+				float64(mq.MaxNumberOfWorkers()),
+				mq.Name,
+			)
+		}
+	}
 }
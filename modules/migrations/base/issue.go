@@ -24,4 +24,5 @@ type Issue struct {
 	Labels      []*Label
 	Reactions   []*Reaction
 	Assignees   []string
+	ParentIndex int64 // the number of the parent issue, 0 if none
 }
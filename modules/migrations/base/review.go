@@ -37,6 +37,7 @@ type ReviewComment struct {
 	DiffHunk  string
 	Position  int
 	Line      int
+	StartLine int // first line of a multi-line comment, negative on the old side, 0 for single line comments
 	CommitID  string
 	PosterID  int64
 	Reactions []*Reaction
@@ -52,6 +52,7 @@ type GiteaLocalUploader struct {
 	userMap        map[int64]int64 // external user id mapping to user id
 	prCache        map[int64]*models.PullRequest
 	gitServiceType structs.GitServiceType
+	subIssues      map[int64][]int64 // parent issue number to the ids of its sub-issues waiting for it
 }
 
 // NewGiteaLocalUploader creates an gitea Uploader via gitea API v1
@@ -64,6 +65,7 @@ func NewGiteaLocalUploader(ctx context.Context, doer *models.User, repoOwner, re
 		prHeadCache: make(map[string]struct{}),
 		userMap:     make(map[int64]int64),
 		prCache:     make(map[int64]*models.PullRequest),
+		subIssues:   make(map[int64][]int64),
 	}
 }
 
@@ -402,11 +404,35 @@ func (g *GiteaLocalUploader) CreateIssues(issues ...*base.Issue) error {
 		for _, is := range iss {
 			g.issues.Store(is.Index, is.ID)
 		}
+
+		for i, issue := range issues {
+			if issue.ParentIndex > 0 {
+				g.subIssues[issue.ParentIndex] = append(g.subIssues[issue.ParentIndex], iss[i].ID)
+			}
+		}
+		if err := g.linkSubIssues(); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// linkSubIssues sets the parents of the sub-issues whose parent issues have been created
+func (g *GiteaLocalUploader) linkSubIssues() error {
+	for parentIndex, issueIDs := range g.subIssues {
+		parentID, ok := g.issues.Load(parentIndex)
+		if !ok {
+			continue
+		}
+		if err := models.UpdateIssuesParentID(parentID.(int64), issueIDs); err != nil {
+			return err
+		}
+		delete(g.subIssues, parentIndex)
+	}
+	return nil
+}
+
 // CreateComments creates comments of issues
 func (g *GiteaLocalUploader) CreateComments(comments ...*base.Comment) error {
 	var cms = make([]*models.Comment, 0, len(comments))
@@ -807,7 +833,10 @@ func (g *GiteaLocalUploader) CreateReviews(reviews ...*base.Review) error {
 				// We should ignore the error since the commit maybe removed when force push to the pull request
 				log.Warn("GetRepoRawDiffForFile failed when migrating [%s, %s, %s, %s]: %v", g.gitRepo.Path, pr.MergeBase, headCommitID, comment.TreePath, err)
 			} else {
-				patch = git.CutDiffAroundLine(patchBuf, int64((&models.Comment{Line: int64(line + comment.Position - 1)}).UnsignedLine()), line < 0, setting.UI.CodeCommentLines)
+				patch, err = git.CutDiffAroundLineRange(patchBuf, int64(comment.StartLine), int64(line+comment.Position-1), setting.UI.CodeCommentLines)
+				if err != nil {
+					return err
+				}
 			}
 
 			var c = models.Comment{
@@ -816,6 +845,7 @@ func (g *GiteaLocalUploader) CreateReviews(reviews ...*base.Review) error {
 				IssueID:     issueID,
 				Content:     comment.Content,
 				Line:        int64(line + comment.Position - 1),
+				StartLine:   int64(comment.StartLine),
 				TreePath:    comment.TreePath,
 				CommitSHA:   comment.CommitID,
 				Patch:       patch,
@@ -676,6 +676,11 @@ func (g *GithubDownloaderV3) convertGithubReviewComments(cs []*github.PullReques
 			}
 		}
 
+		startLine := c.GetStartLine()
+		if c.GetStartSide() == "LEFT" {
+			startLine = -startLine
+		}
+
 		rcs = append(rcs, &base.ReviewComment{
 			ID:        c.GetID(),
 			InReplyTo: c.GetInReplyTo(),
@@ -683,6 +688,7 @@ func (g *GithubDownloaderV3) convertGithubReviewComments(cs []*github.PullReques
 			TreePath:  c.GetPath(),
 			DiffHunk:  c.GetDiffHunk(),
 			Position:  c.GetPosition(),
+			StartLine: startLine,
 			CommitID:  c.GetCommitID(),
 			PosterID:  c.GetUser().GetID(),
 			Reactions: reactions,
@@ -62,6 +62,8 @@ func (f *GitlabDownloaderFactory) GitServiceType() structs.GitServiceType {
 // because Gitlab has individual Issue and Pull Request numbers.
 // - issueSeen, working alongside issueCount, is checked in GetComments() to see whether we
 // need to fetch the Issue or PR comments, as Gitlab stores them separately.
+// - the epics of the issues are migrated as parent issues numbered after the last issue,
+// epicNumbers maps the ids of the epics to their issue numbers.
 type GitlabDownloader struct {
 	ctx             context.Context
 	client          *gitlab.Client
@@ -70,6 +72,9 @@ type GitlabDownloader struct {
 	issueCount      int64
 	fetchPRcomments bool
 	maxPerPage      int
+	maxIssueIID     int64
+	epics           []*gitlab.Epic
+	epicNumbers     map[int]int64
 }
 
 // NewGitlabDownloader creates a gitlab Downloader via gitlab API
@@ -118,11 +123,12 @@ func NewGitlabDownloader(ctx context.Context, baseURL, repoPath, username, passw
 	}
 
 	return &GitlabDownloader{
-		ctx:        ctx,
-		client:     gitlabClient,
-		repoID:     gr.ID,
-		repoName:   gr.Name,
-		maxPerPage: 100,
+		ctx:         ctx,
+		client:      gitlabClient,
+		repoID:      gr.ID,
+		repoName:    gr.Name,
+		maxPerPage:  100,
+		epicNumbers: make(map[int]int64),
 	}, nil
 }
 
@@ -365,6 +371,12 @@ func (g *GitlabDownloader) GetIssues(page, perPage int) ([]*base.Issue, bool, er
 
 	var allIssues = make([]*base.Issue, 0, perPage)
 
+	if g.maxIssueIID == 0 {
+		if err := g.loadMaxIssueIID(); err != nil {
+			return nil, false, err
+		}
+	}
+
 	issues, _, err := g.client.Issues.ListProjectIssues(g.repoID, opt, nil, gitlab.WithContext(g.ctx))
 	if err != nil {
 		return nil, false, fmt.Errorf("error while listing issues: %v", err)
@@ -400,26 +412,114 @@ func (g *GitlabDownloader) GetIssues(page, perPage int) ([]*base.Issue, bool, er
 		}
 
 		allIssues = append(allIssues, &base.Issue{
-			Title:      issue.Title,
-			Number:     int64(issue.IID),
-			PosterID:   int64(issue.Author.ID),
-			PosterName: issue.Author.Username,
-			Content:    issue.Description,
-			Milestone:  milestone,
-			State:      issue.State,
-			Created:    *issue.CreatedAt,
-			Labels:     labels,
-			Reactions:  reactions,
-			Closed:     issue.ClosedAt,
-			IsLocked:   issue.DiscussionLocked,
-			Updated:    *issue.UpdatedAt,
+			Title:       issue.Title,
+			Number:      int64(issue.IID),
+			PosterID:    int64(issue.Author.ID),
+			PosterName:  issue.Author.Username,
+			Content:     issue.Description,
+			Milestone:   milestone,
+			State:       issue.State,
+			Created:     *issue.CreatedAt,
+			Labels:      labels,
+			Reactions:   reactions,
+			Closed:      issue.ClosedAt,
+			IsLocked:    issue.DiscussionLocked,
+			Updated:     *issue.UpdatedAt,
+			ParentIndex: g.epicNumber(issue.Epic),
 		})
 
 		// increment issueCount, to be used in GetPullRequests()
 		g.issueCount++
 	}
 
-	return allIssues, len(issues) < perPage, nil
+	isEnd := len(issues) < perPage
+	if isEnd {
+		epics, err := g.getEpics()
+		if err != nil {
+			return nil, false, err
+		}
+		allIssues = append(allIssues, epics...)
+	}
+
+	return allIssues, isEnd, nil
+}
+
+// loadMaxIssueIID loads the number of the last issue of the project after which the epics are numbered
+func (g *GitlabDownloader) loadMaxIssueIID() error {
+	state := "all"
+	sort := "desc"
+	issues, _, err := g.client.Issues.ListProjectIssues(g.repoID, &gitlab.ListProjectIssuesOptions{
+		State: &state,
+		Sort:  &sort,
+		ListOptions: gitlab.ListOptions{
+			PerPage: 1,
+		},
+	}, nil, gitlab.WithContext(g.ctx))
+	if err != nil {
+		return fmt.Errorf("error while listing issues: %v", err)
+	}
+	if len(issues) > 0 {
+		g.maxIssueIID = int64(issues[0].IID)
+	}
+	return nil
+}
+
+// epicNumber returns the issue number of the epic, numbering it after the last issue the first time it is seen
+func (g *GitlabDownloader) epicNumber(epic *gitlab.Epic) int64 {
+	if epic == nil {
+		return 0
+	}
+	if number, ok := g.epicNumbers[epic.ID]; ok {
+		return number
+	}
+	number := g.maxIssueIID + int64(len(g.epics)) + 1
+	g.epicNumbers[epic.ID] = number
+	g.epics = append(g.epics, epic)
+	return number
+}
+
+// getEpics returns the epics of the issues as issues
+func (g *GitlabDownloader) getEpics() ([]*base.Issue, error) {
+	var allEpics = make([]*base.Issue, 0, len(g.epics))
+	for _, ref := range g.epics {
+		epic, _, err := g.client.Epics.GetEpic(ref.GroupID, ref.IID, gitlab.WithContext(g.ctx))
+		if err != nil {
+			return nil, fmt.Errorf("error while getting epic: %v", err)
+		}
+
+		var labels = make([]*base.Label, 0, len(epic.Labels))
+		for _, l := range epic.Labels {
+			labels = append(labels, &base.Label{
+				Name: l,
+			})
+		}
+
+		var posterID int64
+		var posterName string
+		if epic.Author != nil {
+			posterID = int64(epic.Author.ID)
+			posterName = epic.Author.Username
+		}
+
+		number := g.epicNumbers[ref.ID]
+		allEpics = append(allEpics, &base.Issue{
+			Title:      epic.Title,
+			Number:     number,
+			PosterID:   posterID,
+			PosterName: posterName,
+			Content:    epic.Description,
+			State:      epic.State,
+			Created:    *epic.CreatedAt,
+			Updated:    *epic.UpdatedAt,
+			Labels:     labels,
+		})
+
+		// the pull requests are numbered after the epics
+		if g.issueCount < number {
+			g.issueCount = number
+		}
+	}
+	return allEpics, nil
 }
 
 // GetComments returns comments according issueNumber
@@ -436,6 +536,10 @@ func (g *GitlabDownloader) GetComments(issueNumber int64) ([]*base.Comment, erro
 		var err error
 		// fetchPRcomments decides whether to fetch Issue or PR comments
 		if !g.fetchPRcomments {
+			if issueNumber > g.maxIssueIID && issueNumber <= g.maxIssueIID+int64(len(g.epics)) {
+				// the comments of the epics are not migrated
+				return allComments, nil
+			}
 			realIssueNumber = issueNumber
 			comments, resp, err = g.client.Discussions.ListIssueDiscussions(g.repoID, int(realIssueNumber), &gitlab.ListIssueDiscussionsOptions{
 				Page:    page,
@@ -29,6 +29,7 @@ type Notifier interface {
 	NotifyIssueClearLabels(doer *models.User, issue *models.Issue)
 	NotifyIssueChangeTitle(doer *models.User, issue *models.Issue, oldTitle string)
 	NotifyIssueChangeRef(doer *models.User, issue *models.Issue, oldRef string)
+	NotifyIssueTransfer(doer *models.User, issue *models.Issue, oldRepo *models.Repository)
 	NotifyIssueChangeLabels(doer *models.User, issue *models.Issue,
 		addedLabels []*models.Label, removedLabels []*models.Label)
 
@@ -55,4 +56,6 @@ type Notifier interface {
 	NotifySyncPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits)
 	NotifySyncCreateRef(doer *models.User, repo *models.Repository, refType, refFullName string)
 	NotifySyncDeleteRef(doer *models.User, repo *models.Repository, refType, refFullName string)
+
+	NotifyCreateCommitStatus(creator *models.User, repo *models.Repository, sha string, status *models.CommitStatus)
 }
@@ -106,6 +106,10 @@ func (*NullNotifier) NotifyIssueChangeTitle(doer *models.User, issue *models.Iss
 func (*NullNotifier) NotifyIssueChangeRef(doer *models.User, issue *models.Issue, oldTitle string) {
 }
 
+// NotifyIssueTransfer places a place holder function
+func (*NullNotifier) NotifyIssueTransfer(doer *models.User, issue *models.Issue, oldRepo *models.Repository) {
+}
+
 // NotifyIssueChangeLabels places a place holder function
 func (*NullNotifier) NotifyIssueChangeLabels(doer *models.User, issue *models.Issue,
 	addedLabels []*models.Label, removedLabels []*models.Label) {
@@ -158,3 +162,7 @@ func (*NullNotifier) NotifySyncCreateRef(doer *models.User, repo *models.Reposit
 // NotifySyncDeleteRef places a place holder function
 func (*NullNotifier) NotifySyncDeleteRef(doer *models.User, repo *models.Repository, refType, refFullName string) {
 }
+
+// NotifyCreateCommitStatus places a place holder function
+func (*NullNotifier) NotifyCreateCommitStatus(creator *models.User, repo *models.Repository, sha string, status *models.CommitStatus) {
+}
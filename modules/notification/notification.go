@@ -185,6 +185,13 @@ func NotifyIssueChangeRef(doer *models.User, issue *models.Issue, oldRef string)
 	}
 }
 
+// NotifyIssueTransfer notifies the transfer of an issue to another repository to notifiers
+func NotifyIssueTransfer(doer *models.User, issue *models.Issue, oldRepo *models.Repository) {
+	for _, notifier := range notifiers {
+		notifier.NotifyIssueTransfer(doer, issue, oldRepo)
+	}
+}
+
 // NotifyIssueChangeLabels notifies change labels to notifiers
 func NotifyIssueChangeLabels(doer *models.User, issue *models.Issue,
 	addedLabels []*models.Label, removedLabels []*models.Label) {
@@ -276,3 +283,10 @@ func NotifySyncDeleteRef(pusher *models.User, repo *models.Repository, refType,
 		notifier.NotifySyncDeleteRef(pusher, repo, refType, refFullName)
 	}
 }
+
+// NotifyCreateCommitStatus notifies a new commit status to notifiers
+func NotifyCreateCommitStatus(creator *models.User, repo *models.Repository, sha string, status *models.CommitStatus) {
+	for _, notifier := range notifiers {
+		notifier.NotifyCreateCommitStatus(creator, repo, sha, status)
+	}
+}
@@ -23,6 +23,8 @@ type (
 		CommentID            int64
 		NotificationAuthorID int64
 		ReceiverID           int64 // 0 -- ALL Watcher
+		// notify the users subscribed to the issue filters matching the new issue instead of the watchers
+		IssueFilterSubscribers bool
 	}
 )
 
@@ -40,12 +42,34 @@ func NewNotifier() base.Notifier {
 func (ns *notificationService) handle(data ...queue.Data) {
 	for _, datum := range data {
 		opts := datum.(issueNotificationOpts)
+		if opts.IssueFilterSubscribers {
+			ns.notifyIssueFilterSubscribers(opts)
+			continue
+		}
 		if err := models.CreateOrUpdateIssueNotifications(opts.IssueID, opts.CommentID, opts.NotificationAuthorID, opts.ReceiverID); err != nil {
 			log.Error("Was unable to create issue notification: %v", err)
 		}
 	}
 }
 
+func (ns *notificationService) notifyIssueFilterSubscribers(opts issueNotificationOpts) {
+	issue, err := models.GetIssueByID(opts.IssueID)
+	if err != nil {
+		log.Error("Unable to load issue: %d: Error: %v", opts.IssueID, err)
+		return
+	}
+	subscriberIDs, err := models.GetIssueFilterSubscriberIDs(issue)
+	if err != nil {
+		log.Error("Unable to get the issue filter subscribers of issue: %d: Error: %v", opts.IssueID, err)
+		return
+	}
+	for _, subscriberID := range subscriberIDs {
+		if err := models.CreateOrUpdateIssueNotifications(opts.IssueID, 0, opts.NotificationAuthorID, subscriberID); err != nil {
+			log.Error("Was unable to create issue notification: %v", err)
+		}
+	}
+}
+
 func (ns *notificationService) Run() {
 	graceful.GetManager().RunWithShutdownFns(ns.issueQueue.Run)
 }
@@ -67,6 +91,11 @@ func (ns *notificationService) NotifyNewIssue(issue *models.Issue) {
 		IssueID:              issue.ID,
 		NotificationAuthorID: issue.Poster.ID,
 	})
+	_ = ns.issueQueue.Push(issueNotificationOpts{
+		IssueID:                issue.ID,
+		NotificationAuthorID:   issue.Poster.ID,
+		IssueFilterSubscribers: true,
+	})
 }
 
 func (ns *notificationService) NotifyIssueChangeStatus(doer *models.User, issue *models.Issue, actionComment *models.Comment, isClosed bool) {
@@ -92,6 +121,11 @@ func (ns *notificationService) NotifyNewPullRequest(pr *models.PullRequest) {
 		IssueID:              pr.Issue.ID,
 		NotificationAuthorID: pr.Issue.PosterID,
 	})
+	_ = ns.issueQueue.Push(issueNotificationOpts{
+		IssueID:                pr.Issue.ID,
+		NotificationAuthorID:   pr.Issue.PosterID,
+		IssueFilterSubscribers: true,
+	})
 }
 
 func (ns *notificationService) NotifyPullRequestReview(pr *models.PullRequest, r *models.Review, c *models.Comment) {
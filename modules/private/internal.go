@@ -10,14 +10,26 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"os"
 
 	"code.gitea.io/gitea/modules/httplib"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/tracing"
 )
 
 func newRequest(url, method string) *httplib.Request {
-	return httplib.NewRequest(url, method).Header("Authorization",
+	req := httplib.NewRequest(url, method).Header("Authorization",
 		fmt.Sprintf("Bearer %s", setting.InternalToken))
+	// Hooks and serv are passed the ID of the request that caused them in their environment
+	if requestID := os.Getenv(log.RequestIDEnv); requestID != "" {
+		req.Header(log.RequestIDHeader, requestID)
+	}
+	// and the trace context of the git command which ran them
+	if traceParent := os.Getenv(tracing.TraceParentEnv); traceParent != "" {
+		req.Header(tracing.TraceParentHeader, traceParent)
+	}
+	return req
 }
 
 // Response internal request response
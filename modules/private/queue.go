@@ -0,0 +1,123 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+func queueURL(queue, action string) string {
+	return setting.LocalURL + fmt.Sprintf("api/internal/manager/queues/%s/%s", url.PathEscape(queue), action)
+}
+
+// doQueueRequest sends a request about a queue and returns the status code and the plain text message or the error
+func doQueueRequest(reqURL, method string) (int, string) {
+	resp, err := newInternalRequest(reqURL, method).Response()
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Sprintf("Unable to contact gitea: %v", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, decodeJSONError(resp).Err
+	}
+	msg, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return http.StatusInternalServerError, err.Error()
+	}
+	return http.StatusOK, string(msg)
+}
+
+// ListQueues returns the queues of the running process
+func ListQueues() ([]*api.Queue, int, string) {
+	reqURL := setting.LocalURL + "api/internal/manager/queues"
+
+	resp, err := newInternalRequest(reqURL, "GET").Response()
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Sprintf("Unable to contact gitea: %v", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, decodeJSONError(resp).Err
+	}
+	var queues []*api.Queue
+	if err := json.NewDecoder(resp.Body).Decode(&queues); err != nil {
+		return nil, http.StatusInternalServerError, err.Error()
+	}
+	return queues, http.StatusOK, ""
+}
+
+// PauseQueue pauses the workers of the queue with the name or QID
+func PauseQueue(queue string) (int, string) {
+	return doQueueRequest(queueURL(queue, "pause"), "POST")
+}
+
+// ResumeQueue resumes the workers of the queue with the name or QID
+func ResumeQueue(queue string) (int, string) {
+	return doQueueRequest(queueURL(queue, "resume"), "POST")
+}
+
+// FlushQueue flushes the queue with the name or QID
+func FlushQueue(queue string, timeout time.Duration, nonBlocking bool) (int, string) {
+	req := newInternalRequest(queueURL(queue, "flush"), "POST")
+	if timeout > 0 {
+		req.SetTimeout(timeout+10*time.Second, timeout+10*time.Second)
+	}
+	req = req.Header("Content-Type", "application/json")
+	jsonBytes, _ := json.Marshal(FlushOptions{
+		Timeout:     timeout,
+		NonBlocking: nonBlocking,
+	})
+	req.Body(jsonBytes)
+	resp, err := req.Response()
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Sprintf("Unable to contact gitea: %v", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, decodeJSONError(resp).Err
+	}
+	return http.StatusOK, "Flushed"
+}
+
+// QueueItems returns up to limit persisted items of the queue with the name or QID, skipping the first skip items
+func QueueItems(queue string, skip, limit int) ([]*api.QueueItem, int, string) {
+	reqURL := queueURL(queue, "items") + fmt.Sprintf("?skip=%d&limit=%d", skip, limit)
+
+	resp, err := newInternalRequest(reqURL, "GET").Response()
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Sprintf("Unable to contact gitea: %v", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, decodeJSONError(resp).Err
+	}
+	var items []*api.QueueItem
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, http.StatusInternalServerError, err.Error()
+	}
+	return items, http.StatusOK, ""
+}
+
+// RemoveQueueItem removes the persisted item at the index from the queue with the name or QID
+func RemoveQueueItem(queue string, index int) (int, string) {
+	return doQueueRequest(queueURL(queue, fmt.Sprintf("items/%d", index)), "DELETE")
+}
+
+// PurgeQueue removes all the waiting items of the queue with the name or QID
+func PurgeQueue(queue string) (int, string) {
+	return doQueueRequest(queueURL(queue, "items"), "DELETE")
+}
@@ -52,6 +52,8 @@ type ServCommandResults struct {
 	OwnerName   string
 	RepoName    string
 	RepoID      int64
+	// RepoPath is the path of the git repository, or of the wiki
+	RepoPath string
 }
 
 // ErrServCommand is an error returned from ServCommmand.
@@ -12,6 +12,12 @@ type ByteFIFO interface {
 	PushFunc(data []byte, fn func() error) error
 	// Pop pops data from the start of the fifo
 	Pop() ([]byte, error)
+	// Items returns up to limit items from the start of the fifo, skipping the first skip items
+	Items(skip, limit int) ([][]byte, error)
+	// Remove removes the first item equal to data, returns false if there is none
+	Remove(data []byte) (bool, error)
+	// Purge removes all the items and returns how many were removed
+	Purge() (int64, error)
 	// Close this fifo
 	Close() error
 }
@@ -38,6 +44,21 @@ func (*DummyByteFIFO) Pop() ([]byte, error) {
 	return []byte{}, nil
 }
 
+// Items returns nil
+func (*DummyByteFIFO) Items(skip, limit int) ([][]byte, error) {
+	return nil, nil
+}
+
+// Remove always returns false
+func (*DummyByteFIFO) Remove(data []byte) (bool, error) {
+	return false, nil
+}
+
+// Purge returns 0
+func (*DummyByteFIFO) Purge() (int64, error) {
+	return 0, nil
+}
+
 // Close returns nil
 func (*DummyByteFIFO) Close() error {
 	return nil
@@ -53,6 +53,32 @@ type Flushable interface {
 	IsEmpty() bool
 }
 
+// Pausable represents a pool or queue that can be paused and resumed
+type Pausable interface {
+	// IsPaused returns if the pool is paused
+	IsPaused() bool
+	// Pause stops the workers from taking new work
+	Pause()
+	// Resume lets the workers take new work again
+	Resume()
+}
+
+// Countable represents a pool or queue that can count its waiting items
+type Countable interface {
+	// NumberInQueue returns the number of items waiting in the queue
+	NumberInQueue() int64
+}
+
+// Inspectable represents a queue whose persisted items can be listed and removed
+type Inspectable interface {
+	// Items returns up to limit persisted items from the start of the queue, skipping the first skip items
+	Items(skip, limit int) ([][]byte, error)
+	// RemoveItem removes the first persisted item equal to data, returns false if there is none
+	RemoveItem(data []byte) (bool, error)
+	// Purge removes all the waiting items and returns how many were removed
+	Purge() (int64, error)
+}
+
 // ManagedPool is a simple interface to get certain details from a worker pool
 type ManagedPool interface {
 	// AddWorkers adds a number of worker as group to the pool with the provided timeout. A CancelFunc is provided to cancel the group
@@ -150,6 +176,18 @@ func (m *Manager) GetManagedQueue(qid int64) *ManagedQueue {
 	return m.Queues[qid]
 }
 
+// GetManagedQueueByName returns the managed queue with the name
+func (m *Manager) GetManagedQueueByName(name string) *ManagedQueue {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, mq := range m.Queues {
+		if mq.Name == name {
+			return mq
+		}
+	}
+	return nil
+}
+
 // FlushAll flushes all the flushable queues attached to this manager
 func (m *Manager) FlushAll(baseCtx context.Context, timeout time.Duration) error {
 	var ctx context.Context
@@ -297,6 +335,83 @@ func (q *ManagedQueue) IsEmpty() bool {
 	return true
 }
 
+// NumberInQueue returns the number of items waiting in the queue, -1 if they cannot be counted
+func (q *ManagedQueue) NumberInQueue() int64 {
+	if countable, ok := q.Managed.(Countable); ok {
+		return countable.NumberInQueue()
+	}
+	return -1
+}
+
+// IsPausable returns if the queue can be paused
+func (q *ManagedQueue) IsPausable() bool {
+	_, ok := q.Managed.(Pausable)
+	return ok
+}
+
+// IsPaused returns if the queue is paused
+func (q *ManagedQueue) IsPaused() bool {
+	if pausable, ok := q.Managed.(Pausable); ok {
+		return pausable.IsPaused()
+	}
+	return false
+}
+
+// Pause pauses the queue if it is pausable
+func (q *ManagedQueue) Pause() {
+	if pausable, ok := q.Managed.(Pausable); ok {
+		pausable.Pause()
+	}
+}
+
+// Resume resumes the queue if it is pausable
+func (q *ManagedQueue) Resume() {
+	if pausable, ok := q.Managed.(Pausable); ok {
+		pausable.Resume()
+	}
+}
+
+// IsInspectable returns if the persisted items of the queue can be listed and removed
+func (q *ManagedQueue) IsInspectable() bool {
+	_, ok := q.Managed.(Inspectable)
+	return ok
+}
+
+// Items returns up to limit persisted items of the queue, skipping the first skip items
+func (q *ManagedQueue) Items(skip, limit int) ([][]byte, error) {
+	if inspectable, ok := q.Managed.(Inspectable); ok {
+		return inspectable.Items(skip, limit)
+	}
+	return nil, ErrNotInspectable{Name: q.Name}
+}
+
+// RemoveItem removes the persisted item at the index from the queue and returns it,
+// nil is returned if there is no item at the index
+func (q *ManagedQueue) RemoveItem(index int) ([]byte, error) {
+	inspectable, ok := q.Managed.(Inspectable)
+	if !ok {
+		return nil, ErrNotInspectable{Name: q.Name}
+	}
+	items, err := inspectable.Items(index, 1)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+	removed, err := inspectable.RemoveItem(items[0])
+	if err != nil || !removed {
+		// the item has been taken by the workers in the meantime
+		return nil, err
+	}
+	return items[0], nil
+}
+
+// Purge removes all the waiting items of the queue
+func (q *ManagedQueue) Purge() (int64, error) {
+	if inspectable, ok := q.Managed.(Inspectable); ok {
+		return inspectable.Purge()
+	}
+	return 0, ErrNotInspectable{Name: q.Name}
+}
+
 // NumberOfWorkers returns the number of workers in the queue
 func (q *ManagedQueue) NumberOfWorkers() int {
 	if pool, ok := q.Managed.(ManagedPool); ok {
@@ -29,6 +29,21 @@ func IsErrInvalidConfiguration(err error) bool {
 	return ok
 }
 
+// ErrNotInspectable is returned when the items of a queue which does not persist them are requested
+type ErrNotInspectable struct {
+	Name string
+}
+
+func (err ErrNotInspectable) Error() string {
+	return fmt.Sprintf("the items of queue %s cannot be inspected", err.Name)
+}
+
+// IsErrNotInspectable checks if an error is an ErrNotInspectable
+func IsErrNotInspectable(err error) bool {
+	_, ok := err.(ErrNotInspectable)
+	return ok
+}
+
 // Type is a type of Queue
 type Type string
 
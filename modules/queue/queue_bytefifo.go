@@ -78,6 +78,27 @@ func (q *ByteFIFOQueue) PushFunc(data Data, fn func() error) error {
 	return q.byteFIFO.PushFunc(bs, fn)
 }
 
+// NumberInQueue returns the number of items waiting in the fifo and in the pool
+func (q *ByteFIFOQueue) NumberInQueue() int64 {
+	return q.byteFIFO.Len() + q.WorkerPool.NumberInQueue()
+}
+
+// Items returns up to limit items waiting in the fifo, skipping the first skip items
+func (q *ByteFIFOQueue) Items(skip, limit int) ([][]byte, error) {
+	return q.byteFIFO.Items(skip, limit)
+}
+
+// RemoveItem removes the first item equal to data from the fifo
+func (q *ByteFIFOQueue) RemoveItem(data []byte) (bool, error) {
+	return q.byteFIFO.Remove(data)
+}
+
+// Purge removes the items waiting in the fifo and those not yet taken by the workers
+func (q *ByteFIFOQueue) Purge() (int64, error) {
+	purged, err := q.byteFIFO.Purge()
+	return purged + q.WorkerPool.discard(), err
+}
+
 // IsEmpty checks if the queue is empty
 func (q *ByteFIFOQueue) IsEmpty() bool {
 	q.lock.Lock()
@@ -114,11 +135,20 @@ func (q *ByteFIFOQueue) Run(atShutdown, atTerminate func(context.Context, func()
 
 func (q *ByteFIFOQueue) readToChan() {
 	for {
+		paused, resumed := q.IsPausedIsResumed()
 		select {
 		case <-q.closed:
 			// tell the pool to shutdown.
 			q.cancel()
 			return
+		case <-paused:
+			// leave the data in the fifo whilst paused
+			select {
+			case <-resumed:
+			case <-q.closed:
+				q.cancel()
+				return
+			}
 		default:
 			q.lock.Lock()
 			bs, err := q.byteFIFO.Pop()
@@ -96,3 +96,52 @@ func TestChannelQueue_Batch(t *testing.T) {
 	err = queue.Push(test1)
 	assert.Error(t, err)
 }
+
+func TestChannelQueue_Pause(t *testing.T) {
+	handleChan := make(chan *testData)
+	handle := func(data ...Data) {
+		for _, datum := range data {
+			handleChan <- datum.(*testData)
+		}
+	}
+
+	nilFn := func(_ context.Context, _ func()) {}
+
+	queue, err := NewChannelQueue(handle,
+		ChannelQueueConfiguration{
+			WorkerPoolConfiguration: WorkerPoolConfiguration{
+				QueueLength: 20,
+				BatchLength: 1,
+				MaxWorkers:  10,
+			},
+			Workers: 1,
+			Name:    "TestChannelQueue_Pause",
+		}, &testData{})
+	assert.NoError(t, err)
+
+	go queue.Run(nilFn, nilFn)
+
+	mq := GetManager().GetManagedQueueByName("TestChannelQueue_Pause")
+	defer GetManager().Remove(mq.QID)
+	assert.True(t, mq.IsPausable())
+	mq.Pause()
+	assert.True(t, mq.IsPaused())
+
+	test1 := testData{"A", 1}
+	assert.NoError(t, queue.Push(&test1))
+	select {
+	case <-handleChan:
+		assert.Fail(t, "the data has been handled whilst the queue is paused")
+	case <-time.After(500 * time.Millisecond):
+	}
+	assert.EqualValues(t, 1, mq.NumberInQueue())
+
+	mq.Resume()
+	assert.False(t, mq.IsPaused())
+	select {
+	case result1 := <-handleChan:
+		assert.Equal(t, test1.TestInt, result1.TestInt)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "the data has not been handled after the queue is resumed")
+	}
+}
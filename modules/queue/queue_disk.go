@@ -5,9 +5,15 @@
 package queue
 
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
 	"code.gitea.io/gitea/modules/nosql"
 
 	"gitea.com/lunny/levelqueue"
+	"github.com/syndtr/goleveldb/leveldb"
 )
 
 // LevelQueueType is the type for level queue
@@ -59,7 +65,10 @@ var _ (ByteFIFO) = &LevelQueueByteFIFO{}
 
 // LevelQueueByteFIFO represents a ByteFIFO formed from a LevelQueue
 type LevelQueueByteFIFO struct {
+	lock       sync.Mutex
 	internal   *levelqueue.Queue
+	db         *leveldb.DB
+	prefix     string
 	connection string
 }
 
@@ -78,6 +87,8 @@ func NewLevelQueueByteFIFO(connection, prefix string) (*LevelQueueByteFIFO, erro
 	return &LevelQueueByteFIFO{
 		connection: connection,
 		internal:   internal,
+		db:         db,
+		prefix:     prefix,
 	}, nil
 }
 
@@ -88,11 +99,15 @@ func (fifo *LevelQueueByteFIFO) PushFunc(data []byte, fn func() error) error {
 			return err
 		}
 	}
+	fifo.lock.Lock()
+	defer fifo.lock.Unlock()
 	return fifo.internal.LPush(data)
 }
 
 // Pop pops data from the start of the fifo
 func (fifo *LevelQueueByteFIFO) Pop() ([]byte, error) {
+	fifo.lock.Lock()
+	defer fifo.lock.Unlock()
 	data, err := fifo.internal.RPop()
 	if err != nil && err != levelqueue.ErrNotFound {
 		return nil, err
@@ -100,6 +115,27 @@ func (fifo *LevelQueueByteFIFO) Pop() ([]byte, error) {
 	return data, nil
 }
 
+// Items returns up to limit items from the start of the fifo, skipping the first skip items
+func (fifo *LevelQueueByteFIFO) Items(skip, limit int) ([][]byte, error) {
+	fifo.lock.Lock()
+	defer fifo.lock.Unlock()
+	return levelQueueItems(fifo.db, fifo.prefix, skip, limit)
+}
+
+// Remove removes the first item equal to data, returns false if there is none
+func (fifo *LevelQueueByteFIFO) Remove(data []byte) (bool, error) {
+	fifo.lock.Lock()
+	defer fifo.lock.Unlock()
+	return levelQueueRemove(fifo.internal.Len(), fifo.internal.RPop, fifo.internal.LPush, data)
+}
+
+// Purge removes all the items and returns how many were removed
+func (fifo *LevelQueueByteFIFO) Purge() (int64, error) {
+	fifo.lock.Lock()
+	defer fifo.lock.Unlock()
+	return levelQueuePurge(fifo.internal.RPop)
+}
+
 // Close this fifo
 func (fifo *LevelQueueByteFIFO) Close() error {
 	err := fifo.internal.Close()
@@ -112,6 +148,87 @@ func (fifo *LevelQueueByteFIFO) Len() int64 {
 	return fifo.internal.Len()
 }
 
+// levelQueueKey returns the key of a levelqueue entry, as the levelqueue has no way to peek
+// further than the first item this must be kept in line with its layout
+func levelQueueKey(prefix string, value []byte) []byte {
+	if len(prefix) == 0 {
+		return value
+	}
+	return append([]byte(prefix+"-"), value...)
+}
+
+func levelQueueID(id int64) []byte {
+	buf := make([]byte, 8)
+	binary.PutVarint(buf, id)
+	return buf
+}
+
+func readLevelQueueID(db *leveldb.DB, key []byte) (int64, error) {
+	bs, err := db.Get(key, nil)
+	if err != nil {
+		return 0, err
+	}
+	return binary.ReadVarint(bytes.NewReader(bs))
+}
+
+// levelQueueItems reads the items of the levelqueue with the prefix, the items are popped from the
+// high end of the queue. The queue must not be modified whilst they are read.
+func levelQueueItems(db *leveldb.DB, prefix string, skip, limit int) ([][]byte, error) {
+	low, err := readLevelQueueID(db, levelQueueKey(prefix, []byte("low")))
+	if err != nil {
+		return nil, err
+	}
+	high, err := readLevelQueueID(db, levelQueueKey(prefix, []byte("high")))
+	if err != nil {
+		return nil, err
+	}
+
+	var items [][]byte
+	for id := high - int64(skip); id >= low && (limit <= 0 || len(items) < limit); id-- {
+		data, err := db.Get(levelQueueKey(prefix, levelQueueID(id)), nil)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, data)
+	}
+	return items, nil
+}
+
+// levelQueueRemove removes the first item equal to data by rotating the whole queue once
+// so the order of the other items is kept
+func levelQueueRemove(length int64, pop func() ([]byte, error), push func([]byte) error, data []byte) (bool, error) {
+	removed := false
+	for i := int64(0); i < length; i++ {
+		item, err := pop()
+		if err == levelqueue.ErrNotFound {
+			break
+		} else if err != nil {
+			return removed, err
+		}
+		if !removed && bytes.Equal(item, data) {
+			removed = true
+			continue
+		}
+		if err := push(item); err != nil {
+			return removed, fmt.Errorf("unable to push back %q: %v", item, err)
+		}
+	}
+	return removed, nil
+}
+
+func levelQueuePurge(pop func() ([]byte, error)) (int64, error) {
+	var purged int64
+	for {
+		_, err := pop()
+		if err == levelqueue.ErrNotFound {
+			return purged, nil
+		} else if err != nil {
+			return purged, err
+		}
+		purged++
+	}
+}
+
 func init() {
 	queuesMap[LevelQueueType] = NewLevelQueue
 }
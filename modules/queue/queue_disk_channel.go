@@ -215,6 +215,26 @@ func (q *PersistableChannelQueue) FlushWithContext(ctx context.Context) error {
 	return err2
 }
 
+// NumberInQueue returns the number of items waiting in the channel, the level backend is managed as its own queue
+func (q *PersistableChannelQueue) NumberInQueue() int64 {
+	return q.channelQueue.NumberInQueue()
+}
+
+// IsPaused returns if the channel queue is paused
+func (q *PersistableChannelQueue) IsPaused() bool {
+	return q.channelQueue.IsPaused()
+}
+
+// Pause pauses the channel queue
+func (q *PersistableChannelQueue) Pause() {
+	q.channelQueue.Pause()
+}
+
+// Resume resumes the channel queue
+func (q *PersistableChannelQueue) Resume() {
+	q.channelQueue.Resume()
+}
+
 // IsEmpty checks if a queue is empty
 func (q *PersistableChannelQueue) IsEmpty() bool {
 	if !q.channelQueue.IsEmpty() {
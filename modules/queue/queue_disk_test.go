@@ -150,3 +150,72 @@ func TestLevelQueue(t *testing.T) {
 	}
 	lock.Unlock()
 }
+
+func TestLevelQueueItems(t *testing.T) {
+	for _, typ := range []Type{LevelQueueType, LevelUniqueQueueType} {
+		t.Run(string(typ), func(t *testing.T) {
+			tmpDir, err := ioutil.TempDir("", "level-queue-items-test-data")
+			assert.NoError(t, err)
+			defer util.RemoveAll(tmpDir)
+
+			// the queue is not run so the items stay in the fifo
+			q, err := NewQueue(typ, func(data ...Data) {}, LevelQueueConfiguration{
+				ByteFIFOQueueConfiguration: ByteFIFOQueueConfiguration{
+					WorkerPoolConfiguration: WorkerPoolConfiguration{
+						QueueLength: 20,
+						BatchLength: 2,
+						MaxWorkers:  10,
+					},
+					Workers: 1,
+					Name:    "TestLevelQueueItems-" + string(typ),
+				},
+				DataDir: tmpDir,
+			}, &testData{})
+			assert.NoError(t, err)
+			defer q.(Shutdownable).Terminate()
+
+			for i := 1; i <= 4; i++ {
+				assert.NoError(t, q.Push(&testData{"A", i}))
+			}
+			mq := GetManager().GetManagedQueueByName("TestLevelQueueItems-" + string(typ))
+			if !assert.NotNil(t, mq) {
+				return
+			}
+			defer GetManager().Remove(mq.QID)
+			assert.True(t, mq.IsInspectable())
+			assert.EqualValues(t, 4, mq.NumberInQueue())
+
+			items, err := mq.Items(1, 2)
+			assert.NoError(t, err)
+			assert.Equal(t, [][]byte{
+				[]byte(`{"TestString":"A","TestInt":2}`),
+				[]byte(`{"TestString":"A","TestInt":3}`),
+			}, items)
+
+			removed, err := mq.RemoveItem(1)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte(`{"TestString":"A","TestInt":2}`), removed)
+			removed, err = mq.RemoveItem(5)
+			assert.NoError(t, err)
+			assert.Nil(t, removed)
+
+			// the order of the remaining items is kept
+			items, err = mq.Items(0, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, [][]byte{
+				[]byte(`{"TestString":"A","TestInt":1}`),
+				[]byte(`{"TestString":"A","TestInt":3}`),
+				[]byte(`{"TestString":"A","TestInt":4}`),
+			}, items)
+
+			purged, err := mq.Purge()
+			assert.NoError(t, err)
+			assert.EqualValues(t, 3, purged)
+			assert.EqualValues(t, 0, mq.NumberInQueue())
+			assert.True(t, mq.IsEmpty())
+
+			// the removed items can be pushed again to the unique queue
+			assert.NoError(t, q.Push(&testData{"A", 2}))
+		})
+	}
+}
@@ -56,6 +56,9 @@ type redisClient interface {
 	RPush(key string, args ...interface{}) *redis.IntCmd
 	LPop(key string) *redis.StringCmd
 	LLen(key string) *redis.IntCmd
+	LRange(key string, start, stop int64) *redis.StringSliceCmd
+	LRem(key string, count int64, value interface{}) *redis.IntCmd
+	Del(keys ...string) *redis.IntCmd
 	SAdd(key string, members ...interface{}) *redis.IntCmd
 	SRem(key string, members ...interface{}) *redis.IntCmd
 	SIsMember(key string, member interface{}) *redis.BoolCmd
@@ -108,6 +111,38 @@ func (fifo *RedisByteFIFO) Pop() ([]byte, error) {
 	return data, err
 }
 
+// Items returns up to limit items from the start of the fifo, skipping the first skip items
+func (fifo *RedisByteFIFO) Items(skip, limit int) ([][]byte, error) {
+	stop := int64(-1)
+	if limit > 0 {
+		stop = int64(skip + limit - 1)
+	}
+	values, err := fifo.client.LRange(fifo.queueName, int64(skip), stop).Result()
+	if err != nil {
+		return nil, err
+	}
+	items := make([][]byte, len(values))
+	for i, value := range values {
+		items[i] = []byte(value)
+	}
+	return items, nil
+}
+
+// Remove removes the first item equal to data, returns false if there is none
+func (fifo *RedisByteFIFO) Remove(data []byte) (bool, error) {
+	removed, err := fifo.client.LRem(fifo.queueName, 1, data).Result()
+	return removed > 0, err
+}
+
+// Purge removes all the items and returns how many were removed
+func (fifo *RedisByteFIFO) Purge() (int64, error) {
+	length, err := fifo.client.LLen(fifo.queueName).Result()
+	if err != nil {
+		return 0, err
+	}
+	return length, fifo.client.Del(fifo.queueName).Err()
+}
+
 // Close this fifo
 func (fifo *RedisByteFIFO) Close() error {
 	return fifo.client.Close()
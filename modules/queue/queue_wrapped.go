@@ -207,6 +207,11 @@ func (q *WrappedQueue) FlushWithContext(ctx context.Context) error {
 	}
 }
 
+// NumberInQueue returns the number of items waiting for the internal queue to be created
+func (q *WrappedQueue) NumberInQueue() int64 {
+	return atomic.LoadInt64(&q.numInQueue)
+}
+
 // IsEmpty checks whether the queue is empty
 func (q *WrappedQueue) IsEmpty() bool {
 	if atomic.LoadInt64(&q.numInQueue) != 0 {
@@ -5,12 +5,14 @@
 package queue
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/tracing"
 )
 
 func validType(t string) (Type, error) {
@@ -37,12 +39,25 @@ func getQueueSettings(name string) (setting.QueueSettings, []byte) {
 	return q, cfg
 }
 
+// tracedHandler wraps the handler of the named queue to create a span for each batch handled
+func tracedHandler(name string, handle HandlerFunc) HandlerFunc {
+	return func(data ...Data) {
+		_, span := tracing.Start(context.Background(), "queue "+name, tracing.SpanKindConsumer)
+		span.SetAttribute("queue.name", name)
+		span.SetAttribute("queue.batch_length", len(data))
+		defer span.End()
+
+		handle(data...)
+	}
+}
+
 // CreateQueue for name with provided handler and exemplar
 func CreateQueue(name string, handle HandlerFunc, exemplar interface{}) Queue {
 	q, cfg := getQueueSettings(name)
 	if len(cfg) == 0 {
 		return nil
 	}
+	handle = tracedHandler(name, handle)
 
 	typ, err := validType(q.Type)
 	if err != nil {
@@ -76,6 +91,8 @@ func CreateUniqueQueue(name string, handle HandlerFunc, exemplar interface{}) Un
 		return nil
 	}
 
+	handle = tracedHandler(name, handle)
+
 	if len(q.Type) > 0 && q.Type != "dummy" && !strings.HasPrefix(q.Type, "unique-") {
 		q.Type = "unique-" + q.Type
 	}
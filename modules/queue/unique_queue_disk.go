@@ -5,9 +5,12 @@
 package queue
 
 import (
+	"sync"
+
 	"code.gitea.io/gitea/modules/nosql"
 
 	"gitea.com/lunny/levelqueue"
+	"github.com/syndtr/goleveldb/leveldb"
 )
 
 // LevelUniqueQueueType is the type for level queue
@@ -63,7 +66,10 @@ var _ (UniqueByteFIFO) = &LevelUniqueQueueByteFIFO{}
 
 // LevelUniqueQueueByteFIFO represents a ByteFIFO formed from a LevelUniqueQueue
 type LevelUniqueQueueByteFIFO struct {
+	lock       sync.Mutex
 	internal   *levelqueue.UniqueQueue
+	db         *leveldb.DB
+	prefix     string
 	connection string
 }
 
@@ -82,16 +88,22 @@ func NewLevelUniqueQueueByteFIFO(connection, prefix string) (*LevelUniqueQueueBy
 	return &LevelUniqueQueueByteFIFO{
 		connection: connection,
 		internal:   internal,
+		db:         db,
+		prefix:     prefix,
 	}, nil
 }
 
 // PushFunc pushes data to the end of the fifo and calls the callback if it is added
 func (fifo *LevelUniqueQueueByteFIFO) PushFunc(data []byte, fn func() error) error {
+	fifo.lock.Lock()
+	defer fifo.lock.Unlock()
 	return fifo.internal.LPushFunc(data, fn)
 }
 
 // Pop pops data from the start of the fifo
 func (fifo *LevelUniqueQueueByteFIFO) Pop() ([]byte, error) {
+	fifo.lock.Lock()
+	defer fifo.lock.Unlock()
 	data, err := fifo.internal.RPop()
 	if err != nil && err != levelqueue.ErrNotFound {
 		return nil, err
@@ -104,6 +116,27 @@ func (fifo *LevelUniqueQueueByteFIFO) Len() int64 {
 	return fifo.internal.Len()
 }
 
+// Items returns up to limit items from the start of the fifo, skipping the first skip items
+func (fifo *LevelUniqueQueueByteFIFO) Items(skip, limit int) ([][]byte, error) {
+	fifo.lock.Lock()
+	defer fifo.lock.Unlock()
+	return levelQueueItems(fifo.db, fifo.prefix, skip, limit)
+}
+
+// Remove removes the item equal to data, returns false if there is none
+func (fifo *LevelUniqueQueueByteFIFO) Remove(data []byte) (bool, error) {
+	fifo.lock.Lock()
+	defer fifo.lock.Unlock()
+	return levelQueueRemove(fifo.internal.Len(), fifo.internal.RPop, fifo.internal.LPush, data)
+}
+
+// Purge removes all the items and returns how many were removed
+func (fifo *LevelUniqueQueueByteFIFO) Purge() (int64, error) {
+	fifo.lock.Lock()
+	defer fifo.lock.Unlock()
+	return levelQueuePurge(fifo.internal.RPop)
+}
+
 // Has returns whether the fifo contains this data
 func (fifo *LevelUniqueQueueByteFIFO) Has(data []byte) (bool, error) {
 	return fifo.internal.Has(data)
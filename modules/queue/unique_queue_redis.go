@@ -116,6 +116,28 @@ func (fifo *RedisUniqueByteFIFO) Pop() ([]byte, error) {
 	return data, err
 }
 
+// Remove removes the first item equal to data, returns false if there is none
+func (fifo *RedisUniqueByteFIFO) Remove(data []byte) (bool, error) {
+	removed, err := fifo.RedisByteFIFO.Remove(data)
+	if err != nil || !removed {
+		return removed, err
+	}
+	return true, fifo.client.SRem(fifo.setName, data).Err()
+}
+
+// Purge removes all the items and returns how many were removed
+func (fifo *RedisUniqueByteFIFO) Purge() (int64, error) {
+	length, err := fifo.client.LLen(fifo.queueName).Result()
+	if err != nil {
+		return 0, err
+	}
+	// the keys are deleted one by one as they may be in different slots of a cluster
+	if err := fifo.client.Del(fifo.queueName).Err(); err != nil {
+		return 0, err
+	}
+	return length, fifo.client.Del(fifo.setName).Err()
+}
+
 // Has returns whether the fifo contains this data
 func (fifo *RedisUniqueByteFIFO) Has(data []byte) (bool, error) {
 	return fifo.client.SIsMember(fifo.setName, data).Result()
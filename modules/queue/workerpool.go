@@ -33,6 +33,8 @@ type WorkerPool struct {
 	boostTimeout       time.Duration
 	boostWorkers       int
 	numInQueue         int64
+	paused             chan struct{}
+	resumed            chan struct{}
 }
 
 // WorkerPoolConfiguration is the basic configuration for a WorkerPool
@@ -50,6 +52,8 @@ func NewWorkerPool(handle HandlerFunc, config WorkerPoolConfiguration) *WorkerPo
 	ctx, cancel := context.WithCancel(context.Background())
 
 	dataChan := make(chan Data, config.QueueLength)
+	resumed := make(chan struct{})
+	close(resumed)
 	pool := &WorkerPool{
 		baseCtx:            ctx,
 		cancel:             cancel,
@@ -60,6 +64,8 @@ func NewWorkerPool(handle HandlerFunc, config WorkerPoolConfiguration) *WorkerPo
 		boostTimeout:       config.BoostTimeout,
 		boostWorkers:       config.BoostWorkers,
 		maxNumberOfWorkers: config.MaxWorkers,
+		paused:             make(chan struct{}),
+		resumed:            resumed,
 	}
 
 	return pool
@@ -96,7 +102,7 @@ func (p *WorkerPool) pushBoost(data Data) {
 			util.StopTimer(timer)
 		case <-timer.C:
 			p.lock.Lock()
-			if p.blockTimeout > ourTimeout || (p.numberOfWorkers > p.maxNumberOfWorkers && p.maxNumberOfWorkers >= 0) {
+			if p.blockTimeout > ourTimeout || (p.numberOfWorkers > p.maxNumberOfWorkers && p.maxNumberOfWorkers >= 0) || p.isPaused() {
 				p.lock.Unlock()
 				p.dataChan <- data
 				return
@@ -170,6 +176,59 @@ func (p *WorkerPool) BlockTimeout() time.Duration {
 	return p.blockTimeout
 }
 
+// NumberInQueue returns the number of items waiting in the pool
+func (p *WorkerPool) NumberInQueue() int64 {
+	return atomic.LoadInt64(&p.numInQueue)
+}
+
+// IsPaused returns if the pool is paused
+func (p *WorkerPool) IsPaused() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.isPaused()
+}
+
+// isPaused must be called with the lock locked.
+func (p *WorkerPool) isPaused() bool {
+	select {
+	case <-p.paused:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsPausedIsResumed returns the channels closed when the pool is paused and when it is resumed
+func (p *WorkerPool) IsPausedIsResumed() (<-chan struct{}, <-chan struct{}) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.paused, p.resumed
+}
+
+// Pause stops the workers from taking new work, the work already taken is finished
+func (p *WorkerPool) Pause() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.isPaused() {
+		return
+	}
+	p.resumed = make(chan struct{})
+	close(p.paused)
+	log.Trace("WorkerPool: %d Paused", p.qid)
+}
+
+// Resume lets the workers take new work again
+func (p *WorkerPool) Resume() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if !p.isPaused() {
+		return
+	}
+	p.paused = make(chan struct{})
+	close(p.resumed)
+	log.Trace("WorkerPool: %d Resumed", p.qid)
+}
+
 // SetPoolSettings sets the setable boost values
 func (p *WorkerPool) SetPoolSettings(maxNumberOfWorkers, boostWorkers int, timeout time.Duration) {
 	p.lock.Lock()
@@ -283,6 +342,23 @@ func (p *WorkerPool) CleanUp(ctx context.Context) {
 	log.Trace("WorkerPool: %d CleanUp Done", p.qid)
 }
 
+// discard drops the data which has not been taken by the workers yet and returns how much was dropped
+func (p *WorkerPool) discard() int64 {
+	var discarded int64
+	for {
+		select {
+		case _, ok := <-p.dataChan:
+			if !ok {
+				return discarded
+			}
+			atomic.AddInt64(&p.numInQueue, -1)
+			discarded++
+		default:
+			return discarded
+		}
+	}
+}
+
 // Flush flushes the channel with a timeout - the Flush worker will be registered as a flush worker with the manager
 func (p *WorkerPool) Flush(timeout time.Duration) error {
 	ctx, cancel := p.commonRegisterWorkers(1, timeout, true)
@@ -318,6 +394,26 @@ func (p *WorkerPool) doWork(ctx context.Context) {
 	delay := time.Millisecond * 300
 	var data = make([]Data, 0, p.batchLength)
 	for {
+		paused, resumed := p.IsPausedIsResumed()
+		select {
+		case <-paused:
+			log.Trace("Worker for Queue %d Pausing", p.qid)
+			select {
+			case <-resumed:
+				log.Trace("Worker for Queue %d Resuming", p.qid)
+				continue
+			case <-ctx.Done():
+				if len(data) > 0 {
+					log.Trace("Handling: %d data, %v", len(data), data)
+					p.handle(data...)
+					atomic.AddInt64(&p.numInQueue, -1*int64(len(data)))
+				}
+				log.Trace("Worker shutting down")
+				return
+			}
+		default:
+		}
+
 		select {
 		case <-ctx.Done():
 			if len(data) > 0 {
@@ -348,6 +444,8 @@ func (p *WorkerPool) doWork(ctx context.Context) {
 		default:
 			timer := time.NewTimer(delay)
 			select {
+			case <-paused:
+				util.StopTimer(timer)
 			case <-ctx.Done():
 				util.StopTimer(timer)
 				if len(data) > 0 {
@@ -0,0 +1,203 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+)
+
+// ApplySuggestions commits the suggestions of the given code comments to the head branch of the pull request.
+// All suggestions are applied in a single commit made by doer, the posters of the comments are credited as co-authors.
+func ApplySuggestions(pr *models.PullRequest, doer *models.User, comments []*models.Comment, message string) (string, error) {
+	if len(comments) == 0 {
+		return "", nil
+	}
+	if err := pr.LoadHeadRepo(); err != nil {
+		return "", err
+	}
+	if pr.HeadRepo == nil {
+		return "", models.ErrRepoNotExist{ID: pr.HeadRepoID}
+	}
+
+	// Group the suggestions by file, a line can only be changed by one suggestion
+	suggestions := make(map[string]map[int64]*models.Comment)
+	for _, comment := range comments {
+		if comment.IssueID != pr.IssueID || !comment.HasSuggestion() {
+			return "", models.ErrInvalidSuggestion{CommentID: comment.ID, Reason: "not a suggestion"}
+		}
+		if comment.Invalidated || comment.Line <= 0 || (comment.IsMultiLine() && comment.StartLine <= 0) {
+			return "", models.ErrInvalidSuggestion{CommentID: comment.ID, Reason: "not on current lines"}
+		}
+		lines, ok := suggestions[comment.TreePath]
+		if !ok {
+			lines = make(map[int64]*models.Comment)
+			suggestions[comment.TreePath] = lines
+		}
+		if _, ok := lines[comment.Line]; ok {
+			return "", models.ErrInvalidSuggestion{CommentID: comment.ID, Reason: "conflicts with another suggestion"}
+		}
+		lines[comment.Line] = comment
+	}
+
+	t, err := NewTemporaryUploadRepository(pr.HeadRepo)
+	if err != nil {
+		return "", err
+	}
+	defer t.Close()
+	if err := t.Clone(pr.HeadBranch); err != nil {
+		return "", err
+	}
+	if err := t.SetDefaultIndex(); err != nil {
+		return "", err
+	}
+
+	commit, err := t.GetBranchCommit(pr.HeadBranch)
+	if err != nil {
+		return "", err
+	}
+
+	for treePath, lines := range suggestions {
+		entry, err := commit.GetTreeEntryByPath(treePath)
+		if err != nil {
+			return "", err
+		}
+		if !entry.IsRegular() && !entry.IsExecutable() {
+			return "", models.ErrFilePathInvalid{Message: "suggestions can only be applied to files", Path: treePath}
+		}
+
+		for _, comment := range lines {
+			if changed, err := commit.FileChangedSinceCommit(treePath, comment.CommitSHA); err != nil || changed {
+				return "", models.ErrSuggestionOutdated{CommentID: comment.ID, TreePath: treePath}
+			}
+		}
+
+		rd, err := entry.Blob().DataAsync()
+		if err != nil {
+			return "", err
+		}
+		content, err := ioutil.ReadAll(rd)
+		rd.Close()
+		if err != nil {
+			return "", err
+		}
+
+		newContent, err := applySuggestionsToContent(string(content), lines)
+		if err != nil {
+			return "", err
+		}
+
+		objectHash, err := t.HashObject(strings.NewReader(newContent))
+		if err != nil {
+			return "", err
+		}
+		mode := "100644"
+		if entry.IsExecutable() {
+			mode = "100755"
+		}
+		if err := t.AddObjectToIndex(mode, objectHash, treePath); err != nil {
+			return "", err
+		}
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return "", err
+	}
+
+	commitHash, err := t.CommitTree(doer, doer, treeHash, suggestionCommitMessage(doer, comments, message))
+	if err != nil {
+		return "", err
+	}
+
+	if err := t.Push(doer, commitHash, pr.HeadBranch); err != nil {
+		return "", err
+	}
+	return commitHash, nil
+}
+
+// applySuggestionsToContent replaces the lines of content which have a suggestion,
+// multi-line suggestions replace all the lines of their range
+func applySuggestionsToContent(content string, suggestions map[int64]*models.Comment) (string, error) {
+	eol := "\n"
+	if strings.Contains(content, "\r\n") {
+		eol = "\r\n"
+	}
+	lines := strings.SplitAfter(content, "\n")
+	numLines := int64(len(lines))
+	if lines[numLines-1] == "" {
+		numLines--
+	}
+
+	lineNumbers := make([]int64, 0, len(suggestions))
+	for line := range suggestions {
+		lineNumbers = append(lineNumbers, line)
+	}
+	// Replace from the bottom so the numbers of the lines above stay valid
+	sort.Slice(lineNumbers, func(i, j int) bool { return lineNumbers[i] > lineNumbers[j] })
+
+	previousStart := numLines + 1
+	for _, line := range lineNumbers {
+		comment := suggestions[line]
+		start := line
+		if comment.IsMultiLine() {
+			start = comment.StartLine
+		}
+		if line > numLines {
+			return "", models.ErrInvalidSuggestion{CommentID: comment.ID, Reason: "line does not exist"}
+		}
+		if line >= previousStart {
+			return "", models.ErrInvalidSuggestion{CommentID: comment.ID, Reason: "conflicts with another suggestion"}
+		}
+		previousStart = start
+
+		suggestion, _ := comment.Suggestion()
+		suggestion = strings.ReplaceAll(suggestion, "\r\n", "\n")
+
+		replacement := ""
+		if len(suggestion) > 0 {
+			replacement = strings.ReplaceAll(suggestion, "\n", eol)
+			// keep the end of line of the last replaced line, which is missing on the last line of some files
+			replacement = strings.TrimSuffix(replacement, eol)
+			if strings.HasSuffix(lines[line-1], "\n") {
+				replacement += eol
+			}
+		}
+
+		lines = append(lines[:start-1], append([]string{replacement}, lines[line:]...)...)
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// suggestionCommitMessage returns the message of the commit applying the suggestions,
+// crediting the posters of the comments other than doer as co-authors
+func suggestionCommitMessage(doer *models.User, comments []*models.Comment, message string) string {
+	message = strings.TrimSpace(message)
+	if len(message) == 0 {
+		if len(comments) == 1 {
+			message = "Apply suggestion from code review"
+		} else {
+			message = "Apply suggestions from code review"
+		}
+	}
+
+	var coAuthors []string
+	seen := map[int64]bool{doer.ID: true}
+	for _, comment := range comments {
+		if err := comment.LoadPoster(); err != nil || comment.Poster == nil || seen[comment.PosterID] {
+			continue
+		}
+		seen[comment.PosterID] = true
+		coAuthors = append(coAuthors, fmt.Sprintf("Co-authored-by: %s <%s>", comment.Poster.GetDisplayName(), comment.Poster.GetEmail()))
+	}
+	if len(coAuthors) > 0 {
+		message += "\n\n" + strings.Join(coAuthors, "\n")
+	}
+	return message + "\n"
+}
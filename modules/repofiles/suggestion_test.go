@@ -0,0 +1,75 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func suggestionComment(id, line int64, suggestion string) *models.Comment {
+	return &models.Comment{
+		ID:      id,
+		Type:    models.CommentTypeCode,
+		Line:    line,
+		Content: "```suggestion\n" + suggestion + "```\n",
+	}
+}
+
+func TestApplySuggestionsToContent(t *testing.T) {
+	content, err := applySuggestionsToContent("a\nb\nc\n", map[int64]*models.Comment{
+		1: suggestionComment(1, 1, "A\n"),
+		2: suggestionComment(2, 2, ""),
+		3: suggestionComment(3, 3, "C1\nC2\n"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "A\nC1\nC2\n", content)
+
+	// line endings of the file are kept
+	content, err = applySuggestionsToContent("a\r\nb", map[int64]*models.Comment{
+		1: suggestionComment(1, 1, "A1\nA2\n"),
+		2: suggestionComment(2, 2, "B\n"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "A1\r\nA2\r\nB", content)
+
+	_, err = applySuggestionsToContent("a\nb\n", map[int64]*models.Comment{
+		3: suggestionComment(1, 3, "c\n"),
+	})
+	assert.True(t, models.IsErrInvalidSuggestion(err))
+
+	// multi-line suggestions replace their whole range
+	multiLine := suggestionComment(1, 3, "B\n")
+	multiLine.StartLine = 2
+	content, err = applySuggestionsToContent("a\nb\nc\nd\n", map[int64]*models.Comment{
+		3: multiLine,
+		4: suggestionComment(2, 4, "D\n"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "a\nB\nD\n", content)
+
+	_, err = applySuggestionsToContent("a\nb\nc\nd\n", map[int64]*models.Comment{
+		3: multiLine,
+		2: suggestionComment(2, 2, "B\n"),
+	})
+	assert.True(t, models.IsErrInvalidSuggestion(err))
+}
+
+func TestSuggestionCommitMessage(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	comments := []*models.Comment{
+		{PosterID: 2},
+		{PosterID: 1},
+		{PosterID: 1},
+	}
+	assert.Equal(t, "Apply suggestions from code review\n\nCo-authored-by: user1 <user1@example.com>\n",
+		suggestionCommitMessage(doer, comments, ""))
+	assert.Equal(t, "Fix typo\n", suggestionCommitMessage(doer, comments[:1], " Fix typo "))
+}
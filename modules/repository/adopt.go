@@ -48,7 +48,7 @@ func AdoptRepository(doer, u *models.User, opts models.CreateRepoOptions) (*mode
 	}
 
 	if err := models.WithTx(func(ctx models.DBContext) error {
-		repoPath := models.RepoPath(u.Name, repo.Name)
+		repoPath := repo.RepoPath()
 		if !com.IsExist(repoPath) {
 			return models.ErrRepoNotExist{
 				OwnerName: u.Name,
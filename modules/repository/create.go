@@ -57,7 +57,7 @@ func CreateRepository(doer, u *models.User, opts models.CreateRepoOptions) (*mod
 			return nil
 		}
 
-		repoPath := models.RepoPath(u.Name, repo.Name)
+		repoPath := repo.RepoPath()
 		if com.IsExist(repoPath) {
 			// repo already exists - We have two or three options.
 			// 1. We fail stating that the directory exists
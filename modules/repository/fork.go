@@ -64,7 +64,7 @@ func ForkRepository(doer, owner *models.User, oldRepo *models.Repository, name,
 			return err
 		}
 
-		repoPath := models.RepoPath(owner.Name, repo.Name)
+		repoPath := repo.RepoPath()
 		if stdout, err := git.NewCommand(
 			"clone", "--bare", oldRepoPath, repoPath).
 			SetDescription(fmt.Sprintf("ForkRepository(git clone): %s to %s", oldRepo.FullName(), repo.FullName())).
@@ -259,7 +259,7 @@ func GenerateRepository(ctx models.DBContext, doer, owner *models.User, template
 		}
 	}
 
-	if err = checkInitRepository(owner.Name, generateRepo.Name); err != nil {
+	if err = checkInitRepository(generateRepo); err != nil {
 		return generateRepo, err
 	}
 
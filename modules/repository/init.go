@@ -172,13 +172,13 @@ func initRepoCommit(tmpPath string, repo *models.Repository, u *models.User, def
 	return nil
 }
 
-func checkInitRepository(owner, name string) (err error) {
+func checkInitRepository(repo *models.Repository) (err error) {
 	// Somehow the directory could exist.
-	repoPath := models.RepoPath(owner, name)
+	repoPath := repo.RepoPath()
 	if com.IsExist(repoPath) {
 		return models.ErrRepoFilesAlreadyExist{
-			Uname: owner,
-			Name:  name,
+			Uname: repo.OwnerName,
+			Name:  repo.Name,
 		}
 	}
 
@@ -269,7 +269,7 @@ func adoptRepository(ctx models.DBContext, repoPath string, u *models.User, repo
 
 // InitRepository initializes README and .gitignore if needed.
 func initRepository(ctx models.DBContext, repoPath string, u *models.User, repo *models.Repository, opts models.CreateRepoOptions) (err error) {
-	if err = checkInitRepository(repo.OwnerName, repo.Name); err != nil {
+	if err = checkInitRepository(repo); err != nil {
 		return err
 	}
 
@@ -20,6 +20,7 @@ type PushUpdateOptions struct {
 	RefFullName  string // branch, tag or other name to push
 	OldCommitID  string
 	NewCommitID  string
+	RequestID    string // the ID of the request which caused this update, if any
 }
 
 // IsNewRef return true if it's a first-time push to a branch, tag or etc.
@@ -97,14 +98,14 @@ func (opts PushUpdateOptions) RepoFullName() string {
 	return opts.RepoUserName + "/" + opts.RepoName
 }
 
-// IsForcePush detect if a push is a force push
-func IsForcePush(opts *PushUpdateOptions) (bool, error) {
+// IsForcePush detect if a push to the repository is a force push
+func IsForcePush(repo *models.Repository, opts *PushUpdateOptions) (bool, error) {
 	if !opts.IsUpdateBranch() {
 		return false, nil
 	}
 
 	output, err := git.NewCommand("rev-list", "--max-count=1", opts.OldCommitID, "^"+opts.NewCommitID).
-		RunInDir(models.RepoPath(opts.RepoUserName, opts.RepoName))
+		RunInDir(repo.RepoPath())
 	if err != nil {
 		return false, err
 	} else if len(output) > 0 {
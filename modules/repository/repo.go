@@ -42,7 +42,7 @@ func WikiRemoteURL(remote string) string {
 
 // MigrateRepositoryGitData starts migrating git related data after created migrating repository
 func MigrateRepositoryGitData(doer, u *models.User, repo *models.Repository, opts migration.MigrateOptions) (*models.Repository, error) {
-	repoPath := models.RepoPath(u.Name, opts.RepoName)
+	repoPath := repo.RepoPath()
 
 	if u.IsOrganization() {
 		t, err := u.GetOwnerTeam()
@@ -70,7 +70,7 @@ func MigrateRepositoryGitData(doer, u *models.User, repo *models.Repository, opt
 	}
 
 	if opts.Wiki {
-		wikiPath := models.WikiPath(u.Name, opts.RepoName)
+		wikiPath := repo.WikiPath()
 		wikiRemotePath := WikiRemoteURL(opts.CloneAddr)
 		if len(wikiRemotePath) > 0 {
 			if err := util.RemoveAll(wikiPath); err != nil {
@@ -0,0 +1,42 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Cluster settings
+var (
+	Cluster = struct {
+		LockType    string
+		LockConnStr string
+		LockTTL     time.Duration
+	}{
+		LockType:    "database",
+		LockConnStr: "redis://127.0.0.1:6379/0",
+		LockTTL:     time.Minute,
+	}
+)
+
+func newClusterService() {
+	sec := Cfg.Section("cluster")
+	if err := sec.MapTo(&Cluster); err != nil {
+		log.Fatal("Failed to map Cluster settings: %v", err)
+	}
+	Cluster.LockType = sec.Key("LOCK_TYPE").In("database", []string{"database", "redis", "memory"})
+	if Cluster.LockTTL < 3*time.Second {
+		log.Warn("[cluster] LOCK_TTL %v is too short, using 3s", Cluster.LockTTL)
+		Cluster.LockTTL = 3 * time.Second
+	}
+
+	if Cluster.LockType == "memory" {
+		log.Info("Cluster Locks: using memory, the instances sharing the database won't be coordinated")
+	} else {
+		log.Info("Cluster Locks: using %s", Cluster.LockType)
+	}
+}
@@ -4,7 +4,26 @@
 
 package setting
 
-import "reflect"
+import (
+	"reflect"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Cron settings
+var (
+	Cron = struct {
+		HistoryLength int
+	}{
+		HistoryLength: 50,
+	}
+)
+
+func newCronService() {
+	if err := Cfg.Section("cron").MapTo(&Cron); err != nil {
+		log.Fatal("Failed to map Cron settings: %v", err)
+	}
+}
 
 // GetCronSettings maps the cron subsection to the provided config
 func GetCronSettings(name string, config interface{}) (interface{}, error) {
@@ -0,0 +1,57 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"net/mail"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// IncomingEmailTokenPlaceholder is replaced by the reply token in IncomingEmail.ReplyToAddress
+const IncomingEmailTokenPlaceholder = "%{token}"
+
+// IncomingEmail represents the settings of the reply-by-email service
+var IncomingEmail = struct {
+	Enabled        bool
+	ReplyToAddress string
+	Protocol       string
+	ListenHost     string
+	ListenPort     int
+	MaxMessageSize int64
+}{
+	Enabled:        false,
+	Protocol:       "lmtp",
+	ListenHost:     "127.0.0.1",
+	ListenPort:     2500,
+	MaxMessageSize: 10 << 20,
+}
+
+func newIncomingEmailService() {
+	sec := Cfg.Section("email.incoming")
+	if err := sec.MapTo(&IncomingEmail); err != nil {
+		log.Fatal("Failed to map Incoming Email settings: %v", err)
+	}
+	IncomingEmail.Protocol = sec.Key("PROTOCOL").In("lmtp", []string{"lmtp", "smtp"})
+	if !IncomingEmail.Enabled {
+		return
+	}
+
+	if !strings.Contains(IncomingEmail.ReplyToAddress, IncomingEmailTokenPlaceholder) {
+		log.Error("Incoming Email Service: REPLY_TO_ADDRESS must contain %s, the service is disabled", IncomingEmailTokenPlaceholder)
+		IncomingEmail.Enabled = false
+		return
+	}
+	if _, err := mail.ParseAddress(strings.Replace(IncomingEmail.ReplyToAddress, IncomingEmailTokenPlaceholder, "token", 1)); err != nil {
+		log.Error("Incoming Email Service: invalid REPLY_TO_ADDRESS (%s): %v, the service is disabled", IncomingEmail.ReplyToAddress, err)
+		IncomingEmail.Enabled = false
+		return
+	}
+	if MailService == nil {
+		log.Warn("Incoming Email Service: Mail Service is not enabled, no notification can be replied to")
+	}
+	log.Info("Incoming Email Service Enabled")
+}
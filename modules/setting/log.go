@@ -87,6 +87,7 @@ func RemoveSubLogDescription(key string, name string) bool {
 type defaultLogOptions struct {
 	levelName      string // LogLevel
 	flags          string
+	format         string
 	filename       string //path.Join(LogRootPath, "gitea.log")
 	bufferLength   int64
 	disableConsole bool
@@ -96,6 +97,7 @@ func newDefaultLogOptions() defaultLogOptions {
 	return defaultLogOptions{
 		levelName:      LogLevel,
 		flags:          "stdflags",
+		format:         Cfg.Section("log").Key("FORMAT").In("text", []string{"text", "json"}),
 		filename:       filepath.Join(LogRootPath, "gitea.log"),
 		bufferLength:   10000,
 		disableConsole: false,
@@ -136,6 +138,7 @@ func generateLogConfig(sec *ini.Section, name string, defaults defaultLogOptions
 	flags := log.FlagsFromString(defaults.flags)
 	expression := ""
 	prefix := ""
+	format := defaults.format
 	for _, key := range keys {
 		switch key.Name() {
 		case "MODE":
@@ -152,6 +155,8 @@ func generateLogConfig(sec *ini.Section, name string, defaults defaultLogOptions
 			expression = key.MustString("")
 		case "PREFIX":
 			prefix = key.MustString("")
+		case "FORMAT":
+			format = key.In(defaults.format, []string{"text", "json"})
 		}
 	}
 
@@ -160,6 +165,7 @@ func generateLogConfig(sec *ini.Section, name string, defaults defaultLogOptions
 		"expression":      expression,
 		"prefix":          prefix,
 		"flags":           flags,
+		"format":          format,
 		"stacktraceLevel": stacktraceLevel.String(),
 	}
 
@@ -0,0 +1,129 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+
+	ini "gopkg.in/ini.v1"
+)
+
+// enumerates the layouts of the repositories in their volume
+const (
+	// RepoStorageLayoutName stores the repositories as <owner>/<name>.git
+	RepoStorageLayoutName = "name"
+	// RepoStorageLayoutID stores the repositories in directories sharded by their ID
+	RepoStorageLayoutID = "id"
+)
+
+// enumerates the policies choosing the volume of the new repositories
+const (
+	RepoVolumePlacementDefault           = "default"
+	RepoVolumePlacementLeastRepositories = "least_repositories"
+	RepoVolumePlacementLeastSize         = "least_size"
+)
+
+// DefaultRepoStorageVolume is the name of the volume of the repository ROOT
+const DefaultRepoStorageVolume = "default"
+
+// repoStorageVolumeSectionPrefix prefixes the sections of the additional volumes
+const repoStorageVolumeSectionPrefix = "repository.volume."
+
+// RepoStorageVolume is a root directory the repositories are stored in
+type RepoStorageVolume struct {
+	Name                  string
+	Path                  string
+	AcceptNewRepositories bool
+}
+
+// RepoStorage settings
+var RepoStorage = struct {
+	Layout    string
+	Placement string
+	// Volumes are the additional volumes, the default volume always is the repository ROOT
+	Volumes               map[string]*RepoStorageVolume
+	DefaultAcceptNewRepos bool
+}{
+	Layout:                RepoStorageLayoutName,
+	Placement:             RepoVolumePlacementDefault,
+	Volumes:               map[string]*RepoStorageVolume{},
+	DefaultAcceptNewRepos: true,
+}
+
+func newRepoStorage() {
+	sec := Cfg.Section("repository")
+	RepoStorage.Layout = sec.Key("STORAGE_LAYOUT").In(RepoStorageLayoutName, []string{RepoStorageLayoutName, RepoStorageLayoutID})
+	RepoStorage.Placement = sec.Key("VOLUME_PLACEMENT").In(RepoVolumePlacementDefault,
+		[]string{RepoVolumePlacementDefault, RepoVolumePlacementLeastRepositories, RepoVolumePlacementLeastSize})
+	RepoStorage.Volumes = parseRepoStorageVolumes(Cfg.Sections())
+	RepoStorage.DefaultAcceptNewRepos = Cfg.Section(repoStorageVolumeSectionPrefix + DefaultRepoStorageVolume).
+		Key("ACCEPT_NEW_REPOSITORIES").MustBool(true)
+}
+
+func parseRepoStorageVolumes(sections []*ini.Section) map[string]*RepoStorageVolume {
+	volumes := map[string]*RepoStorageVolume{}
+	for _, sec := range sections {
+		if !strings.HasPrefix(sec.Name(), repoStorageVolumeSectionPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(sec.Name(), repoStorageVolumeSectionPrefix)
+		if name == DefaultRepoStorageVolume {
+			if sec.HasKey("PATH") {
+				log.Warn("The path of the default repository volume is the ROOT of [repository], PATH of [%s] is ignored", sec.Name())
+			}
+			continue
+		}
+		p := sec.Key("PATH").String()
+		if name == "" || p == "" {
+			log.Fatal("The repository volume [%s] must have a name and a PATH", sec.Name())
+		}
+		forcePathSeparator(p)
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(AppWorkPath, p)
+		}
+		volumes[name] = &RepoStorageVolume{
+			Name:                  name,
+			Path:                  filepath.Clean(p),
+			AcceptNewRepositories: sec.Key("ACCEPT_NEW_REPOSITORIES").MustBool(true),
+		}
+	}
+	return volumes
+}
+
+// RepoStorageVolumePath returns the root path of a repository volume,
+// the empty name is the default volume
+func RepoStorageVolumePath(name string) (string, bool) {
+	if name == "" || name == DefaultRepoStorageVolume {
+		return RepoRootPath, true
+	}
+	volume, ok := RepoStorage.Volumes[name]
+	if !ok {
+		return "", false
+	}
+	return volume.Path, true
+}
+
+// RepoStorageVolumeNames returns the names of all the repository volumes, the default volume first
+func RepoStorageVolumeNames() []string {
+	names := make([]string, 0, len(RepoStorage.Volumes))
+	for name := range RepoStorage.Volumes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultRepoStorageVolume}, names...)
+}
+
+// RepoStorageVolumeAcceptsNew returns whether the new repositories may be placed in the volume
+func RepoStorageVolumeAcceptsNew(name string) bool {
+	if name == "" || name == DefaultRepoStorageVolume {
+		return RepoStorage.DefaultAcceptNewRepos
+	}
+	volume, ok := RepoStorage.Volumes[name]
+	return ok && volume.AcceptNewRepositories
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	ini "gopkg.in/ini.v1"
+)
+
+func Test_parseRepoStorageVolumes(t *testing.T) {
+	iniStr := `
+[repository.editor]
+LINE_WRAP_EXTENSIONS = .txt
+
+[repository.volume.default]
+ACCEPT_NEW_REPOSITORIES = false
+
+[repository.volume.fast]
+PATH = /mnt/fast/repositories
+
+[repository.volume.old]
+PATH = old-repositories
+ACCEPT_NEW_REPOSITORIES = false
+`
+	cfg, err := ini.Load([]byte(iniStr))
+	assert.NoError(t, err)
+
+	volumes := parseRepoStorageVolumes(cfg.Sections())
+	assert.Len(t, volumes, 2)
+	assert.Equal(t, &RepoStorageVolume{Name: "fast", Path: filepath.Clean("/mnt/fast/repositories"), AcceptNewRepositories: true}, volumes["fast"])
+	assert.Equal(t, &RepoStorageVolume{Name: "old", Path: filepath.Join(AppWorkPath, "old-repositories"), AcceptNewRepositories: false}, volumes["old"])
+}
@@ -1035,10 +1035,14 @@ func NewServices() {
 	newMailService()
 	newRegisterMailService()
 	newNotifyMailService()
+	newIncomingEmailService()
 	newWebhookService()
 	newMigrationsService()
 	newIndexerService()
 	newTaskService()
 	NewQueueService()
 	newProject()
+	newTracingService()
+	newCronService()
+	newClusterService()
 }
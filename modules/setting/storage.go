@@ -13,10 +13,11 @@ import (
 
 // Storage represents configuration of storages
 type Storage struct {
-	Type        string
-	Path        string
-	Section     *ini.Section
-	ServeDirect bool
+	Type         string
+	Path         string
+	Section      *ini.Section
+	ServeDirect  bool
+	UploadDirect bool
 }
 
 // MapTo implements the Mappable interface
@@ -46,6 +47,7 @@ func getStorage(name, typ string, overrides ...*ini.Section) Storage {
 
 	storage.Type = sec.Key("STORAGE_TYPE").MustString(typ)
 	storage.ServeDirect = sec.Key("SERVE_DIRECT").MustBool(false)
+	storage.UploadDirect = sec.Key("UPLOAD_DIRECT").MustBool(false)
 
 	// Global Defaults
 	sec.Key("MINIO_ENDPOINT").MustString("localhost:9000")
@@ -54,6 +56,13 @@ func getStorage(name, typ string, overrides ...*ini.Section) Storage {
 	sec.Key("MINIO_BUCKET").MustString("gitea")
 	sec.Key("MINIO_LOCATION").MustString("us-east-1")
 	sec.Key("MINIO_USE_SSL").MustBool(false)
+	sec.Key("MINIO_SERVER_SIDE_ENCRYPTION").MustString("")
+	sec.Key("MINIO_SSE_KMS_KEY_ID").MustString("")
+	sec.Key("MINIO_SSE_C_KEY").MustString("")
+	sec.Key("AZURE_BLOB_ENDPOINT").MustString("")
+	sec.Key("AZURE_BLOB_ACCOUNT_NAME").MustString("")
+	sec.Key("AZURE_BLOB_ACCOUNT_KEY").MustString("")
+	sec.Key("AZURE_BLOB_CONTAINER").MustString("gitea")
 
 	storage.Section = sec
 
@@ -64,6 +73,7 @@ func getStorage(name, typ string, overrides ...*ini.Section) Storage {
 			}
 		}
 		storage.ServeDirect = override.Key("SERVE_DIRECT").MustBool(false)
+		storage.UploadDirect = override.Key("UPLOAD_DIRECT").MustBool(false)
 		storage.Section = override
 	}
 
@@ -74,6 +84,7 @@ func getStorage(name, typ string, overrides ...*ini.Section) Storage {
 		storage.Section.Key("PATH").SetValue(storage.Path)
 	}
 	storage.Section.Key("MINIO_BASE_PATH").MustString(name + "/")
+	storage.Section.Key("AZURE_BLOB_BASE_PATH").MustString(name + "/")
 
 	return storage
 }
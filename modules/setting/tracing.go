@@ -0,0 +1,56 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"path/filepath"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Tracing settings
+var (
+	Tracing = struct {
+		Enabled       bool
+		Exporter      string
+		Endpoint      string
+		FilePath      string
+		ServiceName   string
+		SampleRatio   float64
+		BatchSize     int
+		QueueLength   int
+		FlushInterval time.Duration
+		Timeout       time.Duration
+	}{
+		Enabled:       false,
+		Exporter:      "otlp",
+		Endpoint:      "http://localhost:4318/v1/traces",
+		ServiceName:   "gitea",
+		SampleRatio:   1,
+		BatchSize:     512,
+		QueueLength:   2048,
+		FlushInterval: 5 * time.Second,
+		Timeout:       10 * time.Second,
+	}
+)
+
+func newTracingService() {
+	sec := Cfg.Section("tracing")
+	if err := sec.MapTo(&Tracing); err != nil {
+		log.Fatal("Failed to map Tracing settings: %v", err)
+	}
+	Tracing.Exporter = sec.Key("EXPORTER").In("otlp", []string{"otlp", "file"})
+	if Tracing.FilePath == "" {
+		Tracing.FilePath = filepath.Join(LogRootPath, "traces.json")
+	}
+	if !filepath.IsAbs(Tracing.FilePath) {
+		Tracing.FilePath = filepath.Join(AppWorkPath, Tracing.FilePath)
+	}
+
+	if Tracing.Enabled {
+		log.Info("Tracing Enabled: exporting to %s", Tracing.Exporter)
+	}
+}
@@ -60,8 +60,9 @@ func sessionHandler(session ssh.Session) {
 	keyID := session.Context().Value(giteaKeyID).(int64)
 
 	command := session.RawCommand()
+	requestID := log.NewRequestID()
 
-	log.Trace("SSH: Payload: %v", command)
+	log.Trace("SSH: Request: %s Payload: %v", requestID, command)
 
 	args := []string{"serv", "key-" + com.ToStr(keyID), "--config=" + setting.CustomConf}
 	log.Trace("SSH: Arguments: %v", args)
@@ -70,6 +71,7 @@ func sessionHandler(session ssh.Session) {
 		os.Environ(),
 		"SSH_ORIGINAL_COMMAND="+command,
 		"SKIP_MINWINSVC=1",
+		log.RequestIDEnv+"="+requestID,
 	)
 
 	stdout, err := cmd.StdoutPipe()
@@ -0,0 +1,542 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+var (
+	_ ObjectStorage = &AzureBlobStorage{}
+)
+
+// AzureBlobStorageType is the type descriptor for azure blob storage
+const AzureBlobStorageType Type = "azureblob"
+
+const (
+	// azureBlobAPIVersion is the version of the REST API of the blob service
+	azureBlobAPIVersion = "2019-12-12"
+	// azureBlobBlockSize is the size of the blocks of the objects uploaded in several requests
+	azureBlobBlockSize = 8 << 20
+	// azureBlobSASTimeFormat is the format of the expiry time of the shared access signatures
+	azureBlobSASTimeFormat = "2006-01-02T15:04:05Z"
+	// azureBlobTimeout bounds the connection to the blob service and the wait for its responses,
+	// the whole requests aren't bounded as the blobs are streamed to the callers
+	azureBlobTimeout = time.Minute
+)
+
+// AzureBlobStorageConfig represents the configuration for an azure blob storage
+type AzureBlobStorageConfig struct {
+	// Endpoint is the URL of the blob service, https://<account>.blob.core.windows.net by default.
+	// Azurite uses path style URLs, e.g. http://127.0.0.1:10000/devstoreaccount1
+	Endpoint    string `ini:"AZURE_BLOB_ENDPOINT"`
+	AccountName string `ini:"AZURE_BLOB_ACCOUNT_NAME"`
+	AccountKey  string `ini:"AZURE_BLOB_ACCOUNT_KEY"`
+	Container   string `ini:"AZURE_BLOB_CONTAINER"`
+	BasePath    string `ini:"AZURE_BLOB_BASE_PATH"`
+}
+
+// AzureBlobStorage returns an azure blob container storage
+type AzureBlobStorage struct {
+	ctx       context.Context
+	client    *http.Client
+	endpoint  *url.URL
+	account   string
+	key       []byte
+	container string
+	basePath  string
+}
+
+// azureBlobError is an error response of the blob service
+type azureBlobError struct {
+	StatusCode int
+	Code       string
+}
+
+func (err azureBlobError) Error() string {
+	return fmt.Sprintf("azure blob service responded %d: %s", err.StatusCode, err.Code)
+}
+
+func convertAzureBlobErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	errResp, ok := err.(azureBlobError)
+	if !ok {
+		return err
+	}
+
+	// Convert two responses to standard analogues
+	switch errResp.StatusCode {
+	case http.StatusNotFound:
+		return os.ErrNotExist
+	case http.StatusForbidden:
+		return os.ErrPermission
+	}
+
+	return err
+}
+
+// NewAzureBlobStorage returns an azure blob storage
+func NewAzureBlobStorage(ctx context.Context, cfg interface{}) (ObjectStorage, error) {
+	configInterface, err := toConfig(AzureBlobStorageConfig{}, cfg)
+	if err != nil {
+		return nil, err
+	}
+	config := configInterface.(AzureBlobStorageConfig)
+
+	if config.Endpoint == "" {
+		config.Endpoint = "https://" + config.AccountName + ".blob.core.windows.net"
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(config.Endpoint, "/"))
+	if err != nil {
+		return nil, ErrInvalidConfiguration{cfg: cfg, err: err}
+	}
+	key, err := base64.StdEncoding.DecodeString(config.AccountKey)
+	if err != nil {
+		return nil, ErrInvalidConfiguration{cfg: cfg, err: fmt.Errorf("AZURE_BLOB_ACCOUNT_KEY is not base64 encoded: %v", err)}
+	}
+
+	log.Info("Creating Azure Blob storage at %s:%s with base path %s", endpoint, config.Container, config.BasePath)
+
+	a := &AzureBlobStorage{
+		ctx: ctx,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				DialContext:           (&net.Dialer{Timeout: azureBlobTimeout, KeepAlive: 30 * time.Second}).DialContext,
+				TLSHandshakeTimeout:   azureBlobTimeout,
+				ResponseHeaderTimeout: azureBlobTimeout,
+				IdleConnTimeout:       90 * time.Second,
+			},
+		},
+		endpoint:  endpoint,
+		account:   config.AccountName,
+		key:       key,
+		container: config.Container,
+		basePath:  config.BasePath,
+	}
+
+	containerURL := a.containerURL(url.Values{"restype": {"container"}})
+	resp, err := a.do(http.MethodPut, containerURL, nil, nil, http.StatusCreated)
+	if err != nil {
+		// Check to see if we already own this container (which happens if you run this twice)
+		if errResp, ok := err.(azureBlobError); !ok || errResp.Code != "ContainerAlreadyExists" {
+			return nil, convertAzureBlobErr(err)
+		}
+	} else {
+		resp.Body.Close()
+	}
+
+	return a, nil
+}
+
+func (a *AzureBlobStorage) buildAzureBlobPath(p string) string {
+	return strings.TrimPrefix(path.Join(a.basePath, p), "/")
+}
+
+func (a *AzureBlobStorage) containerURL(query url.Values) *url.URL {
+	u := *a.endpoint
+	u.Path += "/" + a.container
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+func (a *AzureBlobStorage) blobURL(name string, query url.Values) *url.URL {
+	u := *a.endpoint
+	u.Path += "/" + a.container + "/" + name
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+// sign adds the shared key authorization to the request
+func (a *AzureBlobStorage) sign(req *http.Request) {
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureBlobAPIVersion)
+
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	// headers starting with x-ms- sorted by name
+	var headers []string
+	for key := range req.Header {
+		if key = strings.ToLower(key); strings.HasPrefix(key, "x-ms-") {
+			headers = append(headers, key)
+		}
+	}
+	sort.Strings(headers)
+	var canonicalized strings.Builder
+	for _, key := range headers {
+		canonicalized.WriteString(key + ":" + strings.TrimSpace(req.Header.Get(key)) + "\n")
+	}
+
+	// resource followed by its query parameters sorted by name
+	canonicalized.WriteString("/" + a.account + req.URL.EscapedPath())
+	query := req.URL.Query()
+	params := make([]string, 0, len(query))
+	for key := range query {
+		params = append(params, key)
+	}
+	sort.Strings(params)
+	for _, key := range params {
+		values := query[key]
+		sort.Strings(values)
+		canonicalized.WriteString("\n" + strings.ToLower(key) + ":" + strings.Join(values, ","))
+	}
+
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, x-ms-date is used instead
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		canonicalized.String(),
+	}, "\n")
+	req.Header.Set("Authorization", "SharedKey "+a.account+":"+a.signature(stringToSign))
+}
+
+func (a *AzureBlobStorage) signature(stringToSign string) string {
+	mac := hmac.New(sha256.New, a.key)
+	_, _ = mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// do sends a signed request and returns the response if its status is the expected one
+func (a *AzureBlobStorage) do(method string, u *url.URL, body []byte, header http.Header, expectedStatus int) (*http.Response, error) {
+	var rd io.Reader
+	if body != nil {
+		rd = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(a.ctx, method, u.String(), rd)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	a.sign(req)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != expectedStatus {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		return nil, azureBlobError{StatusCode: resp.StatusCode, Code: resp.Header.Get("x-ms-error-code")}
+	}
+	return resp, nil
+}
+
+// sasURL returns the URL of a blob with a shared access signature
+func (a *AzureBlobStorage) sasURL(name, permissions string, expiry time.Duration, contentDisposition string) *url.URL {
+	expires := time.Now().UTC().Add(expiry).Format(azureBlobSASTimeFormat)
+	stringToSign := strings.Join([]string{
+		permissions,
+		"", // start, valid immediately
+		expires,
+		"/blob/" + a.account + "/" + a.container + "/" + name,
+		"", // identifier
+		"", // IP range
+		"", // protocol
+		azureBlobAPIVersion,
+		"b", // resource is a blob
+		"",  // snapshot time
+		"",  // Cache-Control
+		contentDisposition,
+		"", // Content-Encoding
+		"", // Content-Language
+		"", // Content-Type
+	}, "\n")
+
+	query := url.Values{
+		"sv":  {azureBlobAPIVersion},
+		"sr":  {"b"},
+		"sp":  {permissions},
+		"se":  {expires},
+		"sig": {a.signature(stringToSign)},
+	}
+	if contentDisposition != "" {
+		query.Set("rscd", contentDisposition)
+	}
+	return a.blobURL(name, query)
+}
+
+type azureBlobFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (a azureBlobFileInfo) Name() string {
+	return a.name
+}
+
+func (a azureBlobFileInfo) Size() int64 {
+	return a.size
+}
+
+func (a azureBlobFileInfo) ModTime() time.Time {
+	return a.modTime
+}
+
+func (a azureBlobFileInfo) IsDir() bool {
+	return strings.HasSuffix(a.name, "/")
+}
+
+func (a azureBlobFileInfo) Mode() os.FileMode {
+	return os.ModePerm
+}
+
+func (a azureBlobFileInfo) Sys() interface{} {
+	return nil
+}
+
+// azureBlobObject reads a blob with range requests starting at the current offset
+type azureBlobObject struct {
+	storage *AzureBlobStorage
+	info    *azureBlobFileInfo
+	offset  int64
+	body    io.ReadCloser
+}
+
+func (o *azureBlobObject) Read(p []byte) (int, error) {
+	if o.body == nil {
+		if o.offset >= o.info.size {
+			return 0, io.EOF
+		}
+		resp, err := o.storage.do(http.MethodGet, o.storage.blobURL(o.info.name, nil), nil, http.Header{
+			"x-ms-range": {fmt.Sprintf("bytes=%d-", o.offset)},
+		}, http.StatusPartialContent)
+		if err != nil {
+			return 0, convertAzureBlobErr(err)
+		}
+		o.body = resp.Body
+	}
+	n, err := o.body.Read(p)
+	o.offset += int64(n)
+	return n, err
+}
+
+func (o *azureBlobObject) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.info.size
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negative position: %d", offset)
+	}
+	if offset != o.offset && o.body != nil {
+		o.body.Close()
+		o.body = nil
+	}
+	o.offset = offset
+	return offset, nil
+}
+
+func (o *azureBlobObject) Close() error {
+	if o.body == nil {
+		return nil
+	}
+	err := o.body.Close()
+	o.body = nil
+	return err
+}
+
+func (o *azureBlobObject) Stat() (os.FileInfo, error) {
+	return o.info, nil
+}
+
+func (a *AzureBlobStorage) stat(name string) (*azureBlobFileInfo, error) {
+	resp, err := a.do(http.MethodHead, a.blobURL(name, nil), nil, nil, http.StatusOK)
+	if err != nil {
+		return nil, convertAzureBlobErr(err)
+	}
+	resp.Body.Close()
+
+	info := &azureBlobFileInfo{name: name}
+	if info.size, err = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid Content-Length of blob %s: %v", name, err)
+	}
+	info.modTime, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+	return info, nil
+}
+
+// Open open a file
+func (a *AzureBlobStorage) Open(path string) (Object, error) {
+	info, err := a.stat(a.buildAzureBlobPath(path))
+	if err != nil {
+		return nil, err
+	}
+	return &azureBlobObject{storage: a, info: info}, nil
+}
+
+// Save save a file to the container, the content is uploaded in blocks when it doesn't fit in one
+func (a *AzureBlobStorage) Save(path string, r io.Reader) (int64, error) {
+	name := a.buildAzureBlobPath(path)
+	buf := make([]byte, azureBlobBlockSize)
+
+	n, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		resp, err := a.do(http.MethodPut, a.blobURL(name, nil), buf[:n], http.Header{
+			"x-ms-blob-type": {"BlockBlob"},
+			"Content-Type":   {"application/octet-stream"},
+		}, http.StatusCreated)
+		if err != nil {
+			return 0, convertAzureBlobErr(err)
+		}
+		resp.Body.Close()
+		return int64(n), nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	var blockList bytes.Buffer
+	blockList.WriteString(`<?xml version="1.0" encoding="utf-8"?><BlockList>`)
+	for i := 0; n > 0; i++ {
+		// all the block IDs of a blob must have the same length
+		blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", i)))
+		resp, err := a.do(http.MethodPut, a.blobURL(name, url.Values{"comp": {"block"}, "blockid": {blockID}}), buf[:n], nil, http.StatusCreated)
+		if err != nil {
+			return 0, convertAzureBlobErr(err)
+		}
+		resp.Body.Close()
+		size += int64(n)
+		blockList.WriteString("<Latest>" + blockID + "</Latest>")
+
+		n, err = io.ReadFull(r, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return 0, err
+		}
+	}
+	blockList.WriteString("</BlockList>")
+
+	resp, err := a.do(http.MethodPut, a.blobURL(name, url.Values{"comp": {"blocklist"}}), blockList.Bytes(), http.Header{
+		"x-ms-blob-content-type": {"application/octet-stream"},
+	}, http.StatusCreated)
+	if err != nil {
+		return 0, convertAzureBlobErr(err)
+	}
+	resp.Body.Close()
+	return size, nil
+}
+
+// Stat returns the stat information of the object
+func (a *AzureBlobStorage) Stat(path string) (os.FileInfo, error) {
+	return a.stat(a.buildAzureBlobPath(path))
+}
+
+// Delete delete a file
+func (a *AzureBlobStorage) Delete(path string) error {
+	resp, err := a.do(http.MethodDelete, a.blobURL(a.buildAzureBlobPath(path), nil), nil, nil, http.StatusAccepted)
+	if err != nil {
+		if errResp, ok := err.(azureBlobError); ok && errResp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return convertAzureBlobErr(err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// URL gets the redirect URL to a file. The shared access signature is valid for 5 minutes.
+func (a *AzureBlobStorage) URL(path, name string) (*url.URL, error) {
+	return a.sasURL(a.buildAzureBlobPath(path), "r", 5*time.Minute, "attachment; filename=\""+quoteEscaper.Replace(name)+"\""), nil
+}
+
+// UploadURL gets a presigned request uploading a file
+func (a *AzureBlobStorage) UploadURL(path string, expiry time.Duration) (*PresignedUpload, error) {
+	return &PresignedUpload{
+		Method: http.MethodPut,
+		URL:    a.sasURL(a.buildAzureBlobPath(path), "cw", expiry, ""),
+		Header: map[string]string{"x-ms-blob-type": "BlockBlob"},
+	}, nil
+}
+
+// azureBlobList is a page of the List Blobs response
+type azureBlobList struct {
+	Blobs []struct {
+		Name       string `xml:"Name"`
+		Properties struct {
+			ContentLength int64  `xml:"Content-Length"`
+			LastModified  string `xml:"Last-Modified"`
+		} `xml:"Properties"`
+	} `xml:"Blobs>Blob"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+// IterateObjects iterates across the objects in the azure blob storage
+func (a *AzureBlobStorage) IterateObjects(fn func(path string, obj Object) error) error {
+	marker := ""
+	for {
+		query := url.Values{"restype": {"container"}, "comp": {"list"}, "prefix": {a.basePath}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		resp, err := a.do(http.MethodGet, a.containerURL(query), nil, nil, http.StatusOK)
+		if err != nil {
+			return convertAzureBlobErr(err)
+		}
+		var list azureBlobList
+		err = xml.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		for _, blob := range list.Blobs {
+			info := &azureBlobFileInfo{name: blob.Name, size: blob.Properties.ContentLength}
+			info.modTime, _ = http.ParseTime(blob.Properties.LastModified)
+			if err := func(object *azureBlobObject, fn func(path string, obj Object) error) error {
+				defer object.Close()
+				return fn(strings.TrimPrefix(blob.Name, a.basePath), object)
+			}(&azureBlobObject{storage: a, info: info}, fn); err != nil {
+				return convertAzureBlobErr(err)
+			}
+		}
+
+		if list.NextMarker == "" {
+			return nil
+		}
+		marker = list.NextMarker
+	}
+}
+
+func init() {
+	RegisterStorageType(AzureBlobStorageType, NewAzureBlobStorage)
+}
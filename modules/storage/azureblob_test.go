@@ -0,0 +1,253 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// azuriteAccountKey is the well known key of the account of the Azurite emulator
+const azuriteAccountKey = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+
+// fakeBlobService emulates the requests of the blob service used by the storage,
+// for the devstoreaccount1 account with path style URLs like Azurite
+type fakeBlobService struct {
+	sync.Mutex
+	containers map[string]bool
+	blobs      map[string][]byte
+	blocks     map[string][]byte
+}
+
+func (f *fakeBlobService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey devstoreaccount1:") || r.Header.Get("x-ms-version") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/devstoreaccount1/"), "/", 2)
+	query := r.URL.Query()
+	body, _ := ioutil.ReadAll(r.Body)
+
+	if len(parts) == 1 {
+		switch {
+		case r.Method == http.MethodPut && query.Get("restype") == "container":
+			if f.containers[parts[0]] {
+				w.Header().Set("x-ms-error-code", "ContainerAlreadyExists")
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			f.containers[parts[0]] = true
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && query.Get("comp") == "list":
+			f.list(w, parts[0]+"/"+query.Get("prefix"), query.Get("marker"))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+
+	name := parts[0] + "/" + parts[1]
+	switch r.Method {
+	case http.MethodPut:
+		switch query.Get("comp") {
+		case "block":
+			f.blocks[name+"#"+query.Get("blockid")] = body
+		case "blocklist":
+			var list struct {
+				Latest []string `xml:"Latest"`
+			}
+			if err := xml.Unmarshal(body, &list); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			var content []byte
+			for _, id := range list.Latest {
+				content = append(content, f.blocks[name+"#"+id]...)
+			}
+			f.blobs[name] = content
+		default:
+			if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			f.blobs[name] = body
+		}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodHead, http.MethodGet:
+		content, ok := f.blobs[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		status := http.StatusOK
+		if rng := r.Header.Get("x-ms-range"); rng != "" {
+			start, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			content = content[start:]
+			status = http.StatusPartialContent
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.WriteHeader(status)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(content)
+		}
+	case http.MethodDelete:
+		if _, ok := f.blobs[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.blobs, name)
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// list returns the blobs one by one to test the markers
+func (f *fakeBlobService) list(w http.ResponseWriter, prefix, marker string) {
+	names := make([]string, 0, len(f.blobs))
+	for name := range f.blobs {
+		if strings.HasPrefix(name, prefix) && name > marker {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var resp bytes.Buffer
+	resp.WriteString(`<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs>`)
+	if len(names) > 0 {
+		container := strings.SplitN(names[0], "/", 2)
+		fmt.Fprintf(&resp, `<Blob><Name>%s</Name><Properties><Content-Length>%d</Content-Length></Properties></Blob>`, container[1], len(f.blobs[names[0]]))
+	}
+	resp.WriteString(`</Blobs>`)
+	if len(names) > 1 {
+		fmt.Fprintf(&resp, `<NextMarker>%s</NextMarker>`, names[0])
+	} else {
+		resp.WriteString(`<NextMarker />`)
+	}
+	resp.WriteString(`</EnumerationResults>`)
+	_, _ = w.Write(resp.Bytes())
+}
+
+func newTestAzureBlobStorage(t *testing.T) (*fakeBlobService, *AzureBlobStorage) {
+	service := &fakeBlobService{containers: map[string]bool{}, blobs: map[string][]byte{}, blocks: map[string][]byte{}}
+	server := httptest.NewServer(service)
+	t.Cleanup(server.Close)
+
+	objStorage, err := NewAzureBlobStorage(context.Background(), AzureBlobStorageConfig{
+		Endpoint:    server.URL + "/devstoreaccount1",
+		AccountName: "devstoreaccount1",
+		AccountKey:  azuriteAccountKey,
+		Container:   "gitea",
+		BasePath:    "lfs/",
+	})
+	assert.NoError(t, err)
+	assert.True(t, service.containers["gitea"])
+	return service, objStorage.(*AzureBlobStorage)
+}
+
+func TestAzureBlobStorage(t *testing.T) {
+	service, objStorage := newTestAzureBlobStorage(t)
+
+	// the existing container is reused
+	_, err := NewAzureBlobStorage(context.Background(), AzureBlobStorageConfig{
+		Endpoint:    objStorage.endpoint.String(),
+		AccountName: "devstoreaccount1",
+		AccountKey:  azuriteAccountKey,
+		Container:   "gitea",
+	})
+	assert.NoError(t, err)
+
+	size, err := objStorage.Save("a/small", strings.NewReader("small content"))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 13, size)
+	assert.Equal(t, "small content", string(service.blobs["gitea/lfs/a/small"]))
+
+	// larger contents are uploaded in blocks
+	large := bytes.Repeat([]byte("0123456789"), azureBlobBlockSize/10+1)
+	size, err = objStorage.Save("b/large", bytes.NewReader(large))
+	assert.NoError(t, err)
+	assert.EqualValues(t, len(large), size)
+	assert.Len(t, service.blocks, 2)
+	assert.Equal(t, large, service.blobs["gitea/lfs/b/large"])
+
+	info, err := objStorage.Stat("b/large")
+	assert.NoError(t, err)
+	assert.EqualValues(t, len(large), info.Size())
+	_, err = objStorage.Stat("missing")
+	assert.True(t, os.IsNotExist(err))
+
+	obj, err := objStorage.Open("b/large")
+	assert.NoError(t, err)
+	_, err = obj.Seek(int64(len(large)-5), io.SeekStart)
+	assert.NoError(t, err)
+	content, err := ioutil.ReadAll(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, "56789", string(content))
+	_, err = obj.Seek(2, io.SeekStart)
+	assert.NoError(t, err)
+	buf := make([]byte, 3)
+	_, err = io.ReadFull(obj, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "234", string(buf))
+	assert.NoError(t, obj.Close())
+
+	var paths []string
+	assert.NoError(t, objStorage.IterateObjects(func(path string, obj Object) error {
+		paths = append(paths, path)
+		info, err := obj.Stat()
+		assert.NoError(t, err)
+		assert.EqualValues(t, len(service.blobs["gitea/lfs/"+path]), info.Size())
+		return nil
+	}))
+	assert.Equal(t, []string{"a/small", "b/large"}, paths)
+
+	assert.NoError(t, objStorage.Delete("a/small"))
+	assert.NotContains(t, service.blobs, "gitea/lfs/a/small")
+	assert.NoError(t, objStorage.Delete("a/small"))
+}
+
+func TestAzureBlobStorageSAS(t *testing.T) {
+	_, objStorage := newTestAzureBlobStorage(t)
+
+	u, err := objStorage.URL("a/b", `file "1".txt`)
+	assert.NoError(t, err)
+	assert.Equal(t, "/devstoreaccount1/gitea/lfs/a/b", u.Path)
+	query := u.Query()
+	assert.Equal(t, "r", query.Get("sp"))
+	assert.Equal(t, "b", query.Get("sr"))
+	assert.Equal(t, azureBlobAPIVersion, query.Get("sv"))
+	assert.Equal(t, `attachment; filename="file \"1\".txt"`, query.Get("rscd"))
+	expires, err := time.Parse(azureBlobSASTimeFormat, query.Get("se"))
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), expires, time.Minute)
+	sig, err := base64.StdEncoding.DecodeString(query.Get("sig"))
+	assert.NoError(t, err)
+	assert.Len(t, sig, 32)
+
+	upload, err := objStorage.UploadURL("a/c", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPut, upload.Method)
+	assert.Equal(t, "cw", upload.URL.Query().Get("sp"))
+	assert.Equal(t, map[string]string{"x-ms-blob-type": "BlockBlob"}, upload.Header)
+}
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/util"
@@ -93,6 +94,11 @@ func (l *LocalStorage) URL(path, name string) (*url.URL, error) {
 	return nil, ErrURLNotSupported
 }
 
+// UploadURL gets a presigned request uploading a file
+func (l *LocalStorage) UploadURL(path string, expiry time.Duration) (*PresignedUpload, error) {
+	return nil, ErrUploadURLNotSupported
+}
+
 // IterateObjects iterates across the objects in the local storage
 func (l *LocalStorage) IterateObjects(fn func(path string, obj Object) error) error {
 	return filepath.Walk(l.dir, func(path string, info os.FileInfo, err error) error {
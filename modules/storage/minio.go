@@ -6,7 +6,11 @@ package storage
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"os"
 	"path"
@@ -17,6 +21,8 @@ import (
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/signer"
 )
 
 var (
@@ -41,15 +47,26 @@ func (m *minioObject) Stat() (os.FileInfo, error) {
 // MinioStorageType is the type descriptor for minio storage
 const MinioStorageType Type = "minio"
 
+// Server side encryptions of the minio storage
+const (
+	MinioSSES3  = "sse-s3"
+	MinioSSEKMS = "sse-kms"
+	MinioSSEC   = "sse-c"
+)
+
 // MinioStorageConfig represents the configuration for a minio storage
 type MinioStorageConfig struct {
-	Endpoint        string `ini:"MINIO_ENDPOINT"`
-	AccessKeyID     string `ini:"MINIO_ACCESS_KEY_ID"`
-	SecretAccessKey string `ini:"MINIO_SECRET_ACCESS_KEY"`
-	Bucket          string `ini:"MINIO_BUCKET"`
-	Location        string `ini:"MINIO_LOCATION"`
-	BasePath        string `ini:"MINIO_BASE_PATH"`
-	UseSSL          bool   `ini:"MINIO_USE_SSL"`
+	Endpoint             string `ini:"MINIO_ENDPOINT"`
+	AccessKeyID          string `ini:"MINIO_ACCESS_KEY_ID"`
+	SecretAccessKey      string `ini:"MINIO_SECRET_ACCESS_KEY"`
+	Bucket               string `ini:"MINIO_BUCKET"`
+	Location             string `ini:"MINIO_LOCATION"`
+	BasePath             string `ini:"MINIO_BASE_PATH"`
+	UseSSL               bool   `ini:"MINIO_USE_SSL"`
+	ServerSideEncryption string `ini:"MINIO_SERVER_SIDE_ENCRYPTION"`
+	SSEKMSKeyID          string `ini:"MINIO_SSE_KMS_KEY_ID"`
+	// SSECKey is the base64 encoded 256 bits key of the SSE-C encryption
+	SSECKey string `ini:"MINIO_SSE_C_KEY"`
 }
 
 // MinioStorage returns a minio bucket storage
@@ -58,6 +75,8 @@ type MinioStorage struct {
 	client   *minio.Client
 	bucket   string
 	basePath string
+	config   MinioStorageConfig
+	sse      encrypt.ServerSide
 }
 
 func convertMinioErr(err error) error {
@@ -90,6 +109,11 @@ func NewMinioStorage(ctx context.Context, cfg interface{}) (ObjectStorage, error
 
 	log.Info("Creating Minio storage at %s:%s with base path %s", config.Endpoint, config.Bucket, config.BasePath)
 
+	sse, err := newMinioServerSideEncryption(config)
+	if err != nil {
+		return nil, ErrInvalidConfiguration{cfg: cfg, err: err}
+	}
+
 	minioClient, err := minio.New(config.Endpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(config.AccessKeyID, config.SecretAccessKey, ""),
 		Secure: config.UseSSL,
@@ -113,17 +137,54 @@ func NewMinioStorage(ctx context.Context, cfg interface{}) (ObjectStorage, error
 		client:   minioClient,
 		bucket:   config.Bucket,
 		basePath: config.BasePath,
+		config:   config,
+		sse:      sse,
 	}, nil
 }
 
+// newMinioServerSideEncryption returns the server side encryption of the objects, nil if they are not encrypted
+func newMinioServerSideEncryption(config MinioStorageConfig) (encrypt.ServerSide, error) {
+	switch strings.ToLower(config.ServerSideEncryption) {
+	case "":
+		return nil, nil
+	case MinioSSES3:
+		return encrypt.NewSSE(), nil
+	case MinioSSEKMS:
+		if config.SSEKMSKeyID == "" {
+			return nil, errors.New("MINIO_SSE_KMS_KEY_ID is required by SSE-KMS")
+		}
+		return encrypt.NewSSEKMS(config.SSEKMSKeyID, nil)
+	case MinioSSEC:
+		key, err := base64.StdEncoding.DecodeString(config.SSECKey)
+		if err != nil {
+			return nil, fmt.Errorf("MINIO_SSE_C_KEY is not base64 encoded: %v", err)
+		}
+		return encrypt.NewSSEC(key)
+	}
+	return nil, fmt.Errorf("unknown server side encryption: %s", config.ServerSideEncryption)
+}
+
+// isSSEC returns whether the objects are encrypted with a key sent with every request
+func (m *MinioStorage) isSSEC() bool {
+	return m.sse != nil && m.sse.Type() == encrypt.SSEC
+}
+
+// getObjectOptions returns the options to read an object, the SSE-C key must be sent to read it
+func (m *MinioStorage) getObjectOptions() minio.GetObjectOptions {
+	var opts = minio.GetObjectOptions{}
+	if m.isSSEC() {
+		opts.ServerSideEncryption = m.sse
+	}
+	return opts
+}
+
 func (m *MinioStorage) buildMinioPath(p string) string {
 	return strings.TrimPrefix(path.Join(m.basePath, p), "/")
 }
 
 // Open open a file
 func (m *MinioStorage) Open(path string) (Object, error) {
-	var opts = minio.GetObjectOptions{}
-	object, err := m.client.GetObject(m.ctx, m.bucket, m.buildMinioPath(path), opts)
+	object, err := m.client.GetObject(m.ctx, m.bucket, m.buildMinioPath(path), m.getObjectOptions())
 	if err != nil {
 		return nil, convertMinioErr(err)
 	}
@@ -138,7 +199,7 @@ func (m *MinioStorage) Save(path string, r io.Reader) (int64, error) {
 		m.buildMinioPath(path),
 		r,
 		-1,
-		minio.PutObjectOptions{ContentType: "application/octet-stream"},
+		minio.PutObjectOptions{ContentType: "application/octet-stream", ServerSideEncryption: m.sse},
 	)
 	if err != nil {
 		return 0, convertMinioErr(err)
@@ -180,7 +241,7 @@ func (m *MinioStorage) Stat(path string) (os.FileInfo, error) {
 		m.ctx,
 		m.bucket,
 		m.buildMinioPath(path),
-		minio.StatObjectOptions{},
+		m.getObjectOptions(),
 	)
 	if err != nil {
 		return nil, convertMinioErr(err)
@@ -197,6 +258,10 @@ func (m *MinioStorage) Delete(path string) error {
 
 // URL gets the redirect URL to a file. The presigned link is valid for 5 minutes.
 func (m *MinioStorage) URL(path, name string) (*url.URL, error) {
+	if m.isSSEC() {
+		// the key would have to be given to the client
+		return nil, ErrURLNotSupported
+	}
 	reqParams := make(url.Values)
 	// TODO it may be good to embed images with 'inline' like ServeData does, but we don't want to have to read the file, do we?
 	reqParams.Set("response-content-disposition", "attachment; filename=\""+quoteEscaper.Replace(name)+"\"")
@@ -204,9 +269,45 @@ func (m *MinioStorage) URL(path, name string) (*url.URL, error) {
 	return u, convertMinioErr(err)
 }
 
+// UploadURL gets a presigned request uploading a file, the encryption headers are part of the signature
+func (m *MinioStorage) UploadURL(path string, expiry time.Duration) (*PresignedUpload, error) {
+	if m.isSSEC() {
+		// the key would have to be given to the client
+		return nil, ErrUploadURLNotSupported
+	}
+	u, err := m.client.PresignedPutObject(m.ctx, m.bucket, m.buildMinioPath(path), expiry)
+	if err != nil {
+		return nil, convertMinioErr(err)
+	}
+	upload := &PresignedUpload{Method: http.MethodPut, URL: u, Header: map[string]string{}}
+	if m.sse == nil {
+		return upload, nil
+	}
+
+	// Sign the request again with the encryption headers, which are only signed when sent as headers
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(key, "X-Amz-") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequest(http.MethodPut, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	m.sse.Marshal(req.Header)
+	for key := range req.Header {
+		upload.Header[key] = req.Header.Get(key)
+	}
+	req = signer.PreSignV4(*req, m.config.AccessKeyID, m.config.SecretAccessKey, "", m.config.Location, int64(expiry/time.Second))
+	upload.URL = req.URL
+	return upload, nil
+}
+
 // IterateObjects iterates across the objects in the miniostorage
 func (m *MinioStorage) IterateObjects(fn func(path string, obj Object) error) error {
-	var opts = minio.GetObjectOptions{}
+	var opts = m.getObjectOptions()
 	lobjectCtx, cancel := context.WithCancel(m.ctx)
 	defer cancel()
 	for mObjInfo := range m.client.ListObjects(lobjectCtx, m.bucket, minio.ListObjectsOptions{
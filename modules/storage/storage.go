@@ -11,6 +11,7 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -21,6 +22,8 @@ var (
 	ErrURLNotSupported = errors.New("url method not supported")
 	// ErrIterateObjectsNotSupported represents IterateObjects not supported
 	ErrIterateObjectsNotSupported = errors.New("iterateObjects method not supported")
+	// ErrUploadURLNotSupported represents UploadURL not supported
+	ErrUploadURLNotSupported = errors.New("uploadURL method not supported")
 )
 
 // ErrInvalidConfiguration is called when there is invalid configuration for a storage
@@ -62,6 +65,14 @@ type Object interface {
 	Stat() (os.FileInfo, error)
 }
 
+// PresignedUpload represents a request uploading the content of an object directly to the storage
+type PresignedUpload struct {
+	Method string
+	URL    *url.URL
+	// Header are the headers the request must be sent with
+	Header map[string]string
+}
+
 // ObjectStorage represents an object storage to handle a bucket and files
 type ObjectStorage interface {
 	Open(path string) (Object, error)
@@ -69,6 +80,7 @@ type ObjectStorage interface {
 	Stat(path string) (os.FileInfo, error)
 	Delete(path string) error
 	URL(path, name string) (*url.URL, error)
+	UploadURL(path string, expiry time.Duration) (*PresignedUpload, error)
 	IterateObjects(func(path string, obj Object) error) error
 }
 
@@ -13,4 +13,33 @@ type Cron struct {
 	Next      time.Time `json:"next"`
 	Prev      time.Time `json:"prev"`
 	ExecTimes int64     `json:"exec_times"`
+	Enabled   bool      `json:"enabled"`
+	// whether the settings of the task have been changed at runtime, overriding the configuration
+	Overridden bool     `json:"overridden"`
+	LastRun    *CronRun `json:"last_run"`
+}
+
+// CronRun represents a run of a Cron task
+type CronRun struct {
+	ID int64 `json:"id"`
+	// running, succeeded, failed or cancelled
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	// name of the user who ran the task, empty if run by the scheduler
+	Doer string `json:"doer"`
+	// the Gitea instance which ran the task
+	Instance string `json:"instance"`
+	// swagger:strfmt date-time
+	Started time.Time `json:"started"`
+	// swagger:strfmt date-time
+	Ended *time.Time `json:"ended"`
+	// duration of the run in seconds
+	Duration float64 `json:"duration"`
+}
+
+// EditCronOption options for changing the settings of a Cron task at runtime
+type EditCronOption struct {
+	Enabled *bool `json:"enabled"`
+	// cron syntax schedule, e.g. @every 1h or 0 30 * * * *
+	Schedule *string `json:"schedule"`
 }
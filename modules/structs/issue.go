@@ -58,6 +58,10 @@ type Issue struct {
 	State    StateType `json:"state"`
 	IsLocked bool      `json:"is_locked"`
 	Comments int       `json:"comments"`
+	// position of the issue in the pinned issues of the repository, 0 if it is not pinned
+	PinOrder int `json:"pin_order"`
+	// estimated time to resolve the issue in seconds, 0 if not estimated
+	EstimatedTime int64 `json:"estimated_time"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -105,6 +109,8 @@ type EditIssueOption struct {
 	// swagger:strfmt date-time
 	Deadline       *time.Time `json:"due_date"`
 	RemoveDeadline *bool      `json:"unset_due_date"`
+	// estimated time to resolve the issue in seconds, 0 removes the estimate
+	EstimatedTime *int64 `json:"estimated_time"`
 }
 
 // EditDeadlineOption options for creating a deadline
@@ -121,6 +127,27 @@ type IssueDeadline struct {
 	Deadline *time.Time `json:"due_date"`
 }
 
+// IssueParentOption options for setting the parent issue of an issue
+type IssueParentOption struct {
+	// owner of the repository of the parent issue, defaults to the owner of the repository of the issue
+	Owner string `json:"owner"`
+	// name of the repository of the parent issue, defaults to the repository of the issue
+	Repo string `json:"repo"`
+	// index of the parent issue
+	// required:true
+	Index int64 `json:"index" binding:"Required"`
+}
+
+// TransferIssueOption options for transferring an issue to another repository
+type TransferIssueOption struct {
+	// owner of the repository to transfer the issue to
+	// required:true
+	Owner string `json:"owner" binding:"Required"`
+	// name of the repository to transfer the issue to
+	// required:true
+	Repo string `json:"repo" binding:"Required"`
+}
+
 // IssueTemplate represents an issue template for a repository
 // swagger:model
 type IssueTemplate struct {
@@ -13,7 +13,9 @@ type Label struct {
 	// example: 00aabb
 	Color       string `json:"color"`
 	Description string `json:"description"`
-	URL         string `json:"url"`
+	// an issue has at most one of the exclusive labels of a scope, the part of the name before the last "/"
+	Exclusive bool   `json:"exclusive"`
+	URL       string `json:"url"`
 }
 
 // CreateLabelOption options for creating a label
@@ -24,6 +26,7 @@ type CreateLabelOption struct {
 	// example: #00aabb
 	Color       string `json:"color" binding:"Required"`
 	Description string `json:"description"`
+	Exclusive   bool   `json:"exclusive"`
 }
 
 // EditLabelOption options for editing a label
@@ -31,6 +34,7 @@ type EditLabelOption struct {
 	Name        *string `json:"name"`
 	Color       *string `json:"color"`
 	Description *string `json:"description"`
+	Exclusive   *bool   `json:"exclusive"`
 }
 
 // IssueLabelsOption a collection of labels
@@ -43,3 +43,24 @@ type EditMilestoneOption struct {
 	State       *string    `json:"state"`
 	Deadline    *time.Time `json:"due_on"`
 }
+
+// MilestoneSnapshot represents the state of a milestone at the start of a day
+type MilestoneSnapshot struct {
+	// swagger:strfmt date-time
+	Day          time.Time `json:"day"`
+	OpenIssues   int       `json:"open_issues"`
+	ClosedIssues int       `json:"closed_issues"`
+	// tracked time of the issues of the milestone in seconds
+	TrackedTime int64 `json:"tracked_time"`
+}
+
+// MilestoneVelocity represents how fast the issues of a closed milestone were completed
+type MilestoneVelocity struct {
+	Milestone *Milestone `json:"milestone"`
+	// number of days between the creation and the closing of the milestone
+	Days         int64 `json:"days"`
+	ClosedIssues int   `json:"closed_issues"`
+	// tracked time of the issues of the milestone in seconds
+	TrackedTime   int64   `json:"tracked_time"`
+	IssuesPerWeek float64 `json:"issues_per_week"`
+}
@@ -62,6 +62,10 @@ type PullReviewComment struct {
 	DiffHunk     string `json:"diff_hunk"`
 	LineNum      uint64 `json:"position"`
 	OldLineNum   uint64 `json:"original_position"`
+	// first line of a multi-line comment or 0
+	StartLineNum uint64 `json:"start_position"`
+	// first original line of a multi-line comment or 0
+	OldStartLineNum uint64 `json:"original_start_position"`
 
 	HTMLURL     string `json:"html_url"`
 	HTMLPullURL string `json:"pull_request_url"`
@@ -84,6 +88,10 @@ type CreatePullReviewComment struct {
 	OldLineNum int64 `json:"old_position"`
 	// if comment to new file line or 0
 	NewLineNum int64 `json:"new_position"`
+	// first old file line of a multi-line comment or 0
+	OldStartLineNum int64 `json:"old_start_position"`
+	// first new file line of a multi-line comment or 0
+	NewStartLineNum int64 `json:"new_start_position"`
 }
 
 // SubmitPullReviewOptions are options to submit a pending pull review
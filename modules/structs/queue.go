@@ -0,0 +1,29 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// Queue represents a queue of the running process
+type Queue struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	ExemplarType string `json:"exemplar_type"`
+	// -1 if the queue has no worker pool
+	NumberOfWorkers    int `json:"number_of_workers"`
+	MaxNumberOfWorkers int `json:"max_number_of_workers"`
+	// -1 if the queue cannot count its items
+	NumberInQueue int64 `json:"number_in_queue"`
+	Pausable      bool  `json:"pausable"`
+	Paused        bool  `json:"paused"`
+	// whether the persisted items of the queue can be listed and removed
+	Inspectable bool `json:"inspectable"`
+}
+
+// QueueItem represents an item waiting in a queue
+type QueueItem struct {
+	Index int `json:"index"`
+	// the JSON encoded item
+	Data string `json:"data"`
+}
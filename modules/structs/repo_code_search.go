@@ -0,0 +1,40 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// CodeSearchResult a code search hit in a file of a repository
+type CodeSearchResult struct {
+	RepoID       int64  `json:"repo_id"`
+	RepoFullName string `json:"repo_full_name"`
+	// full name of the branch or tag, empty for the default branch
+	Ref      string `json:"ref"`
+	Filename string `json:"filename"`
+	CommitID string `json:"commit_id"`
+	Language string `json:"language"`
+	// line numbers of the lines in content
+	LineNumbers []int  `json:"line_numbers"`
+	Content     string `json:"content"`
+	HTMLURL     string `json:"html_url"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated"`
+}
+
+// CodeSearchLanguage number of code search hits for a language
+type CodeSearchLanguage struct {
+	Language string `json:"language"`
+	Color    string `json:"color"`
+	Count    int    `json:"count"`
+}
+
+// CodeSearchResults results of a code search
+type CodeSearchResults struct {
+	TotalCount int                   `json:"total_count"`
+	Data       []*CodeSearchResult   `json:"data"`
+	Languages  []*CodeSearchLanguage `json:"languages"`
+}
@@ -0,0 +1,396 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Options represents the configuration of the tracer
+type Options struct {
+	ServiceName    string
+	ServiceVersion string
+	// Exporter is either "otlp" to send the spans to an OTLP/HTTP collector at Endpoint
+	// or "file" to append them to FilePath, one OTLP JSON request per line
+	Exporter      string
+	Endpoint      string
+	FilePath      string
+	SampleRatio   float64
+	BatchSize     int
+	QueueLength   int
+	FlushInterval time.Duration
+	Timeout       time.Duration
+}
+
+type exporter interface {
+	Export(data []byte) error
+	Close() error
+}
+
+var (
+	enabled int32
+
+	lock    sync.RWMutex
+	current *processor
+)
+
+// IsEnabled returns true if tracing has been initialized
+func IsEnabled() bool {
+	return atomic.LoadInt32(&enabled) == 1
+}
+
+func sampleRatio() float64 {
+	lock.RLock()
+	defer lock.RUnlock()
+	if current == nil {
+		return 0
+	}
+	return current.opts.SampleRatio
+}
+
+// Init starts exporting spans as configured by the options
+func Init(opts Options) error {
+	var exp exporter
+	switch opts.Exporter {
+	case "otlp":
+		exp = &otlpHTTPExporter{
+			endpoint: opts.Endpoint,
+			client: &http.Client{
+				Timeout: opts.Timeout,
+			},
+		}
+	case "file":
+		if err := os.MkdirAll(filepath.Dir(opts.FilePath), os.ModePerm); err != nil {
+			return fmt.Errorf("unable to create directory for %s: %v", opts.FilePath, err)
+		}
+		f, err := os.OpenFile(opts.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("unable to open %s: %v", opts.FilePath, err)
+		}
+		exp = &fileExporter{w: f}
+	default:
+		return fmt.Errorf("unknown tracing exporter: %s", opts.Exporter)
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 512
+	}
+	if opts.QueueLength < opts.BatchSize {
+		opts.QueueLength = 4 * opts.BatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+
+	p := &processor{
+		opts:     opts,
+		exporter: exp,
+		queue:    make(chan *Span, opts.QueueLength),
+		stop:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+
+	Shutdown()
+	lock.Lock()
+	current = p
+	lock.Unlock()
+	go p.run()
+	atomic.StoreInt32(&enabled, 1)
+	return nil
+}
+
+// Shutdown stops tracing and exports the spans which have not yet been exported
+func Shutdown() {
+	atomic.StoreInt32(&enabled, 0)
+	lock.Lock()
+	p := current
+	current = nil
+	lock.Unlock()
+	if p == nil {
+		return
+	}
+	close(p.stop)
+	<-p.finished
+}
+
+// export queues the ended span for export, dropping it if the queue is full
+func export(span *Span) {
+	lock.RLock()
+	p := current
+	lock.RUnlock()
+	if p == nil {
+		return
+	}
+	select {
+	case p.queue <- span:
+	case <-p.stop:
+	default:
+		atomic.AddInt64(&p.dropped, 1)
+	}
+}
+
+// processor batches the ended spans and passes them to the exporter
+type processor struct {
+	opts     Options
+	exporter exporter
+	queue    chan *Span
+	stop     chan struct{}
+	finished chan struct{}
+	dropped  int64
+	failing  bool
+}
+
+func (p *processor) run() {
+	defer close(p.finished)
+	ticker := time.NewTicker(p.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, p.opts.BatchSize)
+	for {
+		select {
+		case span := <-p.queue:
+			batch = append(batch, span)
+			if len(batch) >= p.opts.BatchSize {
+				p.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			p.flush(batch)
+			batch = batch[:0]
+		case <-p.stop:
+		drain:
+			for {
+				select {
+				case span := <-p.queue:
+					batch = append(batch, span)
+				default:
+					break drain
+				}
+			}
+			p.flush(batch)
+			if err := p.exporter.Close(); err != nil {
+				log.Error("Unable to close the tracing exporter: %v", err)
+			}
+			return
+		}
+	}
+}
+
+func (p *processor) flush(batch []*Span) {
+	if dropped := atomic.SwapInt64(&p.dropped, 0); dropped > 0 {
+		log.Warn("Tracing queue is full: %d spans have been dropped", dropped)
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(p.encode(batch))
+	if err != nil {
+		log.Error("Unable to encode %d spans: %v", len(batch), err)
+		return
+	}
+
+	// Only log when the exporter starts or stops failing to prevent flooding the logs when a collector is down
+	if err := p.exporter.Export(data); err != nil {
+		if !p.failing {
+			log.Error("Unable to export %d spans: %v", len(batch), err)
+			p.failing = true
+		}
+		return
+	}
+	if p.failing {
+		log.Info("Exporting spans has recovered")
+		p.failing = false
+	}
+}
+
+type otlpHTTPExporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+// Export posts the data to the OTLP/HTTP endpoint
+func (e *otlpHTTPExporter) Export(data []byte) error {
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected response from %s: %s", e.endpoint, resp.Status)
+	}
+	return nil
+}
+
+// Close does nothing
+func (e *otlpHTTPExporter) Close() error {
+	return nil
+}
+
+type fileExporter struct {
+	mu sync.Mutex
+	w  io.WriteCloser
+}
+
+// Export appends the data to the file as a line
+func (e *fileExporter) Export(data []byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err := e.w.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the file
+func (e *fileExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.w.Close()
+}
+
+// The following types are the JSON encoding of an OTLP ExportTraceServiceRequest
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+const otlpStatusError = 2
+
+func (p *processor) encode(batch []*Span) *otlpRequest {
+	resourceAttributes := []otlpKeyValue{newOTLPKeyValue("service.name", p.opts.ServiceName)}
+	if p.opts.ServiceVersion != "" {
+		resourceAttributes = append(resourceAttributes, newOTLPKeyValue("service.version", p.opts.ServiceVersion))
+	}
+
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, span := range batch {
+		span.mu.Lock()
+		s := otlpSpan{
+			TraceID:           span.traceID.String(),
+			SpanID:            span.spanID.String(),
+			Name:              span.name,
+			Kind:              span.kind,
+			StartTimeUnixNano: strconv.FormatInt(span.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.end.UnixNano(), 10),
+		}
+		if span.parentSpanID.IsValid() {
+			s.ParentSpanID = span.parentSpanID.String()
+		}
+		for _, attr := range span.attributes {
+			s.Attributes = append(s.Attributes, newOTLPKeyValue(attr.key, attr.value))
+		}
+		if span.isError {
+			s.Status = otlpStatus{
+				Code:    otlpStatusError,
+				Message: span.err,
+			}
+		}
+		span.mu.Unlock()
+		spans = append(spans, s)
+	}
+
+	return &otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: resourceAttributes,
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{
+					Name:    "code.gitea.io/gitea",
+					Version: p.opts.ServiceVersion,
+				},
+				Spans: spans,
+			}},
+		}},
+	}
+}
+
+func newOTLPKeyValue(key string, value interface{}) otlpKeyValue {
+	var v otlpAnyValue
+	switch value := value.(type) {
+	case string:
+		v.StringValue = &value
+	case bool:
+		v.BoolValue = &value
+	case int:
+		i := strconv.FormatInt(int64(value), 10)
+		v.IntValue = &i
+	case int64:
+		i := strconv.FormatInt(value, 10)
+		v.IntValue = &i
+	case float64:
+		v.DoubleValue = &value
+	default:
+		s := fmt.Sprintf("%v", value)
+		v.StringValue = &s
+	}
+	return otlpKeyValue{Key: key, Value: v}
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// TraceParentHeader is the W3C Trace Context header used to propagate traces over HTTP
+	TraceParentHeader = "traceparent"
+	// TraceParentEnv is the environment variable used to propagate traces to child processes and hooks
+	TraceParentEnv = "TRACEPARENT"
+)
+
+// TraceParent returns the W3C traceparent value for the span carried by the context or "" if there is none
+func TraceParent(ctx context.Context) string {
+	span := SpanFromContext(ctx)
+	if span == nil {
+		return ""
+	}
+	flags := 0
+	if span.sampled {
+		flags = 1
+	}
+	return fmt.Sprintf("00-%s-%s-%02x", span.traceID, span.spanID, flags)
+}
+
+// ContextWithTraceParent returns a copy of the parent context carrying the remote span described by
+// the W3C traceparent value, so that spans started from it become its children. Invalid values are ignored.
+func ContextWithTraceParent(parent context.Context, traceParent string) context.Context {
+	if !IsEnabled() || traceParent == "" {
+		return parent
+	}
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return parent
+	}
+	// Version 00 has exactly four parts
+	if parts[0] == "00" && len(parts) != 4 {
+		return parent
+	}
+
+	span := &Span{remote: true}
+	if _, err := hex.Decode(span.traceID[:], []byte(parts[1])); err != nil || !span.traceID.IsValid() {
+		return parent
+	}
+	if _, err := hex.Decode(span.spanID[:], []byte(parts[2])); err != nil || !span.spanID.IsValid() {
+		return parent
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return parent
+	}
+	span.sampled = flags[0]&1 == 1
+
+	return context.WithValue(parent, spanContextKey{}, span)
+}
+
+// Inject sets the traceparent header for the span carried by the context
+func Inject(ctx context.Context, header http.Header) {
+	if traceParent := TraceParent(ctx); traceParent != "" {
+		header.Set(TraceParentHeader, traceParent)
+	}
+}
+
+// Extract returns a copy of the parent context carrying the remote span from the traceparent header, if any
+func Extract(parent context.Context, header http.Header) context.Context {
+	return ContextWithTraceParent(parent, header.Get(TraceParentHeader))
+}
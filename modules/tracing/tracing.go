@@ -0,0 +1,224 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// TraceID identifies a trace
+type TraceID [16]byte
+
+// IsValid returns true if the TraceID is not all zeros
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+// IsValid returns true if the SpanID is not all zeros
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// SpanKind describes the relationship between a span, its parent and its children.
+// The values match the OTLP SpanKind enumeration.
+type SpanKind int
+
+// SpanKinds
+const (
+	SpanKindInternal SpanKind = iota + 1
+	SpanKindServer
+	SpanKindClient
+	SpanKindProducer
+	SpanKindConsumer
+)
+
+type attribute struct {
+	key   string
+	value interface{}
+}
+
+// Span represents a single timed operation within a trace.
+// A nil *Span is valid and all its methods do nothing, which is what Start
+// returns when tracing is not enabled.
+type Span struct {
+	mu sync.Mutex
+
+	traceID      TraceID
+	spanID       SpanID
+	parentSpanID SpanID
+	sampled      bool
+	remote       bool
+
+	name       string
+	kind       SpanKind
+	start      time.Time
+	end        time.Time
+	attributes []attribute
+	err        string
+	isError    bool
+	ended      bool
+}
+
+type spanContextKey struct{}
+
+// Start starts a new span as a child of the span carried by the context, if there is one,
+// and returns a context carrying the new span. The span must be ended with End.
+func Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	if !IsEnabled() {
+		return ctx, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	span := &Span{
+		name:  name,
+		kind:  kind,
+		start: time.Now(),
+	}
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.traceID = parent.traceID
+		span.parentSpanID = parent.spanID
+		span.sampled = parent.sampled
+	} else {
+		span.traceID = newTraceID()
+		span.sampled = shouldSample(span.traceID)
+	}
+	span.spanID = newSpanID()
+
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// StartChild starts a new span like Start but only if the context carries a span,
+// the operations run outside of a traced request or task don't each start their own trace.
+func StartChild(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	if SpanFromContext(ctx) == nil {
+		return ctx, nil
+	}
+	return Start(ctx, name, kind)
+}
+
+// SpanFromContext returns the span carried by the context or nil if there is none
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// TraceID returns the ID of the trace this span belongs to
+func (s *Span) TraceID() TraceID {
+	if s == nil {
+		return TraceID{}
+	}
+	return s.traceID
+}
+
+// SpanID returns the ID of this span
+func (s *Span) SpanID() SpanID {
+	if s == nil {
+		return SpanID{}
+	}
+	return s.spanID
+}
+
+// IsSampled returns true if this span will be exported
+func (s *Span) IsSampled() bool {
+	return s != nil && s.sampled
+}
+
+// SetName changes the name of the span
+func (s *Span) SetName(name string) {
+	if !s.IsSampled() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.name = name
+}
+
+// SetAttribute sets an attribute on the span. Values may be strings, bools, integers or floats,
+// anything else will be recorded as a string.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if !s.IsSampled() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.attributes {
+		if s.attributes[i].key == key {
+			s.attributes[i].value = value
+			return
+		}
+	}
+	s.attributes = append(s.attributes, attribute{key: key, value: value})
+}
+
+// SetError marks the span as failed with the provided error, a nil error is ignored
+func (s *Span) SetError(err error) {
+	if err == nil || !s.IsSampled() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.isError = true
+	s.err = err.Error()
+}
+
+// End ends the span and queues it to be exported
+func (s *Span) End() {
+	if !s.IsSampled() || s.remote {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	export(s)
+}
+
+func newTraceID() (id TraceID) {
+	_, _ = rand.Read(id[:])
+	return
+}
+
+func newSpanID() (id SpanID) {
+	_, _ = rand.Read(id[:])
+	return
+}
+
+// shouldSample decides whether a new trace is sampled, consistently for a given trace ID
+func shouldSample(traceID TraceID) bool {
+	ratio := sampleRatio()
+	if ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+	return binary.BigEndian.Uint64(traceID[8:])>>1 < uint64(ratio*(1<<63))
+}
@@ -0,0 +1,238 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisabled(t *testing.T) {
+	Shutdown()
+	assert.False(t, IsEnabled())
+
+	ctx := context.Background()
+	spanCtx, span := Start(ctx, "test", SpanKindInternal)
+	assert.Nil(t, span)
+	assert.Equal(t, ctx, spanCtx)
+
+	// Methods on a nil span must not panic
+	span.SetName("renamed")
+	span.SetAttribute("key", "value")
+	span.SetError(errors.New("error"))
+	span.End()
+	assert.False(t, span.IsSampled())
+	assert.Equal(t, "", TraceParent(spanCtx))
+}
+
+func readExported(t *testing.T, path string) []otlpSpan {
+	f, err := os.Open(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	var spans []otlpSpan
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var req otlpRequest
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &req))
+		assert.Len(t, req.ResourceSpans, 1)
+		assert.Equal(t, "service.name", req.ResourceSpans[0].Resource.Attributes[0].Key)
+		assert.Equal(t, "gitea-test", *req.ResourceSpans[0].Resource.Attributes[0].Value.StringValue)
+		for _, scopeSpans := range req.ResourceSpans[0].ScopeSpans {
+			spans = append(spans, scopeSpans.Spans...)
+		}
+	}
+	assert.NoError(t, scanner.Err())
+	return spans
+}
+
+func TestFileExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracing")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "traces", "traces.json")
+
+	assert.NoError(t, Init(Options{
+		ServiceName: "gitea-test",
+		Exporter:    "file",
+		FilePath:    path,
+		SampleRatio: 1,
+	}))
+	assert.True(t, IsEnabled())
+
+	ctx, parent := Start(context.Background(), "parent", SpanKindServer)
+	assert.True(t, parent.IsSampled())
+	parent.SetAttribute("http.method", "GET")
+	parent.SetAttribute("http.status_code", 200)
+
+	_, child := Start(ctx, "child", SpanKindClient)
+	assert.Equal(t, parent.TraceID(), child.TraceID())
+	child.SetAttribute("db.statement", "SELECT 1")
+	child.SetError(errors.New("failed"))
+	child.End()
+	child.End()
+	parent.End()
+
+	Shutdown()
+	assert.False(t, IsEnabled())
+
+	spans := readExported(t, path)
+	assert.Len(t, spans, 2)
+
+	assert.Equal(t, "child", spans[0].Name)
+	assert.Equal(t, SpanKindClient, spans[0].Kind)
+	assert.Equal(t, parent.SpanID().String(), spans[0].ParentSpanID)
+	assert.Equal(t, parent.TraceID().String(), spans[0].TraceID)
+	assert.Equal(t, otlpStatus{Code: otlpStatusError, Message: "failed"}, spans[0].Status)
+	assert.Equal(t, "SELECT 1", *spans[0].Attributes[0].Value.StringValue)
+
+	assert.Equal(t, "parent", spans[1].Name)
+	assert.Equal(t, "", spans[1].ParentSpanID)
+	assert.Equal(t, "GET", *spans[1].Attributes[0].Value.StringValue)
+	assert.Equal(t, "200", *spans[1].Attributes[1].Value.IntValue)
+	assert.Equal(t, otlpStatus{}, spans[1].Status)
+}
+
+func TestOTLPHTTPExporter(t *testing.T) {
+	received := make(chan otlpRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var req otlpRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		received <- req
+	}))
+	defer server.Close()
+
+	assert.NoError(t, Init(Options{
+		ServiceName: "gitea-test",
+		Exporter:    "otlp",
+		Endpoint:    server.URL + "/v1/traces",
+		SampleRatio: 1,
+	}))
+
+	_, span := Start(context.Background(), "test", SpanKindInternal)
+	span.End()
+	Shutdown()
+
+	req := <-received
+	assert.Equal(t, "test", req.ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+	assert.Equal(t, span.TraceID().String(), req.ResourceSpans[0].ScopeSpans[0].Spans[0].TraceID)
+}
+
+func TestSampling(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracing")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "traces.json")
+
+	assert.NoError(t, Init(Options{
+		ServiceName: "gitea-test",
+		Exporter:    "file",
+		FilePath:    path,
+		SampleRatio: 0,
+	}))
+
+	ctx, span := Start(context.Background(), "unsampled", SpanKindInternal)
+	assert.NotNil(t, span)
+	assert.False(t, span.IsSampled())
+	_, child := Start(ctx, "child", SpanKindInternal)
+	assert.False(t, child.IsSampled())
+	child.End()
+	span.End()
+
+	// A sampled remote parent is followed whatever the ratio
+	ctx = ContextWithTraceParent(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, child = Start(ctx, "remote child", SpanKindServer)
+	assert.True(t, child.IsSampled())
+	child.End()
+
+	Shutdown()
+
+	spans := readExported(t, path)
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "remote child", spans[0].Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].ParentSpanID)
+}
+
+func TestStartChild(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracing")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, Init(Options{
+		ServiceName: "gitea-test",
+		Exporter:    "file",
+		FilePath:    filepath.Join(dir, "traces.json"),
+		SampleRatio: 1,
+	}))
+	defer Shutdown()
+
+	// No trace is started for the operations run outside of a traced context
+	ctx := context.Background()
+	childCtx, child := StartChild(ctx, "orphan", SpanKindClient)
+	assert.Nil(t, child)
+	assert.Equal(t, ctx, childCtx)
+
+	ctx, span := Start(ctx, "request", SpanKindServer)
+	defer span.End()
+	_, child = StartChild(ctx, "child", SpanKindClient)
+	assert.NotNil(t, child)
+	assert.Equal(t, span.TraceID(), child.TraceID())
+	assert.Equal(t, span.SpanID(), child.parentSpanID)
+	child.End()
+}
+
+func TestPropagation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracing")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, Init(Options{
+		ServiceName: "gitea-test",
+		Exporter:    "file",
+		FilePath:    filepath.Join(dir, "traces.json"),
+		SampleRatio: 1,
+	}))
+	defer Shutdown()
+
+	for _, invalid := range []string{
+		"",
+		"garbage",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-zzf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		assert.Nil(t, SpanFromContext(ContextWithTraceParent(context.Background(), invalid)), invalid)
+	}
+
+	ctx, span := Start(context.Background(), "test", SpanKindInternal)
+	defer span.End()
+
+	header := http.Header{}
+	Inject(ctx, header)
+	traceParent := header.Get(TraceParentHeader)
+	assert.Equal(t, "00-"+span.TraceID().String()+"-"+span.SpanID().String()+"-01", traceParent)
+
+	remote := SpanFromContext(Extract(context.Background(), header))
+	assert.NotNil(t, remote)
+	assert.Equal(t, span.TraceID(), remote.TraceID())
+	assert.Equal(t, span.SpanID(), remote.SpanID())
+	assert.True(t, remote.IsSampled())
+}
@@ -79,6 +79,10 @@ func AddUploadContext(ctx *context.Context, uploadType string) {
 		ctx.Data["UploadAccepts"] = strings.ReplaceAll(setting.Repository.Release.AllowedTypes, "|", ",")
 		ctx.Data["UploadMaxFiles"] = setting.Attachment.MaxFiles
 		ctx.Data["UploadMaxSize"] = setting.Attachment.MaxSize
+		if setting.Attachment.UploadDirect {
+			ctx.Data["UploadPresignUrl"] = ctx.Repo.RepoLink + "/releases/attachments/presign"
+			ctx.Data["UploadCompleteUrl"] = ctx.Repo.RepoLink + "/releases/attachments/complete"
+		}
 	} else if uploadType == "comment" {
 		ctx.Data["UploadUrl"] = ctx.Repo.RepoLink + "/issues/attachments"
 		ctx.Data["UploadRemoveUrl"] = ctx.Repo.RepoLink + "/issues/attachments/remove"
@@ -90,6 +94,10 @@ func AddUploadContext(ctx *context.Context, uploadType string) {
 		ctx.Data["UploadAccepts"] = strings.ReplaceAll(setting.Attachment.AllowedTypes, "|", ",")
 		ctx.Data["UploadMaxFiles"] = setting.Attachment.MaxFiles
 		ctx.Data["UploadMaxSize"] = setting.Attachment.MaxSize
+		if setting.Attachment.UploadDirect {
+			ctx.Data["UploadPresignUrl"] = ctx.Repo.RepoLink + "/issues/attachments/presign"
+			ctx.Data["UploadCompleteUrl"] = ctx.Repo.RepoLink + "/issues/attachments/complete"
+		}
 	} else if uploadType == "repo" {
 		ctx.Data["UploadUrl"] = ctx.Repo.RepoLink + "/upload-file"
 		ctx.Data["UploadRemoveUrl"] = ctx.Repo.RepoLink + "/upload-remove"
@@ -100,3 +100,34 @@ func NormalizeEOL(input []byte) []byte {
 	}
 	return tmp[:pos]
 }
+
+// SplitQuotedTerms splits a search query on whitespaces, except inside double quotes.
+// The quotes are removed from the terms.
+func SplitQuotedTerms(query string) []string {
+	var (
+		terms   []string
+		current strings.Builder
+		quoted  bool
+		hasTerm bool
+	)
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			hasTerm = true
+		case !quoted && (r == ' ' || r == '\t' || r == '\n' || r == '\r'):
+			if hasTerm {
+				terms = append(terms, current.String())
+				current.Reset()
+				hasTerm = false
+			}
+		default:
+			current.WriteRune(r)
+			hasTerm = true
+		}
+	}
+	if hasTerm {
+		terms = append(terms, current.String())
+	}
+	return terms
+}
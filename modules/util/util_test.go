@@ -118,3 +118,12 @@ func Test_NormalizeEOL(t *testing.T) {
 
 	assert.Equal(t, []byte("mix\nand\nmatch\n."), NormalizeEOL([]byte("mix\r\nand\rmatch\n.")))
 }
+
+func TestSplitQuotedTerms(t *testing.T) {
+	assert.Empty(t, SplitQuotedTerms(""))
+	assert.Empty(t, SplitQuotedTerms(" \t\n"))
+	assert.Equal(t, []string{"a", "b"}, SplitQuotedTerms("  a \t b\n"))
+	assert.Equal(t, []string{"label:a b", "c"}, SplitQuotedTerms(`label:"a b" c`))
+	assert.Equal(t, []string{"", "d"}, SplitQuotedTerms(`"" d`))
+	assert.Equal(t, []string{"unterminated quote"}, SplitQuotedTerms(`"unterminated quote`))
+}
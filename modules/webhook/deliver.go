@@ -17,9 +17,11 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/cluster"
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/tracing"
 	"github.com/gobwas/glob"
 	"github.com/unknwon/com"
 )
@@ -91,6 +93,17 @@ func Deliver(t *models.HookTask) error {
 		return fmt.Errorf("Invalid http method for webhook: [%d] %v", t.ID, t.HTTPMethod)
 	}
 
+	ctx, span := tracing.Start(req.Context(), "webhook deliver", tracing.SpanKindClient)
+	span.SetAttribute("webhook.id", t.HookID)
+	span.SetAttribute("webhook.task_id", t.ID)
+	span.SetAttribute("webhook.type", t.Type.Name())
+	span.SetAttribute("webhook.event", t.EventType.Event())
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("net.peer.name", req.URL.Hostname())
+	defer span.End()
+	req = req.WithContext(ctx)
+	tracing.Inject(ctx, req.Header)
+
 	req.Header.Add("X-Gitea-Delivery", t.UUID)
 	req.Header.Add("X-Gitea-Event", t.EventType.Event())
 	req.Header.Add("X-Gitea-Signature", t.Signature)
@@ -112,8 +125,10 @@ func Deliver(t *models.HookTask) error {
 		Headers: map[string]string{},
 	}
 
+	start := time.Now()
 	defer func() {
 		t.Delivered = time.Now().UnixNano()
+		observeDelivery(t.Type.Name(), t.IsSucceed, start)
 		if t.IsSucceed {
 			log.Trace("Hook delivered: %s", t.UUID)
 		} else {
@@ -143,6 +158,7 @@ func Deliver(t *models.HookTask) error {
 
 	resp, err := webhookHTTPClient.Do(req)
 	if err != nil {
+		span.SetError(err)
 		t.ResponseInfo.Body = fmt.Sprintf("Delivery: %v", err)
 		return err
 	}
@@ -151,6 +167,10 @@ func Deliver(t *models.HookTask) error {
 	// Status code is 20x can be seen as succeed.
 	t.IsSucceed = resp.StatusCode/100 == 2
 	t.ResponseInfo.Status = resp.StatusCode
+	span.SetAttribute("http.status_code", resp.StatusCode)
+	if !t.IsSucceed {
+		span.SetError(fmt.Errorf("unexpected status: %s", resp.Status))
+	}
 	for k, vals := range resp.Header {
 		t.ResponseInfo.Headers[k] = strings.Join(vals, ",")
 	}
@@ -164,6 +184,20 @@ func Deliver(t *models.HookTask) error {
 	return nil
 }
 
+// deliverOnce delivers the hook task unless another instance is delivering it or has already delivered it
+func deliverOnce(ctx context.Context, t *models.HookTask) error {
+	taskLock, err := cluster.TryAcquire(ctx, fmt.Sprintf("hook_task:%d", t.ID))
+	if err != nil || taskLock == nil {
+		return err
+	}
+	defer taskLock.Release()
+
+	if delivered, err := models.IsHookTaskDelivered(t.ID); err != nil || delivered {
+		return err
+	}
+	return Deliver(t)
+}
+
 // DeliverHooks checks and delivers undelivered hooks.
 // FIXME: graceful: This would likely benefit from either a worker pool with dummy queue
 // or a full queue. Then more hooks could be sent at same time.
@@ -186,7 +220,7 @@ func DeliverHooks(ctx context.Context) {
 			return
 		default:
 		}
-		if err = Deliver(t); err != nil {
+		if err = deliverOnce(ctx, t); err != nil {
 			log.Error("deliver: %v", err)
 		}
 	}
@@ -218,7 +252,7 @@ func DeliverHooks(ctx context.Context) {
 					return
 				default:
 				}
-				if err = Deliver(t); err != nil {
+				if err = deliverOnce(ctx, t); err != nil {
 					log.Error("deliver: %v", err)
 				}
 			}
@@ -0,0 +1,32 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var deliveryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "gitea",
+	Subsystem: "webhook",
+	Name:      "delivery_duration_seconds",
+	Help:      "Duration of webhook deliveries by webhook type and status",
+	Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+}, []string{"type", "status"})
+
+func init() {
+	prometheus.MustRegister(deliveryDuration)
+}
+
+// observeDelivery records the duration and outcome of a webhook delivery
+func observeDelivery(hookType string, succeeded bool, start time.Time) {
+	status := "success"
+	if !succeeded {
+		status = "failure"
+	}
+	deliveryDuration.WithLabelValues(hookType, status).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/cron"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplCron base.TplName = "admin/cron"
+)
+
+// CronTask shows the settings and the run history of a cron task
+func CronTask(ctx *context.Context) {
+	task := cron.GetTaskTableRow(ctx.Params(":task"))
+	if task == nil {
+		ctx.NotFound("GetTaskTableRow", nil)
+		return
+	}
+	ctx.Data["Title"] = ctx.Tr("admin.monitor.cron.task", ctx.Tr("admin.dashboard."+task.Name))
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminMonitor"] = true
+	ctx.Data["Task"] = task
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	runs, count, err := models.GetCronTaskRuns(task.Name, models.ListOptions{
+		Page:     page,
+		PageSize: setting.UI.Admin.NoticePagingNum,
+	})
+	if err != nil {
+		ctx.ServerError("GetCronTaskRuns", err)
+		return
+	}
+	ctx.Data["Runs"] = runs
+	ctx.Data["Page"] = context.NewPagination(int(count), setting.UI.Admin.NoticePagingNum, page, 5)
+
+	ctx.HTML(200, tplCron)
+}
+
+// SetCronTask enables or disables a cron task and changes its schedule
+func SetCronTask(ctx *context.Context) {
+	name := ctx.Params(":task")
+	link := setting.AppSubURL + "/admin/monitor/cron/" + name
+	if cron.GetTask(name) == nil {
+		ctx.NotFound("GetTask", nil)
+		return
+	}
+
+	enabled := ctx.QueryBool("enabled")
+	schedule := strings.TrimSpace(ctx.Query("schedule"))
+	if err := cron.UpdateTask(name, enabled, schedule); err != nil {
+		if cron.IsErrInvalidSchedule(err) {
+			ctx.Flash.Error(ctx.Tr("admin.monitor.cron.settings.schedule.error", schedule))
+			ctx.Redirect(link)
+			return
+		}
+		ctx.ServerError("UpdateTask", err)
+		return
+	}
+	log.Info("Cron Task %s changed by admin(%s): enabled %t, schedule %s", name, ctx.User.Name, enabled, schedule)
+
+	ctx.Flash.Success(ctx.Tr("admin.monitor.cron.settings.changed"))
+	ctx.Redirect(link)
+}
+
+// ResetCronTask removes the settings of a cron task changed at runtime
+func ResetCronTask(ctx *context.Context) {
+	name := ctx.Params(":task")
+	if cron.GetTask(name) == nil {
+		ctx.NotFound("GetTask", nil)
+		return
+	}
+	if err := cron.ResetTask(name); err != nil {
+		ctx.ServerError("ResetTask", err)
+		return
+	}
+	log.Info("Cron Task %s reset by admin(%s)", name, ctx.User.Name)
+
+	ctx.Flash.Success(ctx.Tr("admin.monitor.cron.settings.reset_success"))
+	ctx.Redirect(setting.AppSubURL + "/admin/monitor/cron/" + name)
+}
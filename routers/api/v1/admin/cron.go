@@ -5,8 +5,11 @@
 package admin
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/cron"
 	"code.gitea.io/gitea/modules/log"
@@ -43,16 +46,214 @@ func ListCronTasks(ctx *context.APIContext) {
 		tasks = tasks[start:end]
 	}
 
-	res := make([]structs.Cron, len(tasks))
+	res := make([]*structs.Cron, len(tasks))
 	for i, task := range tasks {
-		res[i] = structs.Cron{
-			Name:      task.Name,
-			Schedule:  task.Spec,
-			Next:      task.Next,
-			Prev:      task.Prev,
-			ExecTimes: task.ExecTimes,
+		res[i] = toAPICron(task)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// toAPICron converts a cron.TaskTableRow to a structs.Cron
+func toAPICron(task *cron.TaskTableRow) *structs.Cron {
+	apiCron := &structs.Cron{
+		Name:       task.Name,
+		Schedule:   task.Spec,
+		Next:       task.Next,
+		Prev:       task.Prev,
+		ExecTimes:  task.ExecTimes,
+		Enabled:    task.Enabled,
+		Overridden: task.Overridden,
+	}
+	if task.LastRun != nil {
+		apiCron.LastRun = toAPICronRun(task.LastRun)
+	}
+	return apiCron
+}
+
+// toAPICronRun converts a models.CronTaskRun to a structs.CronRun
+func toAPICronRun(run *models.CronTaskRun) *structs.CronRun {
+	apiRun := &structs.CronRun{
+		ID:       run.ID,
+		Status:   run.Status.String(),
+		Message:  run.Message,
+		Instance: run.Instance,
+		Started:  run.StartedUnix.AsTime(),
+		Duration: run.Duration().Seconds(),
+	}
+	if run.DoerID > 0 {
+		apiRun.Doer = run.DoerName
+	}
+	if run.Status != models.CronTaskRunRunning {
+		ended := run.EndedUnix.AsTime()
+		apiRun.Ended = &ended
+	}
+	return apiRun
+}
+
+// GetCronTask api for getting a cron task
+func GetCronTask(ctx *context.APIContext) {
+	// swagger:operation GET /admin/cron/{task} admin adminCronGet
+	// ---
+	// summary: Get a cron task
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: task
+	//   in: path
+	//   description: name of the task
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Cron"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	task := cron.GetTaskTableRow(ctx.Params(":task"))
+	if task == nil {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, toAPICron(task))
+}
+
+// EditCronTask api for changing the settings of a cron task at runtime
+func EditCronTask(ctx *context.APIContext, form structs.EditCronOption) {
+	// swagger:operation PATCH /admin/cron/{task} admin adminCronEdit
+	// ---
+	// summary: Enable or disable a cron task or change its schedule, overriding the configuration
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: task
+	//   in: path
+	//   description: name of the task
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditCronOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Cron"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	task := cron.GetTaskTableRow(ctx.Params(":task"))
+	if task == nil {
+		ctx.NotFound()
+		return
+	}
+
+	enabled, schedule := task.Enabled, ""
+	if form.Enabled != nil {
+		enabled = *form.Enabled
+	}
+	if form.Schedule != nil {
+		schedule = strings.TrimSpace(*form.Schedule)
+	}
+	if err := cron.UpdateTask(task.Name, enabled, schedule); err != nil {
+		if cron.IsErrInvalidSchedule(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
 		}
+		ctx.Error(http.StatusInternalServerError, "UpdateTask", err)
+		return
+	}
+	log.Info("Cron Task %s changed by admin(%s): enabled %t, schedule %s", task.Name, ctx.User.Name, enabled, schedule)
+
+	ctx.JSON(http.StatusOK, toAPICron(cron.GetTaskTableRow(task.Name)))
+}
+
+// ResetCronTask api for removing the settings of a cron task changed at runtime
+func ResetCronTask(ctx *context.APIContext) {
+	// swagger:operation POST /admin/cron/{task}/reset admin adminCronReset
+	// ---
+	// summary: Remove the settings of a cron task changed at runtime so that the configuration applies again
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: task
+	//   in: path
+	//   description: name of the task
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Cron"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	task := cron.GetTaskTableRow(ctx.Params(":task"))
+	if task == nil {
+		ctx.NotFound()
+		return
+	}
+	if err := cron.ResetTask(task.Name); err != nil {
+		ctx.Error(http.StatusInternalServerError, "ResetTask", err)
+		return
 	}
+	log.Info("Cron Task %s reset by admin(%s)", task.Name, ctx.User.Name)
+
+	ctx.JSON(http.StatusOK, toAPICron(cron.GetTaskTableRow(task.Name)))
+}
+
+// ListCronTaskRuns api for listing the runs of a cron task
+func ListCronTaskRuns(ctx *context.APIContext) {
+	// swagger:operation GET /admin/cron/{task}/runs admin adminCronRuns
+	// ---
+	// summary: List the runs of a cron task, most recent first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: task
+	//   in: path
+	//   description: name of the task
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CronRunList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	task := cron.GetTask(ctx.Params(":task"))
+	if task == nil {
+		ctx.NotFound()
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	runs, count, err := models.GetCronTaskRuns(task.Name, listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCronTaskRuns", err)
+		return
+	}
+	res := make([]*structs.CronRun, len(runs))
+	for i, run := range runs {
+		res[i] = toAPICronRun(run)
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
 	ctx.JSON(http.StatusOK, res)
 }
 
@@ -0,0 +1,350 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// getManagedQueue returns the queue of the qid parameter, it writes the not found error if there is none
+func getManagedQueue(ctx *context.APIContext) *queue.ManagedQueue {
+	mq := queue.GetManager().GetManagedQueue(ctx.ParamsInt64(":qid"))
+	if mq == nil {
+		ctx.NotFound()
+	}
+	return mq
+}
+
+// listSkip returns the number of elements before the requested page
+func listSkip(ctx *context.APIContext) (skip, limit int) {
+	listOpts := utils.GetListOptions(ctx)
+	page := listOpts.Page
+	if page <= 0 {
+		page = 1
+	}
+	return (page - 1) * listOpts.PageSize, listOpts.PageSize
+}
+
+// ListQueues api for listing the queues
+func ListQueues(ctx *context.APIContext) {
+	// swagger:operation GET /admin/queues admin adminListQueues
+	// ---
+	// summary: List the queues of the running process
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/QueueList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	mqs := queue.GetManager().ManagedQueues()
+	skip, limit := listSkip(ctx)
+	if skip > len(mqs) {
+		skip = len(mqs)
+	}
+	if skip+limit < len(mqs) {
+		mqs = mqs[skip : skip+limit]
+	} else {
+		mqs = mqs[skip:]
+	}
+
+	res := make([]*api.Queue, len(mqs))
+	for i, mq := range mqs {
+		res[i] = convert.ToQueue(mq)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetQueue api for getting a queue
+func GetQueue(ctx *context.APIContext) {
+	// swagger:operation GET /admin/queues/{qid} admin adminGetQueue
+	// ---
+	// summary: Get a queue of the running process
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: qid
+	//   in: path
+	//   description: id of the queue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Queue"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	mq := getManagedQueue(ctx)
+	if mq == nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToQueue(mq))
+}
+
+// PauseQueue api for pausing the workers of a queue
+func PauseQueue(ctx *context.APIContext) {
+	// swagger:operation POST /admin/queues/{qid}/pause admin adminPauseQueue
+	// ---
+	// summary: Stop the workers of a queue from taking new items
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: qid
+	//   in: path
+	//   description: id of the queue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	mq := getManagedQueue(ctx)
+	if mq == nil {
+		return
+	}
+	if !mq.IsPausable() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("queue %s cannot be paused", mq.Name))
+		return
+	}
+	mq.Pause()
+	log.Info("Queue %s paused by admin(%s)", mq.Name, ctx.User.Name)
+	ctx.Status(http.StatusNoContent)
+}
+
+// ResumeQueue api for resuming the workers of a queue
+func ResumeQueue(ctx *context.APIContext) {
+	// swagger:operation POST /admin/queues/{qid}/resume admin adminResumeQueue
+	// ---
+	// summary: Resume the workers of a paused queue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: qid
+	//   in: path
+	//   description: id of the queue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	mq := getManagedQueue(ctx)
+	if mq == nil {
+		return
+	}
+	mq.Resume()
+	log.Info("Queue %s resumed by admin(%s)", mq.Name, ctx.User.Name)
+	ctx.Status(http.StatusNoContent)
+}
+
+// FlushQueue api for flushing a queue
+func FlushQueue(ctx *context.APIContext) {
+	// swagger:operation POST /admin/queues/{qid}/flush admin adminFlushQueue
+	// ---
+	// summary: Add a worker flushing a queue, it stops once the queue is empty or it times out
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: qid
+	//   in: path
+	//   description: id of the queue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: timeout
+	//   in: query
+	//   description: timeout of the flush as a duration, e.g. 5m, no timeout if empty
+	//   type: string
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	mq := getManagedQueue(ctx)
+	if mq == nil {
+		return
+	}
+	timeout := time.Duration(-1)
+	if ctx.Query("timeout") != "" {
+		var err error
+		if timeout, err = time.ParseDuration(ctx.Query("timeout")); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+	}
+	go func() {
+		if err := mq.Flush(timeout); err != nil {
+			log.Error("Flushing failure for %s: Error %v", mq.Name, err)
+		}
+	}()
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListQueueItems api for listing the persisted items of a queue
+func ListQueueItems(ctx *context.APIContext) {
+	// swagger:operation GET /admin/queues/{qid}/items admin adminListQueueItems
+	// ---
+	// summary: List the persisted items of a level or redis backed queue, from the next to be handled
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: qid
+	//   in: path
+	//   description: id of the queue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/QueueItemList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	mq := getManagedQueue(ctx)
+	if mq == nil {
+		return
+	}
+	skip, limit := listSkip(ctx)
+	items, err := mq.Items(skip, limit)
+	if err != nil {
+		writeQueueError(ctx, "Items", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToQueueItems(skip, items))
+}
+
+// DeleteQueueItem api for removing a persisted item from a queue
+func DeleteQueueItem(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/queues/{qid}/items/{index} admin adminDeleteQueueItem
+	// ---
+	// summary: Remove a persisted item from a level or redis backed queue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: qid
+	//   in: path
+	//   description: id of the queue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the item in the queue
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	mq := getManagedQueue(ctx)
+	if mq == nil {
+		return
+	}
+	removed, err := mq.RemoveItem(ctx.ParamsInt(":index"))
+	if err != nil {
+		writeQueueError(ctx, "RemoveItem", err)
+		return
+	}
+	if removed == nil {
+		ctx.NotFound()
+		return
+	}
+	log.Info("Item %s removed from queue %s by admin(%s)", removed, mq.Name, ctx.User.Name)
+	ctx.Status(http.StatusNoContent)
+}
+
+// PurgeQueue api for removing all the waiting items of a queue
+func PurgeQueue(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/queues/{qid}/items admin adminPurgeQueue
+	// ---
+	// summary: Remove all the waiting items of a level or redis backed queue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: qid
+	//   in: path
+	//   description: id of the queue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	mq := getManagedQueue(ctx)
+	if mq == nil {
+		return
+	}
+	purged, err := mq.Purge()
+	if err != nil {
+		writeQueueError(ctx, "Purge", err)
+		return
+	}
+	log.Info("%d items purged from queue %s by admin(%s)", purged, mq.Name, ctx.User.Name)
+	ctx.Status(http.StatusNoContent)
+}
+
+func writeQueueError(ctx *context.APIContext, title string, err error) {
+	if queue.IsErrNotInspectable(err) {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+	ctx.Error(http.StatusInternalServerError, title, err)
+}
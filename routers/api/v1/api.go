@@ -635,6 +635,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 
 			m.Get("/issues/search", repo.SearchIssues)
 
+			m.Get("/search/code", repo.SearchCode)
+
 			m.Post("/migrate", reqToken(), bind(api.MigrateRepoOptions{}), repo.Migrate)
 
 			m.Group("/:username/:reponame", func() {
@@ -704,6 +706,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Group("/issues", func() {
 					m.Combo("").Get(repo.ListIssues).
 						Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueOption{}), repo.CreateIssue)
+					m.Get("/pinned", repo.ListPinnedIssues)
 					m.Group("/comments", func() {
 						m.Get("", repo.ListRepoIssueComments)
 						m.Group("/:id", func() {
@@ -741,6 +744,13 @@ func RegisterRoutes(m *macaron.Macaron) {
 							m.Delete("/:id", repo.DeleteTime)
 						}, reqToken())
 						m.Combo("/deadline").Post(reqToken(), bind(api.EditDeadlineOption{}), repo.UpdateIssueDeadline)
+						m.Combo("/parent").Get(repo.GetIssueParent).
+							Put(reqToken(), mustNotBeArchived, bind(api.IssueParentOption{}), repo.SetIssueParent).
+							Delete(reqToken(), mustNotBeArchived, repo.DeleteIssueParent)
+						m.Get("/children", repo.ListIssueChildren)
+						m.Combo("/pin", reqToken(), mustNotBeArchived).Post(repo.PinIssue).
+							Delete(repo.UnpinIssue)
+						m.Post("/transfer", reqToken(), mustNotBeArchived, bind(api.TransferIssueOption{}), repo.TransferIssue)
 						m.Group("/stopwatch", func() {
 							m.Post("/start", reqToken(), repo.StartIssueStopwatch)
 							m.Post("/stop", reqToken(), repo.StopIssueStopwatch)
@@ -773,7 +783,9 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Combo("/:id").Get(repo.GetMilestone).
 						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditMilestoneOption{}), repo.EditMilestone).
 						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteMilestone)
+					m.Get("/:id/burndown", repo.GetMilestoneBurndown)
 				})
+				m.Get("/velocity", repo.ListMilestonesVelocity)
 				m.Get("/stargazers", repo.ListStargazers)
 				m.Get("/subscribers", repo.ListSubscribers)
 				m.Group("/subscription", func() {
@@ -874,6 +886,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 				}, reqAnyRepoReader())
 				m.Get("/issue_templates", context.ReferencesGitRepo(false), repo.GetIssueTemplates)
 				m.Get("/languages", reqRepoReader(models.UnitTypeCode), repo.GetLanguages)
+				m.Get("/search/code", reqRepoReader(models.UnitTypeCode), repo.SearchRepoCode)
 			}, repoAssignment())
 		})
 
@@ -945,9 +958,27 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Group("/admin", func() {
 			m.Group("/cron", func() {
 				m.Get("", admin.ListCronTasks)
-				m.Post("/:task", admin.PostCronTask)
+				m.Group("/:task", func() {
+					m.Combo("").Get(admin.GetCronTask).
+						Post(admin.PostCronTask).
+						Patch(bind(api.EditCronOption{}), admin.EditCronTask)
+					m.Post("/reset", admin.ResetCronTask)
+					m.Get("/runs", admin.ListCronTaskRuns)
+				})
 			})
 			m.Get("/orgs", admin.GetAllOrgs)
+			m.Group("/queues", func() {
+				m.Get("", admin.ListQueues)
+				m.Group("/:qid", func() {
+					m.Get("", admin.GetQueue)
+					m.Post("/pause", admin.PauseQueue)
+					m.Post("/resume", admin.ResumeQueue)
+					m.Post("/flush", admin.FlushQueue)
+					m.Combo("/items").Get(admin.ListQueueItems).
+						Delete(admin.PurgeQueue)
+					m.Delete("/items/:index", admin.DeleteQueueItem)
+				})
+			})
 			m.Group("/users", func() {
 				m.Get("", admin.GetAllUsers)
 				m.Post("", bind(api.CreateUserOption{}), admin.CreateUser)
@@ -163,6 +163,7 @@ func DeleteBranch(ctx *context.APIContext) {
 			PusherName:   ctx.User.Name,
 			RepoUserName: ctx.Repo.Owner.Name,
 			RepoName:     ctx.Repo.Repository.Name,
+			RequestID:    log.RequestIDFromContext(ctx.Req.Context()),
 		}); err != nil {
 		log.Error("Update: %v", err)
 	}
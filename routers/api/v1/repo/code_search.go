@@ -0,0 +1,211 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// SearchCode searches for code across the repositories that the user has access to
+func SearchCode(ctx *context.APIContext) {
+	// swagger:operation GET /repos/search/code repository repoSearchCode
+	// ---
+	// summary: Search for code across the repositories that the user has access to
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: keyword
+	//   type: string
+	//   required: true
+	// - name: l
+	//   in: query
+	//   description: limit search to files of this language
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeSearchResults"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !setting.Indexer.RepoIndexerEnabled {
+		ctx.NotFound()
+		return
+	}
+
+	var repoIDs []int64
+	// admins can search all the repositories, which is done by not limiting the repositories
+	if ctx.User == nil || !ctx.User.IsAdmin {
+		accessibleIDs, err := models.FindUserAccessibleRepoIDs(ctx.User)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "FindUserAccessibleRepoIDs", err)
+			return
+		}
+		repos, err := models.GetRepositoriesMapByIDs(accessibleIDs)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoriesMapByIDs", err)
+			return
+		}
+		repoIDs = make([]int64, 0, len(repos))
+		for id, repo := range repos {
+			if ctx.User == nil || repo.CheckUnitUser(ctx.User, models.UnitTypeCode) {
+				repoIDs = append(repoIDs, id)
+			}
+		}
+	}
+
+	searchCode(ctx, repoIDs, "", nil)
+}
+
+// SearchRepoCode searches for code in a repository
+func SearchRepoCode(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/search/code repository repoSearchRepoCode
+	// ---
+	// summary: Search for code in a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: q
+	//   in: query
+	//   description: keyword
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: branch or tag to search, defaults to the default branch. Only the refs configured for the code indexer can be searched
+	//   type: string
+	// - name: l
+	//   in: query
+	//   description: limit search to files of this language
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeSearchResults"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !setting.Indexer.RepoIndexerEnabled {
+		ctx.NotFound()
+		return
+	}
+
+	repo := ctx.Repo.Repository
+	ref, ok, err := code_indexer.ResolveIndexedRef(repo, strings.TrimSpace(ctx.Query("ref")))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ResolveIndexedRef", err)
+		return
+	} else if !ok {
+		ctx.NotFound()
+		return
+	}
+	searchCode(ctx, []int64{repo.ID}, ref, map[int64]*models.Repository{repo.ID: repo})
+}
+
+// searchCode searches the code indexer for the keyword of the request in the ref of the given repositories,
+// a nil repoIDs searches all the repositories, the repositories of the results missing from repos are loaded
+func searchCode(ctx *context.APIContext, repoIDs []int64, ref string, repos map[int64]*models.Repository) {
+	keyword := strings.TrimSpace(ctx.Query("q"))
+	if len(keyword) == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("keyword is required"))
+		return
+	}
+	language := strings.TrimSpace(ctx.Query("l"))
+	listOptions := utils.GetListOptions(ctx)
+	if listOptions.Page <= 0 {
+		listOptions.Page = 1
+	}
+
+	var (
+		total     int
+		results   []*code_indexer.Result
+		languages []*code_indexer.SearchResultLanguages
+	)
+	// an empty list of repositories would search all of them
+	if repoIDs == nil || len(repoIDs) > 0 {
+		var err error
+		total, results, languages, err = code_indexer.PerformSearch(repoIDs, ref, language, keyword, listOptions.Page, listOptions.PageSize)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "PerformSearch", err)
+			return
+		}
+	}
+
+	var missingIDs []int64
+	for _, result := range results {
+		if _, ok := repos[result.RepoID]; !ok {
+			missingIDs = append(missingIDs, result.RepoID)
+		}
+	}
+	if len(missingIDs) > 0 {
+		loaded, err := models.GetRepositoriesMapByIDs(missingIDs)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoriesMapByIDs", err)
+			return
+		}
+		if repos == nil {
+			repos = loaded
+		} else {
+			for id, repo := range loaded {
+				repos[id] = repo
+			}
+		}
+	}
+
+	apiResults := make([]*api.CodeSearchResult, 0, len(results))
+	for _, result := range results {
+		repo, ok := repos[result.RepoID]
+		if !ok {
+			// the repository has been deleted since it was indexed
+			continue
+		}
+		apiResults = append(apiResults, convert.ToCodeSearchResult(repo, result))
+	}
+
+	ctx.SetLinkHeader(total, listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", total))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, api.CodeSearchResults{
+		TotalCount: total,
+		Data:       apiResults,
+		Languages:  convert.ToCodeSearchLanguages(languages),
+	})
+}
@@ -96,7 +96,7 @@ func GetArchive(ctx *context.APIContext) {
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
-	repoPath := models.RepoPath(ctx.Params(":username"), ctx.Params(":reponame"))
+	repoPath := ctx.Repo.Repository.RepoPath()
 	gitRepo, err := git.OpenRepository(repoPath)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
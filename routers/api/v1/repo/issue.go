@@ -44,7 +44,7 @@ func SearchIssues(ctx *context.APIContext) {
 	//   type: string
 	// - name: q
 	//   in: query
-	//   description: search string
+	//   description: search string, which can contain qualifiers like `is:open label:bug -label:wontfix assignee:@me milestone:"v1.2" author:user updated:>2020-01-01 sort:comments`
 	//   type: string
 	// - name: priority_repo_id
 	//   in: query
@@ -63,14 +63,22 @@ func SearchIssues(ctx *context.APIContext) {
 	//   "200":
 	//     "$ref": "#/responses/IssueList"
 
+	searchQuery := models.ParseIssueSearchQuery(strings.Trim(ctx.Query("q"), " "), ctx.User)
+
 	var isClosed util.OptionalBool
 	switch ctx.Query("state") {
 	case "closed":
 		isClosed = util.OptionalBoolTrue
 	case "all":
 		isClosed = util.OptionalBoolNone
-	default:
+	case "open":
 		isClosed = util.OptionalBoolFalse
+	default:
+		if searchQuery.IsClosed.IsNone() {
+			isClosed = util.OptionalBoolFalse
+		} else {
+			isClosed = searchQuery.IsClosed
+		}
 	}
 
 	// find repos user can access (for issue search)
@@ -113,7 +121,7 @@ func SearchIssues(ctx *context.APIContext) {
 	var issues []*models.Issue
 	var filteredCount int64
 
-	keyword := strings.Trim(ctx.Query("q"), " ")
+	keyword := searchQuery.Keyword
 	if strings.IndexByte(keyword, 0) >= 0 {
 		keyword = ""
 	}
@@ -143,6 +151,11 @@ func SearchIssues(ctx *context.APIContext) {
 		includedLabelNames = strings.Split(labels, ",")
 	}
 
+	sortType := "priorityrepo"
+	if len(searchQuery.SortType) > 0 {
+		sortType = searchQuery.SortType
+	}
+
 	// Only fetch the issues if we either don't have a keyword or the search returned issues
 	// This would otherwise return all issues if no issues were found by the search.
 	if len(keyword) == 0 || len(issueIDs) > 0 || len(labelIDs) > 0 {
@@ -155,9 +168,10 @@ func SearchIssues(ctx *context.APIContext) {
 			IsClosed:           isClosed,
 			IssueIDs:           issueIDs,
 			IncludedLabelNames: includedLabelNames,
-			SortType:           "priorityrepo",
+			SortType:           sortType,
 			PriorityRepoID:     ctx.QueryInt64("priority_repo_id"),
 			IsPull:             isPull,
+			SearchQuery:        searchQuery,
 		}
 
 		if issues, err = models.Issues(issuesOpt); err != nil {
@@ -209,7 +223,7 @@ func ListIssues(ctx *context.APIContext) {
 	//   type: string
 	// - name: q
 	//   in: query
-	//   description: search string
+	//   description: search string, which can contain qualifiers like `is:open label:bug -label:wontfix assignee:@me milestone:"v1.2" author:user updated:>2020-01-01 sort:comments`
 	//   type: string
 	// - name: type
 	//   in: query
@@ -220,6 +234,11 @@ func ListIssues(ctx *context.APIContext) {
 	//   in: query
 	//   description: comma separated list of milestone names or ids. It uses names and fall back to ids. Fetch only issues that have any of this milestones. Non existent milestones are discarded
 	//   type: string
+	// - name: parent
+	//   in: query
+	//   description: index of an issue of the repository. Fetch only the sub-issues of this issue
+	//   type: integer
+	//   format: int64
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
@@ -232,20 +251,28 @@ func ListIssues(ctx *context.APIContext) {
 	//   "200":
 	//     "$ref": "#/responses/IssueList"
 
+	searchQuery := models.ParseIssueSearchQuery(strings.Trim(ctx.Query("q"), " "), ctx.User)
+
 	var isClosed util.OptionalBool
 	switch ctx.Query("state") {
 	case "closed":
 		isClosed = util.OptionalBoolTrue
 	case "all":
 		isClosed = util.OptionalBoolNone
-	default:
+	case "open":
 		isClosed = util.OptionalBoolFalse
+	default:
+		if searchQuery.IsClosed.IsNone() {
+			isClosed = util.OptionalBoolFalse
+		} else {
+			isClosed = searchQuery.IsClosed
+		}
 	}
 
 	var issues []*models.Issue
 	var filteredCount int64
 
-	keyword := strings.Trim(ctx.Query("q"), " ")
+	keyword := searchQuery.Keyword
 	if strings.IndexByte(keyword, 0) >= 0 {
 		keyword = ""
 	}
@@ -298,6 +325,21 @@ func ListIssues(ctx *context.APIContext) {
 		}
 	}
 
+	var parentID int64
+	parentExists := true
+	if parentIndex := ctx.QueryInt64("parent"); parentIndex > 0 {
+		parent, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, parentIndex)
+		if err != nil {
+			if !models.IsErrIssueNotExist(err) {
+				ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+				return
+			}
+			parentExists = false
+		} else {
+			parentID = parent.ID
+		}
+	}
+
 	listOptions := utils.GetListOptions(ctx)
 
 	var isPull util.OptionalBool
@@ -312,7 +354,7 @@ func ListIssues(ctx *context.APIContext) {
 
 	// Only fetch the issues if we either don't have a keyword or the search returned issues
 	// This would otherwise return all issues if no issues were found by the search.
-	if len(keyword) == 0 || len(issueIDs) > 0 || len(labelIDs) > 0 {
+	if parentExists && (len(keyword) == 0 || len(issueIDs) > 0 || len(labelIDs) > 0) {
 		issuesOpt := &models.IssuesOptions{
 			ListOptions:  listOptions,
 			RepoIDs:      []int64{ctx.Repo.Repository.ID},
@@ -320,7 +362,10 @@ func ListIssues(ctx *context.APIContext) {
 			IssueIDs:     issueIDs,
 			LabelIDs:     labelIDs,
 			MilestoneIDs: mileIDs,
+			ParentID:     parentID,
 			IsPull:       isPull,
+			SortType:     searchQuery.SortType,
+			SearchQuery:  searchQuery,
 		}
 
 		if issues, err = models.Issues(issuesOpt); err != nil {
@@ -370,13 +415,15 @@ func GetIssue(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Issue"
+	//   "301":
+	//     description: redirection to the issue if it has been transferred to another repository
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
 	issue, err := models.GetIssueWithAttrsByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
 		if models.IsErrIssueNotExist(err) {
-			ctx.NotFound()
+			redirectTransferredIssue(ctx)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
 		}
@@ -588,6 +635,18 @@ func EditIssue(ctx *context.APIContext, form api.EditIssueOption) {
 		issue.DeadlineUnix = deadlineUnix
 	}
 
+	// Update or remove the estimated time, only if set and allowed
+	if form.EstimatedTime != nil && canWrite {
+		if *form.EstimatedTime < 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", "estimated time must not be negative")
+			return
+		}
+		if err := models.SetIssueEstimatedTime(issue, ctx.User, *form.EstimatedTime); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetIssueEstimatedTime", err)
+			return
+		}
+	}
+
 	// Add/delete assignees
 
 	// Deleting is done the GitHub way (quote from their api documentation):
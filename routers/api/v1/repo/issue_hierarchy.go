@@ -0,0 +1,304 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// canReadIssue returns whether the user can read the issue which may be in another repository
+func canReadIssue(ctx *context.APIContext, issue *models.Issue) (bool, error) {
+	if err := issue.LoadRepo(); err != nil {
+		return false, err
+	}
+	if issue.RepoID == ctx.Repo.Repository.ID {
+		return ctx.Repo.CanReadIssuesOrPulls(issue.IsPull), nil
+	}
+	perm, err := models.GetUserRepoPermission(issue.Repo, ctx.User)
+	if err != nil {
+		return false, err
+	}
+	return perm.CanReadIssuesOrPulls(issue.IsPull), nil
+}
+
+// GetIssueParent get the parent issue of an issue
+func GetIssueParent(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/parent issue issueGetParent
+	// ---
+	// summary: Get the parent issue of an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Issue"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	if err = issue.LoadParent(); err != nil && !models.IsErrIssueNotExist(err) {
+		ctx.Error(http.StatusInternalServerError, "LoadParent", err)
+		return
+	}
+	if issue.Parent == nil {
+		ctx.NotFound()
+		return
+	}
+	if canRead, err := canReadIssue(ctx, issue.Parent); err != nil {
+		ctx.Error(http.StatusInternalServerError, "canReadIssue", err)
+		return
+	} else if !canRead {
+		ctx.NotFound()
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssue(issue.Parent))
+}
+
+// SetIssueParent set the parent issue of an issue
+func SetIssueParent(ctx *context.APIContext, form api.IssueParentOption) {
+	// swagger:operation PUT /repos/{owner}/{repo}/issues/{index}/parent issue issueSetParent
+	// ---
+	// summary: Set the parent issue of an issue. The parent issue must be an issue of the same repository or of a repository of the same organization.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/IssueParentOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Issue"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden, "", "Not repo writer")
+		return
+	}
+
+	repo := ctx.Repo.Repository
+	if form.Owner != "" || form.Repo != "" {
+		ownerName, repoName := form.Owner, form.Repo
+		if ownerName == "" {
+			ownerName = ctx.Repo.Owner.Name
+		}
+		if repoName == "" {
+			repoName = ctx.Repo.Repository.Name
+		}
+		if repo, err = models.GetRepositoryByOwnerAndName(ownerName, repoName); err != nil {
+			if models.IsErrRepoNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+			}
+			return
+		}
+	}
+
+	parent, err := models.GetIssueByIndex(repo.ID, form.Index)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+	parent.Repo = repo
+	if canRead, err := canReadIssue(ctx, parent); err != nil {
+		ctx.Error(http.StatusInternalServerError, "canReadIssue", err)
+		return
+	} else if !canRead {
+		ctx.NotFound()
+		return
+	}
+
+	if err = models.SetIssueParent(issue, parent, ctx.User); err != nil {
+		if models.IsErrCircularIssueParent(err) || models.IsErrIssueParentNotAllowed(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "SetIssueParent", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetIssueParent", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssue(parent))
+}
+
+// DeleteIssueParent remove the parent issue of an issue
+func DeleteIssueParent(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/parent issue issueDeleteParent
+	// ---
+	// summary: Remove the parent issue of an issue
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden, "", "Not repo writer")
+		return
+	}
+
+	if err = models.SetIssueParent(issue, nil, ctx.User); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetIssueParent", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListIssueChildren list the sub-issues of an issue
+func ListIssueChildren(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/children issue issueListChildren
+	// ---
+	// summary: List the sub-issues of an issue, including the sub-issues in other repositories of the same organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	children, err := models.GetIssueChildren(issue.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueChildren", err)
+		return
+	}
+
+	readable := make(models.IssueList, 0, len(children))
+	for _, child := range children {
+		if canRead, err := canReadIssue(ctx, child); err != nil {
+			ctx.Error(http.StatusInternalServerError, "canReadIssue", err)
+			return
+		} else if canRead {
+			readable = append(readable, child)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(readable))
+}
@@ -98,6 +98,8 @@ func AddIssueLabels(ctx *context.APIContext, form api.IssueLabelsOption) {
 	//     "$ref": "#/responses/LabelList"
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	issue, labels, err := prepareForReplaceOrAdd(ctx, form)
 	if err != nil {
@@ -224,6 +226,8 @@ func ReplaceIssueLabels(ctx *context.APIContext, form api.IssueLabelsOption) {
 	//     "$ref": "#/responses/LabelList"
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	issue, labels, err := prepareForReplaceOrAdd(ctx, form)
 	if err != nil {
@@ -313,6 +317,10 @@ func prepareForReplaceOrAdd(ctx *context.APIContext, form api.IssueLabelsOption)
 		ctx.Error(http.StatusInternalServerError, "GetLabelsByIDs", err)
 		return
 	}
+	if err = models.CheckExclusiveLabels(labels); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
 
 	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
 		ctx.Status(http.StatusForbidden)
@@ -0,0 +1,294 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// ListPinnedIssues list the pinned issues of a repository
+func ListPinnedIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/pinned issue issueListPinned
+	// ---
+	// summary: List the pinned issues or pull requests of a repository in their pinned order
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: query
+	//   description: list pinned issues or pull requests, defaults to issues
+	//   type: string
+	//   enum: [issues, pulls]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+
+	isPull := ctx.Query("type") == "pulls"
+	if !ctx.Repo.CanReadIssuesOrPulls(isPull) {
+		ctx.NotFound()
+		return
+	}
+
+	issues, err := models.GetPinnedIssues(ctx.Repo.Repository.ID, isPull)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPinnedIssues", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(issues))
+}
+
+// PinIssue pin an issue
+func PinIssue(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/pin issue issuePin
+	// ---
+	// summary: Pin an issue to the top of the issues of the repository
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	issue := getIssueForWriter(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issue.Pin(ctx.User); err != nil {
+		if models.IsErrIssueMaxPinReached(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "Pin", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "Pin", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// UnpinIssue unpin an issue
+func UnpinIssue(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/pin issue issueUnpin
+	// ---
+	// summary: Unpin an issue
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForWriter(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issue.Unpin(ctx.User); err != nil {
+		ctx.Error(http.StatusInternalServerError, "Unpin", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// TransferIssue transfer an issue to another repository
+func TransferIssue(ctx *context.APIContext, form api.TransferIssueOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/transfer issue issueTransfer
+	// ---
+	// summary: Transfer an issue with its comments, reactions, attachments and tracked times to another repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/TransferIssueOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Issue"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	issue := getIssueForWriter(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	newRepo, err := models.GetRepositoryByOwnerAndName(form.Owner, form.Repo)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+		}
+		return
+	}
+
+	perm, err := models.GetUserRepoPermission(newRepo, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+		return
+	}
+	if !perm.CanRead(models.UnitTypeIssues) {
+		ctx.NotFound()
+		return
+	}
+	if !perm.CanWrite(models.UnitTypeIssues) || newRepo.IsArchived {
+		ctx.Error(http.StatusForbidden, "", "Not writer of the target repository")
+		return
+	}
+
+	if err = issue_service.TransferIssue(issue, ctx.User, newRepo); err != nil {
+		if models.IsErrIssueTransferNotAllowed(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "TransferIssue", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "TransferIssue", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssue(issue))
+}
+
+// redirectTransferredIssue redirects to the issue if the requested issue has been transferred to another repository
+// the user can read, it responds not found otherwise
+func redirectTransferredIssue(ctx *context.APIContext) {
+	issueID, err := models.LookupIssueRedirect(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueRedirectNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "LookupIssueRedirect", err)
+		}
+		return
+	}
+
+	issue, err := models.GetIssueByID(issueID)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByID", err)
+		}
+		return
+	}
+	if err = issue.LoadRepo(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadRepo", err)
+		return
+	}
+	perm, err := models.GetUserRepoPermission(issue.Repo, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+		return
+	}
+	if !perm.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.NotFound()
+		return
+	}
+
+	redirect := fmt.Sprintf("%s/api/v1/repos/%s/issues/%d", setting.AppSubURL, issue.Repo.FullName(), issue.Index)
+	if len(ctx.Req.URL.RawQuery) > 0 {
+		redirect += "?" + ctx.Req.URL.RawQuery
+	}
+	ctx.Redirect(redirect, http.StatusMovedPermanently)
+}
+
+// getIssueForWriter returns the issue of the request if the user can write to it
+func getIssueForWriter(ctx *context.APIContext) *models.Issue {
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden, "", "Not repo writer")
+		return nil
+	}
+	return issue
+}
@@ -151,6 +151,7 @@ func CreateLabel(ctx *context.APIContext, form api.CreateLabelOption) {
 		Color:       form.Color,
 		RepoID:      ctx.Repo.Repository.ID,
 		Description: form.Description,
+		Exclusive:   form.Exclusive,
 	}
 	if err := models.NewLabel(label); err != nil {
 		ctx.Error(http.StatusInternalServerError, "NewLabel", err)
@@ -221,6 +222,9 @@ func EditLabel(ctx *context.APIContext, form api.EditLabelOption) {
 	if form.Description != nil {
 		label.Description = *form.Description
 	}
+	if form.Exclusive != nil {
+		label.Exclusive = *form.Exclusive
+	}
 	if err := models.UpdateLabel(label); err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateLabel", err)
 		return
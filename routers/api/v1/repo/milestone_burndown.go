@@ -0,0 +1,107 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetMilestoneBurndown list the daily snapshots of a milestone
+func GetMilestoneBurndown(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/milestones/{id}/burndown issue issueGetMilestoneBurndown
+	// ---
+	// summary: Get the daily snapshots of the open and closed issues of a milestone
+	// description: The state of an open milestone is always included for the current day.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: the milestone to get, identified by ID and if not available by name
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MilestoneSnapshotList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	milestone := getMilestoneByIDOrName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	snapshots, err := models.GetMilestoneBurndown(milestone)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMilestoneBurndown", err)
+		return
+	}
+
+	apiSnapshots := make([]*api.MilestoneSnapshot, len(snapshots))
+	for i := range snapshots {
+		apiSnapshots[i] = convert.ToAPIMilestoneSnapshot(snapshots[i])
+	}
+	ctx.JSON(http.StatusOK, &apiSnapshots)
+}
+
+// ListMilestonesVelocity list the velocity of the last closed milestones of a repository
+func ListMilestonesVelocity(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/velocity issue issueListMilestonesVelocity
+	// ---
+	// summary: List the velocity of the last closed milestones of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: limit
+	//   in: query
+	//   description: number of closed milestones to return, most recently closed first
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MilestoneVelocityList"
+
+	limit := ctx.QueryInt("limit")
+	if limit <= 0 || limit > setting.API.MaxResponseItems {
+		limit = setting.API.DefaultPagingNum
+	}
+
+	velocities, err := models.GetMilestonesVelocity(ctx.Repo.Repository.ID, limit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMilestonesVelocity", err)
+		return
+	}
+
+	apiVelocities := make([]*api.MilestoneVelocity, len(velocities))
+	for i := range velocities {
+		apiVelocities[i] = convert.ToAPIMilestoneVelocity(velocities[i])
+	}
+	ctx.JSON(http.StatusOK, &apiVelocities)
+}
@@ -339,12 +339,17 @@ func CreatePullReview(ctx *context.APIContext, opts api.CreatePullReviewOptions)
 		if c.OldLineNum > 0 {
 			line = c.OldLineNum * -1
 		}
+		startLine := c.NewStartLineNum
+		if c.OldStartLineNum > 0 {
+			startLine = c.OldStartLineNum * -1
+		}
 
 		if _, err := pull_service.CreateCodeComment(
 			ctx.User,
 			ctx.Repo.GitRepo,
 			pr.Issue,
 			line,
+			startLine,
 			c.Body,
 			c.Path,
 			true, // is review
@@ -14,3 +14,17 @@ type swaggerResponseCronList struct {
 	// in:body
 	Body []api.Cron `json:"body"`
 }
+
+// Cron
+// swagger:response Cron
+type swaggerResponseCron struct {
+	// in:body
+	Body api.Cron `json:"body"`
+}
+
+// CronRunList
+// swagger:response CronRunList
+type swaggerResponseCronRunList struct {
+	// in:body
+	Body []api.CronRun `json:"body"`
+}
@@ -64,6 +64,20 @@ type swaggerResponseMilestoneList struct {
 	Body []api.Milestone `json:"body"`
 }
 
+// MilestoneSnapshotList
+// swagger:response MilestoneSnapshotList
+type swaggerResponseMilestoneSnapshotList struct {
+	// in:body
+	Body []api.MilestoneSnapshot `json:"body"`
+}
+
+// MilestoneVelocityList
+// swagger:response MilestoneVelocityList
+type swaggerResponseMilestoneVelocityList struct {
+	// in:body
+	Body []api.MilestoneVelocity `json:"body"`
+}
+
 // TrackedTime
 // swagger:response TrackedTime
 type swaggerResponseTrackedTime struct {
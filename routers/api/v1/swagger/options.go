@@ -38,6 +38,12 @@ type swaggerParameterBodies struct {
 	// in:body
 	EditDeadlineOption api.EditDeadlineOption
 
+	// in:body
+	IssueParentOption api.IssueParentOption
+
+	// in:body
+	TransferIssueOption api.TransferIssueOption
+
 	// in:body
 	CreateIssueCommentOption api.CreateIssueCommentOption
 	// in:body
@@ -153,6 +159,9 @@ type swaggerParameterBodies struct {
 	// in:body
 	MigrateRepoOptions api.MigrateRepoOptions
 
+	// in:body
+	EditCronOption api.EditCronOption
+
 	// in:body
 	PullReviewRequestOptions api.PullReviewRequestOptions
 }
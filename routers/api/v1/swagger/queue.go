@@ -0,0 +1,30 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// Queue
+// swagger:response Queue
+type swaggerResponseQueue struct {
+	// in:body
+	Body api.Queue `json:"body"`
+}
+
+// QueueList
+// swagger:response QueueList
+type swaggerResponseQueueList struct {
+	// in:body
+	Body []api.Queue `json:"body"`
+}
+
+// QueueItemList
+// swagger:response QueueItemList
+type swaggerResponseQueueItemList struct {
+	// in:body
+	Body []api.QueueItem `json:"body"`
+}
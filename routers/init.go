@@ -14,9 +14,11 @@ import (
 	"code.gitea.io/gitea/models/migrations"
 	"code.gitea.io/gitea/modules/auth/sso"
 	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/cluster"
 	"code.gitea.io/gitea/modules/cron"
 	"code.gitea.io/gitea/modules/eventsource"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/highlight"
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
@@ -31,8 +33,10 @@ import (
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/svg"
 	"code.gitea.io/gitea/modules/task"
+	"code.gitea.io/gitea/modules/tracing"
 	"code.gitea.io/gitea/modules/webhook"
 	"code.gitea.io/gitea/services/mailer"
+	"code.gitea.io/gitea/services/mailer/incoming"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 	pull_service "code.gitea.io/gitea/services/pull"
 	"code.gitea.io/gitea/services/repository"
@@ -171,6 +175,25 @@ func GlobalInit(ctx context.Context) {
 
 	NewServices()
 
+	if setting.Tracing.Enabled {
+		if err := tracing.Init(tracing.Options{
+			ServiceName:    setting.Tracing.ServiceName,
+			ServiceVersion: setting.AppVer,
+			Exporter:       setting.Tracing.Exporter,
+			Endpoint:       setting.Tracing.Endpoint,
+			FilePath:       setting.Tracing.FilePath,
+			SampleRatio:    setting.Tracing.SampleRatio,
+			BatchSize:      setting.Tracing.BatchSize,
+			QueueLength:    setting.Tracing.QueueLength,
+			FlushInterval:  setting.Tracing.FlushInterval,
+			Timeout:        setting.Tracing.Timeout,
+		}); err != nil {
+			log.Fatal("Failed to initialize tracing: %v", err)
+		}
+		// Keep tracing until the end so that the spans of the queues flushed at shutdown are exported
+		graceful.GetManager().RunAtTerminate(context.Background(), tracing.Shutdown)
+	}
+
 	highlight.NewContext()
 	external.RegisterParsers()
 	markup.Init()
@@ -186,6 +209,10 @@ func GlobalInit(ctx context.Context) {
 
 	models.NewRepoContext()
 
+	if err := cluster.Init(); err != nil {
+		log.Fatal("Failed to initialize cluster locks: %v", err)
+	}
+
 	// Booting long running goroutines.
 	cron.NewContext()
 	issue_indexer.InitIssueIndexer(false)
@@ -214,6 +241,11 @@ func GlobalInit(ctx context.Context) {
 	} else {
 		ssh.Unused()
 	}
+	if setting.IncomingEmail.Enabled {
+		incoming.Listen()
+	} else {
+		incoming.Unused()
+	}
 	sso.Init()
 
 	svg.Init()
@@ -0,0 +1,59 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/repo"
+)
+
+const (
+	// tplTimeReport template path for the tracked times report of an organization
+	tplTimeReport base.TplName = "org/time_report"
+)
+
+// TimeReport renders the tracked times of the repositories of an organization the user can access
+func TimeReport(ctx *context.Context) {
+	org := ctx.Org.Organization
+
+	env, err := org.AccessibleReposEnv(ctx.User.ID)
+	if err != nil {
+		ctx.ServerError("AccessibleReposEnv", err)
+		return
+	}
+	count, err := env.CountRepos()
+	if err != nil {
+		ctx.ServerError("CountRepos", err)
+		return
+	}
+	repos, err := env.Repos(1, int(count))
+	if err != nil {
+		ctx.ServerError("Repos", err)
+		return
+	}
+
+	// an unknown repository id makes sure that nothing is reported if no repository is accessible
+	repoIDs := []int64{0}
+	for _, repository := range repos {
+		if !repository.IsTimetrackerEnabled() {
+			continue
+		}
+		perm, err := models.GetUserRepoPermission(repository, ctx.User)
+		if err != nil {
+			ctx.ServerError("GetUserRepoPermission", err)
+			return
+		}
+		if perm.CanRead(models.UnitTypeIssues) {
+			repoIDs = append(repoIDs, repository.ID)
+		}
+	}
+
+	ctx.Data["PageIsOrgTimeReport"] = true
+	repo.RenderTimeReport(ctx, models.FindTrackedTimesOptions{
+		RepositoryIDs: repoIDs,
+	}, ctx.Org.IsOwner || ctx.IsUserSiteAdmin(), tplTimeReport)
+}
@@ -411,6 +411,7 @@ func HookPostReceive(ctx *macaron.Context, opts private.HookOptions) {
 				PusherName:   opts.UserName,
 				RepoUserName: ownerName,
 				RepoName:     repoName,
+				RequestID:    log.RequestIDFromContext(ctx.Req.Context()),
 			}
 			updates = append(updates, &option)
 			if repo.IsEmpty && option.IsBranch() && option.BranchName() == "master" {
@@ -42,6 +42,13 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Post("/manager/shutdown", Shutdown)
 		m.Post("/manager/restart", Restart)
 		m.Post("/manager/flush-queues", bind(private.FlushOptions{}), FlushQueues)
+		m.Get("/manager/queues", ListQueues)
+		m.Post("/manager/queues/:queue/pause", PauseQueue)
+		m.Post("/manager/queues/:queue/resume", ResumeQueue)
+		m.Post("/manager/queues/:queue/flush", bind(private.FlushOptions{}), FlushQueue)
+		m.Get("/manager/queues/:queue/items", QueueItems)
+		m.Delete("/manager/queues/:queue/items", PurgeQueue)
+		m.Delete("/manager/queues/:queue/items/:index", RemoveQueueItem)
 		m.Post("/manager/pause-logging", PauseLogging)
 		m.Post("/manager/resume-logging", ResumeLogging)
 		m.Post("/manager/release-and-reopen-logging", ReleaseReopenLogging)
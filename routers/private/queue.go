@@ -0,0 +1,163 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/queue"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"gitea.com/macaron/macaron"
+)
+
+// getManagedQueue returns the queue named by its QID or its name, it writes the error if there is none
+func getManagedQueue(ctx *macaron.Context) *queue.ManagedQueue {
+	name := ctx.Params(":queue")
+	var mq *queue.ManagedQueue
+	if qid, err := strconv.ParseInt(name, 10, 64); err == nil {
+		mq = queue.GetManager().GetManagedQueue(qid)
+	}
+	if mq == nil {
+		mq = queue.GetManager().GetManagedQueueByName(name)
+	}
+	if mq == nil {
+		ctx.JSON(http.StatusNotFound, map[string]interface{}{
+			"err": fmt.Sprintf("Queue %s does not exist", name),
+		})
+	}
+	return mq
+}
+
+// ListQueues lists the queues
+func ListQueues(ctx *macaron.Context) {
+	mqs := queue.GetManager().ManagedQueues()
+	queues := make([]*api.Queue, len(mqs))
+	for i, mq := range mqs {
+		queues[i] = convert.ToQueue(mq)
+	}
+	ctx.JSON(http.StatusOK, queues)
+}
+
+// PauseQueue pauses the workers of a queue
+func PauseQueue(ctx *macaron.Context) {
+	mq := getManagedQueue(ctx)
+	if mq == nil {
+		return
+	}
+	if !mq.IsPausable() {
+		ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+			"err": fmt.Sprintf("Queue %s cannot be paused", mq.Name),
+		})
+		return
+	}
+	mq.Pause()
+	log.Info("Queue %s paused", mq.Name)
+	ctx.PlainText(http.StatusOK, []byte(fmt.Sprintf("Paused %s", mq.Name)))
+}
+
+// ResumeQueue resumes the workers of a queue
+func ResumeQueue(ctx *macaron.Context) {
+	mq := getManagedQueue(ctx)
+	if mq == nil {
+		return
+	}
+	mq.Resume()
+	log.Info("Queue %s resumed", mq.Name)
+	ctx.PlainText(http.StatusOK, []byte(fmt.Sprintf("Resumed %s", mq.Name)))
+}
+
+// FlushQueue flushes a queue
+func FlushQueue(ctx *macaron.Context, opts private.FlushOptions) {
+	mq := getManagedQueue(ctx)
+	if mq == nil {
+		return
+	}
+	if opts.NonBlocking {
+		go func() {
+			if err := mq.Flush(opts.Timeout); err != nil {
+				log.Error("Flushing failure for %s: Error %v", mq.Name, err)
+			}
+		}()
+		ctx.JSON(http.StatusAccepted, map[string]interface{}{
+			"err": "Flushing",
+		})
+		return
+	}
+	if err := mq.Flush(opts.Timeout); err != nil {
+		ctx.JSON(http.StatusRequestTimeout, map[string]interface{}{
+			"err": fmt.Sprintf("%v", err),
+		})
+		return
+	}
+	ctx.PlainText(http.StatusOK, []byte(fmt.Sprintf("Flushed %s", mq.Name)))
+}
+
+// QueueItems lists the persisted items of a queue
+func QueueItems(ctx *macaron.Context) {
+	mq := getManagedQueue(ctx)
+	if mq == nil {
+		return
+	}
+	skip := ctx.QueryInt("skip")
+	items, err := mq.Items(skip, ctx.QueryInt("limit"))
+	if err != nil {
+		writeQueueError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToQueueItems(skip, items))
+}
+
+// RemoveQueueItem removes a persisted item from a queue
+func RemoveQueueItem(ctx *macaron.Context) {
+	mq := getManagedQueue(ctx)
+	if mq == nil {
+		return
+	}
+	index := ctx.ParamsInt(":index")
+	removed, err := mq.RemoveItem(index)
+	if err != nil {
+		writeQueueError(ctx, err)
+		return
+	}
+	if removed == nil {
+		ctx.JSON(http.StatusNotFound, map[string]interface{}{
+			"err": fmt.Sprintf("Queue %s has no item at index %d", mq.Name, index),
+		})
+		return
+	}
+	log.Info("Item %s removed from queue %s", removed, mq.Name)
+	ctx.PlainText(http.StatusOK, []byte(fmt.Sprintf("Removed %s", removed)))
+}
+
+// PurgeQueue removes all the waiting items of a queue
+func PurgeQueue(ctx *macaron.Context) {
+	mq := getManagedQueue(ctx)
+	if mq == nil {
+		return
+	}
+	purged, err := mq.Purge()
+	if err != nil {
+		writeQueueError(ctx, err)
+		return
+	}
+	log.Info("%d items purged from queue %s", purged, mq.Name)
+	ctx.PlainText(http.StatusOK, []byte(fmt.Sprintf("Purged %d items from %s", purged, mq.Name)))
+}
+
+func writeQueueError(ctx *macaron.Context, err error) {
+	status := http.StatusInternalServerError
+	if queue.IsErrNotInspectable(err) {
+		status = http.StatusUnprocessableEntity
+	}
+	ctx.JSON(status, map[string]interface{}{
+		"err": err.Error(),
+	})
+}
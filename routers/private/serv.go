@@ -400,6 +400,12 @@ func ServCommand(ctx *macaron.Context) {
 			return
 		}
 	}
+	if results.IsWiki {
+		results.RepoPath = repo.WikiPath()
+	} else {
+		results.RepoPath = repo.RepoPath()
+	}
+
 	log.Debug("Serv Results:\nIsWiki: %t\nIsDeployKey: %t\nKeyID: %d\tKeyName: %s\nUserName: %s\nUserID: %d\nOwnerName: %s\nRepoName: %s\nRepoID: %d",
 		results.IsWiki,
 		results.IsDeployKey,
@@ -5,8 +5,12 @@
 package repo
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
@@ -14,6 +18,8 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/upload"
+
+	gouuid "github.com/google/uuid"
 )
 
 // UploadIssueAttachment response for Issue/PR attachments
@@ -67,6 +73,166 @@ func uploadAttachment(ctx *context.Context, allowedTypes string) {
 	})
 }
 
+// attachmentUploadURLExpiry is the time the clients have to start uploading an attachment to the storage
+const attachmentUploadURLExpiry = 15 * time.Minute
+
+// PresignIssueAttachment response for getting the request uploading an Issue/PR attachment to the storage
+func PresignIssueAttachment(ctx *context.Context) {
+	presignAttachment(ctx)
+}
+
+// PresignReleaseAttachment response for getting the request uploading a release attachment to the storage
+func PresignReleaseAttachment(ctx *context.Context) {
+	presignAttachment(ctx)
+}
+
+// presignAttachment returns the request uploading an attachment to the storage directly,
+// the attachment is created once the upload is completed
+func presignAttachment(ctx *context.Context) {
+	if !setting.Attachment.Enabled || !setting.Attachment.UploadDirect {
+		ctx.Error(http.StatusNotFound, "direct attachment upload is not enabled")
+		return
+	}
+	if ctx.QueryTrim("name") == "" {
+		ctx.Error(http.StatusBadRequest, "name is required")
+		return
+	}
+	if ctx.QueryInt64("size") > setting.Attachment.MaxSize<<20 {
+		ctx.Error(http.StatusRequestEntityTooLarge, "file is too big")
+		return
+	}
+
+	uuid := gouuid.New().String()
+	p := models.AttachmentUploadRelativePath(uuid)
+	upload, err := storage.Attachments.UploadURL(p, attachmentUploadURLExpiry)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, fmt.Sprintf("UploadURL: %v", err))
+		return
+	}
+	// the content left by the upload is deleted by the cron task once the URL has expired
+	if err := models.NewPendingUpload(models.PendingUploadAttachments, p, ctx.User.ID, attachmentUploadURLExpiry); err != nil {
+		ctx.Error(http.StatusInternalServerError, fmt.Sprintf("NewPendingUpload: %v", err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"uuid":   uuid,
+		"method": upload.Method,
+		"url":    upload.URL.String(),
+		"header": upload.Header,
+	})
+}
+
+// CompleteIssueAttachment response for creating an Issue/PR attachment uploaded to the storage
+func CompleteIssueAttachment(ctx *context.Context) {
+	completeAttachment(ctx, setting.Attachment.AllowedTypes)
+}
+
+// CompleteReleaseAttachment response for creating a release attachment uploaded to the storage
+func CompleteReleaseAttachment(ctx *context.Context) {
+	completeAttachment(ctx, setting.Repository.Release.AllowedTypes)
+}
+
+// completeAttachment checks the content uploaded to the storage while it copies it to the attachment and creates it,
+// the uploaded content is deleted in any case
+func completeAttachment(ctx *context.Context, allowedTypes string) {
+	if !setting.Attachment.Enabled || !setting.Attachment.UploadDirect {
+		ctx.Error(http.StatusNotFound, "direct attachment upload is not enabled")
+		return
+	}
+	uuid := ctx.Query("file")
+	if _, err := gouuid.Parse(uuid); err != nil {
+		ctx.Error(http.StatusBadRequest, "invalid file")
+		return
+	}
+	name := ctx.QueryTrim("name")
+	if name == "" {
+		ctx.Error(http.StatusBadRequest, "name is required")
+		return
+	}
+	if _, err := models.GetAttachmentByUUID(uuid); err == nil {
+		ctx.Error(http.StatusBadRequest, "file is already attached")
+		return
+	} else if !models.IsErrAttachmentNotExist(err) {
+		ctx.Error(http.StatusInternalServerError, fmt.Sprintf("GetAttachmentByUUID: %v", err))
+		return
+	}
+
+	uploadPath := models.AttachmentUploadRelativePath(uuid)
+	if pending, err := models.IsPendingUpload(models.PendingUploadAttachments, uploadPath, ctx.User.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, fmt.Sprintf("IsPendingUpload: %v", err))
+		return
+	} else if !pending {
+		ctx.Error(http.StatusBadRequest, "file has not been uploaded")
+		return
+	}
+	if _, err := storage.Attachments.Stat(uploadPath); err != nil {
+		if os.IsNotExist(err) {
+			ctx.Error(http.StatusBadRequest, "file has not been uploaded")
+		} else {
+			ctx.Error(http.StatusInternalServerError, fmt.Sprintf("Stat: %v", err))
+		}
+		return
+	}
+	file, err := storage.Attachments.Open(uploadPath)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, fmt.Sprintf("Open: %v", err))
+		return
+	}
+	// the pending upload is kept until it expires so the content uploaded again with its URL is deleted too
+	defer func() {
+		file.Close()
+		if err := storage.Attachments.Delete(uploadPath); err != nil {
+			log.Error("Unable to delete the uploaded content of the attachment %s: %v", uuid, err)
+		}
+	}()
+
+	// the content is checked as it is copied, it could be replaced in the meantime with the URL of the upload
+	maxSize := setting.Attachment.MaxSize << 20
+	rd := io.LimitReader(file, maxSize+1)
+	buf := make([]byte, 1024)
+	n, err := io.ReadFull(rd, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		ctx.Error(http.StatusInternalServerError, fmt.Sprintf("Read: %v", err))
+		return
+	}
+	buf = buf[:n]
+	if err := upload.Verify(buf, name, allowedTypes); err != nil {
+		ctx.Error(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := models.AttachmentRelativePath(uuid)
+	size, err := storage.Attachments.Save(p, io.MultiReader(bytes.NewReader(buf), rd))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, fmt.Sprintf("Save: %v", err))
+		return
+	}
+	if size > maxSize {
+		if err := storage.Attachments.Delete(p); err != nil {
+			log.Error("Unable to delete the rejected attachment %s: %v", uuid, err)
+		}
+		ctx.Error(http.StatusBadRequest, "file is too big")
+		return
+	}
+
+	attach, err := models.NewUploadedAttachment(&models.Attachment{
+		UUID:       uuid,
+		UploaderID: ctx.User.ID,
+		Name:       name,
+		Size:       size,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, fmt.Sprintf("NewUploadedAttachment: %v", err))
+		return
+	}
+
+	log.Trace("New attachment uploaded to the storage: %s", attach.UUID)
+	ctx.JSON(http.StatusOK, map[string]string{
+		"uuid": attach.UUID,
+	})
+}
+
 // DeleteAttachment response for deleting issue's attachment
 func DeleteAttachment(ctx *context.Context) {
 	file := ctx.Query("file")
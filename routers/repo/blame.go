@@ -35,8 +35,6 @@ func RefBlame(ctx *context.Context) {
 		return
 	}
 
-	userName := ctx.Repo.Owner.Name
-	repoName := ctx.Repo.Repository.Name
 	commitID := ctx.Repo.CommitID
 
 	commit, err := ctx.Repo.GitRepo.GetCommit(commitID)
@@ -123,7 +121,7 @@ func RefBlame(ctx *context.Context) {
 		return
 	}
 
-	blameReader, err := git.CreateBlameReader(ctx.Req.Context(), models.RepoPath(userName, repoName), commitID, fileName)
+	blameReader, err := git.CreateBlameReader(ctx.Req.Context(), ctx.Repo.Repository.RepoPath(), commitID, fileName)
 	if err != nil {
 		ctx.NotFound("CreateBlameReader", err)
 		return
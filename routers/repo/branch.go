@@ -128,6 +128,7 @@ func RestoreBranchPost(ctx *context.Context) {
 			PusherName:   ctx.User.Name,
 			RepoUserName: ctx.Repo.Owner.Name,
 			RepoName:     ctx.Repo.Repository.Name,
+			RequestID:    log.RequestIDFromContext(ctx.Req.Context()),
 		}); err != nil {
 		log.Error("Update: %v", err)
 	}
@@ -165,6 +166,7 @@ func deleteBranch(ctx *context.Context, branchName string) error {
 			PusherName:   ctx.User.Name,
 			RepoUserName: ctx.Repo.Owner.Name,
 			RepoName:     ctx.Repo.Repository.Name,
+			RequestID:    log.RequestIDFromContext(ctx.Req.Context()),
 		}); err != nil {
 		log.Error("Update: %v", err)
 	}
@@ -48,7 +48,7 @@ func Commits(ctx *context.Context) {
 	}
 	ctx.Data["PageIsViewCode"] = true
 
-	commitsCount, err := ctx.Repo.GetCommitsCount()
+	commitsCount, err := ctx.Repo.GetCommitsCount(ctx.Req.Context())
 	if err != nil {
 		ctx.ServerError("GetCommitsCount", err)
 		return
@@ -110,18 +110,18 @@ func Graph(ctx *context.Context) {
 	ctx.Data["SelectedBranches"] = realBranches
 	files := ctx.QueryStrings("file")
 
-	commitsCount, err := ctx.Repo.GetCommitsCount()
+	commitsCount, err := ctx.Repo.GetCommitsCount(ctx.Req.Context())
 	if err != nil {
 		ctx.ServerError("GetCommitsCount", err)
 		return
 	}
 
-	graphCommitsCount, err := ctx.Repo.GetCommitGraphsCount(hidePRRefs, realBranches, files)
+	graphCommitsCount, err := ctx.Repo.GetCommitGraphsCount(ctx.Req.Context(), hidePRRefs, realBranches, files)
 	if err != nil {
 		log.Warn("GetCommitGraphsCount error for generate graph exclude prs: %t branches: %s in %-v, Will Ignore branches and try again. Underlying Error: %v", hidePRRefs, branches, ctx.Repo.Repository, err)
 		realBranches = []string{}
 		branches = []string{}
-		graphCommitsCount, err = ctx.Repo.GetCommitGraphsCount(hidePRRefs, realBranches, files)
+		graphCommitsCount, err = ctx.Repo.GetCommitGraphsCount(ctx.Req.Context(), hidePRRefs, realBranches, files)
 		if err != nil {
 			ctx.ServerError("GetCommitGraphsCount", err)
 			return
@@ -280,7 +280,7 @@ func Diff(ctx *context.Context) {
 		repoPath = ctx.Repo.Repository.WikiPath()
 	} else {
 		gitRepo = ctx.Repo.GitRepo
-		repoPath = models.RepoPath(userName, repoName)
+		repoPath = ctx.Repo.Repository.RepoPath()
 	}
 
 	commit, err := gitRepo.GetCommit(commitID)
@@ -379,7 +379,7 @@ func RawDiff(ctx *context.Context) {
 	if ctx.Data["PageIsWiki"] != nil {
 		repoPath = ctx.Repo.Repository.WikiPath()
 	} else {
-		repoPath = models.RepoPath(ctx.Repo.Owner.Name, ctx.Repo.Repository.Name)
+		repoPath = ctx.Repo.Repository.RepoPath()
 	}
 	if err := git.GetRawDiff(
 		repoPath,
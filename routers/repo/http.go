@@ -334,6 +334,14 @@ func HTTP(ctx *context.Context) {
 	}
 
 	environ = append(environ, models.EnvRepoID+fmt.Sprintf("=%d", repo.ID))
+	if requestID := log.RequestIDFromContext(ctx.Req.Context()); requestID != "" {
+		environ = append(environ, log.RequestIDEnv+"="+requestID)
+	}
+
+	repoPath := repo.RepoPath()
+	if isWiki {
+		repoPath = repo.WikiPath()
+	}
 
 	w := ctx.Resp
 	r := ctx.Req.Request
@@ -373,7 +381,7 @@ func HTTP(ctx *context.Context) {
 			}
 
 			file := strings.Replace(r.URL.Path, m[1]+"/", "", 1)
-			dir, err := getGitRepoPath(m[1])
+			dir, err := getGitRepoPath(repoPath)
 			if err != nil {
 				log.Error(err.Error())
 				ctx.NotFound("Smart Git HTTP", err)
@@ -673,12 +681,7 @@ func getIdxFile(h serviceHandler) {
 	h.sendFile("application/x-git-packed-objects-toc")
 }
 
-func getGitRepoPath(subdir string) (string, error) {
-	if !strings.HasSuffix(subdir, ".git") {
-		subdir += ".git"
-	}
-
-	fpath := path.Join(setting.RepoRootPath, subdir)
+func getGitRepoPath(fpath string) (string, error) {
 	if _, err := os.Stat(fpath); os.IsNotExist(err) {
 		return "", err
 	}
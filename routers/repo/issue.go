@@ -150,9 +150,29 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 		keyword = ""
 	}
 
+	searchQuery := models.ParseIssueSearchQuery(keyword, ctx.User)
+	if len(sortType) == 0 {
+		sortType = searchQuery.SortType
+	}
+
+	var parentID int64
+	parentIndex := ctx.QueryInt64("parent")
+	if parentIndex > 0 {
+		parent, err := models.GetIssueByIndex(repo.ID, parentIndex)
+		if err != nil {
+			if !models.IsErrIssueNotExist(err) {
+				ctx.ServerError("GetIssueByIndex", err)
+				return
+			}
+			forceEmpty = true
+		} else {
+			parentID = parent.ID
+		}
+	}
+
 	var issueIDs []int64
-	if len(keyword) > 0 {
-		issueIDs, err = issue_indexer.SearchIssuesByKeyword([]int64{repo.ID}, keyword)
+	if len(searchQuery.Keyword) > 0 {
+		issueIDs, err = issue_indexer.SearchIssuesByKeyword([]int64{repo.ID}, searchQuery.Keyword)
 		if err != nil {
 			ctx.ServerError("issueIndexer.Search", err)
 			return
@@ -175,6 +195,8 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 			PosterID:    posterID,
 			IsPull:      isPullOption,
 			IssueIDs:    issueIDs,
+			ParentID:    parentID,
+			SearchQuery: searchQuery,
 		})
 		if err != nil {
 			ctx.ServerError("GetIssueStats", err)
@@ -183,9 +205,13 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 	}
 
 	isShowClosed := ctx.Query("state") == "closed"
-	// if open issues are zero and close don't, use closed as default
-	if len(ctx.Query("state")) == 0 && issueStats.OpenCount == 0 && issueStats.ClosedCount != 0 {
-		isShowClosed = true
+	if len(ctx.Query("state")) == 0 {
+		if !searchQuery.IsClosed.IsNone() {
+			isShowClosed = searchQuery.IsClosed.IsTrue()
+		} else if issueStats.OpenCount == 0 && issueStats.ClosedCount != 0 {
+			// if open issues are zero and close don't, use closed as default
+			isShowClosed = true
+		}
 	}
 
 	page := ctx.QueryInt("page")
@@ -226,6 +252,8 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 			LabelIDs:     labelIDs,
 			SortType:     sortType,
 			IssueIDs:     issueIDs,
+			ParentID:     parentID,
+			SearchQuery:  searchQuery,
 		})
 		if err != nil {
 			ctx.ServerError("Issues", err)
@@ -326,6 +354,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 	ctx.Data["SortType"] = sortType
 	ctx.Data["MilestoneID"] = milestoneID
 	ctx.Data["AssigneeID"] = assigneeID
+	ctx.Data["ParentIndex"] = parentIndex
 	ctx.Data["IsShowClosed"] = isShowClosed
 	ctx.Data["Keyword"] = keyword
 	if isShowClosed {
@@ -341,6 +370,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 	pager.AddParam(ctx, "labels", "SelectLabels")
 	pager.AddParam(ctx, "milestone", "MilestoneID")
 	pager.AddParam(ctx, "assignee", "AssigneeID")
+	pager.AddParam(ctx, "parent", "ParentIndex")
 	ctx.Data["Page"] = pager
 }
 
@@ -377,6 +407,12 @@ func Issues(ctx *context.Context) {
 		return
 	}
 
+	ctx.Data["PinnedIssues"], err = models.GetPinnedIssues(ctx.Repo.Repository.ID, isPullList)
+	if err != nil {
+		ctx.ServerError("GetPinnedIssues", err)
+		return
+	}
+
 	ctx.Data["CanWriteIssuesOrPulls"] = ctx.Repo.CanWriteIssuesOrPulls(isPullList)
 
 	ctx.HTML(200, tplIssues)
@@ -1020,7 +1056,7 @@ func ViewIssue(ctx *context.Context) {
 	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
 		if models.IsErrIssueNotExist(err) {
-			ctx.NotFound("GetIssueByIndex", err)
+			redirectTransferredIssue(ctx, err)
 		} else {
 			ctx.ServerError("GetIssueByIndex", err)
 		}
@@ -1219,6 +1255,13 @@ func ViewIssue(ctx *context.Context) {
 			ctx.ServerError("TotalTimes", err)
 			return
 		}
+		if issue.EstimatedTime > 0 {
+			progress := issue.TotalTrackedTime * 100 / issue.EstimatedTime
+			if progress > 100 {
+				progress = 100
+			}
+			ctx.Data["EstimatedTimeProgress"] = progress
+		}
 	}
 
 	// Check if the user can use the dependencies
@@ -1318,6 +1361,20 @@ func ViewIssue(ctx *context.Context) {
 					return
 				}
 			}
+		} else if comment.Type == models.CommentTypeAddParentIssue || comment.Type == models.CommentTypeRemoveParentIssue {
+			if err = comment.LoadDepIssueDetails(); err != nil {
+				if !models.IsErrIssueNotExist(err) {
+					ctx.ServerError("LoadDepIssueDetails", err)
+					return
+				}
+			}
+			if comment.DependentIssue != nil {
+				if canRead := canReadRelatedIssue(ctx, comment.DependentIssue); ctx.Written() {
+					return
+				} else if !canRead {
+					comment.DependentIssue = nil
+				}
+			}
 		} else if comment.Type == models.CommentTypeCode || comment.Type == models.CommentTypeReview {
 			comment.RenderedContent = string(markdown.Render([]byte(comment.Content), ctx.Repo.RepoLink,
 				ctx.Repo.Repository.ComposeMetas()))
@@ -1475,6 +1532,24 @@ func ViewIssue(ctx *context.Context) {
 		return
 	}
 
+	// Get parent issue and sub-issues
+	if err = issue.LoadParent(); err != nil && !models.IsErrIssueNotExist(err) {
+		ctx.ServerError("LoadParent", err)
+		return
+	}
+	if issue.Parent != nil {
+		if canRead := canReadRelatedIssue(ctx, issue.Parent); ctx.Written() {
+			return
+		} else if canRead {
+			ctx.Data["IssueParent"] = issue.Parent
+		}
+	}
+	ctx.Data["IssueTree"], err = models.GetIssueTree(issue, ctx.User)
+	if err != nil {
+		ctx.ServerError("GetIssueTree", err)
+		return
+	}
+
 	ctx.Data["Participants"] = participants
 	ctx.Data["NumParticipants"] = len(participants)
 	ctx.Data["Issue"] = issue
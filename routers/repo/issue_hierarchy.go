@@ -0,0 +1,148 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/references"
+)
+
+// getIssueByReference returns the issue referenced as #index in the current repository or as owner/repo#index,
+// it returns nil if the reference is invalid or if the issue can't be read by the user
+func getIssueByReference(ctx *context.Context, ref string) *models.Issue {
+	refs := references.FindAllIssueReferences(strings.TrimSpace(ref))
+	if len(refs) == 0 {
+		return nil
+	}
+
+	repo := ctx.Repo.Repository
+	if refs[0].Owner != "" {
+		var err error
+		repo, err = models.GetRepositoryByOwnerAndName(refs[0].Owner, refs[0].Name)
+		if err != nil {
+			if !models.IsErrRepoNotExist(err) {
+				ctx.ServerError("GetRepositoryByOwnerAndName", err)
+			}
+			return nil
+		}
+	}
+
+	issue, err := models.GetIssueByIndex(repo.ID, refs[0].Index)
+	if err != nil {
+		if !models.IsErrIssueNotExist(err) {
+			ctx.ServerError("GetIssueByIndex", err)
+		}
+		return nil
+	}
+	issue.Repo = repo
+
+	perm, err := models.GetUserRepoPermission(repo, ctx.User)
+	if err != nil {
+		ctx.ServerError("GetUserRepoPermission", err)
+		return nil
+	}
+	if !perm.CanReadIssuesOrPulls(issue.IsPull) {
+		return nil
+	}
+	return issue
+}
+
+// setIssueParent sets the parent of the issue and flashes the errors
+func setIssueParent(ctx *context.Context, issue, parent *models.Issue) {
+	if err := models.SetIssueParent(issue, parent, ctx.User); err != nil {
+		if models.IsErrCircularIssueParent(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.hierarchy.error_circular"))
+		} else if models.IsErrIssueParentNotAllowed(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.hierarchy.error_not_allowed"))
+		} else {
+			ctx.ServerError("SetIssueParent", err)
+		}
+	}
+}
+
+// UpdateIssueParent sets or removes the parent issue of an issue
+func UpdateIssueParent(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden)
+		return
+	}
+
+	var parent *models.Issue
+	if ref := ctx.Query("parent"); strings.TrimSpace(ref) != "" {
+		if parent = getIssueByReference(ctx, ref); ctx.Written() {
+			return
+		} else if parent == nil {
+			ctx.Flash.Error(ctx.Tr("repo.issues.hierarchy.error_issue_not_exist"))
+			ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+			return
+		}
+	}
+
+	setIssueParent(ctx, issue, parent)
+	if ctx.Written() {
+		return
+	}
+	ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+}
+
+// AddIssueChild makes an issue a sub-issue of the current issue
+func AddIssueChild(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	child := getIssueByReference(ctx, ctx.Query("child"))
+	if ctx.Written() {
+		return
+	}
+	if child == nil {
+		ctx.Flash.Error(ctx.Tr("repo.issues.hierarchy.error_issue_not_exist"))
+		ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+		return
+	}
+
+	perm, err := models.GetUserRepoPermission(child.Repo, ctx.User)
+	if err != nil {
+		ctx.ServerError("GetUserRepoPermission", err)
+		return
+	}
+	if !perm.CanWriteIssuesOrPulls(child.IsPull) {
+		ctx.Error(http.StatusForbidden)
+		return
+	}
+
+	setIssueParent(ctx, child, issue)
+	if ctx.Written() {
+		return
+	}
+	ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+}
+
+// canReadRelatedIssue returns whether the user can read an issue related to the current issue,
+// which may be in another repository
+func canReadRelatedIssue(ctx *context.Context, issue *models.Issue) bool {
+	if err := issue.LoadRepo(); err != nil {
+		ctx.ServerError("LoadRepo", err)
+		return false
+	}
+	if issue.RepoID == ctx.Repo.Repository.ID {
+		return ctx.Repo.CanReadIssuesOrPulls(issue.IsPull)
+	}
+	perm, err := models.GetUserRepoPermission(issue.Repo, ctx.User)
+	if err != nil {
+		ctx.ServerError("GetUserRepoPermission", err)
+		return false
+	}
+	return perm.CanReadIssuesOrPulls(issue.IsPull)
+}
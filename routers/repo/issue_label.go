@@ -109,6 +109,7 @@ func NewLabel(ctx *context.Context, form auth.CreateLabelForm) {
 		Name:        form.Title,
 		Description: form.Description,
 		Color:       form.Color,
+		Exclusive:   form.Exclusive,
 	}
 	if err := models.NewLabel(l); err != nil {
 		ctx.ServerError("NewLabel", err)
@@ -133,6 +134,7 @@ func UpdateLabel(ctx *context.Context, form auth.CreateLabelForm) {
 	l.Name = form.Title
 	l.Description = form.Description
 	l.Color = form.Color
+	l.Exclusive = form.Exclusive
 	if err := models.UpdateLabel(l); err != nil {
 		ctx.ServerError("UpdateLabel", err)
 		return
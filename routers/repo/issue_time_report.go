@@ -0,0 +1,213 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplTimeReport base.TplName = "repo/issue/time_report"
+)
+
+// timeReportEntryJSON represents a line of the JSON export of a tracked time report
+type timeReportEntryJSON struct {
+	Repository       string `json:"repository"`
+	IssueIndex       int64  `json:"issue_index"`
+	IssueTitle       string `json:"issue_title"`
+	Milestone        string `json:"milestone"`
+	User             string `json:"user"`
+	Seconds          int64  `json:"seconds"`
+	EstimatedSeconds int64  `json:"estimated_seconds"`
+}
+
+// timeReportSummaryJSON represents a group of the JSON export of a tracked time report
+type timeReportSummaryJSON struct {
+	Name             string `json:"name"`
+	Seconds          int64  `json:"seconds"`
+	EstimatedSeconds int64  `json:"estimated_seconds"`
+}
+
+// timeReportJSON represents the JSON export of a tracked time report
+type timeReportJSON struct {
+	Since                 string                   `json:"since,omitempty"`
+	Until                 string                   `json:"until,omitempty"`
+	GroupBy               models.TrackedTimeGroup  `json:"group_by"`
+	TotalSeconds          int64                    `json:"total_seconds"`
+	TotalEstimatedSeconds int64                    `json:"total_estimated_seconds"`
+	Summaries             []*timeReportSummaryJSON `json:"summaries"`
+	Entries               []*timeReportEntryJSON   `json:"entries"`
+}
+
+// parseTimeReportDate parses a date of the report filters, the end of the day is returned if endOfDay is set
+func parseTimeReportDate(value string, endOfDay bool) (int64, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, setting.DefaultUILocation)
+	if err != nil {
+		return 0, err
+	}
+	if endOfDay {
+		date = date.Add(24*time.Hour - time.Second)
+	}
+	return date.Unix(), nil
+}
+
+// RenderTimeReport renders or exports the tracked times matching the options and the query filters,
+// the users who can't see all users only get their own tracked times
+func RenderTimeReport(ctx *context.Context, opts models.FindTrackedTimesOptions, canSeeAllUsers bool, tpl base.TplName) {
+	since, until := ctx.Query("since"), ctx.Query("until")
+	var err error
+	if opts.CreatedAfterUnix, err = parseTimeReportDate(since, false); err != nil {
+		ctx.Flash.Error(ctx.Tr("repo.issues.time_report.invalid_date"), true)
+		since = ""
+	}
+	if opts.CreatedBeforeUnix, err = parseTimeReportDate(until, true); err != nil {
+		ctx.Flash.Error(ctx.Tr("repo.issues.time_report.invalid_date"), true)
+		until = ""
+	}
+
+	userName := strings.TrimSpace(ctx.Query("user"))
+	if !canSeeAllUsers {
+		opts.UserID = ctx.User.ID
+		userName = ""
+	} else if userName != "" {
+		user, err := models.GetUserByName(userName)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.NotFound("GetUserByName", err)
+			} else {
+				ctx.ServerError("GetUserByName", err)
+			}
+			return
+		}
+		opts.UserID = user.ID
+	}
+
+	group := models.TrackedTimeGroup(ctx.Query("group"))
+	switch group {
+	case models.TrackedTimeGroupByUser, models.TrackedTimeGroupByRepo, models.TrackedTimeGroupByMilestone:
+	default:
+		group = models.TrackedTimeGroupByIssue
+	}
+
+	report, err := models.GetTrackedTimeReport(opts)
+	if err != nil {
+		ctx.ServerError("GetTrackedTimeReport", err)
+		return
+	}
+	summaries := report.Summarize(group)
+
+	switch ctx.Query("format") {
+	case "csv":
+		exportTimeReportCSV(ctx, report)
+		return
+	case "json":
+		data := &timeReportJSON{
+			Since:                 since,
+			Until:                 until,
+			GroupBy:               group,
+			TotalSeconds:          report.TotalSeconds,
+			TotalEstimatedSeconds: report.TotalEstimatedSeconds(),
+			Summaries:             make([]*timeReportSummaryJSON, 0, len(summaries)),
+			Entries:               make([]*timeReportEntryJSON, 0, len(report.Entries)),
+		}
+		for _, summary := range summaries {
+			data.Summaries = append(data.Summaries, &timeReportSummaryJSON{
+				Name:             summary.Name,
+				Seconds:          summary.Seconds,
+				EstimatedSeconds: summary.EstimatedSeconds,
+			})
+		}
+		for _, entry := range report.Entries {
+			data.Entries = append(data.Entries, toTimeReportEntryJSON(entry))
+		}
+		ctx.Resp.Header().Set("Content-Disposition", `attachment; filename="time-report.json"`)
+		ctx.JSON(http.StatusOK, data)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("repo.issues.time_report")
+	ctx.Data["PageIsTimeReport"] = true
+	ctx.Data["CanSeeAllUsers"] = canSeeAllUsers
+	ctx.Data["Report"] = report
+	ctx.Data["Summaries"] = summaries
+	ctx.Data["GroupBy"] = string(group)
+	ctx.Data["Since"] = since
+	ctx.Data["Until"] = until
+	ctx.Data["UserName"] = userName
+	ctx.Data["MilestoneID"] = opts.MilestoneID
+	ctx.HTML(http.StatusOK, tpl)
+}
+
+func toTimeReportEntryJSON(entry *models.TrackedTimeReportEntry) *timeReportEntryJSON {
+	var milestone string
+	if entry.Issue.Milestone != nil {
+		milestone = entry.Issue.Milestone.Name
+	}
+	return &timeReportEntryJSON{
+		Repository:       entry.Issue.Repo.FullName(),
+		IssueIndex:       entry.Issue.Index,
+		IssueTitle:       entry.Issue.Title,
+		Milestone:        milestone,
+		User:             entry.User.Name,
+		Seconds:          entry.Seconds,
+		EstimatedSeconds: entry.Issue.EstimatedTime,
+	}
+}
+
+func exportTimeReportCSV(ctx *context.Context, report *models.TrackedTimeReport) {
+	ctx.Resp.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	ctx.Resp.Header().Set("Content-Disposition", `attachment; filename="time-report.csv"`)
+	ctx.Resp.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(ctx.Resp)
+	_ = w.Write([]string{"repository", "issue", "title", "milestone", "user", "seconds", "hours", "estimated_seconds"})
+	for _, entry := range report.Entries {
+		data := toTimeReportEntryJSON(entry)
+		_ = w.Write([]string{
+			data.Repository,
+			strconv.FormatInt(data.IssueIndex, 10),
+			data.IssueTitle,
+			data.Milestone,
+			data.User,
+			strconv.FormatInt(data.Seconds, 10),
+			strconv.FormatFloat(float64(data.Seconds)/3600, 'f', 2, 64),
+			strconv.FormatInt(data.EstimatedSeconds, 10),
+		})
+	}
+	w.Flush()
+}
+
+// TimeReport renders the tracked times of a repository
+func TimeReport(ctx *context.Context) {
+	if !ctx.Repo.Repository.IsTimetrackerEnabled() {
+		ctx.NotFound("IsTimetrackerEnabled", nil)
+		return
+	}
+
+	milestones, err := models.GetMilestones(models.GetMilestonesOption{RepoID: ctx.Repo.Repository.ID})
+	if err != nil {
+		ctx.ServerError("GetMilestones", err)
+		return
+	}
+	ctx.Data["Milestones"] = milestones
+	ctx.Data["PageIsIssueList"] = true
+
+	RenderTimeReport(ctx, models.FindTrackedTimesOptions{
+		RepositoryID: ctx.Repo.Repository.ID,
+		MilestoneID:  ctx.QueryInt64("milestone"),
+	}, ctx.IsUserRepoAdmin() || ctx.IsUserSiteAdmin(), tplTimeReport)
+}
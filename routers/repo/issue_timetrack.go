@@ -46,3 +46,30 @@ func AddTimeManually(c *context.Context, form auth.AddTimeManuallyForm) {
 
 	c.Redirect(url, http.StatusSeeOther)
 }
+
+// SetEstimatedTime sets or removes the estimated time of an issue
+func SetEstimatedTime(c *context.Context, form auth.EstimatedTimeForm) {
+	issue := GetActionIssue(c)
+	if c.Written() {
+		return
+	}
+	if !c.Repo.Repository.IsTimetrackerEnabled() || !c.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		c.NotFound("CanWriteIssuesOrPulls", nil)
+		return
+	}
+	url := issue.HTMLURL()
+
+	if c.HasError() {
+		c.Flash.Error(c.GetErrMsg())
+		c.Redirect(url)
+		return
+	}
+
+	total := time.Duration(form.Hours)*time.Hour + time.Duration(form.Minutes)*time.Minute
+	if err := models.SetIssueEstimatedTime(issue, c.User, int64(total.Seconds())); err != nil {
+		c.ServerError("SetIssueEstimatedTime", err)
+		return
+	}
+
+	c.Redirect(url, http.StatusSeeOther)
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// redirectTransferredIssue redirects to the issue if the requested issue has been transferred to another repository
+// the user can read, it renders the not found page otherwise
+func redirectTransferredIssue(ctx *context.Context, notFoundErr error) {
+	issueID, err := models.LookupIssueRedirect(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueRedirectNotExist(err) {
+			ctx.NotFound("GetIssueByIndex", notFoundErr)
+		} else {
+			ctx.ServerError("LookupIssueRedirect", err)
+		}
+		return
+	}
+
+	issue, err := models.GetIssueByID(issueID)
+	if err != nil {
+		ctx.NotFoundOrServerError("GetIssueByID", models.IsErrIssueNotExist, err)
+		return
+	}
+	if err = issue.LoadRepo(); err != nil {
+		ctx.ServerError("LoadRepo", err)
+		return
+	}
+	// the repository the issue has been transferred to must not be disclosed to the users who can't read it
+	perm, err := models.GetUserRepoPermission(issue.Repo, ctx.User)
+	if err != nil {
+		ctx.ServerError("GetUserRepoPermission", err)
+		return
+	}
+	if !perm.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.NotFound("GetIssueByIndex", notFoundErr)
+		return
+	}
+	ctx.Redirect(fmt.Sprintf("%s/issues/%d", issue.Repo.Link(), issue.Index), http.StatusMovedPermanently)
+}
+
+// IssuePin pins or unpins an issue
+func IssuePin(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden)
+		return
+	}
+
+	var err error
+	if issue.IsPinned() {
+		err = issue.Unpin(ctx.User)
+	} else {
+		err = issue.Pin(ctx.User)
+	}
+	if err != nil {
+		if models.IsErrIssueMaxPinReached(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.max_pinned"))
+		} else {
+			ctx.ServerError("Pin", err)
+			return
+		}
+	}
+
+	ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+}
+
+// TransferIssuePost moves an issue to another repository the user can write to
+func TransferIssuePost(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if issue.IsPull || !ctx.Repo.CanWrite(models.UnitTypeIssues) {
+		ctx.Error(http.StatusForbidden)
+		return
+	}
+
+	fields := strings.SplitN(strings.TrimSpace(ctx.Query("repo")), "/", 2)
+	if len(fields) != 2 {
+		ctx.Flash.Error(ctx.Tr("repo.issues.transfer.error_repo_not_exist"))
+		ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+		return
+	}
+	newRepo, err := models.GetRepositoryByOwnerAndName(fields[0], fields[1])
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.transfer.error_repo_not_exist"))
+			ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+		} else {
+			ctx.ServerError("GetRepositoryByOwnerAndName", err)
+		}
+		return
+	}
+
+	perm, err := models.GetUserRepoPermission(newRepo, ctx.User)
+	if err != nil {
+		ctx.ServerError("GetUserRepoPermission", err)
+		return
+	}
+	if !perm.CanWrite(models.UnitTypeIssues) || newRepo.IsArchived {
+		ctx.Flash.Error(ctx.Tr("repo.issues.transfer.error_repo_not_exist"))
+		ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+		return
+	}
+
+	if err = issue_service.TransferIssue(issue, ctx.User, newRepo); err != nil {
+		if models.IsErrIssueTransferNotAllowed(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.transfer.error_not_allowed"))
+			ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+		} else {
+			ctx.ServerError("TransferIssue", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.issues.transfer.success", newRepo.FullName()))
+	ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+}
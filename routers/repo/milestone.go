@@ -263,6 +263,13 @@ func MilestoneIssuesAndPulls(ctx *context.Context) {
 	ctx.Data["Title"] = milestone.Name
 	ctx.Data["Milestone"] = milestone
 
+	snapshots, err := models.GetMilestoneBurndown(milestone)
+	if err != nil {
+		ctx.ServerError("GetMilestoneBurndown", err)
+		return
+	}
+	ctx.Data["BurndownChart"] = newBurndownChart(milestone, snapshots)
+
 	issues(ctx, milestoneID, 0, util.OptionalBoolNone)
 	ctx.Data["NewIssueChooseTemplate"] = len(ctx.IssueTemplatesFromDefaultBranch()) > 0
 
@@ -0,0 +1,127 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+const (
+	tplMilestonesVelocity base.TplName = "repo/issue/milestones_velocity"
+
+	burndownChartWidth  = 600
+	burndownChartHeight = 200
+
+	// milestonesVelocityLimit is the number of closed milestones shown by the velocity view
+	milestonesVelocityLimit = 10
+)
+
+// burndownChart holds the SVG coordinates of the burndown chart of a milestone
+type burndownChart struct {
+	Width, Height int
+	MaxIssues     int
+	Start, End    string
+	// Actual are the points of the open issues line
+	Actual string
+	// Ideal are the points of the line going from the first snapshot to zero at the due date
+	Ideal string
+}
+
+// newBurndownChart computes the chart of the snapshots, the x axis goes from the first snapshot
+// to the due date or the last snapshot if it comes later
+func newBurndownChart(m *models.Milestone, snapshots []*models.MilestoneSnapshot) *burndownChart {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	start, end := snapshots[0].Day, snapshots[len(snapshots)-1].Day
+	hasDeadline := m.DeadlineUnix.Year() != 9999 && !m.DeadlineUnix.IsZero()
+	if hasDeadline && m.DeadlineUnix > end {
+		end = m.DeadlineUnix
+	}
+	maxIssues := 1
+	for _, s := range snapshots {
+		if s.NumIssues > maxIssues {
+			maxIssues = s.NumIssues
+		}
+	}
+
+	chart := &burndownChart{
+		Width:     burndownChartWidth,
+		Height:    burndownChartHeight,
+		MaxIssues: maxIssues,
+		Start:     start.FormatInLocation("2006-01-02", setting.DefaultUILocation),
+		End:       end.FormatInLocation("2006-01-02", setting.DefaultUILocation),
+	}
+	x := func(day timeutil.TimeStamp) float64 {
+		if end == start {
+			return 0
+		}
+		return float64(day-start) * float64(chart.Width) / float64(end-start)
+	}
+	y := func(open int) float64 {
+		return float64(chart.Height) - float64(open)*float64(chart.Height)/float64(maxIssues)
+	}
+
+	points := make([]string, 0, len(snapshots))
+	for _, s := range snapshots {
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x(s.Day), y(s.NumOpenIssues)))
+	}
+	chart.Actual = strings.Join(points, " ")
+	if hasDeadline && end > start {
+		chart.Ideal = fmt.Sprintf("%.1f,%.1f %.1f,%.1f", x(start), y(snapshots[0].NumOpenIssues), x(m.DeadlineUnix), y(0))
+	}
+	return chart
+}
+
+// milestoneVelocityRow is a line of the velocity view
+type milestoneVelocityRow struct {
+	*models.MilestoneVelocity
+	PerWeek string
+	// Bar is the width of the bar in percent of the cell, the fastest milestone taking 80% to leave room for the label
+	Bar int
+}
+
+// MilestonesVelocity renders the velocity of the last closed milestones
+func MilestonesVelocity(ctx *context.Context) {
+	velocities, err := models.GetMilestonesVelocity(ctx.Repo.Repository.ID, milestonesVelocityLimit)
+	if err != nil {
+		ctx.ServerError("GetMilestonesVelocity", err)
+		return
+	}
+
+	maxIssuesPerWeek := 0.0
+	for _, v := range velocities {
+		if perWeek := v.IssuesPerWeek(); perWeek > maxIssuesPerWeek {
+			maxIssuesPerWeek = perWeek
+		}
+	}
+	rows := make([]*milestoneVelocityRow, 0, len(velocities))
+	for _, v := range velocities {
+		row := &milestoneVelocityRow{
+			MilestoneVelocity: v,
+			PerWeek:           fmt.Sprintf("%.1f", v.IssuesPerWeek()),
+		}
+		if maxIssuesPerWeek > 0 {
+			row.Bar = int(v.IssuesPerWeek() * 80 / maxIssuesPerWeek)
+		}
+		rows = append(rows, row)
+	}
+
+	ctx.Data["Title"] = ctx.Tr("repo.milestones.velocity")
+	ctx.Data["PageIsMilestones"] = true
+	ctx.Data["Velocities"] = rows
+	ctx.Data["AverageIssuesPerWeek"] = fmt.Sprintf("%.1f", velocities.AverageIssuesPerWeek())
+	ctx.Data["IsTimetrackerEnabled"] = ctx.Repo.Repository.IsTimetrackerEnabled()
+	ctx.HTML(http.StatusOK, tplMilestonesVelocity)
+}
@@ -36,10 +36,11 @@ import (
 )
 
 const (
-	tplFork        base.TplName = "repo/pulls/fork"
-	tplCompareDiff base.TplName = "repo/diff/compare"
-	tplPullCommits base.TplName = "repo/pulls/commits"
-	tplPullFiles   base.TplName = "repo/pulls/files"
+	tplFork         base.TplName = "repo/pulls/fork"
+	tplCompareDiff  base.TplName = "repo/diff/compare"
+	tplPullCommits  base.TplName = "repo/pulls/commits"
+	tplPullFiles    base.TplName = "repo/pulls/files"
+	tplPullFileDiff base.TplName = "repo/diff/file_only"
 
 	pullRequestTemplateKey = "PullRequestTemplate"
 )
@@ -309,7 +310,7 @@ func PrepareMergedViewPullInfo(ctx *context.Context, issue *models.Issue) *git.C
 	setMergeTarget(ctx, pull)
 	ctx.Data["HasMerged"] = true
 
-	compareInfo, err := ctx.Repo.GitRepo.GetCompareInfo(ctx.Repo.Repository.RepoPath(),
+	compareInfo, err := ctx.Repo.GitRepo.GetCompareInfo(ctx.Req.Context(), ctx.Repo.Repository.RepoPath(),
 		pull.MergeBase, pull.GetGitRefName())
 	if err != nil {
 		if strings.Contains(err.Error(), "fatal: Not a valid object name") || strings.Contains(err.Error(), "unknown revision or path not in the working tree") {
@@ -378,7 +379,7 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 			ctx.Data["LatestCommitStatus"] = models.CalcCommitStatus(commitStatuses)
 		}
 
-		compareInfo, err := baseGitRepo.GetCompareInfo(pull.BaseRepo.RepoPath(),
+		compareInfo, err := baseGitRepo.GetCompareInfo(ctx.Req.Context(), pull.BaseRepo.RepoPath(),
 			pull.MergeBase, pull.GetGitRefName())
 		if err != nil {
 			if strings.Contains(err.Error(), "fatal: Not a valid object name") {
@@ -486,7 +487,7 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 		}
 	}
 
-	compareInfo, err := baseGitRepo.GetCompareInfo(pull.BaseRepo.RepoPath(),
+	compareInfo, err := baseGitRepo.GetCompareInfo(ctx.Req.Context(), pull.BaseRepo.RepoPath(),
 		git.BranchPrefix+pull.BaseBranch, pull.GetGitRefName())
 	if err != nil {
 		if strings.Contains(err.Error(), "fatal: Not a valid object name") {
@@ -557,6 +558,25 @@ func ViewPullCommits(ctx *context.Context) {
 
 // ViewPullFiles render pull request changed files list page
 func ViewPullFiles(ctx *context.Context) {
+	viewPullFiles(ctx, nil, tplPullFiles)
+}
+
+// ViewPullFileDiff render the diff of a single changed file of a pull request
+func ViewPullFileDiff(ctx *context.Context) {
+	treePath := ctx.Query("path")
+	if len(treePath) == 0 {
+		ctx.NotFound("ViewPullFileDiff", nil)
+		return
+	}
+	files := []string{treePath}
+	if oldPath := ctx.Query("old_path"); len(oldPath) > 0 && oldPath != treePath {
+		// both names are needed to detect the rename
+		files = append(files, oldPath)
+	}
+	viewPullFiles(ctx, files, tplPullFileDiff)
+}
+
+func viewPullFiles(ctx *context.Context, files []string, tpl base.TplName) {
 	ctx.Data["PageIsPullList"] = true
 	ctx.Data["PageIsPullFiles"] = true
 
@@ -611,15 +631,56 @@ func ViewPullFiles(ctx *context.Context) {
 	ctx.Data["Reponame"] = ctx.Repo.Repository.Name
 	ctx.Data["AfterCommitID"] = endCommitID
 
-	diff, err := gitdiff.GetDiffRangeWithWhitespaceBehavior(diffRepoPath,
-		startCommitID, endCommitID, setting.Git.MaxGitDiffLines,
-		setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles,
-		whitespaceFlags[ctx.Data["WhitespaceBehavior"].(string)])
+	diffOptions := &gitdiff.DiffOptions{
+		BeforeCommitID:     startCommitID,
+		AfterCommitID:      endCommitID,
+		SkipTo:             ctx.Query("skip-to"),
+		Files:              files,
+		MaxLines:           setting.Git.MaxGitDiffLines,
+		MaxLineCharacters:  setting.Git.MaxGitDiffLineCharacters,
+		MaxFiles:           setting.Git.MaxGitDiffFiles,
+		WhitespaceBehavior: whitespaceFlags[ctx.Data["WhitespaceBehavior"].(string)],
+	}
+	if len(files) > 0 {
+		diffOptions.SkipTo = ""
+	}
+	diff, err := gitdiff.GetDiff(diffRepoPath, diffOptions)
 	if err != nil {
-		ctx.ServerError("GetDiffRangeWithWhitespaceBehavior", err)
+		ctx.ServerError("GetDiff", err)
 		return
 	}
 
+	// Only a part of the files has been loaded so list the others to allow loading them on demand,
+	// signed users also need the blob SHAs of the files which are not in their patches to track
+	// which ones they have viewed
+	if diff.IsIncomplete || len(diff.Start) > 0 || (ctx.IsSigned && !diff.HasAllBlobSHAs()) {
+		fileList, err := gitdiff.GetDiffFileList(diffRepoPath, diffOptions)
+		if err != nil {
+			ctx.ServerError("GetDiffFileList", err)
+			return
+		}
+		diff.MergeFileList(fileList)
+	}
+	if len(files) > 0 {
+		for _, file := range diff.Files {
+			file.Index = ctx.QueryInt("index")
+		}
+	}
+
+	if ctx.IsSigned {
+		reviewState, err := models.GetReviewState(ctx.User.ID, pull.ID)
+		if err != nil {
+			ctx.ServerError("GetReviewState", err)
+			return
+		}
+		diff.LoadReviewState(reviewState)
+	}
+	if updateAllowed, ok := ctx.Data["UpdateAllowed"].(bool); ok {
+		ctx.Data["CanApplySuggestions"] = updateAllowed && !issue.IsClosed
+	}
+	ctx.Data["ViewedFilesLink"] = fmt.Sprintf("%s/pulls/%d/files/viewed", ctx.Repo.RepoLink, issue.Index)
+	ctx.Data["DiffFileLink"] = fmt.Sprintf("%s/pulls/%d/files/file", ctx.Repo.RepoLink, issue.Index)
+
 	if err = diff.LoadComments(issue, ctx.User); err != nil {
 		ctx.ServerError("LoadComments", err)
 		return
@@ -678,7 +739,7 @@ func ViewPullFiles(ctx *context.Context) {
 	getBranchData(ctx, issue)
 	ctx.Data["IsIssuePoster"] = ctx.IsSigned && issue.IsPoster(ctx.User.ID)
 	ctx.Data["HasIssuesOrPullsWritePermission"] = ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull)
-	ctx.HTML(200, tplPullFiles)
+	ctx.HTML(200, tpl)
 }
 
 // UpdatePullRequest merge PR's baseBranch into headBranch
@@ -721,7 +782,7 @@ func UpdatePullRequest(ctx *context.Context) {
 	// default merge commit message
 	message := fmt.Sprintf("Merge branch '%s' into %s", issue.PullRequest.BaseBranch, issue.PullRequest.HeadBranch)
 
-	if err = pull_service.Update(issue.PullRequest, ctx.User, message); err != nil {
+	if err = pull_service.Update(ctx.Req.Context(), issue.PullRequest, ctx.User, message); err != nil {
 		if models.IsErrMergeConflicts(err) {
 			conflictError := err.(models.ErrMergeConflicts)
 			flashError, err := ctx.HTMLString(string(tplAlertDetails), map[string]interface{}{
@@ -849,7 +910,7 @@ func MergePullRequest(ctx *context.Context, form auth.MergePullRequestForm) {
 		return
 	}
 
-	if err = pull_service.Merge(pr, ctx.User, ctx.Repo.GitRepo, models.MergeStyle(form.Do), message); err != nil {
+	if err = pull_service.Merge(ctx.Req.Context(), pr, ctx.User, ctx.Repo.GitRepo, models.MergeStyle(form.Do), message); err != nil {
 		if models.IsErrInvalidMergeStyle(err) {
 			ctx.Flash.Error(ctx.Tr("repo.pulls.invalid_merge_option"))
 			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
@@ -1194,6 +1255,7 @@ func CleanUpPullRequest(ctx *context.Context) {
 			PusherName:   ctx.User.Name,
 			RepoUserName: pr.HeadRepo.Owner.Name,
 			RepoName:     pr.HeadRepo.Name,
+			RequestID:    log.RequestIDFromContext(ctx.Req.Context()),
 		}); err != nil {
 		log.Error("Update: %v", err)
 	}
@@ -6,11 +6,14 @@ package repo
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/repofiles"
 	pull_service "code.gitea.io/gitea/services/pull"
 )
 
@@ -34,12 +37,17 @@ func CreateCodeComment(ctx *context.Context, form auth.CodeCommentForm) {
 	if form.Side == "previous" {
 		signedLine *= -1
 	}
+	signedStartLine := form.StartLine
+	if form.StartSide == "previous" {
+		signedStartLine *= -1
+	}
 
 	comment, err := pull_service.CreateCodeComment(
 		ctx.User,
 		ctx.Repo.GitRepo,
 		issue,
 		signedLine,
+		signedStartLine,
 		form.Content,
 		form.TreePath,
 		form.IsReview,
@@ -108,6 +116,117 @@ func UpdateResolveConversation(ctx *context.Context) {
 	})
 }
 
+// UpdateViewedFiles marks or unmarks a file of a pull request as viewed by the current user
+func UpdateViewedFiles(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !issue.IsPull {
+		ctx.Error(400)
+		return
+	}
+
+	name := ctx.Query("path")
+	blobSHA := ctx.Query("sha")
+	if len(name) == 0 || len(blobSHA) == 0 {
+		ctx.Error(400)
+		return
+	}
+	if !ctx.QueryBool("viewed") {
+		blobSHA = ""
+	}
+
+	if err := models.UpdateReviewState(ctx.User.ID, issue.PullRequest.ID, map[string]string{name: blobSHA}); err != nil {
+		ctx.ServerError("UpdateReviewState", err)
+		return
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"ok": true,
+	})
+}
+
+// ApplySuggestions commits the suggestions of one or more code comments to the head branch of the pull request
+func ApplySuggestions(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !issue.IsPull {
+		ctx.NotFound("ApplySuggestions", nil)
+		return
+	}
+	redirect := fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index)
+
+	if issue.IsClosed {
+		ctx.Flash.Error(ctx.Tr("repo.pulls.apply_suggestion_closed"))
+		ctx.Redirect(redirect)
+		return
+	}
+
+	pr := issue.PullRequest
+	if err := pr.LoadHeadRepo(); err != nil {
+		ctx.ServerError("LoadHeadRepo", err)
+		return
+	}
+	if pr.HeadRepo == nil {
+		ctx.NotFound("ApplySuggestions", nil)
+		return
+	}
+	allowed, err := pull_service.IsUserAllowedToUpdate(pr, ctx.User)
+	if err != nil {
+		ctx.ServerError("IsUserAllowedToUpdate", err)
+		return
+	}
+	if !allowed {
+		ctx.Flash.Error(ctx.Tr("repo.pulls.apply_suggestion_not_allowed"))
+		ctx.Redirect(redirect)
+		return
+	}
+
+	var comments []*models.Comment
+	for _, id := range strings.Split(ctx.Query("comment_ids"), ",") {
+		commentID, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+		if err != nil {
+			continue
+		}
+		comment, err := models.GetCommentByID(commentID)
+		if err != nil {
+			ctx.NotFoundOrServerError("GetCommentByID", models.IsErrCommentNotExist, err)
+			return
+		}
+		if err := comment.LoadReview(); err != nil && !models.IsErrReviewNotExist(err) {
+			ctx.ServerError("LoadReview", err)
+			return
+		}
+		// suggestions of pending reviews are only visible to their author
+		if comment.Review != nil && comment.Review.Type == models.ReviewTypePending && comment.PosterID != ctx.User.ID {
+			ctx.NotFound("ApplySuggestions", nil)
+			return
+		}
+		comments = append(comments, comment)
+	}
+	if len(comments) == 0 {
+		ctx.Redirect(redirect)
+		return
+	}
+
+	if _, err := repofiles.ApplySuggestions(pr, ctx.User, comments, ctx.Query("message")); err != nil {
+		if models.IsErrInvalidSuggestion(err) || models.IsErrSuggestionOutdated(err) {
+			log.Debug("ApplySuggestions: %v", err)
+			ctx.Flash.Error(ctx.Tr("repo.pulls.apply_suggestion_outdated"))
+			ctx.Redirect(redirect)
+			return
+		}
+		ctx.ServerError("ApplySuggestions", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.pulls.apply_suggestion_success", len(comments)))
+	ctx.Redirect(redirect)
+}
+
 // SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist
 func SubmitReview(ctx *context.Context, form auth.SubmitReviewForm) {
 	issue := GetActionIssue(ctx)
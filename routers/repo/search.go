@@ -10,12 +10,19 @@ import (
 
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
 	"code.gitea.io/gitea/modules/setting"
 )
 
 const tplSearch base.TplName = "repo/search"
 
+// indexedRef a ref other than the default branch which can be searched
+type indexedRef struct {
+	Ref  string
+	Name string
+}
+
 // Search render repository search page
 func Search(ctx *context.Context) {
 	if !setting.Indexer.RepoIndexerEnabled {
@@ -28,14 +35,33 @@ func Search(ctx *context.Context) {
 	if page <= 0 {
 		page = 1
 	}
+
+	refs, err := code_indexer.GetIndexedRefs(ctx.Repo.Repository)
+	if err != nil {
+		ctx.ServerError("GetIndexedRefs", err)
+		return
+	}
+	indexedRefs := make([]*indexedRef, len(refs))
+	for i, ref := range refs {
+		indexedRefs[i] = &indexedRef{Ref: ref, Name: git.RefEndName(ref)}
+	}
+	// fallback to the default branch if the ref is not indexed
+	ref, _, err := code_indexer.ResolveIndexedRef(ctx.Repo.Repository, strings.TrimSpace(ctx.Query("ref")))
+	if err != nil {
+		ctx.ServerError("ResolveIndexedRef", err)
+		return
+	}
+
 	total, searchResults, searchResultLanguages, err := code_indexer.PerformSearch([]int64{ctx.Repo.Repository.ID},
-		language, keyword, page, setting.UI.RepoSearchPagingNum)
+		ref, language, keyword, page, setting.UI.RepoSearchPagingNum)
 	if err != nil {
 		ctx.ServerError("SearchResults", err)
 		return
 	}
 	ctx.Data["Keyword"] = keyword
 	ctx.Data["Language"] = language
+	ctx.Data["Ref"] = ref
+	ctx.Data["IndexedRefs"] = indexedRefs
 	ctx.Data["SourcePath"] = setting.AppSubURL + "/" +
 		path.Join(ctx.Repo.Repository.Owner.Name, ctx.Repo.Repository.Name)
 	ctx.Data["SearchResults"] = searchResults
@@ -46,6 +72,7 @@ func Search(ctx *context.Context) {
 	pager := context.NewPagination(total, setting.UI.RepoSearchPagingNum, page, 5)
 	pager.SetDefaultParams(ctx)
 	pager.AddParam(ctx, "l", "Language")
+	pager.AddParam(ctx, "ref", "Ref")
 	ctx.Data["Page"] = pager
 
 	ctx.HTML(200, tplSearch)
@@ -19,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
@@ -54,6 +55,9 @@ func Settings(ctx *context.Context) {
 	signing, _ := models.SigningKey(ctx.Repo.Repository.RepoPath())
 	ctx.Data["SigningKeyAvailable"] = len(signing) > 0
 	ctx.Data["SigningSettings"] = setting.Repository.Signing
+	ctx.Data["IsRepoIndexerEnabled"] = setting.Indexer.RepoIndexerEnabled
+	ctx.Data["CodeIndexerRefs"] = strings.Join(ctx.Repo.Repository.CodeIndexerRefs, "\n")
+	ctx.Data["MaxExtraRefs"] = setting.Indexer.MaxExtraRefs
 
 	ctx.HTML(200, tplSettingsOptions)
 }
@@ -138,6 +142,28 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
 		ctx.Redirect(repo.Link() + "/settings")
 
+	case "code_indexer":
+		if !setting.Indexer.RepoIndexerEnabled {
+			ctx.NotFound("", nil)
+			return
+		}
+		refs := make([]string, 0, 5)
+		for _, ref := range strings.Split(form.CodeIndexerRefs, "\n") {
+			if ref = strings.TrimSpace(ref); len(ref) > 0 {
+				refs = append(refs, ref)
+			}
+		}
+		repo.CodeIndexerRefs = refs
+		if err := models.UpdateRepositoryCols(repo, "code_indexer_refs"); err != nil {
+			ctx.ServerError("UpdateRepositoryCols", err)
+			return
+		}
+		code_indexer.UpdateRepoIndexer(repo)
+		log.Trace("Repository code indexer settings updated: %s/%s", ctx.Repo.Owner.Name, repo.Name)
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+		ctx.Redirect(repo.Link() + "/settings")
+
 	case "mirror":
 		if !repo.IsMirror {
 			ctx.NotFound("", nil)
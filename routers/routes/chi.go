@@ -22,6 +22,7 @@ import (
 	"code.gitea.io/gitea/modules/public"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/tracing"
 	"code.gitea.io/gitea/routers"
 
 	"github.com/go-chi/chi"
@@ -34,6 +35,7 @@ type routerLoggerOptions struct {
 	Identity       *string
 	Start          *time.Time
 	ResponseWriter http.ResponseWriter
+	RequestID      string
 }
 
 // SignedUserName returns signed user's name via context
@@ -60,18 +62,21 @@ func setupAccessLogger(c chi.Router) {
 			}
 			rw := w
 
+			requestID := log.RequestIDFromContext(req.Context())
+
 			buf := bytes.NewBuffer([]byte{})
 			err := logTemplate.Execute(buf, routerLoggerOptions{
 				req:            req,
 				Identity:       &identity,
 				Start:          &start,
 				ResponseWriter: rw,
+				RequestID:      requestID,
 			})
 			if err != nil {
 				log.Error("Could not set up macaron access logger: %v", err.Error())
 			}
 
-			err = logger.SendLog(log.INFO, "", "", 0, buf.String(), "")
+			err = logger.SendLogWithRequestID(requestID, log.INFO, "", "", 0, buf.String(), "")
 			if err != nil {
 				log.Error("Could not set up macaron access logger: %v", err.Error())
 			}
@@ -84,15 +89,64 @@ func LoggerHandler(level log.Level) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			start := time.Now()
+			logger := log.GetLogger("router").WithRequestID(log.RequestIDFromContext(req.Context()))
 
-			_ = log.GetLogger("router").Log(0, level, "Started %s %s for %s", log.ColoredMethod(req.Method), req.RequestURI, req.RemoteAddr)
+			_ = logger.Log(0, level, "Started %s %s for %s", log.ColoredMethod(req.Method), req.RequestURI, req.RemoteAddr)
 
 			next.ServeHTTP(w, req)
 
 			ww := middleware.NewWrapResponseWriter(w, req.ProtoMajor)
 
 			status := ww.Status()
-			_ = log.GetLogger("router").Log(0, level, "Completed %s %s %v %s in %v", log.ColoredMethod(req.Method), req.RequestURI, log.ColoredStatus(status), log.ColoredStatus(status, http.StatusText(status)), log.ColoredTime(time.Since(start)))
+			_ = logger.Log(0, level, "Completed %s %s %v %s in %v", log.ColoredMethod(req.Method), req.RequestURI, log.ColoredStatus(status), log.ColoredStatus(status, http.StatusText(status)), log.ColoredTime(time.Since(start)))
+		})
+	}
+}
+
+// RequestID returns a middleware that gives each request an ID, which is carried by the request's
+// context and returned in the X-Request-ID response header. A valid ID provided by the client, e.g.
+// by a reverse proxy or by a Gitea hook calling the internal API, is reused.
+func RequestID() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requestID := req.Header.Get(log.RequestIDHeader)
+			if !log.IsValidRequestID(requestID) {
+				requestID = log.NewRequestID()
+			}
+			w.Header().Set(log.RequestIDHeader, requestID)
+
+			next.ServeHTTP(w, req.WithContext(log.ContextWithRequestID(req.Context(), requestID)))
+		})
+	}
+}
+
+// Tracing returns a middleware that starts a server span for each request, continuing the trace
+// from the traceparent header if the client provided one
+func Tracing() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if !tracing.IsEnabled() {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			ctx, span := tracing.Start(tracing.Extract(req.Context(), req.Header), "HTTP "+req.Method, tracing.SpanKindServer)
+			defer span.End()
+			span.SetAttribute("http.method", req.Method)
+			span.SetAttribute("http.target", req.URL.Path)
+			span.SetAttribute("http.request_id", log.RequestIDFromContext(req.Context()))
+
+			ww := middleware.NewWrapResponseWriter(w, req.ProtoMajor)
+			next.ServeHTTP(ww, req.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetAttribute("http.status_code", status)
+			if status >= 500 {
+				span.SetError(errors.New(http.StatusText(status)))
+			}
 		})
 	}
 }
@@ -197,6 +251,11 @@ func storageHandler(storageSetting setting.Storage, prefix string, objStore stor
 func NewChi() chi.Router {
 	c := chi.NewRouter()
 	c.Use(middleware.RealIP)
+	c.Use(RequestID())
+	c.Use(Tracing())
+	if setting.Metrics.Enabled {
+		c.Use(Metrics())
+	}
 	if !setting.DisableRouterLog && setting.RouterLogLevel != log.NONE {
 		if log.GetLogger("router").GetLevel() <= setting.RouterLogLevel {
 			c.Use(LoggerHandler(setting.RouterLogLevel))
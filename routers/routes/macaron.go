@@ -57,6 +57,9 @@ func NewMacaron() *macaron.Macaron {
 		m = macaron.New()
 	}
 
+	if setting.Metrics.Enabled {
+		m.SetHandlerWrapper(recordRouteName)
+	}
 	if setting.EnableGzip {
 		m.Use(gzip.Middleware())
 	}
@@ -200,6 +203,11 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 	m.Combo("/install", routers.InstallInit).Get(routers.Install).
 		Post(bindIgnErr(auth.InstallForm{}), routers.InstallPost)
 	m.Get("/^:type(issues|pulls)$", reqSignIn, user.Issues)
+	m.Group("/issues/filters", func() {
+		m.Combo("").Get(user.IssueFilters).
+			Post(bindIgnErr(auth.SaveIssueFilterForm{}), user.SaveIssueFilterPost)
+		m.Post("/:id/action/:action", user.IssueFilterAction)
+	}, reqSignIn)
 	m.Get("/milestones", reqSignIn, reqMilestonesDashboardPageEnabled, user.Milestones)
 
 	// ***** START: User *****
@@ -338,12 +346,19 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		m.Group("/monitor", func() {
 			m.Get("", admin.Monitor)
 			m.Post("/cancel/:pid", admin.MonitorCancel)
+			m.Group("/cron/:task", func() {
+				m.Get("", admin.CronTask)
+				m.Post("/set", admin.SetCronTask)
+				m.Post("/reset", admin.ResetCronTask)
+			})
 			m.Group("/queue/:qid", func() {
 				m.Get("", admin.Queue)
 				m.Post("/set", admin.SetQueueSettings)
 				m.Post("/add", admin.AddWorkers)
 				m.Post("/cancel/:pid", admin.WorkerCancel)
 				m.Post("/flush", admin.Flush)
+				m.Post("/pause", admin.Pause)
+				m.Post("/resume", admin.Resume)
 			})
 		})
 
@@ -448,6 +463,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Get("/dashboard", user.Dashboard)
 			m.Get("/^:type(issues|pulls)$", user.Issues)
 			m.Get("/milestones", reqMilestonesDashboardPageEnabled, user.Milestones)
+			m.Get("/times", org.TimeReport)
 			m.Get("/members", org.Members)
 			m.Post("/members/action/:action", org.MembersAction)
 
@@ -641,9 +657,14 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 					m.Post("/add", repo.AddDependency)
 					m.Post("/delete", repo.RemoveDependency)
 				})
+				m.Post("/parent", repo.UpdateIssueParent)
+				m.Post("/children/add", repo.AddIssueChild)
+				m.Post("/pin", repo.IssuePin)
+				m.Post("/transfer", repo.TransferIssuePost)
 				m.Combo("/comments").Post(repo.MustAllowUserComment, bindIgnErr(auth.CreateCommentForm{}), repo.NewComment)
 				m.Group("/times", func() {
 					m.Post("/add", bindIgnErr(auth.AddTimeManuallyForm{}), repo.AddTimeManually)
+					m.Post("/estimate", bindIgnErr(auth.EstimatedTimeForm{}), repo.SetEstimatedTime)
 					m.Group("/stopwatch", func() {
 						m.Post("/toggle", repo.IssueStopwatch)
 						m.Post("/cancel", repo.CancelStopwatch)
@@ -666,6 +687,8 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Post("/status", reqRepoIssuesOrPullsWriter, repo.UpdateIssueStatus)
 			m.Post("/resolve_conversation", reqRepoIssuesOrPullsReader, repo.UpdateResolveConversation)
 			m.Post("/attachments", repo.UploadIssueAttachment)
+			m.Post("/attachments/presign", repo.PresignIssueAttachment)
+			m.Post("/attachments/complete", repo.CompleteIssueAttachment)
 			m.Post("/attachments/remove", repo.DeleteAttachment)
 		}, context.RepoMustNotBeArchived())
 		m.Group("/comments/:id", func() {
@@ -740,6 +763,8 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Post("/new", bindIgnErr(auth.NewReleaseForm{}), repo.NewReleasePost)
 			m.Post("/delete", repo.DeleteRelease)
 			m.Post("/attachments", repo.UploadReleaseAttachment)
+			m.Post("/attachments/presign", repo.PresignReleaseAttachment)
+			m.Post("/attachments/complete", repo.CompleteReleaseAttachment)
 			m.Post("/attachments/remove", repo.DeleteAttachment)
 		}, reqSignIn, repo.MustBeNotEmpty, context.RepoMustNotBeArchived(), reqRepoReleaseWriter, context.RepoRef())
 		m.Post("/tags/delete", repo.DeleteTag, reqSignIn,
@@ -754,7 +779,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 				ctx.ServerError("GetBranchCommit", err)
 				return
 			}
-			ctx.Repo.CommitsCount, err = ctx.Repo.GetCommitsCount()
+			ctx.Repo.CommitsCount, err = ctx.Repo.GetCommitsCount(ctx.Req.Context())
 			if err != nil {
 				ctx.ServerError("GetCommitsCount", err)
 				return
@@ -773,6 +798,8 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Get("/^:type(issues|pulls)$/:index", repo.ViewIssue)
 			m.Get("/labels/", reqRepoIssuesOrPullsReader, repo.RetrieveLabels, repo.Labels)
 			m.Get("/milestones", reqRepoIssuesOrPullsReader, repo.Milestones)
+			m.Get("/milestones/velocity", reqRepoIssuesOrPullsReader, repo.MilestonesVelocity)
+			m.Get("/times", reqSignIn, reqRepoIssueReader, repo.TimeReport)
 		}, context.RepoRef())
 
 		m.Group("/projects", func() {
@@ -853,9 +880,12 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Post("/cleanup", context.RepoMustNotBeArchived(), context.RepoRef(), repo.CleanUpPullRequest)
 			m.Group("/files", func() {
 				m.Get("", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.ViewPullFiles)
+				m.Get("/file", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.ViewPullFileDiff)
+				m.Post("/viewed", reqSignIn, repo.UpdateViewedFiles)
 				m.Group("/reviews", func() {
 					m.Post("/comments", bindIgnErr(auth.CodeCommentForm{}), repo.CreateCodeComment)
 					m.Post("/submit", bindIgnErr(auth.SubmitReviewForm{}), repo.SubmitReview)
+					m.Post("/suggestions", repo.ApplySuggestions)
 				}, context.RepoMustNotBeArchived())
 			})
 		}, repo.MustAllowPulls)
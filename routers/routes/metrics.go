@@ -0,0 +1,94 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package routes
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitea.com/macaron/macaron"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "gitea",
+	Subsystem: "http",
+	Name:      "request_duration_seconds",
+	Help:      "Duration of HTTP requests by method, route and status code",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "route", "status"})
+
+func init() {
+	prometheus.MustRegister(requestDuration)
+}
+
+type routeContextKey struct{}
+
+// routeName holds the name of the macaron handler which served a request
+type routeName struct {
+	name string
+}
+
+// Metrics returns a middleware that records the duration of the requests
+func Metrics() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			route := &routeName{}
+			ww := middleware.NewWrapResponseWriter(w, req.ProtoMajor)
+			next.ServeHTTP(ww, req.WithContext(context.WithValue(req.Context(), routeContextKey{}, route)))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			requestDuration.WithLabelValues(req.Method, requestRoute(req, route), strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// requestRoute returns the pattern of the chi route or the name of the macaron handler which served the request
+func requestRoute(req *http.Request, route *routeName) string {
+	if route.name != "" {
+		return route.name
+	}
+	// The chi route context is only populated once the request has reached the router
+	if rctx := chi.RouteContext(req.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" && pattern != "/*" {
+			return pattern
+		}
+	}
+	return "other"
+}
+
+// recordRouteName wraps the macaron handlers so that the last of them to be run for a request, which is
+// normally the one that serves it, is used as the route of the request in the metrics.
+// Macaron does not wrap handlers it can invoke directly, such as func(*macaron.Context).
+func recordRouteName(h macaron.Handler) macaron.Handler {
+	name := runtime.FuncForPC(reflect.ValueOf(h).Pointer()).Name()
+	name = strings.TrimPrefix(name, "code.gitea.io/gitea/")
+
+	return func(ctx *macaron.Context) {
+		if route, ok := ctx.Req.Context().Value(routeContextKey{}).(*routeName); ok {
+			route.name = name
+		}
+		vals, err := ctx.Invoke(h)
+		if err != nil {
+			panic(err)
+		}
+		if len(vals) > 0 {
+			ev := ctx.GetVal(reflect.TypeOf(macaron.ReturnHandler(nil)))
+			handleReturn := ev.Interface().(macaron.ReturnHandler)
+			handleReturn(ctx, vals)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitea.com/macaron/macaron"
+	"github.com/go-chi/chi"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func macaronTestHandler(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusTeapot)
+}
+
+func requestCount(t *testing.T, method, route, status string) uint64 {
+	var m dto.Metric
+	assert.NoError(t, requestDuration.WithLabelValues(method, route, status).(interface{ Write(*dto.Metric) error }).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestMetrics(t *testing.T) {
+	m := macaron.New()
+	m.SetHandlerWrapper(recordRouteName)
+	m.Get("/macaron/:name", func() {}, macaronTestHandler)
+
+	c := chi.NewRouter()
+	c.Use(Metrics())
+	c.Get("/chi/{name}", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	c.NotFound(m.ServeHTTP)
+
+	for _, tc := range []struct {
+		path   string
+		route  string
+		status string
+	}{
+		{"/chi/test", "/chi/{name}", "202"},
+		{"/macaron/test", "routers/routes.macaronTestHandler", "418"},
+		{"/unknown", "other", "404"},
+	} {
+		before := requestCount(t, "GET", tc.route, tc.status)
+		c.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", tc.path, nil))
+		assert.Equal(t, before+1, requestCount(t, "GET", tc.route, tc.status), tc.path)
+	}
+}
@@ -406,7 +406,16 @@ func Issues(ctx *context.Context) {
 		}
 	}
 
+	keyword := strings.Trim(ctx.Query("q"), " ")
+	searchQuery := models.ParseIssueSearchQuery(keyword, ctx.User)
+	if len(sortType) == 0 {
+		sortType = searchQuery.SortType
+	}
+
 	isShowClosed := ctx.Query("state") == "closed"
+	if len(ctx.Query("state")) == 0 && !searchQuery.IsClosed.IsNone() {
+		isShowClosed = searchQuery.IsClosed.IsTrue()
+	}
 
 	// Get repositories.
 	var err error
@@ -438,9 +447,15 @@ func Issues(ctx *context.Context) {
 		userRepoIDs = []int64{-1}
 	}
 
+	loadPinnedIssueFilters(ctx, ctxUser, isPullList, userRepoIDs)
+	if ctx.Written() {
+		return
+	}
+
 	opts := &models.IssuesOptions{
-		IsPull:   util.OptionalBoolOf(isPullList),
-		SortType: sortType,
+		IsPull:      util.OptionalBoolOf(isPullList),
+		SortType:    sortType,
+		SearchQuery: searchQuery,
 	}
 
 	switch filterMode {
@@ -456,15 +471,14 @@ func Issues(ctx *context.Context) {
 
 	var forceEmpty bool
 	var issueIDsFromSearch []int64
-	var keyword = strings.Trim(ctx.Query("q"), " ")
 
-	if len(keyword) > 0 {
+	if len(searchQuery.Keyword) > 0 {
 		searchRepoIDs, err := models.GetRepoIDsForIssuesOptions(opts, ctxUser)
 		if err != nil {
 			ctx.ServerError("GetRepoIDsForIssuesOptions", err)
 			return
 		}
-		issueIDsFromSearch, err = issue_indexer.SearchIssuesByKeyword(searchRepoIDs, keyword)
+		issueIDsFromSearch, err = issue_indexer.SearchIssuesByKeyword(searchRepoIDs, searchQuery.Keyword)
 		if err != nil {
 			ctx.ServerError("SearchIssuesByKeyword", err)
 			return
@@ -592,6 +606,7 @@ func Issues(ctx *context.Context) {
 			IsPull:      isPullList,
 			IsClosed:    isShowClosed,
 			IssueIDs:    issueIDsFromSearch,
+			SearchQuery: searchQuery,
 		}
 		if len(repoIDs) > 0 {
 			statsOpts.RepoIDs = repoIDs
@@ -614,6 +629,7 @@ func Issues(ctx *context.Context) {
 			IsPull:      isPullList,
 			IsClosed:    isShowClosed,
 			IssueIDs:    issueIDsFromSearch,
+			SearchQuery: searchQuery,
 		})
 		if err != nil {
 			ctx.ServerError("GetUserIssueStats All", err)
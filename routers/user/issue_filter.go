@@ -0,0 +1,267 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+const (
+	tplIssueFilters base.TplName = "user/dashboard/issue_filters"
+)
+
+// issueFilterItem an issue filter with the data to render it
+type issueFilterItem struct {
+	Filter     *models.IssueFilter
+	Link       string
+	Count      int64
+	CanManage  bool
+	Subscribed bool
+}
+
+// issueFilterLink returns the link to the issues dashboard of the owner of the filter searching with the filter
+func issueFilterLink(filter *models.IssueFilter) string {
+	link := setting.AppSubURL
+	if filter.Owner.IsOrganization() {
+		link += "/org/" + url.PathEscape(filter.Owner.Name)
+	}
+	if filter.IsPull {
+		link += "/pulls"
+	} else {
+		link += "/issues"
+	}
+
+	params := url.Values{}
+	params.Set("type", "your_repositories")
+	if len(filter.RepoIDs) > 0 {
+		repos, _ := json.Marshal(filter.RepoIDs)
+		params.Set("repos", string(repos))
+	}
+	params.Set("q", filter.Query)
+	return link + "?" + params.Encode()
+}
+
+// countIssueFilter counts the issues of the filter in the repositories
+func countIssueFilter(ctx *context.Context, filter *models.IssueFilter, repoIDs []int64) (int64, error) {
+	opts := filter.IssuesOptions(ctx.User, repoIDs)
+	if len(opts.SearchQuery.Keyword) > 0 {
+		issueIDs, err := issue_indexer.SearchIssuesByKeyword(opts.RepoIDs, opts.SearchQuery.Keyword)
+		if err != nil {
+			return 0, err
+		}
+		if len(issueIDs) == 0 {
+			return 0, nil
+		}
+		opts.IssueIDs = issueIDs
+	}
+	return models.CountIssues(opts)
+}
+
+// loadPinnedIssueFilters loads the pinned issue filters of the context user of the issues dashboard
+// with their counts of issues in the repositories of the dashboard
+func loadPinnedIssueFilters(ctx *context.Context, ctxUser *models.User, isPull bool, repoIDs []int64) {
+	filters, err := models.FindIssueFilters(&models.FindIssueFiltersOptions{
+		OwnerIDs: []int64{ctxUser.ID},
+		IsPull:   util.OptionalBoolOf(isPull),
+		IsPinned: util.OptionalBoolTrue,
+	})
+	if err != nil {
+		ctx.ServerError("FindIssueFilters", err)
+		return
+	}
+
+	items := make([]*issueFilterItem, 0, len(filters))
+	for _, filter := range filters {
+		filter.Owner = ctxUser
+		count, err := countIssueFilter(ctx, filter, repoIDs)
+		if err != nil {
+			ctx.ServerError("countIssueFilter", err)
+			return
+		}
+		items = append(items, &issueFilterItem{
+			Filter: filter,
+			Link:   issueFilterLink(filter),
+			Count:  count,
+		})
+	}
+	ctx.Data["IssueFilters"] = items
+	ctx.Data["CanSaveIssueFilter"] = !ctxUser.IsOrganization() || ctx.Org.IsOwner
+}
+
+// IssueFilters render the saved issue filters of the user and of the organizations of the user
+func IssueFilters(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("home.issues.saved_filters")
+	ctx.Data["PageIsIssueFilters"] = true
+	getDashboardContextUser(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	orgs, err := models.GetOrgsByUserID(ctx.User.ID, true)
+	if err != nil {
+		ctx.ServerError("GetOrgsByUserID", err)
+		return
+	}
+	owners := make(map[int64]*models.User, len(orgs)+1)
+	owners[ctx.User.ID] = ctx.User
+	ownerIDs := []int64{ctx.User.ID}
+	for _, org := range orgs {
+		owners[org.ID] = org
+		ownerIDs = append(ownerIDs, org.ID)
+	}
+
+	filters, err := models.FindIssueFilters(&models.FindIssueFiltersOptions{
+		OwnerIDs: ownerIDs,
+	})
+	if err != nil {
+		ctx.ServerError("FindIssueFilters", err)
+		return
+	}
+	subscribedIDs, err := models.GetSubscribedIssueFilterIDs(ctx.User.ID)
+	if err != nil {
+		ctx.ServerError("GetSubscribedIssueFilterIDs", err)
+		return
+	}
+
+	items := make([]*issueFilterItem, 0, len(filters))
+	for _, filter := range filters {
+		filter.Owner = owners[filter.OwnerID]
+		canManage, err := filter.CanBeManagedBy(ctx.User)
+		if err != nil {
+			ctx.ServerError("CanBeManagedBy", err)
+			return
+		}
+		items = append(items, &issueFilterItem{
+			Filter:     filter,
+			Link:       issueFilterLink(filter),
+			CanManage:  canManage,
+			Subscribed: util.IsInt64InSlice(filter.ID, subscribedIDs),
+		})
+	}
+	ctx.Data["IssueFilters"] = items
+
+	ctx.HTML(http.StatusOK, tplIssueFilters)
+}
+
+// SaveIssueFilterPost saves an issue search as a filter of the user or of an organization
+func SaveIssueFilterPost(ctx *context.Context, form auth.SaveIssueFilterForm) {
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(setting.AppSubURL + "/issues/filters")
+		return
+	}
+
+	owner := ctx.User
+	if form.OwnerID > 0 && form.OwnerID != ctx.User.ID {
+		org, err := models.GetUserByID(form.OwnerID)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.NotFound("GetUserByID", err)
+			} else {
+				ctx.ServerError("GetUserByID", err)
+			}
+			return
+		}
+		isOwner, err := models.IsOrganizationOwner(org.ID, ctx.User.ID)
+		if err != nil {
+			ctx.ServerError("IsOrganizationOwner", err)
+			return
+		}
+		if !org.IsOrganization() || !isOwner {
+			ctx.Error(http.StatusForbidden)
+			return
+		}
+		owner = org
+	}
+
+	var repoIDs []int64
+	if issueReposQueryPattern.MatchString(form.Repos) {
+		for _, rID := range strings.Split(form.Repos[1:len(form.Repos)-1], ",") {
+			if id, err := strconv.ParseInt(rID, 10, 64); err == nil && id > 0 {
+				repoIDs = append(repoIDs, id)
+			}
+		}
+	}
+
+	filter := &models.IssueFilter{
+		OwnerID:  owner.ID,
+		Owner:    owner,
+		Name:     form.Name,
+		Query:    strings.TrimSpace(form.Query),
+		RepoIDs:  repoIDs,
+		IsPull:   form.IsPull,
+		IsPinned: form.IsPinned,
+	}
+	if err := models.CreateIssueFilter(filter); err != nil {
+		ctx.ServerError("CreateIssueFilter", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("home.issues.filter_saved", filter.Name))
+	ctx.Redirect(issueFilterLink(filter))
+}
+
+// IssueFilterAction response for actions on a saved issue filter
+func IssueFilterAction(ctx *context.Context) {
+	filter, err := models.GetIssueFilterByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrIssueFilterNotExist(err) {
+			ctx.NotFound("GetIssueFilterByID", err)
+		} else {
+			ctx.ServerError("GetIssueFilterByID", err)
+		}
+		return
+	}
+
+	action := ctx.Params(":action")
+	var allowed bool
+	switch action {
+	case "pin", "unpin", "delete":
+		allowed, err = filter.CanBeManagedBy(ctx.User)
+	case "subscribe", "unsubscribe":
+		allowed, err = filter.CanBeUsedBy(ctx.User)
+	default:
+		ctx.NotFound("IssueFilterAction", nil)
+		return
+	}
+	if err != nil {
+		ctx.ServerError("IssueFilterPermission", err)
+		return
+	}
+	if !allowed {
+		ctx.NotFound("IssueFilterAction", nil)
+		return
+	}
+
+	switch action {
+	case "pin", "unpin":
+		filter.IsPinned = action == "pin"
+		err = models.UpdateIssueFilterCols(filter, "is_pinned")
+	case "subscribe", "unsubscribe":
+		err = models.SubscribeIssueFilter(ctx.User.ID, filter.ID, action == "subscribe")
+	case "delete":
+		if err = models.DeleteIssueFilterByID(filter.ID); err == nil {
+			ctx.Flash.Success(ctx.Tr("home.issues.filter_deleted", filter.Name))
+		}
+	}
+	if err != nil {
+		ctx.ServerError("IssueFilterAction", err)
+		return
+	}
+
+	ctx.Redirect(setting.AppSubURL + "/issues/filters")
+}
@@ -160,6 +160,39 @@ func EmailPost(ctx *context.Context, form auth.AddEmailForm) {
 		ctx.Redirect(setting.AppSubURL + "/user/settings/account")
 		return
 	}
+	// Set the emailed events and the digest mode
+	if ctx.Query("_method") == "NOTIFICATION_EVENTS" {
+		digest := ctx.Query("digest")
+		if !(digest == models.EmailDigestNone ||
+			digest == models.EmailDigestHourly ||
+			digest == models.EmailDigestDaily) {
+			log.Error("Email digest change returned unrecognized option %s: %s", digest, ctx.User.Name)
+			ctx.ServerError("SetEmailNotificationsDigest", errors.New("option unrecognized"))
+			return
+		}
+		emailed := make(map[models.EmailNotificationEvent]bool, len(models.EmailNotificationEvents))
+		for _, event := range ctx.QueryStrings("events") {
+			emailed[models.EmailNotificationEvent(event)] = true
+		}
+		optOuts := make([]models.EmailNotificationEvent, 0, len(models.EmailNotificationEvents))
+		for _, event := range models.EmailNotificationEvents {
+			if !emailed[event] {
+				optOuts = append(optOuts, event)
+			}
+		}
+		if err := models.SetEmailNotificationOptOuts(ctx.User.ID, optOuts); err != nil {
+			ctx.ServerError("SetEmailNotificationOptOuts", err)
+			return
+		}
+		if err := ctx.User.SetEmailNotificationsDigest(digest); err != nil {
+			ctx.ServerError("SetEmailNotificationsDigest", err)
+			return
+		}
+		log.Trace("Email notification events and digest %s made: %s", digest, ctx.User.Name)
+		ctx.Flash.Success(ctx.Tr("settings.email_preference_set_success"))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/account")
+		return
+	}
 
 	if ctx.HasError() {
 		loadAccountData(ctx)
@@ -299,6 +332,14 @@ func loadAccountData(ctx *context.Context) {
 	}
 	ctx.Data["Emails"] = emails
 	ctx.Data["EmailNotificationsPreference"] = ctx.User.EmailNotifications()
+	ctx.Data["EmailNotificationsDigest"] = ctx.User.EmailNotificationsDigest
+	ctx.Data["EmailNotificationEvents"] = models.EmailNotificationEvents
+	optOuts, err := models.GetEmailNotificationOptOuts(ctx.User.ID)
+	if err != nil {
+		ctx.ServerError("GetEmailNotificationOptOuts", err)
+		return
+	}
+	ctx.Data["EmailNotificationOptOuts"] = optOuts
 	ctx.Data["ActivationsPending"] = pendingActivation
 	ctx.Data["CanAddEmails"] = !pendingActivation || !setting.Service.RegisterEmailConfirm
 }
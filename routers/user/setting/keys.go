@@ -141,6 +141,20 @@ func KeysPost(ctx *context.Context, form auth.AddKeyForm) {
 		}
 		ctx.Flash.Success(ctx.Tr("settings.add_key_success", form.Title))
 		ctx.Redirect(setting.AppSubURL + "/user/settings/keys")
+	case "verify_ssh":
+		fingerprint, err := models.VerifySSHKey(ctx.User, form.Fingerprint, form.Content)
+		if err != nil {
+			switch {
+			case models.IsErrSSHInvalidTokenSignature(err), models.IsErrKeyNotExist(err):
+				ctx.Flash.Error(ctx.Tr("settings.ssh_invalid_token_signature"))
+			default:
+				ctx.ServerError("VerifySSHKey", err)
+				return
+			}
+		} else {
+			ctx.Flash.Success(ctx.Tr("settings.verify_ssh_key_success", fingerprint))
+		}
+		ctx.Redirect(setting.AppSubURL + "/user/settings/keys")
 
 	default:
 		ctx.Flash.Warning("Function not implemented")
@@ -187,6 +201,7 @@ func loadKeysData(ctx *context.Context) {
 		return
 	}
 	ctx.Data["Keys"] = keys
+	ctx.Data["TokenToSign"] = models.VerificationToken(ctx.User, 0)
 
 	gpgkeys, err := models.ListGPGKeys(ctx.User.ID, models.ListOptions{})
 	if err != nil {
@@ -10,12 +10,14 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/cluster"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/graceful"
@@ -194,6 +196,15 @@ func doArchive(r *ArchiveRequest) {
 	// has finished.
 	defer close(r.cchan)
 
+	// Another instance sharing the repositories may be creating the same
+	// archive, so wait for it to finish.
+	archiveLock, err := cluster.Acquire(graceful.GetManager().ShutdownContext(), "archive:"+base.EncodeSha1(r.archivePath))
+	if err != nil {
+		log.Error("Unable to lock archive %s: %v", r.archivePath, err)
+		return
+	}
+	defer archiveLock.Release()
+
 	// It could have happened that we enqueued two archival requests, due to
 	// race conditions and difficulties in locking.  Do one last check that
 	// the archive we're referring to doesn't already exist.  If it does exist,
@@ -224,14 +235,19 @@ func doArchive(r *ArchiveRequest) {
 		return
 	}
 
-	// Now we copy it into place
-	if destArchive, err = os.Create(r.archivePath); err != nil {
+	// Now we copy it next to its destination and rename it into place, so
+	// that no one serves a partially written archive
+	if destArchive, err = ioutil.TempFile(filepath.Dir(r.archivePath), "archive"); err != nil {
 		log.Error("Unable to open archive " + r.archivePath)
 		return
 	}
 	_, err = io.Copy(destArchive, tmpArchive)
 	destArchive.Close()
+	if err == nil {
+		err = os.Rename(destArchive.Name(), r.archivePath)
+	}
 	if err != nil {
+		os.Remove(destArchive.Name())
 		log.Error("Unable to write archive " + r.archivePath)
 		return
 	}
@@ -18,6 +18,7 @@ import (
 	"os/exec"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"code.gitea.io/gitea/models"
@@ -76,6 +77,8 @@ type DiffLine struct {
 	Content     string
 	Comments    []*models.Comment
 	SectionInfo *DiffLineSectionInfo
+	// IsInCommentRange is true if the line is part of the range of a multi-line comment
+	IsInCommentRange bool
 }
 
 // DiffLineSectionInfo represents diff line section meta data
@@ -378,6 +381,38 @@ type DiffFile struct {
 	Sections           []*DiffSection
 	IsIncomplete       bool
 	IsProtected        bool
+	IsLazy             bool
+	IsViewed           bool
+	BlobSHA            string
+}
+
+// isLine returns true if the diff line is the signed line of a comment
+func (d *DiffLine) isLine(line int64) bool {
+	if line < 0 {
+		return int64(d.LeftIdx) == -line
+	}
+	return int64(d.RightIdx) == line
+}
+
+// markCommentRange marks the lines from startLine to line as part of a multi-line comment
+func (diffFile *DiffFile) markCommentRange(startLine, line int64) {
+	inRange := false
+	for _, section := range diffFile.Sections {
+		for _, diffLine := range section.Lines {
+			if diffLine.Type == DiffLineSection {
+				continue
+			}
+			if !inRange && diffLine.isLine(startLine) {
+				inRange = true
+			}
+			if inRange {
+				diffLine.IsInCommentRange = true
+				if diffLine.isLine(line) {
+					return
+				}
+			}
+		}
+	}
 }
 
 // GetType returns type of diff file.
@@ -434,9 +469,55 @@ func getCommitFileLineCount(commit *git.Commit, filePath string) int {
 
 // Diff represents a difference between two git trees.
 type Diff struct {
+	Start, End                             string
 	NumFiles, TotalAddition, TotalDeletion int
 	Files                                  []*DiffFile
 	IsIncomplete                           bool
+	NumViewedFiles                         int
+}
+
+// MergeFileList adds the files of list which have not been loaded by the diff
+// as placeholders to be loaded on demand, keeping the order of list.
+// The blob SHAs of the loaded files are taken from list.
+func (diff *Diff) MergeFileList(list []*DiffFile) {
+	loaded := make(map[string]*DiffFile, len(diff.Files))
+	for _, file := range diff.Files {
+		loaded[file.Name] = file
+	}
+	files := make([]*DiffFile, 0, len(list))
+	for _, file := range list {
+		if loadedFile, ok := loaded[file.Name]; ok {
+			loadedFile.Index = file.Index
+			loadedFile.BlobSHA = file.BlobSHA
+			files = append(files, loadedFile)
+			continue
+		}
+		file.IsLazy = true
+		files = append(files, file)
+	}
+	diff.Files = files
+}
+
+// HasAllBlobSHAs returns whether the blob SHAs of all the loaded files are known.
+// They are missing for the files whose patch has no index line, like pure renames.
+func (diff *Diff) HasAllBlobSHAs() bool {
+	for _, file := range diff.Files {
+		if len(file.BlobSHA) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadReviewState marks the files the user has viewed in their current version
+func (diff *Diff) LoadReviewState(state *models.ReviewState) {
+	diff.NumViewedFiles = 0
+	for _, file := range diff.Files {
+		file.IsViewed = state.IsViewed(file.Name, file.BlobSHA)
+		if file.IsViewed {
+			diff.NumViewedFiles++
+		}
+	}
 }
 
 // LoadComments loads comments into each line
@@ -460,6 +541,13 @@ func (diff *Diff) LoadComments(issue *models.Issue, currentUser *models.User) er
 					})
 				}
 			}
+			for _, comments := range lineCommits {
+				for _, comment := range comments {
+					if comment.IsMultiLine() {
+						file.markCommentRange(comment.StartLine, comment.Line)
+					}
+				}
+			}
 		}
 	}
 	return nil
@@ -468,10 +556,14 @@ func (diff *Diff) LoadComments(issue *models.Issue, currentUser *models.User) er
 const cmdDiffHead = "diff --git "
 
 // ParsePatch builds a Diff object from a io.Reader and some parameters.
-func ParsePatch(maxLines, maxLineCharacters, maxFiles int, reader io.Reader) (*Diff, error) {
+// If skipToFile is not empty all files before the named file are skipped.
+func ParsePatch(maxLines, maxLineCharacters, maxFiles int, reader io.Reader, skipToFile string) (*Diff, error) {
 	var curFile *DiffFile
 
-	diff := &Diff{Files: make([]*DiffFile, 0)}
+	skipping := skipToFile != ""
+	skippedFiles := 0
+
+	diff := &Diff{Files: make([]*DiffFile, 0), Start: skipToFile}
 
 	sb := strings.Builder{}
 
@@ -498,9 +590,27 @@ parsingLoop:
 			return diff, fmt.Errorf("Invalid first file line: %s", line)
 		}
 
-		// TODO: Handle skipping first n files
+		curFile = createDiffFile(diff, line)
+		curFile.Index += skippedFiles
+
+		if skipping {
+			if curFile.Name != skipToFile {
+				skippedFiles++
+				line, err = skipToNextDiffHead(input)
+				if err != nil {
+					if err == io.EOF {
+						break parsingLoop
+					}
+					return diff, err
+				}
+				continue
+			}
+			skipping = false
+		}
+
 		if len(diff.Files) >= maxFiles {
 			diff.IsIncomplete = true
+			diff.End = curFile.Name
 			_, err := io.Copy(ioutil.Discard, reader)
 			if err != nil {
 				// By the definition of io.Copy this never returns io.EOF
@@ -509,7 +619,6 @@ parsingLoop:
 			break parsingLoop
 		}
 
-		curFile = createDiffFile(diff, line)
 		diff.Files = append(diff.Files, curFile)
 
 		// 2. It is followed by one or more extended header lines:
@@ -589,6 +698,7 @@ parsingLoop:
 				if strings.HasSuffix(line, " 160000\n") {
 					curFile.IsSubmodule = true
 				}
+				curFile.BlobSHA = parseIndexBlobSHA(line, curFile.IsDeleted)
 			case strings.HasPrefix(line, "similarity index 100%"):
 				curFile.Type = DiffFileRename
 			case strings.HasPrefix(line, "Binary"):
@@ -664,6 +774,39 @@ parsingLoop:
 	return diff, nil
 }
 
+// skipToNextDiffHead discards the rest of the current file and returns the header line of the next file
+func skipToNextDiffHead(input *bufio.Reader) (line string, err error) {
+	// need to skip until the next cmdDiffHead
+	isFragment, wasFragment := false, false
+	var lineBytes []byte
+	for {
+		lineBytes, isFragment, err = input.ReadLine()
+		if err != nil {
+			return
+		}
+		if wasFragment {
+			wasFragment = isFragment
+			continue
+		}
+		if bytes.HasPrefix(lineBytes, []byte(cmdDiffHead)) {
+			break
+		}
+		wasFragment = isFragment
+	}
+	line = string(lineBytes)
+	if isFragment {
+		var tail string
+		tail, err = input.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line += tail
+	} else {
+		line += "\n"
+	}
+	return
+}
+
 func parseHunks(curFile *DiffFile, maxLines, maxLineCharacters int, input *bufio.Reader) (lineBytes []byte, isFragment bool, err error) {
 	sb := strings.Builder{}
 
@@ -866,13 +1009,85 @@ func GetDiffRange(repoPath, beforeCommitID, afterCommitID string, maxLines, maxL
 // Passing the empty string as beforeCommitID returns a diff from the parent commit.
 // The whitespaceBehavior is either an empty string or a git flag
 func GetDiffRangeWithWhitespaceBehavior(repoPath, beforeCommitID, afterCommitID string, maxLines, maxLineCharacters, maxFiles int, whitespaceBehavior string) (*Diff, error) {
+	return GetDiff(repoPath, &DiffOptions{
+		BeforeCommitID:     beforeCommitID,
+		AfterCommitID:      afterCommitID,
+		MaxLines:           maxLines,
+		MaxLineCharacters:  maxLineCharacters,
+		MaxFiles:           maxFiles,
+		WhitespaceBehavior: whitespaceBehavior,
+	})
+}
+
+// parseIndexBlobSHA returns the blob SHA of the file from the index line of its patch,
+// "index <old sha>..<new sha>[ <mode>]", the old one if the file has been deleted.
+// The SHAs abbreviated by the patches generated without --full-index are ignored.
+func parseIndexBlobSHA(line string, isDeleted bool) string {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return ""
+	}
+	shas := strings.SplitN(fields[1], "..", 2)
+	if len(shas) != 2 {
+		return ""
+	}
+	sha := shas[1]
+	if isDeleted {
+		sha = shas[0]
+	}
+	if _, err := git.NewIDFromString(sha); err != nil {
+		return ""
+	}
+	return sha
+}
+
+// DiffOptions represents the options for a Diff between two commits
+type DiffOptions struct {
+	BeforeCommitID     string
+	AfterCommitID      string
+	SkipTo             string
+	Files              []string
+	MaxLines           int
+	MaxLineCharacters  int
+	MaxFiles           int
+	WhitespaceBehavior string
+}
+
+// diffArgs returns the arguments for git diff and the resolved before commit
+func (opts *DiffOptions) diffArgs(commit *git.Commit) ([]string, string) {
+	diffArgs := []string{"diff", "--src-prefix=\\a/", "--dst-prefix=\\b/", "-M", "--full-index"}
+	if len(opts.WhitespaceBehavior) != 0 {
+		diffArgs = append(diffArgs, opts.WhitespaceBehavior)
+	}
+
+	beforeCommitID := opts.BeforeCommitID
+	if (len(beforeCommitID) == 0 || beforeCommitID == git.EmptySHA) && commit.ParentCount() == 0 {
+		// append empty tree ref
+		diffArgs = append(diffArgs, git.EmptyTreeSHA, opts.AfterCommitID)
+	} else {
+		if len(beforeCommitID) == 0 {
+			parentCommit, _ := commit.Parent(0)
+			beforeCommitID = parentCommit.ID.String()
+		}
+		diffArgs = append(diffArgs, beforeCommitID, opts.AfterCommitID)
+	}
+	if len(opts.Files) > 0 {
+		diffArgs = append(diffArgs, "--")
+		diffArgs = append(diffArgs, opts.Files...)
+	}
+	return diffArgs, beforeCommitID
+}
+
+// GetDiff builds a Diff between two commits of a repository as described by opts.
+// Passing the empty string as BeforeCommitID returns a diff from the parent commit.
+func GetDiff(repoPath string, opts *DiffOptions) (*Diff, error) {
 	gitRepo, err := git.OpenRepository(repoPath)
 	if err != nil {
 		return nil, err
 	}
 	defer gitRepo.Close()
 
-	commit, err := gitRepo.GetCommit(afterCommitID)
+	commit, err := gitRepo.GetCommit(opts.AfterCommitID)
 	if err != nil {
 		return nil, err
 	}
@@ -880,31 +1095,9 @@ func GetDiffRangeWithWhitespaceBehavior(repoPath, beforeCommitID, afterCommitID
 	// FIXME: graceful: These commands should likely have a timeout
 	ctx, cancel := context.WithCancel(git.DefaultContext)
 	defer cancel()
-	var cmd *exec.Cmd
-	if (len(beforeCommitID) == 0 || beforeCommitID == git.EmptySHA) && commit.ParentCount() == 0 {
-		diffArgs := []string{"diff", "--src-prefix=\\a/", "--dst-prefix=\\b/", "-M"}
-		if len(whitespaceBehavior) != 0 {
-			diffArgs = append(diffArgs, whitespaceBehavior)
-		}
-		// append empty tree ref
-		diffArgs = append(diffArgs, "4b825dc642cb6eb9a060e54bf8d69288fbee4904")
-		diffArgs = append(diffArgs, afterCommitID)
-		cmd = exec.CommandContext(ctx, git.GitExecutable, diffArgs...)
-	} else {
-		actualBeforeCommitID := beforeCommitID
-		if len(actualBeforeCommitID) == 0 {
-			parentCommit, _ := commit.Parent(0)
-			actualBeforeCommitID = parentCommit.ID.String()
-		}
-		diffArgs := []string{"diff", "--src-prefix=\\a/", "--dst-prefix=\\b/", "-M"}
-		if len(whitespaceBehavior) != 0 {
-			diffArgs = append(diffArgs, whitespaceBehavior)
-		}
-		diffArgs = append(diffArgs, actualBeforeCommitID)
-		diffArgs = append(diffArgs, afterCommitID)
-		cmd = exec.CommandContext(ctx, git.GitExecutable, diffArgs...)
-		beforeCommitID = actualBeforeCommitID
-	}
+
+	diffArgs, beforeCommitID := opts.diffArgs(commit)
+	cmd := exec.CommandContext(ctx, git.GitExecutable, diffArgs...)
 	cmd.Dir = repoPath
 	cmd.Stderr = os.Stderr
 
@@ -920,12 +1113,12 @@ func GetDiffRangeWithWhitespaceBehavior(repoPath, beforeCommitID, afterCommitID
 	pid := process.GetManager().Add(fmt.Sprintf("GetDiffRange [repo_path: %s]", repoPath), cancel)
 	defer process.GetManager().Remove(pid)
 
-	diff, err := ParsePatch(maxLines, maxLineCharacters, maxFiles, stdout)
+	diff, err := ParsePatch(opts.MaxLines, opts.MaxLineCharacters, opts.MaxFiles, stdout, opts.SkipTo)
 	if err != nil {
 		return nil, fmt.Errorf("ParsePatch: %v", err)
 	}
 	for _, diffFile := range diff.Files {
-		tailSection := diffFile.GetTailSection(gitRepo, beforeCommitID, afterCommitID)
+		tailSection := diffFile.GetTailSection(gitRepo, beforeCommitID, opts.AfterCommitID)
 		if tailSection != nil {
 			diffFile.Sections = append(diffFile.Sections, tailSection)
 		}
@@ -935,16 +1128,20 @@ func GetDiffRangeWithWhitespaceBehavior(repoPath, beforeCommitID, afterCommitID
 		return nil, fmt.Errorf("Wait: %v", err)
 	}
 
-	shortstatArgs := []string{beforeCommitID + "..." + afterCommitID}
+	var pathspec []string
+	if len(opts.Files) > 0 {
+		pathspec = append([]string{"--"}, opts.Files...)
+	}
+	shortstatArgs := []string{beforeCommitID + "..." + opts.AfterCommitID}
 	if len(beforeCommitID) == 0 || beforeCommitID == git.EmptySHA {
-		shortstatArgs = []string{git.EmptyTreeSHA, afterCommitID}
+		shortstatArgs = []string{git.EmptyTreeSHA, opts.AfterCommitID}
 	}
-	diff.NumFiles, diff.TotalAddition, diff.TotalDeletion, err = git.GetDiffShortStat(repoPath, shortstatArgs...)
+	diff.NumFiles, diff.TotalAddition, diff.TotalDeletion, err = git.GetDiffShortStat(repoPath, append(shortstatArgs, pathspec...)...)
 	if err != nil && strings.Contains(err.Error(), "no merge base") {
 		// git >= 2.28 now returns an error if base and head have become unrelated.
 		// previously it would return the results of git diff --shortstat base head so let's try that...
-		shortstatArgs = []string{beforeCommitID, afterCommitID}
-		diff.NumFiles, diff.TotalAddition, diff.TotalDeletion, err = git.GetDiffShortStat(repoPath, shortstatArgs...)
+		shortstatArgs = []string{beforeCommitID, opts.AfterCommitID}
+		diff.NumFiles, diff.TotalAddition, diff.TotalDeletion, err = git.GetDiffShortStat(repoPath, append(shortstatArgs, pathspec...)...)
 	}
 	if err != nil {
 		return nil, err
@@ -953,6 +1150,102 @@ func GetDiffRangeWithWhitespaceBehavior(repoPath, beforeCommitID, afterCommitID
 	return diff, nil
 }
 
+// GetDiffFileList returns the list of files changed between the commits of opts without parsing their patches.
+// The returned files contain no sections but their names, types, blob SHAs and line stats.
+func GetDiffFileList(repoPath string, opts *DiffOptions) ([]*DiffFile, error) {
+	gitRepo, err := git.OpenRepository(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(opts.AfterCommitID)
+	if err != nil {
+		return nil, err
+	}
+
+	diffArgs, _ := opts.diffArgs(commit)
+	// insert the list flags after "diff" and the prefix flags
+	args := append([]string{}, diffArgs[:4]...)
+	args = append(args, "--raw", "--numstat", "-z", "--no-abbrev")
+	args = append(args, diffArgs[4:]...)
+
+	stdout, err := git.NewCommand(args...).RunInDirBytes(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return parseDiffFileList(stdout)
+}
+
+// parseDiffFileList parses the output of git diff --raw --numstat -z
+func parseDiffFileList(stdout []byte) ([]*DiffFile, error) {
+	fields := strings.Split(strings.TrimSuffix(string(stdout), "\x00"), "\x00")
+	files := make([]*DiffFile, 0, len(fields)/3)
+	i := 0
+	// First the raw records: ":<old mode> <new mode> <old sha> <new sha> <status>" NUL <path> NUL [<path> NUL]
+	for ; i < len(fields) && strings.HasPrefix(fields[i], ":"); i++ {
+		raw := strings.Fields(fields[i])
+		if len(raw) != 5 || i+1 >= len(fields) {
+			return nil, fmt.Errorf("invalid raw diff record: %q", fields[i])
+		}
+		file := &DiffFile{
+			Index:   len(files) + 1,
+			Type:    DiffFileChange,
+			BlobSHA: raw[3],
+		}
+		status := raw[4]
+		i++
+		file.Name = fields[i]
+		file.OldName = fields[i]
+		switch status[0] {
+		case 'A':
+			file.Type = DiffFileAdd
+			file.IsCreated = true
+		case 'D':
+			file.Type = DiffFileDel
+			file.IsDeleted = true
+			file.BlobSHA = raw[2]
+		case 'R', 'C':
+			if i+1 >= len(fields) {
+				return nil, fmt.Errorf("invalid raw diff record: %q", raw)
+			}
+			i++
+			file.Name = fields[i]
+			file.IsRenamed = true
+			if status[0] == 'C' {
+				file.Type = DiffFileCopy
+			} else if status == "R100" {
+				file.Type = DiffFileRename
+			}
+		}
+		file.IsSubmodule = raw[0] == ":160000" || raw[1] == "160000"
+		files = append(files, file)
+	}
+
+	// Then the numstat records in the same order: "<added>\t<deleted>\t<path>" NUL or "<added>\t<deleted>\t" NUL <old> NUL <new> NUL
+	for _, file := range files {
+		if i >= len(fields) {
+			break
+		}
+		stat := strings.SplitN(fields[i], "\t", 3)
+		if len(stat) != 3 {
+			return nil, fmt.Errorf("invalid numstat diff record: %q", fields[i])
+		}
+		if stat[2] == "" {
+			// renamed or copied files list both paths after the counts
+			i += 2
+		}
+		i++
+		if stat[0] == "-" && stat[1] == "-" {
+			file.IsBin = true
+			continue
+		}
+		file.Addition, _ = strconv.Atoi(stat[0])
+		file.Deletion, _ = strconv.Atoi(stat[1])
+	}
+	return files, nil
+}
+
 // GetDiffCommit builds a Diff representing the given commitID.
 func GetDiffCommit(repoPath, commitID string, maxLines, maxLineCharacters, maxFiles int) (*Diff, error) {
 	return GetDiffRange(repoPath, "", commitID, maxLines, maxLineCharacters, maxFiles)
@@ -961,7 +1254,7 @@ func GetDiffCommit(repoPath, commitID string, maxLines, maxLineCharacters, maxFi
 // CommentAsDiff returns c.Patch as *Diff
 func CommentAsDiff(c *models.Comment) (*Diff, error) {
 	diff, err := ParsePatch(setting.Git.MaxGitDiffLines,
-		setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(c.Patch))
+		setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(c.Patch), "")
 	if err != nil {
 		return nil, err
 	}
@@ -972,6 +1265,9 @@ func CommentAsDiff(c *models.Comment) (*Diff, error) {
 	if len(secs) == 0 {
 		return nil, fmt.Errorf("no sections found for comment ID: %d", c.ID)
 	}
+	if c.IsMultiLine() {
+		diff.Files[0].markCommentRange(c.StartLine, c.Line)
+	}
 	return diff, nil
 }
 
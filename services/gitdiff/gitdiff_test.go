@@ -231,7 +231,7 @@ index 6961180..9ba1a00 100644
 
 	for _, testcase := range tests {
 		t.Run(testcase.name, func(t *testing.T) {
-			got, err := ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(testcase.gitdiff))
+			got, err := ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(testcase.gitdiff), "")
 			if (err != nil) != testcase.wantErr {
 				t.Errorf("ParsePatch() error = %v, wantErr %v", err, testcase.wantErr)
 				return
@@ -274,7 +274,7 @@ index 6961180..9ba1a00 100644
  Docker Pulls
 + cut off
 + cut off`
-	result, err := ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(diff))
+	result, err := ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(diff), "")
 	if err != nil {
 		t.Errorf("ParsePatch failed: %s", err)
 	}
@@ -291,7 +291,7 @@ index 6961180..9ba1a00 100644
  Docker Pulls
 + cut off
 + cut off`
-	result, err = ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(diff2))
+	result, err = ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(diff2), "")
 	if err != nil {
 		t.Errorf("ParsePatch failed: %s", err)
 	}
@@ -308,7 +308,7 @@ index 6961180..9ba1a00 100644
  Docker Pulls
 + cut off
 + cut off`
-	result, err = ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(diff2a))
+	result, err = ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(diff2a), "")
 	if err != nil {
 		t.Errorf("ParsePatch failed: %s", err)
 	}
@@ -325,7 +325,7 @@ index 6961180..9ba1a00 100644
  Docker Pulls
 + cut off
 + cut off`
-	result, err = ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(diff3))
+	result, err = ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(diff3), "")
 	if err != nil {
 		t.Errorf("ParsePatch failed: %s", err)
 	}
@@ -361,6 +361,33 @@ func TestDiff_LoadComments(t *testing.T) {
 	assert.Len(t, diff.Files[0].Sections[0].Lines[0].Comments, 2)
 }
 
+func TestCommentAsDiff_multiLine(t *testing.T) {
+	comment := &models.Comment{
+		Line:      3,
+		StartLine: -2,
+		Patch: `diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1,3 +1,4 @@
+ # repo
+-old
++new
++more
+ end`,
+	}
+	diff, err := CommentAsDiff(comment)
+	assert.NoError(t, err)
+	lines := diff.Files[0].Sections[0].Lines
+	if assert.Len(t, lines, 6) {
+		assert.False(t, lines[0].IsInCommentRange) // section header
+		assert.False(t, lines[1].IsInCommentRange)
+		assert.True(t, lines[2].IsInCommentRange)
+		assert.True(t, lines[3].IsInCommentRange)
+		assert.True(t, lines[4].IsInCommentRange)
+		assert.False(t, lines[5].IsInCommentRange)
+	}
+}
+
 func TestDiffLine_CanComment(t *testing.T) {
 	assert.False(t, (&DiffLine{Type: DiffLineSection}).CanComment())
 	assert.False(t, (&DiffLine{Type: DiffLineAdd, Comments: []*models.Comment{{Content: "bla"}}}).CanComment())
@@ -385,3 +412,137 @@ func TestGetDiffRangeWithWhitespaceBehavior(t *testing.T) {
 		}
 	}
 }
+
+func TestParsePatch_skipTo(t *testing.T) {
+	var diff = `diff --git a/a.txt b/a.txt
+index 422c2b7..0f7bc76 100644
+--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+ a
+-b
++c
+diff --git a/b.txt b/b.txt
+index 422c2b7..0f7bc76 100644
+--- a/b.txt
++++ b/b.txt
+@@ -1,2 +1,2 @@
+ a
+-b
++c
+diff --git a/c.txt b/c.txt
+index 422c2b7..0f7bc76 100644
+--- a/c.txt
++++ b/c.txt
+@@ -1,2 +1,2 @@
+ a
+-b
++c
+`
+	result, err := ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, 1, strings.NewReader(diff), "b.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "b.txt", result.Start)
+	assert.Equal(t, "c.txt", result.End)
+	assert.True(t, result.IsIncomplete)
+	if assert.Len(t, result.Files, 1) {
+		assert.Equal(t, "b.txt", result.Files[0].Name)
+		assert.Equal(t, 2, result.Files[0].Index)
+		assert.Equal(t, 1, result.Files[0].Addition)
+		assert.Equal(t, 1, result.Files[0].Deletion)
+	}
+
+	result, err = ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(diff), "c.txt")
+	assert.NoError(t, err)
+	assert.False(t, result.IsIncomplete)
+	if assert.Len(t, result.Files, 1) {
+		assert.Equal(t, "c.txt", result.Files[0].Name)
+		assert.Equal(t, 3, result.Files[0].Index)
+	}
+
+	result, err = ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles, strings.NewReader(diff), "unknown.txt")
+	assert.NoError(t, err)
+	assert.Len(t, result.Files, 0)
+}
+
+func TestParseDiffFileList(t *testing.T) {
+	stdout := ":100644 100644 422c2b7 0f7bc76 M\x00a.txt\x00" +
+		":100644 000000 c1b0730 0000000 D\x00b.bin\x00" +
+		":100644 100644 03be4ff 03be4ff R100\x00c.txt\x00d.txt\x00" +
+		":000000 100644 0000000 bdc955b A\x00e.bin\x00" +
+		"1\t1\ta.txt\x00" +
+		"0\t1\tb.bin\x00" +
+		"0\t0\t\x00c.txt\x00d.txt\x00" +
+		"-\t-\te.bin\x00"
+	files, err := parseDiffFileList([]byte(stdout))
+	assert.NoError(t, err)
+	if assert.Len(t, files, 4) {
+		assert.Equal(t, "a.txt", files[0].Name)
+		assert.Equal(t, DiffFileChange, files[0].Type)
+		assert.Equal(t, 1, files[0].Addition)
+		assert.Equal(t, 1, files[0].Deletion)
+		assert.Equal(t, "0f7bc76", files[0].BlobSHA)
+
+		assert.Equal(t, "b.bin", files[1].Name)
+		assert.True(t, files[1].IsDeleted)
+		assert.Equal(t, "c1b0730", files[1].BlobSHA)
+		assert.Equal(t, 1, files[1].Deletion)
+
+		assert.Equal(t, "c.txt", files[2].OldName)
+		assert.Equal(t, "d.txt", files[2].Name)
+		assert.True(t, files[2].IsRenamed)
+		assert.Equal(t, DiffFileRename, files[2].Type)
+
+		assert.Equal(t, "e.bin", files[3].Name)
+		assert.True(t, files[3].IsCreated)
+		assert.True(t, files[3].IsBin)
+		assert.Equal(t, 4, files[3].Index)
+	}
+}
+
+func TestParseIndexBlobSHA(t *testing.T) {
+	assert.Equal(t, "c1b0730b3d2e0d0a5e3f7b8c8c5e5b6a4b3c2d1e", parseIndexBlobSHA("index 0f7bc76a1b2c3d4e5f60718293a4b5c6d7e8f901..c1b0730b3d2e0d0a5e3f7b8c8c5e5b6a4b3c2d1e 100644\n", false))
+	assert.Equal(t, "0f7bc76a1b2c3d4e5f60718293a4b5c6d7e8f901", parseIndexBlobSHA("index 0f7bc76a1b2c3d4e5f60718293a4b5c6d7e8f901..0000000000000000000000000000000000000000\n", true))
+	assert.Equal(t, "", parseIndexBlobSHA("index 0f7bc76..c1b0730 100644\n", false))
+	assert.Equal(t, "", parseIndexBlobSHA("index\n", false))
+}
+
+func TestGetDiffFileList(t *testing.T) {
+	diff, err := GetDiff("./testdata/academic-module", &DiffOptions{
+		BeforeCommitID:    "559c156f8e0178b71cb44355428f24001b08fc68",
+		AfterCommitID:     "bd7063cc7c04689c4d082183d32a604ed27a24f9",
+		MaxLines:          setting.Git.MaxGitDiffLines,
+		MaxLineCharacters: setting.Git.MaxGitDiffLineCharacters,
+		MaxFiles:          1,
+	})
+	assert.NoError(t, err)
+	assert.True(t, diff.IsIncomplete)
+	assert.Len(t, diff.Files, 1)
+
+	files, err := GetDiffFileList("./testdata/academic-module", &DiffOptions{
+		BeforeCommitID: "559c156f8e0178b71cb44355428f24001b08fc68",
+		AfterCommitID:  "bd7063cc7c04689c4d082183d32a604ed27a24f9",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, files, diff.NumFiles)
+	assert.Equal(t, diff.End, files[1].Name)
+	assert.Len(t, files[0].BlobSHA, 40)
+	// the blob SHAs of the loaded files are parsed from their patches
+	assert.Equal(t, files[0].BlobSHA, diff.Files[0].BlobSHA)
+	assert.True(t, diff.HasAllBlobSHAs())
+
+	diff.MergeFileList(files)
+	assert.Len(t, diff.Files, diff.NumFiles)
+	assert.False(t, diff.Files[0].IsLazy)
+	assert.True(t, diff.Files[1].IsLazy)
+	assert.Equal(t, files[0].BlobSHA, diff.Files[0].BlobSHA)
+
+	diff.LoadReviewState(&models.ReviewState{
+		ViewedFiles: map[string]string{
+			files[0].Name: files[0].BlobSHA,
+			files[1].Name: "0000000000000000000000000000000000000000",
+		},
+	})
+	assert.True(t, diff.Files[0].IsViewed)
+	assert.False(t, diff.Files[1].IsViewed)
+	assert.Equal(t, 1, diff.NumViewedFiles)
+}
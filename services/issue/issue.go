@@ -56,6 +56,22 @@ func ChangeIssueRef(issue *models.Issue, doer *models.User, ref string) error {
 	return nil
 }
 
+// TransferIssue moves an issue to another repository, as the given user.
+func TransferIssue(issue *models.Issue, doer *models.User, newRepo *models.Repository) error {
+	if err := issue.LoadRepo(); err != nil {
+		return err
+	}
+	oldRepo := issue.Repo
+
+	if err := models.TransferIssue(doer, issue, newRepo); err != nil {
+		return err
+	}
+
+	notification.NotifyIssueTransfer(doer, issue, oldRepo)
+
+	return nil
+}
+
 // UpdateAssignees is a helper function to add or delete one or multiple issue assignee(s)
 // Deleting is done the GitHub way (quote from their api documentation):
 // https://developer.github.com/v3/issues/#edit-an-issue
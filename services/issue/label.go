@@ -20,23 +20,54 @@ func ClearLabels(issue *models.Issue, doer *models.User) (err error) {
 	return nil
 }
 
-// AddLabel adds a new label to the issue.
-func AddLabel(issue *models.Issue, doer *models.User, label *models.Label) error {
-	if err := models.NewIssueLabel(issue, label, doer); err != nil {
-		return err
+// removedLabels returns the labels of old which are no longer on the issue
+func removedLabels(issue *models.Issue, old []*models.Label) ([]*models.Label, error) {
+	current, err := models.GetLabelsByIssueID(issue.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	notification.NotifyIssueChangeLabels(doer, issue, []*models.Label{label}, nil)
-	return nil
+	var removed []*models.Label
+	for _, label := range old {
+		found := false
+		for _, l := range current {
+			if l.ID == label.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			removed = append(removed, label)
+		}
+	}
+	return removed, nil
+}
+
+// AddLabel adds a new label to the issue,
+// the other labels of the exclusive scope of the label are removed from the issue.
+func AddLabel(issue *models.Issue, doer *models.User, label *models.Label) error {
+	return AddLabels(issue, doer, []*models.Label{label})
 }
 
-// AddLabels adds a list of new labels to the issue.
+// AddLabels adds a list of new labels to the issue,
+// the other labels of the exclusive scopes of the labels are removed from the issue.
 func AddLabels(issue *models.Issue, doer *models.User, labels []*models.Label) error {
+	old, err := models.GetLabelsByIssueID(issue.ID)
+	if err != nil {
+		return err
+	}
+
+	labels = models.RemoveDuplicateExclusiveLabels(labels)
 	if err := models.NewIssueLabels(issue, labels, doer); err != nil {
 		return err
 	}
 
-	notification.NotifyIssueChangeLabels(doer, issue, labels, nil)
+	removed, err := removedLabels(issue, old)
+	if err != nil {
+		return err
+	}
+
+	notification.NotifyIssueChangeLabels(doer, issue, labels, removed)
 	return nil
 }
 
@@ -65,13 +96,15 @@ func RemoveLabel(issue *models.Issue, doer *models.User, label *models.Label) er
 	return nil
 }
 
-// ReplaceLabels removes all current labels and add new labels to the issue.
+// ReplaceLabels removes all current labels and add new labels to the issue,
+// only the last of the new labels of each exclusive scope is added.
 func ReplaceLabels(issue *models.Issue, doer *models.User, labels []*models.Label) error {
 	old, err := models.GetLabelsByIssueID(issue.ID)
 	if err != nil {
 		return err
 	}
 
+	labels = models.RemoveDuplicateExclusiveLabels(labels)
 	if err := issue.ReplaceLabels(labels, doer); err != nil {
 		return err
 	}
@@ -0,0 +1,126 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/charset"
+
+	"github.com/jaytaylor/html2text"
+)
+
+var (
+	// quoteHeaderPatterns match the first line of the quoted notification added by the mail clients,
+	// everything from there is dropped
+	quoteHeaderPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^On\s.+\s(wrote|writes):\s*$`),
+		regexp.MustCompile(`(?m)^On\s.+\n.*\s(wrote|writes):\s*$`),
+		regexp.MustCompile(`(?mi)^-{2,}\s*Original Message\s*-{2,}\s*$`),
+		regexp.MustCompile(`(?m)^_{10,}\s*$`),
+		// signature delimiter
+		regexp.MustCompile(`(?m)^-- $`),
+	}
+)
+
+// readReplyText returns the text of the mail without the quoted notification
+func readReplyText(msg *mail.Message) (string, error) {
+	text, err := readTextPart(textproto.MIMEHeader(msg.Header), msg.Body)
+	if err != nil {
+		return "", err
+	}
+	return stripQuotedText(text), nil
+}
+
+// readTextPart returns the text of a part, preferring the plain text alternative of multipart parts
+func readTextPart(header textproto.MIMEHeader, body io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		var html string
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return "", err
+			}
+			if strings.HasPrefix(part.Header.Get("Content-Disposition"), "attachment") {
+				continue
+			}
+			text, err := readTextPart(part.Header, part)
+			if err != nil {
+				return "", err
+			}
+			partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			if partType == "text/html" {
+				if html == "" {
+					html = text
+				}
+				continue
+			}
+			if text != "" {
+				return text, nil
+			}
+		}
+		return html, nil
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", nil
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	if cs := strings.ToLower(params["charset"]); cs != "" && cs != "utf-8" && cs != "us-ascii" {
+		content = charset.ToUTF8WithFallback(content)
+	}
+
+	if mediaType == "text/html" {
+		return html2text.FromString(string(content))
+	}
+	return string(content), nil
+}
+
+// stripQuotedText removes the quoted notification and the signature from a reply
+func stripQuotedText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for _, re := range quoteHeaderPatterns {
+		if loc := re.FindStringIndex(text); loc != nil {
+			text = text[:loc[0]]
+		}
+	}
+
+	lines := strings.Split(text, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if strings.HasPrefix(line, ">") {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(line, " \t"))
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripQuotedText(t *testing.T) {
+	kases := map[string]string{
+		"Thanks!\r\n\r\nOn Mon, Oct 5, 2020 at 10:00 AM Gitea <gitea@example.com> wrote:\r\n> issue body\r\n": "Thanks!",
+		"Thanks!\n\nOn Mon, Oct 5, 2020 at 10:00 AM Gitea\n<gitea@example.com> wrote:\n> issue body\n":        "Thanks!",
+		"Thanks!\n\n-----Original Message-----\nFrom: Gitea\n":                                                "Thanks!",
+		"Thanks!\n\n________________________________\nFrom: Gitea\n":                                          "Thanks!",
+		"Thanks!\n-- \nJohn\n":                          "Thanks!",
+		"> quoted\nLooks good\n> quoted again\nto me\n": "Looks good\nto me",
+		"  \n> only quotes\n":                           "",
+	}
+	for input, expected := range kases {
+		assert.Equal(t, expected, stripQuotedText(input), input)
+	}
+}
+
+func TestReadReplyText(t *testing.T) {
+	kases := map[string]string{
+		"plain": "From: user2@example.com\r\n" +
+			"Subject: Re: issue\r\n" +
+			"\r\n" +
+			"Plain reply\r\n" +
+			"> quote\r\n",
+		"quoted-printable": "From: user2@example.com\r\n" +
+			"Content-Type: text/plain; charset=utf-8\r\n" +
+			"Content-Transfer-Encoding: quoted-printable\r\n" +
+			"\r\n" +
+			"Caf=C3=A9 reply\r\n",
+		"multipart": "From: user2@example.com\r\n" +
+			"Content-Type: multipart/alternative; boundary=\"b1\"\r\n" +
+			"\r\n" +
+			"--b1\r\n" +
+			"Content-Type: text/plain; charset=utf-8\r\n" +
+			"Content-Transfer-Encoding: base64\r\n" +
+			"\r\n" +
+			"TXVsdGlwYXJ0IHJlcGx5Cg==\r\n" +
+			"--b1\r\n" +
+			"Content-Type: text/html; charset=utf-8\r\n" +
+			"\r\n" +
+			"<p>HTML reply</p>\r\n" +
+			"--b1--\r\n",
+		"html": "From: user2@example.com\r\n" +
+			"Content-Type: multipart/mixed; boundary=\"b1\"\r\n" +
+			"\r\n" +
+			"--b1\r\n" +
+			"Content-Type: text/html; charset=utf-8\r\n" +
+			"\r\n" +
+			"<p>HTML reply</p>\r\n" +
+			"--b1\r\n" +
+			"Content-Type: text/plain\r\n" +
+			"Content-Disposition: attachment; filename=\"notes.txt\"\r\n" +
+			"\r\n" +
+			"attached notes\r\n" +
+			"--b1--\r\n",
+	}
+	expected := map[string]string{
+		"plain":            "Plain reply",
+		"quoted-printable": "Café reply",
+		"multipart":        "Multipart reply",
+		"html":             "HTML reply",
+	}
+
+	for name, data := range kases {
+		msg, err := mail.ReadMessage(strings.NewReader(data))
+		assert.NoError(t, err, name)
+		text, err := readReplyText(msg)
+		assert.NoError(t, err, name)
+		assert.Equal(t, expected[name], text, name)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"bytes"
+	"mime"
+	"net/mail"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/services/comments"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// unsubscribeKeyword unwatches the issue when it is the subject or the whole text of the reply
+const unsubscribeKeyword = "unsubscribe"
+
+// isAutoSubmitted reports whether the mail was sent by an auto responder or is a bounce,
+// these are dropped to avoid mail loops
+func isAutoSubmitted(header mail.Header) bool {
+	if v := strings.ToLower(strings.TrimSpace(header.Get("Auto-Submitted"))); v != "" && v != "no" {
+		return true
+	}
+	if v := strings.ToLower(header.Get("Precedence")); v == "bulk" || v == "junk" || v == "list" || v == "auto_reply" {
+		return true
+	}
+	return header.Get("X-Autoreply") != "" || header.Get("X-Autorespond") != ""
+}
+
+// isSenderOf reports whether the From address of the mail is one of the activated addresses of the user
+func isSenderOf(header mail.Header, user *models.User) bool {
+	from, err := header.AddressList("From")
+	if err != nil || len(from) != 1 {
+		return false
+	}
+	sender, err := models.GetUserByEmail(from[0].Address)
+	if err != nil {
+		if !models.IsErrUserNotExist(err) {
+			log.Error("GetUserByEmail: %v", err)
+		}
+		return false
+	}
+	return sender.ID == user.ID
+}
+
+// handleReply posts the reply to a notification mail as a comment of the recipient the reply token was created for
+func handleReply(token string, data []byte) error {
+	userID, issueID, err := mailer.ParseReplyToken(token)
+	if err != nil {
+		return reject("invalid reply address")
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return reject("malformed mail")
+	}
+	if isAutoSubmitted(msg.Header) {
+		log.Trace("Dropping automatic reply to the notification of issue %d", issueID)
+		return nil
+	}
+
+	doer, err := models.GetUserByID(userID)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			return reject("invalid reply address")
+		}
+		return err
+	}
+	if !doer.IsActive || doer.ProhibitLogin {
+		return reject("account is disabled")
+	}
+	if !isSenderOf(msg.Header, doer) {
+		return reject("sender doesn't match the recipient of the notification")
+	}
+
+	issue, err := models.GetIssueByID(issueID)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			return reject("issue doesn't exist anymore")
+		}
+		return err
+	}
+	if err = issue.LoadRepo(); err != nil {
+		return err
+	}
+	perm, err := models.GetUserRepoPermission(issue.Repo, doer)
+	if err != nil {
+		return err
+	}
+	if !perm.CanReadIssuesOrPulls(issue.IsPull) {
+		return reject("issue doesn't exist anymore")
+	}
+
+	content, err := readReplyText(msg)
+	if err != nil {
+		return reject("unable to read the text of the mail")
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+	if strings.EqualFold(strings.TrimSpace(subject), unsubscribeKeyword) || strings.EqualFold(content, unsubscribeKeyword) {
+		return models.CreateOrUpdateIssueWatch(doer.ID, issue.ID, false)
+	}
+
+	if issue.Repo.IsArchived {
+		return reject("repository is archived")
+	}
+	if issue.IsLocked && !perm.CanWriteIssuesOrPulls(issue.IsPull) && !doer.IsAdmin {
+		return reject("conversation is locked")
+	}
+	if content == "" {
+		return reject("reply is empty")
+	}
+
+	_, err = comments.CreateIssueComment(doer, issue.Repo, issue, content, nil)
+	return err
+}
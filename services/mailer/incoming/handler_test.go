@@ -0,0 +1,71 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"errors"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/services/mailer"
+
+	"github.com/stretchr/testify/assert"
+	"xorm.io/builder"
+)
+
+func testReplyMail(from, subject, body string) []byte {
+	return []byte("From: " + from + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"\r\n" +
+		body)
+}
+
+func assertRejected(t *testing.T, err error, reason string) {
+	var reject *rejectError
+	if assert.True(t, errors.As(err, &reject), "expected a rejection, got %v", err) {
+		assert.Equal(t, reason, reject.reason)
+	}
+}
+
+func TestHandleReply(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	token := mailer.CreateReplyToken(2, issue.ID)
+
+	err := handleReply(token, testReplyMail("User Two <user2@example.com>", "Re: [user2/repo1] issue1 (#1)",
+		"Sounds good to me.\r\n\r\nOn Mon, Oct 5, 2020 at 10:00 AM Gitea <gitea@example.com> wrote:\r\n> content for the first issue\r\n"))
+	assert.NoError(t, err)
+	comment := models.AssertExistsAndLoadBean(t, &models.Comment{IssueID: issue.ID, PosterID: 2, Content: "Sounds good to me."}).(*models.Comment)
+	assert.Equal(t, models.CommentTypeComment, comment.Type)
+
+	// automatic replies are dropped
+	assert.NoError(t, handleReply(token, []byte("From: user2@example.com\r\nAuto-Submitted: auto-replied\r\n\r\nOut of office\r\n")))
+	models.AssertNotExistsBean(t, &models.Comment{IssueID: issue.ID, Content: "Out of office"})
+
+	assertRejected(t, handleReply("2-1-0123456789abcdef01234567", testReplyMail("user2@example.com", "Re", "forged")),
+		"invalid reply address")
+	assertRejected(t, handleReply(token, testReplyMail("user4@example.com", "Re", "spoofed")),
+		"sender doesn't match the recipient of the notification")
+	assertRejected(t, handleReply(token, testReplyMail("user2@example.com", "Re", "> only quoted\r\n")),
+		"reply is empty")
+	assertRejected(t, handleReply(mailer.CreateReplyToken(9, issue.ID), testReplyMail("user9@example.com", "Re", "inactive")),
+		"account is disabled")
+	// user4 can't read the issues of the private repo2
+	assertRejected(t, handleReply(mailer.CreateReplyToken(4, 4), testReplyMail("user4@example.com", "Re", "private")),
+		"issue doesn't exist anymore")
+	assertRejected(t, handleReply(mailer.CreateReplyToken(2, 999), testReplyMail("user2@example.com", "Re", "missing")),
+		"issue doesn't exist anymore")
+}
+
+func TestHandleReplyUnsubscribe(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	assert.NoError(t, handleReply(mailer.CreateReplyToken(4, 1), testReplyMail("user4@example.com", "unsubscribe", "")))
+	models.AssertExistsAndLoadBean(t, &models.IssueWatch{UserID: 4, IssueID: 1}, builder.Eq{"is_watching": false})
+
+	assert.NoError(t, handleReply(mailer.CreateReplyToken(5, 1), testReplyMail("user5@example.com", "Re: issue1", "Unsubscribe\r\n")))
+	models.AssertExistsAndLoadBean(t, &models.IssueWatch{UserID: 5, IssueID: 1}, builder.Eq{"is_watching": false})
+	models.AssertNotExistsBean(t, &models.Comment{IssueID: 1, PosterID: 5, Content: "Unsubscribe"})
+}
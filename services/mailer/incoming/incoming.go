@@ -0,0 +1,84 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// newTokenExtractor returns a function extracting the reply token of the addresses
+// matching the reply address pattern, the comparison is case insensitive
+func newTokenExtractor(replyToAddress string) func(string) (string, bool) {
+	parts := strings.SplitN(replyToAddress, setting.IncomingEmailTokenPlaceholder, 2)
+	pattern := "(?i)^" + regexp.QuoteMeta(parts[0]) + "([0-9a-z-]+)"
+	if len(parts) == 2 {
+		pattern += regexp.QuoteMeta(parts[1])
+	}
+	re := regexp.MustCompile(pattern + "$")
+
+	return func(address string) (string, bool) {
+		matches := re.FindStringSubmatch(address)
+		if matches == nil {
+			return "", false
+		}
+		return matches[1], true
+	}
+}
+
+// Listen starts the server receiving the replies to the notification mails
+func Listen() {
+	srv := &server{
+		lmtp:         setting.IncomingEmail.Protocol == "lmtp",
+		hostname:     setting.Domain,
+		maxSize:      setting.IncomingEmail.MaxMessageSize,
+		extractToken: newTokenExtractor(setting.IncomingEmail.ReplyToAddress),
+		handler:      handleReply,
+	}
+	addr := net.JoinHostPort(setting.IncomingEmail.ListenHost, strconv.Itoa(setting.IncomingEmail.ListenPort))
+
+	go listen(srv, addr)
+	log.Info("Incoming email server (%s) started on %s", strings.ToUpper(setting.IncomingEmail.Protocol), addr)
+}
+
+func listen(srv *server, addr string) {
+	gracefulServer := graceful.NewServer("tcp", addr)
+
+	err := gracefulServer.ListenAndServe(srv.serve)
+	if err != nil {
+		select {
+		case <-graceful.GetManager().IsShutdown():
+			log.Critical("Failed to start incoming email server: %v", err)
+		default:
+			log.Fatal("Failed to start incoming email server: %v", err)
+		}
+	}
+	log.Info("Incoming email Listener: %s Closed", addr)
+}
+
+// Unused informs our cleanup routine that we will not be using the incoming email port
+func Unused() {
+	graceful.GetManager().InformCleanup()
+}
+
+// rejectError is a permanent failure reported to the sender of the mail
+type rejectError struct {
+	reason string
+}
+
+func (err *rejectError) Error() string {
+	return fmt.Sprintf("rejected: %s", err.reason)
+}
+
+func reject(format string, args ...interface{}) error {
+	return &rejectError{reason: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,258 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+const (
+	// maxRecipients is the number of recipients accepted for a single mail
+	maxRecipients = 100
+	// commandTimeout is the time a client has to send the next command
+	commandTimeout = 5 * time.Minute
+)
+
+// handlerFunc delivers a mail to the reply address holding the token
+type handlerFunc func(token string, data []byte) error
+
+// server receives mails with LMTP or SMTP, only the recipients matching
+// the reply address are accepted
+type server struct {
+	lmtp     bool
+	hostname string
+	maxSize  int64
+	// extractToken returns the reply token of a recipient address if it matches the reply address
+	extractToken func(address string) (string, bool)
+	handler      handlerFunc
+}
+
+// session is the state of a connection
+type session struct {
+	greeted bool
+	from    string
+	tokens  []string
+}
+
+func (sess *session) reset() {
+	sess.from = ""
+	sess.tokens = nil
+}
+
+// serve accepts the connections of the listener until it is closed
+func (s *server) serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handleConn(conn)
+	}
+}
+
+func (s *server) handleConn(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+
+	protocol := "ESMTP"
+	if s.lmtp {
+		protocol = "LMTP"
+	}
+	if err := tp.PrintfLine("220 %s %s Gitea ready", s.hostname, protocol); err != nil {
+		return
+	}
+
+	sess := &session{}
+	for {
+		_ = conn.SetDeadline(time.Now().Add(commandTimeout))
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg := line, ""
+		if idx := strings.IndexByte(line, ' '); idx >= 0 {
+			verb, arg = line[:idx], strings.TrimSpace(line[idx+1:])
+		}
+
+		switch verb = strings.ToUpper(verb); {
+		case verb == "LHLO" && s.lmtp, (verb == "EHLO" || verb == "HELO") && !s.lmtp:
+			sess.greeted = true
+			sess.reset()
+			err = s.replyLines(tp, 250, s.hostname, "PIPELINING", "8BITMIME", "ENHANCEDSTATUSCODES", fmt.Sprintf("SIZE %d", s.maxSize))
+		case verb == "MAIL":
+			err = s.handleMail(tp, sess, arg)
+		case verb == "RCPT":
+			err = s.handleRcpt(tp, sess, arg)
+		case verb == "DATA":
+			err = s.handleData(tp, sess)
+		case verb == "RSET":
+			sess.reset()
+			err = tp.PrintfLine("250 2.0.0 OK")
+		case verb == "NOOP":
+			err = tp.PrintfLine("250 2.0.0 OK")
+		case verb == "VRFY":
+			err = tp.PrintfLine("252 2.5.0 Cannot verify the user")
+		case verb == "QUIT":
+			_ = tp.PrintfLine("221 2.0.0 Bye")
+			return
+		default:
+			err = tp.PrintfLine("500 5.5.2 Unrecognized command")
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// replyLines writes a multiline reply
+func (s *server) replyLines(tp *textproto.Conn, code int, lines ...string) error {
+	for i, line := range lines {
+		sep := "-"
+		if i == len(lines)-1 {
+			sep = " "
+		}
+		if err := tp.PrintfLine("%d%s%s", code, sep, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parsePath returns the address of a "FROM:<address>" or "TO:<address>" argument, ignoring the parameters
+func parsePath(arg, prefix string) (string, bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", false
+	}
+	arg = strings.TrimSpace(arg[len(prefix):])
+	if !strings.HasPrefix(arg, "<") {
+		return "", false
+	}
+	end := strings.IndexByte(arg, '>')
+	if end < 0 {
+		return "", false
+	}
+	return arg[1:end], true
+}
+
+func (s *server) handleMail(tp *textproto.Conn, sess *session, arg string) error {
+	if !sess.greeted {
+		return tp.PrintfLine("503 5.5.1 Send greeting first")
+	} else if sess.from != "" {
+		return tp.PrintfLine("503 5.5.1 Sender already specified")
+	}
+	from, ok := parsePath(arg, "FROM:")
+	if !ok {
+		return tp.PrintfLine("501 5.5.4 Syntax: MAIL FROM:<address>")
+	}
+	// the null reverse path of bounces is accepted, they are dropped by the handler
+	sess.from = "<" + from + ">"
+	return tp.PrintfLine("250 2.1.0 OK")
+}
+
+func (s *server) handleRcpt(tp *textproto.Conn, sess *session, arg string) error {
+	if sess.from == "" {
+		return tp.PrintfLine("503 5.5.1 Need MAIL command first")
+	} else if len(sess.tokens) >= maxRecipients {
+		return tp.PrintfLine("452 4.5.3 Too many recipients")
+	}
+	to, ok := parsePath(arg, "TO:")
+	if !ok {
+		return tp.PrintfLine("501 5.5.4 Syntax: RCPT TO:<address>")
+	}
+	token, ok := s.extractToken(to)
+	if !ok {
+		return tp.PrintfLine("550 5.1.1 No such recipient")
+	}
+	sess.tokens = append(sess.tokens, token)
+	return tp.PrintfLine("250 2.1.5 OK")
+}
+
+func (s *server) handleData(tp *textproto.Conn, sess *session) error {
+	if len(sess.tokens) == 0 {
+		return tp.PrintfLine("503 5.5.1 Need RCPT command first")
+	}
+	if err := tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>"); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	r := tp.DotReader()
+	n, err := io.Copy(&buf, io.LimitReader(r, s.maxSize+1))
+	if err == nil && n > s.maxSize {
+		// read the rest of the data to stay in sync with the client
+		_, err = io.Copy(ioutil.Discard, r)
+		if err == nil {
+			err = s.replyAll(tp, sess, "552 5.3.4 Message too big")
+			sess.reset()
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	replies := make([]string, len(sess.tokens))
+	for i, token := range sess.tokens {
+		replies[i] = s.deliver(token, buf.Bytes())
+	}
+	sess.reset()
+
+	if s.lmtp {
+		// LMTP replies once per accepted recipient
+		for _, reply := range replies {
+			if err := tp.PrintfLine("%s", reply); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, reply := range replies {
+		if !strings.HasPrefix(reply, "250") {
+			return tp.PrintfLine("%s", reply)
+		}
+	}
+	return tp.PrintfLine("%s", replies[0])
+}
+
+// replyAll writes the same reply for all recipients with LMTP, once with SMTP
+func (s *server) replyAll(tp *textproto.Conn, sess *session, reply string) error {
+	count := 1
+	if s.lmtp {
+		count = len(sess.tokens)
+	}
+	for i := 0; i < count; i++ {
+		if err := tp.PrintfLine("%s", reply); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver runs the handler and returns the reply to the client
+func (s *server) deliver(token string, data []byte) string {
+	err := s.handler(token, data)
+	if err == nil {
+		return "250 2.0.0 OK"
+	}
+
+	var reject *rejectError
+	if errors.As(err, &reject) {
+		log.Debug("Incoming email rejected: %v", err)
+		return "550 5.7.1 " + reject.reason
+	}
+	log.Error("Unable to handle incoming email: %v", err)
+	return "451 4.3.0 Temporary failure, please retry later"
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"net"
+	"net/textproto"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDelivery struct {
+	token string
+	data  string
+}
+
+func startTestServer(t *testing.T, lmtp bool, handler handlerFunc) (*textproto.Conn, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	srv := &server{
+		lmtp:         lmtp,
+		hostname:     "localhost",
+		maxSize:      64,
+		extractToken: newTokenExtractor("incoming+%{token}@localhost"),
+		handler:      handler,
+	}
+	go func() {
+		_ = srv.serve(l)
+	}()
+
+	conn, err := textproto.Dial("tcp", l.Addr().String())
+	assert.NoError(t, err)
+	return conn, func() {
+		conn.Close()
+		l.Close()
+	}
+}
+
+func expectReply(t *testing.T, conn *textproto.Conn, cmd string, expectCode int) string {
+	if cmd != "" {
+		assert.NoError(t, conn.PrintfLine("%s", cmd))
+	}
+	code, msg, err := conn.ReadResponse(expectCode)
+	assert.NoError(t, err, cmd)
+	assert.Equal(t, expectCode, code, cmd)
+	return msg
+}
+
+func TestTokenExtractor(t *testing.T) {
+	extract := newTokenExtractor("incoming+%{token}@example.com")
+
+	token, ok := extract("incoming+2-ya-abcdef@example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "2-ya-abcdef", token)
+
+	token, ok = extract("INCOMING+2-YA-ABCDEF@EXAMPLE.COM")
+	assert.True(t, ok)
+	assert.Equal(t, "2-YA-ABCDEF", token)
+
+	for _, address := range []string{"incoming@example.com", "incoming+@example.com", "other+2-ya@example.com", "incoming+2-ya@example.org", "incoming+2.ya@example.com"} {
+		_, ok = extract(address)
+		assert.False(t, ok, address)
+	}
+}
+
+func TestServerLMTP(t *testing.T) {
+	var mu sync.Mutex
+	var deliveries []testDelivery
+	conn, closer := startTestServer(t, true, func(token string, data []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		deliveries = append(deliveries, testDelivery{token, string(data)})
+		if token == "rejected" {
+			return reject("conversation is locked")
+		}
+		return nil
+	})
+	defer closer()
+
+	expectReply(t, conn, "", 220)
+	expectReply(t, conn, "EHLO client", 500)
+	expectReply(t, conn, "MAIL FROM:<user2@example.com>", 503)
+	expectReply(t, conn, "LHLO client", 250)
+	expectReply(t, conn, "RCPT TO:<incoming+token1@localhost>", 503)
+	expectReply(t, conn, "MAIL FROM:<user2@example.com> BODY=8BITMIME", 250)
+	expectReply(t, conn, "RCPT TO:<incoming+token1@localhost>", 250)
+	expectReply(t, conn, "RCPT TO:<someone@localhost>", 550)
+	expectReply(t, conn, "RCPT TO:<incoming+rejected@localhost>", 250)
+	expectReply(t, conn, "DATA", 354)
+
+	dw := conn.DotWriter()
+	_, err := dw.Write([]byte("Subject: Re\n\n.reply\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, dw.Close())
+	expectReply(t, conn, "", 250)
+	assert.Contains(t, expectReply(t, conn, "", 550), "conversation is locked")
+
+	mu.Lock()
+	assert.Equal(t, []testDelivery{
+		{"token1", "Subject: Re\n\n.reply\n"},
+		{"rejected", "Subject: Re\n\n.reply\n"},
+	}, deliveries)
+	mu.Unlock()
+
+	// too big mails are refused once per recipient
+	expectReply(t, conn, "MAIL FROM:<>", 250)
+	expectReply(t, conn, "RCPT TO:<incoming+token2@localhost>", 250)
+	expectReply(t, conn, "DATA", 354)
+	dw = conn.DotWriter()
+	_, err = dw.Write([]byte("Subject: Re\n\n" + string(make([]byte, 100)) + "\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, dw.Close())
+	expectReply(t, conn, "", 552)
+
+	expectReply(t, conn, "NOOP", 250)
+	expectReply(t, conn, "QUIT", 221)
+
+	mu.Lock()
+	assert.Len(t, deliveries, 2)
+	mu.Unlock()
+}
+
+func TestServerSMTP(t *testing.T) {
+	conn, closer := startTestServer(t, false, func(token string, data []byte) error {
+		if token == "rejected" {
+			return reject("reply is empty")
+		}
+		return nil
+	})
+	defer closer()
+
+	expectReply(t, conn, "", 220)
+	expectReply(t, conn, "LHLO client", 500)
+	expectReply(t, conn, "EHLO client", 250)
+	expectReply(t, conn, "MAIL FROM:<user2@example.com>", 250)
+	expectReply(t, conn, "RCPT TO:<incoming+token1@localhost>", 250)
+	expectReply(t, conn, "RCPT TO:<incoming+rejected@localhost>", 250)
+	expectReply(t, conn, "DATA", 354)
+	dw := conn.DotWriter()
+	_, err := dw.Write([]byte("Subject: Re\n\nreply\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, dw.Close())
+	// SMTP only has a single reply, the first failure
+	assert.Contains(t, expectReply(t, conn, "", 550), "reply is empty")
+
+	expectReply(t, conn, "RSET", 250)
+	expectReply(t, conn, "DATA", 503)
+	expectReply(t, conn, "QUIT", 221)
+}
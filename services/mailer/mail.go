@@ -164,7 +164,7 @@ func SendCollaboratorMail(u, doer *models.User, repo *models.Repository) {
 	SendAsync(msg)
 }
 
-func composeIssueCommentMessages(ctx *mailCommentContext, tos []string, fromMention bool, info string) []*Message {
+func composeIssueCommentMessages(ctx *mailCommentContext, recipients []*models.User, fromMention bool, info string) []*Message {
 
 	var (
 		subject string
@@ -246,10 +246,11 @@ func composeIssueCommentMessages(ctx *mailCommentContext, tos []string, fromMent
 	}
 
 	// Make sure to compose independent messages to avoid leaking user emails
-	msgs := make([]*Message, 0, len(tos))
-	for _, to := range tos {
-		msg := NewMessageFrom([]string{to}, ctx.Doer.DisplayName(), setting.MailService.FromEmail, subject, mailBody.String())
+	msgs := make([]*Message, 0, len(recipients))
+	for _, recipient := range recipients {
+		msg := NewMessageFrom([]string{recipient.Email}, ctx.Doer.DisplayName(), setting.MailService.FromEmail, subject, mailBody.String())
 		msg.Info = fmt.Sprintf("Subject: %s, %s", subject, info)
+		msg.Link = link
 
 		// Set Message-ID on first message so replies know what to reference
 		if actName == "new" {
@@ -258,6 +259,13 @@ func composeIssueCommentMessages(ctx *mailCommentContext, tos []string, fromMent
 			msg.SetHeader("In-Reply-To", "<"+ctx.Issue.ReplyReference()+">")
 			msg.SetHeader("References", "<"+ctx.Issue.ReplyReference()+">")
 		}
+
+		// Replies are posted as comments of the recipient, replying "unsubscribe" unwatches the issue
+		if setting.IncomingEmail.Enabled {
+			replyAddress := ReplyAddress(recipient.ID, ctx.Issue.ID)
+			msg.SetHeader("Reply-To", replyAddress)
+			msg.SetHeader("List-Unsubscribe", "<mailto:"+replyAddress+"?subject=unsubscribe>")
+		}
 		msgs = append(msgs, msg)
 	}
 
@@ -273,15 +281,24 @@ func sanitizeSubject(subject string) string {
 	return mime.QEncoding.Encode("utf-8", string(runes))
 }
 
-// SendIssueAssignedMail composes and sends issue assigned email
-func SendIssueAssignedMail(issue *models.Issue, doer *models.User, content string, comment *models.Comment, tos []string) {
-	SendAsyncs(composeIssueCommentMessages(&mailCommentContext{
+// SendIssueAssignedMail composes and sends issue assigned email, event is either
+// models.EmailEventAssigned or models.EmailEventReviewRequested
+func SendIssueAssignedMail(issue *models.Issue, doer *models.User, content string, comment *models.Comment, recipients []*models.User, event models.EmailNotificationEvent) {
+	recipients, err := models.FilterUsersByEmailEvent(recipients, event)
+	if err != nil {
+		log.Error("FilterUsersByEmailEvent: %v", err)
+		return
+	}
+	if len(recipients) == 0 {
+		return
+	}
+	sendOrBufferMessages(recipients, composeIssueCommentMessages(&mailCommentContext{
 		Issue:      issue,
 		Doer:       doer,
 		ActionType: models.ActionType(0),
 		Content:    content,
 		Comment:    comment,
-	}, tos, false, "issue assigned"))
+	}, recipients, false, "issue assigned"), event)
 }
 
 // actionToTemplate returns the type and name of the action facing the user
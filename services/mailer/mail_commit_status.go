@@ -0,0 +1,78 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	mailNotifyCommitStatus base.TplName = "notify/commit_status"
+)
+
+// MailCommitStatusFailure notifies the author of a commit that one of its checks failed
+func MailCommitStatusFailure(repo *models.Repository, sha string, status *models.CommitStatus) error {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("OpenRepository: %v", err)
+	}
+	commit, err := gitRepo.GetCommit(sha)
+	gitRepo.Close()
+	if err != nil {
+		return fmt.Errorf("GetCommit [%s]: %v", sha, err)
+	}
+
+	author, err := models.GetUserByEmail(commit.Author.Email)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if !author.IsMailable() || author.EmailNotifications() == models.EmailNotificationsDisabled {
+		return nil
+	}
+	perm, err := models.GetUserRepoPermission(repo, author)
+	if err != nil {
+		return err
+	}
+	if !perm.CanRead(models.UnitTypeCode) {
+		return nil
+	}
+	recipients, err := models.FilterUsersByEmailEvent([]*models.User{author}, models.EmailEventCIFailure)
+	if err != nil || len(recipients) == 0 {
+		return err
+	}
+
+	link := status.TargetURL
+	if link == "" {
+		link = repo.CommitLink(sha)
+	}
+	subject := fmt.Sprintf("[%s] %s %s on %s", repo.FullName(), status.Context, status.State, base.ShortSha(sha))
+
+	var content bytes.Buffer
+	if err = bodyTemplates.ExecuteTemplate(&content, string(mailNotifyCommitStatus), map[string]interface{}{
+		"Subject":    subject,
+		"Repo":       repo.FullName(),
+		"Status":     status,
+		"SHA":        base.ShortSha(sha),
+		"CommitLink": repo.CommitLink(sha),
+		"Link":       link,
+	}); err != nil {
+		return err
+	}
+
+	msg := NewMessageFrom([]string{author.Email}, repo.FullName(), setting.MailService.FromEmail, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, commit status %s", author.ID, status.State)
+	msg.Link = link
+	sendOrBufferMessages(recipients, []*Message{msg}, models.EmailEventCIFailure)
+	return nil
+}
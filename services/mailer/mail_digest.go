@@ -0,0 +1,113 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+)
+
+const (
+	mailNotifyDigest base.TplName = "notify/digest"
+)
+
+// sendOrBufferMessages sends the messages of the users receiving their notifications immediately
+// and buffers the others until their digest is sent, msgs[i] being the message of recipients[i]
+func sendOrBufferMessages(recipients []*models.User, msgs []*Message, event models.EmailNotificationEvent) {
+	immediate := make([]*Message, 0, len(msgs))
+	items := make([]*models.EmailDigestItem, 0, len(msgs))
+	for i, msg := range msgs {
+		switch recipients[i].EmailNotificationsDigest {
+		case models.EmailDigestHourly, models.EmailDigestDaily:
+			subject, err := new(mime.WordDecoder).DecodeHeader(msg.Subject)
+			if err != nil {
+				subject = msg.Subject
+			}
+			items = append(items, &models.EmailDigestItem{
+				UserID:  recipients[i].ID,
+				Event:   event,
+				Subject: subject,
+				Link:    msg.Link,
+			})
+		default:
+			immediate = append(immediate, msg)
+		}
+	}
+
+	if err := models.AddEmailDigestItems(items); err != nil {
+		log.Error("AddEmailDigestItems: %v", err)
+	}
+	if len(immediate) > 0 {
+		SendAsyncs(immediate)
+	}
+}
+
+// SendEmailDigests sends one email summarizing the buffered notifications of each user having one of the digest modes
+func SendEmailDigests(ctx context.Context, digests ...string) error {
+	userIDs, err := models.GetEmailDigestUserIDs(digests...)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before sending the email digest of user %d", userID)
+		default:
+		}
+
+		if err := sendEmailDigest(userID); err != nil {
+			return fmt.Errorf("sendEmailDigest [%d]: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+func sendEmailDigest(userID int64) error {
+	user, err := models.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	items, err := models.GetEmailDigestItems(userID)
+	if err != nil || len(items) == 0 {
+		return err
+	}
+
+	if user.IsMailable() && user.EmailNotifications() != models.EmailNotificationsDisabled {
+		msg, err := composeEmailDigest(user, items)
+		if err != nil {
+			return err
+		}
+		SendAsync(msg)
+	}
+
+	return models.DeleteEmailDigestItems(userID, items[len(items)-1].ID)
+}
+
+func composeEmailDigest(user *models.User, items []*models.EmailDigestItem) (*Message, error) {
+	subject := fmt.Sprintf("Notification digest: %d updates", len(items))
+	if len(items) == 1 {
+		subject = "Notification digest: 1 update"
+	}
+
+	var content bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyDigest), map[string]interface{}{
+		"Subject":     subject,
+		"DisplayName": user.DisplayName(),
+		"Items":       items,
+	}); err != nil {
+		return nil, err
+	}
+
+	msg := NewMessage([]string{user.Email}, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, notification digest", user.ID)
+	return msg, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"context"
+	"html/template"
+	"testing"
+	texttmpl "text/template"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const digestBodyTpl = `{{.Subject}}:{{range .Items}} <a href="{{.Link}}">{{.Subject}}</a>{{end}}`
+
+func TestSendOrBufferMessages(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	setting.MailService = &setting.Mailer{From: "test@gitea.com"}
+
+	user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	user4 := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+	user2.EmailNotificationsDigest = models.EmailDigestHourly
+	user4.EmailNotificationsDigest = models.EmailDigestDaily
+
+	msg2 := NewMessage([]string{user2.Email}, sanitizeSubject("[user2/repo1] Ünicode"), "body")
+	msg2.Link = "http://localhost:3000/user2/repo1/issues/1"
+	msg4 := NewMessage([]string{user4.Email}, "[user2/repo1] issue1", "body")
+	sendOrBufferMessages([]*models.User{user2, user4}, []*Message{msg2, msg4}, models.EmailEventMention)
+
+	models.AssertExistsAndLoadBean(t, &models.EmailDigestItem{
+		UserID:  2,
+		Event:   models.EmailEventMention,
+		Subject: "[user2/repo1] Ünicode",
+		Link:    "http://localhost:3000/user2/repo1/issues/1",
+	})
+	models.AssertExistsAndLoadBean(t, &models.EmailDigestItem{UserID: 4, Subject: "[user2/repo1] issue1"})
+}
+
+func TestComposeEmailDigest(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	setting.MailService = &setting.Mailer{From: "test@gitea.com"}
+	InitMailRender(texttmpl.New(""), template.Must(template.New(string(mailNotifyDigest)).Parse(digestBodyTpl)))
+
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	msg, err := composeEmailDigest(user, []*models.EmailDigestItem{
+		{Subject: "first", Link: "http://localhost:3000/first"},
+		{Subject: "second", Link: "http://localhost:3000/second"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{user.Email}, msg.To)
+	assert.Equal(t, "Notification digest: 2 updates", msg.Subject)
+	assert.Equal(t, `Notification digest: 2 updates: <a href="http://localhost:3000/first">first</a> <a href="http://localhost:3000/second">second</a>`, msg.Body)
+}
+
+func TestSendEmailDigestsNotMailable(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	// the digests of users who can't be mailed anymore are dropped
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 9}).(*models.User)
+	assert.False(t, user.IsMailable())
+	assert.NoError(t, user.SetEmailNotificationsDigest(models.EmailDigestDaily))
+	assert.NoError(t, models.AddEmailDigestItems([]*models.EmailDigestItem{{UserID: 9, Subject: "dropped"}}))
+
+	assert.NoError(t, SendEmailDigests(context.Background(), models.EmailDigestHourly))
+	models.AssertExistsAndLoadBean(t, &models.EmailDigestItem{UserID: 9})
+	assert.NoError(t, SendEmailDigests(context.Background(), models.EmailDigestDaily))
+	models.AssertNotExistsBean(t, &models.EmailDigestItem{UserID: 9})
+}
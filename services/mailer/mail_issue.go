@@ -124,15 +124,34 @@ func mailIssueCommentBatch(ctx *mailCommentContext, ids []int64, visited map[int
 		}
 		// TODO: Check issue visibility for each user
 		// TODO: Separate recipients by language for i18n mail templates
-		tos := make([]string, len(recipients))
-		for i := range recipients {
-			tos[i] = recipients[i].Email
+		for event, users := range groupRecipientsByEvent(ctx, recipients, fromMention) {
+			if users, err = models.FilterUsersByEmailEvent(users, event); err != nil {
+				return err
+			}
+			if len(users) > 0 {
+				sendOrBufferMessages(users, composeIssueCommentMessages(ctx, users, fromMention, "issue comments"), event)
+			}
 		}
-		SendAsyncs(composeIssueCommentMessages(ctx, tos, fromMention, "issue comments"))
 	}
 	return nil
 }
 
+// groupRecipientsByEvent groups the recipients by the event they are notified of,
+// so that their per-event preferences can be applied
+func groupRecipientsByEvent(ctx *mailCommentContext, recipients []*models.User, fromMention bool) map[models.EmailNotificationEvent][]*models.User {
+	groups := make(map[models.EmailNotificationEvent][]*models.User, 2)
+	for _, recipient := range recipients {
+		event := models.EmailEventIssueActivity
+		if fromMention {
+			event = models.EmailEventMention
+		} else if ctx.Issue.IsPull && recipient.ID == ctx.Issue.PosterID {
+			event = models.EmailEventOwnPullRequests
+		}
+		groups[event] = append(groups[event], recipient)
+	}
+	return groups
+}
+
 // MailParticipants sends new issue thread created emails to repository watchers
 // and mentioned people.
 func MailParticipants(issue *models.Issue, doer *models.User, opType models.ActionType) error {
@@ -33,12 +33,16 @@ func MailNewRelease(rel *models.Release) {
 		return
 	}
 
-	tos := make([]string, 0, len(recipients))
+	tos := make([]*models.User, 0, len(recipients))
 	for _, to := range recipients {
 		if to.ID != rel.PublisherID {
-			tos = append(tos, to.Email)
+			tos = append(tos, to)
 		}
 	}
+	if tos, err = models.FilterUsersByEmailEvent(tos, models.EmailEventRelease); err != nil {
+		log.Error("FilterUsersByEmailEvent: %v", err)
+		return
+	}
 
 	rel.RenderedNote = markdown.RenderString(rel.Note, rel.Repo.Link(), rel.Repo.ComposeMetas())
 	subject := fmt.Sprintf("%s in %s released", rel.TagName, rel.Repo.FullName())
@@ -59,11 +63,12 @@ func MailNewRelease(rel *models.Release) {
 	publisherName := rel.Publisher.DisplayName()
 	relURL := "<" + rel.HTMLURL() + ">"
 	for _, to := range tos {
-		msg := NewMessageFrom([]string{to}, publisherName, setting.MailService.FromEmail, subject, mailBody.String())
+		msg := NewMessageFrom([]string{to.Email}, publisherName, setting.MailService.FromEmail, subject, mailBody.String())
 		msg.Info = subject
+		msg.Link = rel.HTMLURL()
 		msg.SetHeader("Message-ID", relURL)
 		msgs = append(msgs, msg)
 	}
 
-	SendAsyncs(msgs)
+	sendOrBufferMessages(tos, msgs, models.EmailEventRelease)
 }
@@ -39,6 +39,7 @@ type Message struct {
 	Date            time.Time
 	Body            string
 	Headers         map[string][]string
+	Link            string // Link to the notified content, listed in the digests
 }
 
 // ToMessage converts a Message to gomail.Message
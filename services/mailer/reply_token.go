@@ -0,0 +1,65 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// replyTokenSignatureLength is the number of hex characters of the signature kept in the reply token
+const replyTokenSignatureLength = 24
+
+// ErrInvalidReplyToken is returned when a reply token is malformed or its signature doesn't match
+var ErrInvalidReplyToken = errors.New("invalid reply token")
+
+func signReplyToken(payload string) string {
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	_, _ = mac.Write([]byte("reply:" + payload))
+	return hex.EncodeToString(mac.Sum(nil))[:replyTokenSignatureLength]
+}
+
+// CreateReplyToken returns the token allowing the user to reply by email to the notifications of the issue,
+// it only contains lowercase letters, digits and dashes so it can be used in the local part of an address
+func CreateReplyToken(userID, issueID int64) string {
+	payload := strconv.FormatInt(userID, 36) + "-" + strconv.FormatInt(issueID, 36)
+	return payload + "-" + signReplyToken(payload)
+}
+
+// ParseReplyToken checks the signature of a reply token and returns the user and the issue it was created for
+func ParseReplyToken(token string) (userID, issueID int64, err error) {
+	token = strings.ToLower(token)
+	idx := strings.LastIndexByte(token, '-')
+	if idx < 0 {
+		return 0, 0, ErrInvalidReplyToken
+	}
+	payload, signature := token[:idx], token[idx+1:]
+	if !hmac.Equal([]byte(signature), []byte(signReplyToken(payload))) {
+		return 0, 0, ErrInvalidReplyToken
+	}
+
+	parts := strings.SplitN(payload, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, ErrInvalidReplyToken
+	}
+	if userID, err = strconv.ParseInt(parts[0], 36, 64); err != nil {
+		return 0, 0, ErrInvalidReplyToken
+	}
+	if issueID, err = strconv.ParseInt(parts[1], 36, 64); err != nil {
+		return 0, 0, ErrInvalidReplyToken
+	}
+	return userID, issueID, nil
+}
+
+// ReplyAddress returns the Reply-To address of the notifications of the issue sent to the user
+func ReplyAddress(userID, issueID int64) string {
+	return strings.Replace(setting.IncomingEmail.ReplyToAddress, setting.IncomingEmailTokenPlaceholder, CreateReplyToken(userID, issueID), 1)
+}
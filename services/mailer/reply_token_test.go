@@ -0,0 +1,49 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplyToken(t *testing.T) {
+	token := CreateReplyToken(2, 1234)
+	assert.Regexp(t, "^[0-9a-z-]+$", token)
+
+	userID, issueID, err := ParseReplyToken(token)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, userID)
+	assert.EqualValues(t, 1234, issueID)
+
+	// mail servers may change the case of the local part
+	userID, issueID, err = ParseReplyToken(strings.ToUpper(token))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, userID)
+	assert.EqualValues(t, 1234, issueID)
+
+	// the issue can't be changed without the signature
+	forged := strings.Replace(token, "2-ya-", "2-yb-", 1)
+	assert.NotEqual(t, token, forged)
+	_, _, err = ParseReplyToken(forged)
+	assert.Equal(t, ErrInvalidReplyToken, err)
+
+	for _, invalid := range []string{"", "abc", "2-ya", "-" + token, "2-ya-0123456789abcdef01234567"} {
+		_, _, err = ParseReplyToken(invalid)
+		assert.Equal(t, ErrInvalidReplyToken, err, invalid)
+	}
+
+	oldSecret := setting.SecretKey
+	setting.SecretKey = "another secret"
+	defer func() {
+		setting.SecretKey = oldSecret
+	}()
+	_, _, err = ParseReplyToken(token)
+	assert.Equal(t, ErrInvalidReplyToken, err)
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var syncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "gitea",
+	Subsystem: "mirror",
+	Name:      "sync_duration_seconds",
+	Help:      "Duration of mirror synchronizations by status",
+	Buckets:   []float64{.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800},
+}, []string{"status"})
+
+func init() {
+	prometheus.MustRegister(syncDuration)
+}
+
+// observeSync records the duration and outcome of a mirror synchronization
+func observeSync(succeeded bool, start time.Time) {
+	status := "success"
+	if !succeeded {
+		status = "failure"
+	}
+	syncDuration.WithLabelValues(status).Observe(time.Since(start).Seconds())
+}
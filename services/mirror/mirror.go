@@ -13,6 +13,7 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/cluster"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
@@ -374,12 +375,12 @@ func SyncMirrors(ctx context.Context) {
 			mirrorQueue.Close()
 			return
 		case repoID := <-mirrorQueue.Queue():
-			syncMirror(repoID)
+			syncMirror(ctx, repoID)
 		}
 	}
 }
 
-func syncMirror(repoID string) {
+func syncMirror(ctx context.Context, repoID string) {
 	log.Trace("SyncMirrors [repo_id: %v]", repoID)
 	defer func() {
 		err := recover()
@@ -391,6 +392,17 @@ func syncMirror(repoID string) {
 	}()
 	mirrorQueue.Remove(repoID)
 
+	// The mirror may have been queued on several instances
+	mirrorLock, err := cluster.TryAcquire(ctx, "mirror:"+repoID)
+	if err != nil {
+		log.Error("Unable to lock mirror [%s]: %v", repoID, err)
+		return
+	} else if mirrorLock == nil {
+		log.Trace("SyncMirrors [repo_id: %v]: already syncing on another instance", repoID)
+		return
+	}
+	defer mirrorLock.Release()
+
 	m, err := models.GetMirrorByRepoID(com.StrTo(repoID).MustInt64())
 	if err != nil {
 		log.Error("GetMirrorByRepoID [%s]: %v", repoID, err)
@@ -399,7 +411,9 @@ func syncMirror(repoID string) {
 	}
 
 	log.Trace("SyncMirrors [repo: %-v]: Running Sync", m.Repo)
+	start := time.Now()
 	results, ok := runSync(m)
+	observeSync(ok, start)
 	if !ok {
 		return
 	}
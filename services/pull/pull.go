@@ -63,7 +63,7 @@ func NewPullRequest(repo *models.Repository, pull *models.Issue, labelIDs []int6
 	}
 	defer baseGitRepo.Close()
 
-	compareInfo, err := baseGitRepo.GetCompareInfo(pr.BaseRepo.RepoPath(),
+	compareInfo, err := baseGitRepo.GetCompareInfo(git.DefaultContext, pr.BaseRepo.RepoPath(),
 		git.BranchPrefix+pr.BaseBranch, pr.GetGitRefName())
 	if err != nil {
 		return err
@@ -18,7 +18,8 @@ import (
 )
 
 // CreateCodeComment creates a comment on the code line
-func CreateCodeComment(doer *models.User, gitRepo *git.Repository, issue *models.Issue, line int64, content string, treePath string, isReview bool, replyReviewID int64, latestCommitID string) (*models.Comment, error) {
+// A startLine other than 0 makes it a multi-line comment on the lines from startLine to line.
+func CreateCodeComment(doer *models.User, gitRepo *git.Repository, issue *models.Issue, line, startLine int64, content string, treePath string, isReview bool, replyReviewID int64, latestCommitID string) (*models.Comment, error) {
 
 	var (
 		existsReview bool
@@ -51,6 +52,7 @@ func CreateCodeComment(doer *models.User, gitRepo *git.Repository, issue *models
 			content,
 			treePath,
 			line,
+			startLine,
 			replyReviewID,
 		)
 		if err != nil {
@@ -86,6 +88,7 @@ func CreateCodeComment(doer *models.User, gitRepo *git.Repository, issue *models
 		content,
 		treePath,
 		line,
+		startLine,
 		review.ID,
 	)
 	if err != nil {
@@ -107,8 +110,13 @@ func CreateCodeComment(doer *models.User, gitRepo *git.Repository, issue *models
 var notEnoughLines = regexp.MustCompile(`exit status 128 - fatal: file .* has only \d+ lines?`)
 
 // createCodeComment creates a plain code comment at the specified line / path
-func createCodeComment(doer *models.User, repo *models.Repository, issue *models.Issue, content, treePath string, line, reviewID int64) (*models.Comment, error) {
+func createCodeComment(doer *models.User, repo *models.Repository, issue *models.Issue, content, treePath string, line, startLine, reviewID int64) (*models.Comment, error) {
 	var commitID, patch string
+	// a range of lines on a single side of the diff has to end at the commented line
+	if r := (&models.Comment{Line: line, StartLine: startLine}); !r.IsMultiLine() ||
+		((startLine < 0) == (line < 0) && r.UnsignedStartLine() > r.UnsignedLine()) {
+		startLine = 0
+	}
 	if err := issue.LoadPullRequest(); err != nil {
 		return nil, fmt.Errorf("GetPullRequestByIssueID: %v", err)
 	}
@@ -178,20 +186,24 @@ func createCodeComment(doer *models.User, repo *models.Repository, issue *models
 		if err := git.GetRepoRawDiffForFile(gitRepo, pr.MergeBase, commitID, git.RawDiffNormal, treePath, patchBuf); err != nil {
 			return nil, fmt.Errorf("GetRawDiffForLine[%s, %s, %s, %s]: %v", gitRepo.Path, pr.MergeBase, commitID, treePath, err)
 		}
-		patch = git.CutDiffAroundLine(patchBuf, int64((&models.Comment{Line: line}).UnsignedLine()), line < 0, setting.UI.CodeCommentLines)
+		patch, err = git.CutDiffAroundLineRange(patchBuf, startLine, line, setting.UI.CodeCommentLines)
+		if err != nil {
+			return nil, fmt.Errorf("CutDiffAroundLineRange[%s]: %v", treePath, err)
+		}
 	}
 	return models.CreateComment(&models.CreateCommentOptions{
-		Type:        models.CommentTypeCode,
-		Doer:        doer,
-		Repo:        repo,
-		Issue:       issue,
-		Content:     content,
-		LineNum:     line,
-		TreePath:    treePath,
-		CommitSHA:   commitID,
-		ReviewID:    reviewID,
-		Patch:       patch,
-		Invalidated: invalidated,
+		Type:         models.CommentTypeCode,
+		Doer:         doer,
+		Repo:         repo,
+		Issue:        issue,
+		Content:      content,
+		LineNum:      line,
+		StartLineNum: startLine,
+		TreePath:     treePath,
+		CommitSHA:    commitID,
+		ReviewID:     reviewID,
+		Patch:        patch,
+		Invalidated:  invalidated,
 	})
 }
 
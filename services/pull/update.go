@@ -5,15 +5,18 @@
 package pull
 
 import (
+	"context"
 	"fmt"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
 )
 
-// Update updates pull request with base branch.
-func Update(pull *models.PullRequest, doer *models.User, message string) error {
+// Update updates pull request with base branch,
+// the git commands are run with the values of ctx like in Merge.
+func Update(ctx context.Context, pull *models.PullRequest, doer *models.User, message string) error {
 	//use merge functions but switch repo's and branch's
 	pr := &models.PullRequest{
 		HeadRepoID: pull.BaseRepoID,
@@ -37,7 +40,8 @@ func Update(pull *models.PullRequest, doer *models.User, message string) error {
 		return fmt.Errorf("HeadBranch of PR %d is up to date", pull.Index)
 	}
 
-	_, err = rawMerge(pr, doer, models.MergeStyleMerge, message)
+	ctx = graceful.NewValuesContext(git.DefaultContext, ctx)
+	_, err = rawMerge(ctx, pr, doer, models.MergeStyleMerge, message)
 
 	defer func() {
 		go AddTestPullRequestTask(doer, pr.HeadRepo.ID, pr.HeadBranch, false, "", "")
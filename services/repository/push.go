@@ -31,7 +31,8 @@ func handle(data ...queue.Data) {
 	for _, datum := range data {
 		opts := datum.([]*repo_module.PushUpdateOptions)
 		if err := pushUpdates(opts); err != nil {
-			log.Error("pushUpdate failed: %v", err)
+			// pushUpdates only fails for a non-empty opts
+			log.WithRequestID(opts[0].RequestID).Error("pushUpdate failed: %v", err)
 		}
 	}
 }
@@ -71,6 +72,7 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 	if len(optsList) == 0 {
 		return nil
 	}
+	logger := log.WithRequestID(optsList[0].RequestID)
 
 	repo, err := models.GetRepositoryByOwnerAndName(optsList[0].RepoUserName, optsList[0].RepoName)
 	if err != nil {
@@ -85,7 +87,7 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 	defer gitRepo.Close()
 
 	if err = repo.UpdateSize(models.DefaultDBContext()); err != nil {
-		log.Error("Failed to update size for repository: %v", err)
+		logger.Error("Failed to update size for repository: %v", err)
 	}
 
 	addTags := make([]string, 0, len(optsList))
@@ -115,7 +117,7 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 
 			branch := opts.BranchName()
 			if !opts.IsDelRef() {
-				log.Trace("TriggerTask '%s/%s' by %s", repo.Name, branch, pusher.Name)
+				logger.Trace("TriggerTask '%s/%s' by %s", repo.Name, branch, pusher.Name)
 				go pull_service.AddTestPullRequestTask(pusher, repo.ID, branch, true, opts.OldCommitID, opts.NewCommitID)
 
 				newCommit, err := gitRepo.GetCommit(opts.NewCommitID)
@@ -136,13 +138,13 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 						return fmt.Errorf("newCommit.CommitsBeforeUntil: %v", err)
 					}
 
-					isForce, err := repo_module.IsForcePush(opts)
+					isForce, err := repo_module.IsForcePush(repo, opts)
 					if err != nil {
-						log.Error("isForcePush %s:%s failed: %v", repo.FullName(), branch, err)
+						logger.Error("isForcePush %s:%s failed: %v", repo.FullName(), branch, err)
 					}
 
 					if isForce {
-						log.Trace("Push %s is a force push", opts.NewCommitID)
+						logger.Trace("Push %s is a force push", opts.NewCommitID)
 
 						cache.Remove(repo.GetCommitsCountCacheKey(opts.RefName(), true))
 					} else {
@@ -154,21 +156,21 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 				commits = repo_module.ListToPushCommits(l)
 
 				if err = models.RemoveDeletedBranch(repo.ID, branch); err != nil {
-					log.Error("models.RemoveDeletedBranch %s/%s failed: %v", repo.ID, branch, err)
+					logger.Error("models.RemoveDeletedBranch %s/%s failed: %v", repo.ID, branch, err)
 				}
 
 				// Cache for big repository
 				if err := repo_module.CacheRef(repo, gitRepo, opts.RefFullName); err != nil {
-					log.Error("repo_module.CacheRef %s/%s failed: %v", repo.ID, branch, err)
+					logger.Error("repo_module.CacheRef %s/%s failed: %v", repo.ID, branch, err)
 				}
 			} else if err = pull_service.CloseBranchPulls(pusher, repo.ID, branch); err != nil {
 				// close all related pulls
-				log.Error("close related pull request failed: %v", err)
+				logger.Error("close related pull request failed: %v", err)
 			}
 
 			// Even if user delete a branch on a repository which he didn't watch, he will be watch that.
 			if err = models.WatchIfAuto(opts.PusherID, repo.ID, true); err != nil {
-				log.Warn("Fail to perform auto watch on user %v for repo %v: %v", opts.PusherID, repo.ID, err)
+				logger.Warn("Fail to perform auto watch on user %v for repo %v: %v", opts.PusherID, repo.ID, err)
 			}
 		}
 		actions = append(actions, &commitRepoActionOptions{
@@ -1,3 +1,50 @@
+const {csrf} = window.config;
+
+// directUploadOptions makes the files go to the storage directly with the requests returned
+// by the presign URL of the dropzone, the attachments are then created with its complete URL
+function directUploadOptions(el, opts) {
+  const presignUrl = el.getAttribute('data-presign-url');
+  const completeUrl = el.getAttribute('data-complete-url');
+  return {
+    ...opts,
+    url: (files) => files[0].directUpload.url,
+    method: (files) => files[0].directUpload.method,
+    // the default headers would have to be allowed by the storage
+    headers: {Accept: null, 'Cache-Control': null, 'X-Requested-With': null},
+    accept(file, done) {
+      $.post(presignUrl, {name: file.name, size: file.size, _csrf: csrf}).done((data) => {
+        file.directUpload = data;
+        done();
+      }).fail((xhr) => done(xhr.responseText));
+    },
+    init() {
+      const dz = this;
+      dz.on('sending', (file, xhr) => {
+        for (const [name, value] of Object.entries(file.directUpload.header || {})) {
+          xhr.setRequestHeader(name, value);
+        }
+        // the storage expects the content of the file instead of a form
+        const send = xhr.send;
+        xhr.send = () => send.call(xhr, file);
+      });
+      // the success listeners are given the attachment once it is created
+      const emit = dz.emit;
+      dz.emit = (event, file, ...args) => {
+        if (event !== 'success' || !file.directUpload) {
+          return emit.call(dz, event, file, ...args);
+        }
+        $.post(completeUrl, {file: file.directUpload.uuid, name: file.name, _csrf: csrf}).done((data) => {
+          emit.call(dz, 'success', file, data);
+        }).fail((xhr) => {
+          emit.call(dz, 'error', file, xhr.responseText);
+        });
+        return dz;
+      };
+      if (opts.init) opts.init.call(dz);
+    },
+  };
+}
+
 export default async function createDropzone(el, opts) {
   const [{default: Dropzone}] = await Promise.all([
     import(/* webpackChunkName: "dropzone" */'dropzone'),
@@ -5,5 +52,9 @@ export default async function createDropzone(el, opts) {
   ]);
 
   Dropzone.autoDiscover = false;
+  const element = typeof el === 'string' ? document.querySelector(el) : el;
+  if (element.getAttribute('data-presign-url')) {
+    return new Dropzone(element, directUploadOptions(element, opts));
+  }
   return new Dropzone(el, opts);
 }
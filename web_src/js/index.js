@@ -162,6 +162,7 @@ function initLabelEdit() {
     $('#label-modal-id').val($(this).data('id'));
     $('.edit-label .new-label-input').val($(this).data('title'));
     $('.edit-label .new-label-desc-input').val($(this).data('description'));
+    $('.edit-label .new-label-exclusive-input').prop('checked', $(this).data('exclusive'));
     $('.edit-label .color-picker').val($(this).data('color'));
     $('.minicolors-swatch-color').css('background-color', $(this).data('color'));
     $('.edit-label.modal').modal({
@@ -426,6 +427,13 @@ function initCommentForm() {
           }
         }
       } else {
+        // only one of the exclusive labels of a scope can be selected
+        const scope = $(this).data('scope');
+        if (scope) {
+          $listMenu.find('.item.checked').filter(function () {
+            return $(this).data('scope') === scope;
+          }).trigger('click');
+        }
         $(this).addClass('checked');
         $(this).find('.octicon-check').removeClass('invisible');
         if (hasUpdateAction) {
@@ -1211,7 +1219,7 @@ function initPullRequestReview() {
     }
   }
 
-  $('.show-outdated').on('click', function (e) {
+  $(document).on('click', '.show-outdated', function (e) {
     e.preventDefault();
     const id = $(this).data('comment');
     $(this).addClass('hide');
@@ -1220,7 +1228,7 @@ function initPullRequestReview() {
     $(`#hide-outdated-${id}`).removeClass('hide');
   });
 
-  $('.hide-outdated').on('click', function (e) {
+  $(document).on('click', '.hide-outdated', function (e) {
     e.preventDefault();
     const id = $(this).data('comment');
     $(this).addClass('hide');
@@ -1229,7 +1237,7 @@ function initPullRequestReview() {
     $(`#show-outdated-${id}`).removeClass('hide');
   });
 
-  $('button.comment-form-reply').on('click', function (e) {
+  $(document).on('click', 'button.comment-form-reply', function (e) {
     e.preventDefault();
     $(this).hide();
     const form = $(this).parent().find('.comment-form');
@@ -1261,7 +1269,8 @@ function initPullRequestReview() {
       $(this).closest('.menu').toggle('visible');
     });
 
-  $('.add-code-comment').on('click', function (e) {
+  let lastCodeCommentLine = null;
+  $(document).on('click', '.add-code-comment', function (e) {
     if ($(e.target).hasClass('btn-add-single')) return; // https://github.com/go-gitea/gitea/issues/4745
     e.preventDefault();
 
@@ -1307,7 +1316,13 @@ function initPullRequestReview() {
       td.find("input[name='line']").val(idx);
       td.find("input[name='side']").val(side === 'left' ? 'previous' : 'proposed');
       td.find("input[name='path']").val(path);
+      // Shift-click after clicking another line of the same file comments on the lines in between
+      if (e.shiftKey && lastCodeCommentLine && lastCodeCommentLine.path === path) {
+        td.find("input[name='start_line']").val(lastCodeCommentLine.idx);
+        td.find("input[name='start_side']").val(lastCodeCommentLine.side === 'left' ? 'previous' : 'proposed');
+      }
     }
+    lastCodeCommentLine = {path, side, idx};
     const $textarea = commentCloud.find('textarea');
     attachTribute($textarea.get(), {mentions: true, emoji: true});
 
@@ -2120,6 +2135,85 @@ function initCodeView() {
   });
 }
 
+// In split view an added line directly following a removed line is moved next to it
+function alignSplitDiffRows($container) {
+  $container.find('.code-diff-split tr.add-code').each(function () {
+    let prev = $(this).prev();
+    if (prev.is('.del-code') && prev.children().eq(5).text().trim() === '') {
+      while (prev.prev().is('.del-code') && prev.prev().children().eq(5).text().trim() === '') {
+        prev = prev.prev();
+      }
+      prev.children().eq(3).attr('data-line-num', $(this).children().eq(3).attr('data-line-num'));
+      prev.children().eq(3).html($(this).children().eq(3).html());
+      prev.children().eq(4).html($(this).children().eq(4).html());
+      prev.children().eq(5).html($(this).children().eq(5).html());
+
+      prev.children().eq(0).addClass('del-code');
+      prev.children().eq(1).addClass('del-code');
+      prev.children().eq(2).addClass('del-code');
+      prev.children().eq(3).addClass('add-code');
+      prev.children().eq(4).addClass('add-code');
+      prev.children().eq(5).addClass('add-code');
+
+      $(this).remove();
+    }
+  });
+}
+
+function initDiffFiles() {
+  alignSplitDiffRows($(document));
+
+  // Files whose diff has not been rendered with the page are loaded on demand
+  $(document).on('click', '.diff-load-file', async function (e) {
+    e.preventDefault();
+    const $box = $(this).closest('.diff-file-box');
+    $(this).addClass('loading disabled');
+    try {
+      const $file = $($.parseHTML(await $.get($(this).data('href')))).filter('.diff-file-box');
+      $box.replaceWith($file);
+      alignSplitDiffRows($file);
+    } catch (err) {
+      $(this).removeClass('loading disabled');
+    }
+  });
+
+  // Suggestions selected for a batch are applied in a single commit
+  $(document).on('change', '.suggestion-batch', () => {
+    const ids = $('.suggestion-batch:checked').map((_, el) => el.value).get();
+    const $form = $('#apply-suggestions-batch');
+    $form.find('input[name=comment_ids]').val(ids.join(','));
+    const $button = $form.find('button');
+    $button.text($button.data('label').replace('%d', ids.length));
+    $form.toggleClass('hide', ids.length === 0);
+  });
+
+  // Viewed files are folded and marked in the file list
+  $(document).on('change', '.diff-viewed-toggle input', async function () {
+    const $toggle = $(this).closest('.diff-viewed-toggle');
+    const $box = $toggle.closest('.diff-file-box');
+    const viewed = this.checked;
+    $toggle.toggleClass('active', viewed);
+    await $.post($toggle.data('link'), {
+      _csrf: csrf,
+      path: $toggle.data('path'),
+      sha: $toggle.data('sha'),
+      viewed,
+    });
+
+    const $fold = $box.find('.fold-file');
+    if ($fold.length > 0 && ($box.attr('data-folded') === 'true') !== viewed) {
+      $fold.trigger('click');
+    }
+    const index = $box.attr('id').replace('diff-', '');
+    $(`.diff-file-viewed[data-index="${index}"]`).toggleClass('hide', !viewed);
+    const $stats = $('.diff-viewed-stats');
+    if ($stats.length > 0) {
+      const numViewed = $('.diff-file-viewed').not('.hide').length;
+      $stats.text($stats.text().replace(/^\d+/, numViewed));
+    }
+  });
+}
+
 function initU2FAuth() {
   if ($('#wait-for-key').length === 0) {
     return;
@@ -2504,6 +2598,7 @@ $(document).ready(async () => {
   initWebhook();
   initAdmin();
   initCodeView();
+  initDiffFiles();
   initVueApp();
   initTeamSettings();
   initCtrlEnterSubmit();